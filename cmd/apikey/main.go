@@ -0,0 +1,81 @@
+// Command apikey issues and revokes the static API keys stored in MongoDB.
+//
+//	go run ./cmd/apikey -name ci -roles admin [-- <server config flags>]
+//	go run ./cmd/apikey -revoke <key ID> [-- <server config flags>]
+//
+// Flags after -- are those of the server, so the same config file and CRUD_*
+// environment variables select the database.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	db "github.com/Crud-application/db"
+	authApp "github.com/Crud-application/pkg/application/auth"
+	"github.com/Crud-application/pkg/config"
+	kRepo "github.com/Crud-application/pkg/infrastructure/persistence/apiKey"
+	"github.com/google/uuid"
+)
+
+func main() {
+	fs := flag.NewFlagSet("apikey", flag.ExitOnError)
+	name := fs.String("name", "", "name of the key to issue, e.g. the client it is for")
+	roles := fs.String("roles", "", "comma-separated roles the key grants")
+	revoke := fs.String("revoke", "", "ID of the key to revoke")
+	_ = fs.Parse(os.Args[1:])
+
+	cfg, err := config.Load(fs.Args())
+	if err != nil {
+		log.Fatalf("Failed to load configuration, err: %v", err)
+	}
+	if cfg.Repository.Backend != config.RepoBackendMongo {
+		log.Fatalf("API keys are stored in MongoDB; the %s backend keeps nothing between runs", cfg.Repository.Backend)
+	}
+
+	client, _, err := db.GetMongoDB(cfg.Mongo)
+	if err != nil {
+		log.Fatalf("Failed to connect to MongoDB, err: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Mongo.ConnectTimeout.Std())
+	defer cancel()
+	defer func() { _ = client.Disconnect(context.Background()) }()
+
+	repo := kRepo.NewMongoAPIKeyRepository(client, cfg.Mongo)
+	if err := repo.EnsureIndexes(ctx); err != nil {
+		log.Fatalf("Failed to ensure API key indexes, err: %v", err)
+	}
+	keys := authApp.NewAPIKeyService(repo, uuid.NewString, func() time.Time { return time.Now().UTC().Truncate(time.Millisecond) })
+
+	switch {
+	case *revoke != "":
+		if err := keys.RevokeAPIKey(ctx, *revoke); err != nil {
+			log.Fatalf("Failed to revoke API key, err: %v", err)
+		}
+		fmt.Printf("Revoked API key %s\n", *revoke)
+	case *name != "":
+		k, key, err := keys.CreateAPIKey(ctx, *name, splitRoles(*roles))
+		if err != nil {
+			log.Fatalf("Failed to issue API key, err: %v", err)
+		}
+		fmt.Printf("Issued API key %s (%s). It is shown only once:\n%s\n", k.ID, k.Name, key)
+	default:
+		fs.Usage()
+		os.Exit(2)
+	}
+}
+
+func splitRoles(roles string) []string {
+	var out []string
+	for _, r := range strings.Split(roles, ",") {
+		if r = strings.TrimSpace(r); r != "" {
+			out = append(out, r)
+		}
+	}
+	return out
+}
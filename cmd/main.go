@@ -1,13 +1,21 @@
 package main
 
 import (
-	"github.com/Crud-application/cmd/server"
 	"log"
+	"os"
+
+	"github.com/Crud-application/cmd/server"
+	"github.com/Crud-application/pkg/config"
 )
 
 func main() {
 
-	s, err := server.NewServer()
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		log.Fatalf("Failed to load configuration, err: %v. Shutting down.", err)
+	}
+
+	s, err := server.NewServer(cfg)
 	if err != nil {
 		log.Fatalf("Failed to start server, err: %v. Shutting down.", err)
 	}
@@ -0,0 +1,37 @@
+package server
+
+import (
+	mw "github.com/Crud-application/pkg/api/middleware"
+	"github.com/Crud-application/pkg/domain/authAgg"
+	"github.com/gin-gonic/gin"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// publicRoutes are served without credentials; every other route needs them.
+// Routes are given as "METHOD /full/path" with gin patterns.
+var publicRoutes = []string{
+	"GET /health",
+	"GET " + BasePath + "/openapi.json",
+	"GET " + BasePath + "/docs",
+	"GET " + BasePath + "/graphql/playground", // The page only; its queries are authenticated
+	"POST " + BasePath + "/auth/login",
+	"POST " + BasePath + "/auth/refresh", // The refresh token is the credential
+	"POST " + BasePath + "/auth/logout",
+	"POST " + BasePath + "/auth/forgot-password",
+	"POST " + BasePath + "/auth/reset-password", // The emailed token is the credential
+	"POST " + BasePath + "/users/verify",        // The emailed token is the credential
+}
+
+// publicMethods are the gRPC methods served without credentials
+var publicMethods = []string{
+	healthpb.Health_Check_FullMethodName,
+}
+
+// authorize checks the access policy on a route whose handler does not go through
+// a service enforcing it. Without authentication every route is open.
+func (h *HTTPServer) authorize(action authAgg.Action) gin.HandlerFunc {
+	if !h.Config.Auth.Enabled {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return mw.Authorize(h.Policy, action)
+}
@@ -0,0 +1,26 @@
+package server
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// setupAuthRoutes registers the routes for password login, sessions and password resets
+func setupAuthRoutes(r *gin.RouterGroup, s *HTTPServer) {
+	//Log in with an email and password
+	r.POST("/login", s.Handlers.AuthHandler.Login)
+
+	//Rotate a refresh token
+	r.POST("/refresh", s.Handlers.AuthHandler.Refresh)
+
+	//Revoke the session of a refresh token
+	r.POST("/logout", s.Handlers.AuthHandler.Logout)
+
+	//Change the password of the caller
+	r.POST("/password", s.Handlers.AuthHandler.ChangePassword)
+
+	//Email a password reset link
+	r.POST("/forgot-password", s.Handlers.AccountHandler.ForgotPassword)
+
+	//Set a new password with the token of a reset link
+	r.POST("/reset-password", s.Handlers.AccountHandler.ResetPassword)
+}
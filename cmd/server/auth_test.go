@@ -0,0 +1,25 @@
+package server
+
+import (
+	"testing"
+
+	h "github.com/Crud-application/pkg/api/handlers"
+	"github.com/Crud-application/pkg/config"
+	"github.com/gin-gonic/gin"
+)
+
+func TestPublicRoutesServed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := &HTTPServer{Engine: gin.New(), Handlers: &h.Handlers{}, Config: config.Default()}
+	s.SetupRoutes()
+
+	served := map[string]bool{}
+	for _, r := range s.Engine.Routes() {
+		served[r.Method+" "+r.Path] = true
+	}
+	for _, route := range publicRoutes {
+		if !served[route] {
+			t.Errorf("%s is public but not served", route)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package server
+
+import (
+	"github.com/Crud-application/pkg/api/openapi"
+	"github.com/gin-gonic/gin"
+)
+
+// setupDocsRoutes serves the OpenAPI document and the docs rendered from it
+func setupDocsRoutes(r *gin.RouterGroup) {
+	//Get the OpenAPI document
+	r.GET("/openapi.json", openapi.ServeSpec)
+
+	//Browse the API
+	r.GET("/docs", openapi.UI(r.BasePath()+"/openapi.json"))
+}
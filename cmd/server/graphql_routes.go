@@ -0,0 +1,18 @@
+package server
+
+import (
+	gqlH "github.com/Crud-application/pkg/api/graphqlHandlers"
+	"github.com/Crud-application/pkg/config"
+	"github.com/gin-gonic/gin"
+)
+
+// setupGraphQLRoutes registers the GraphQL endpoint, and its playground in dev
+func setupGraphQLRoutes(r *gin.RouterGroup, s *HTTPServer) {
+	//Execute a query or mutation
+	r.POST("", s.Handlers.GraphQLHandler.Query)
+
+	//Explore the schema in the browser
+	if s.Config.Env == config.EnvDev {
+		r.GET("/playground", gqlH.Playground(r.BasePath()))
+	}
+}
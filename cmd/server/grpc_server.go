@@ -0,0 +1,60 @@
+package server
+
+import (
+	"log"
+	"net"
+
+	gh "github.com/Crud-application/pkg/api/grpcHandlers"
+	"github.com/Crud-application/pkg/config"
+	"github.com/Crud-application/pkg/contracts/userv1"
+	"github.com/Crud-application/pkg/di"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// GRPCServer serves the gRPC API on its own port next to the HTTP server
+type GRPCServer struct {
+	Server *grpc.Server
+	Health *health.Server
+	Config *config.Config
+}
+
+// NewGRPCServer registers the gRPC services of app, along with the health and reflection services
+func NewGRPCServer(cfg *config.Config, app *di.Application) *GRPCServer {
+	interceptors := []grpc.UnaryServerInterceptor{gh.Logger(), gh.RequestID(), gh.Recovery()}
+	if cfg.Auth.Enabled {
+		interceptors = append(interceptors, gh.Authenticate(app.Authenticator, publicMethods))
+	}
+	srv := grpc.NewServer(grpc.ChainUnaryInterceptor(interceptors...))
+	userv1.RegisterUserServiceServer(srv, app.UserServer)
+
+	// The empty service name reports the health of the server as a whole
+	healthSrv := health.NewServer()
+	healthSrv.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthSrv.SetServingStatus(userv1.UserService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(srv, healthSrv)
+
+	// Lets tools such as grpcurl discover the services without the proto files
+	reflection.Register(srv)
+
+	return &GRPCServer{
+		Server: srv,
+		Health: healthSrv,
+		Config: cfg,
+	}
+}
+
+// Run listens for gRPC calls until the server stops
+func (g *GRPCServer) Run() {
+	addr := g.Config.Server.GRPCAddr()
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Fatalf("Failed to listen for gRPC on %s: %v", addr, err)
+	}
+	log.Printf("Starting gRPC server on %s", addr)
+	if err := g.Server.Serve(lis); err != nil {
+		log.Fatalf("Failed to serve gRPC: %v", err)
+	}
+}
@@ -0,0 +1,15 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setupHealthRoutes registers the liveness check polled by load balancers and orchestrators
+func setupHealthRoutes(r gin.IRouter) {
+	//Report that the server is up
+	r.GET("/health", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+
+	h "github.com/Crud-application/pkg/api/handlers"
+	"github.com/Crud-application/pkg/api/openapi"
+	"github.com/Crud-application/pkg/config"
+	"github.com/gin-gonic/gin"
+)
+
+// undocumented are the routes that serve the docs themselves
+var undocumented = map[string]bool{
+	"GET /openapi.json": true,
+	"GET /docs":         true,
+}
+
+var pathParam = regexp.MustCompile(`:(\w+)`)
+
+func TestRoutesDocumented(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := config.Default() // dev, so the playground is registered too
+	s := &HTTPServer{Engine: gin.New(), Handlers: &h.Handlers{}, Config: cfg}
+	s.SetupRoutes()
+
+	var doc openapi.Document
+	if err := json.Unmarshal(openapi.Spec, &doc); err != nil {
+		t.Fatalf("openapi.json is not a document: %v", err)
+	}
+
+	served := map[string]bool{}
+	for _, r := range s.Engine.Routes() {
+		if !strings.HasPrefix(r.Path, BasePath) {
+			continue
+		}
+		path := pathParam.ReplaceAllString(strings.TrimPrefix(r.Path, BasePath), "{$1}")
+		if undocumented[r.Method+" "+path] {
+			continue
+		}
+		served[r.Method+" "+path] = true
+		if _, ok := doc.Paths[path][strings.ToLower(r.Method)]; !ok {
+			t.Errorf("%s %s is served but missing from openapi.json", r.Method, path)
+		}
+	}
+
+	for path, ops := range doc.Paths {
+		for method := range ops {
+			if route := strings.ToUpper(method) + " " + path; !served[route] {
+				t.Errorf("%s is in openapi.json but not served", route)
+			}
+		}
+	}
+}
@@ -1,12 +1,34 @@
 package server
 
-// SetupPublicRoutes sets up public routes for user-related resources
+import "github.com/Crud-application/pkg/api/openapi"
+
+// SetupPublicRoutes sets up the routes for user-related resources, webhooks, sessions, GraphQL, the API docs and the health check.
+// Only those in publicRoutes are served without credentials.
 func SetupPublicRoutes(h *HTTPServer) {
-	crud := h.Engine.Group(BasePath)
+	// Set up the health check outside the API
+	setupHealthRoutes(h.Engine)
+
+	// Requests are checked against the OpenAPI document before they reach the handlers
+	crud := h.Engine.Group(BasePath, openapi.Validate(BasePath))
 
 	// Define API groups for user-related routes
 	userGroup := crud.Group("/users")
+	webhookGroup := crud.Group("/webhooks")
+	graphqlGroup := crud.Group("/graphql")
+	authGroup := crud.Group("/auth")
 
 	// Set up user-related routes
 	setupUserRoutes(userGroup, h)
+
+	// Set up webhook subscription routes
+	setupWebhookRoutes(webhookGroup, h)
+
+	// Set up login and session routes
+	setupAuthRoutes(authGroup, h)
+
+	// Set up the GraphQL endpoint
+	setupGraphQLRoutes(graphqlGroup, h)
+
+	// Serve the OpenAPI document and docs
+	setupDocsRoutes(crud)
 }
@@ -1,10 +1,20 @@
 package server
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	h "github.com/Crud-application/pkg/api/handlers"
+	mw "github.com/Crud-application/pkg/api/middleware"
+	"github.com/Crud-application/pkg/config"
 	"github.com/Crud-application/pkg/di"
+	"github.com/Crud-application/pkg/domain/authAgg"
 	"github.com/gin-gonic/gin"
 )
 
@@ -12,31 +22,84 @@ var (
 	BasePath = "/api"
 )
 
+// shutdownTimeout bounds how long requests in flight may take to finish on shutdown
+const shutdownTimeout = 10 * time.Second
+
 type HTTPServer struct {
 	Engine   *gin.Engine
 	Handlers *h.Handlers
+	Config   *config.Config
+	App      *di.Application
+	GRPC     *GRPCServer
+	Policy   authAgg.Policy // Guards the routes whose handlers bypass the services
 }
 
 // NewServer initializes a new HTTP server with configuration and routes
-func NewServer() (*HTTPServer, error) {
+func NewServer(cfg *config.Config) (*HTTPServer, error) {
+	if cfg.Env == config.EnvProd {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	app, err := di.InjectApplication(cfg)
+	if err != nil {
+		return nil, err
+	}
 
 	engine := gin.New()
 	// Add middlewares (like logger and recovery)
-	engine.Use(gin.Logger(), gin.Recovery())
+	// Errors from every route are rendered as application/problem+json
+	engine.Use(gin.Logger(), mw.RequestID(), mw.Problems(), mw.Recovery())
+	// Callers are authenticated before any route runs, so the services know who is calling
+	if cfg.Auth.Enabled {
+		engine.Use(mw.Authenticate(app.Authenticator, publicRoutes))
+	} else {
+		log.Printf("Authentication is disabled; every request is served anonymously")
+	}
+	engine.NoRoute(mw.NoRoute)
 
 	return &HTTPServer{
 		Engine:   engine,
-		Handlers: di.InjectHandler(),
+		Handlers: app.Handlers,
+		Config:   cfg,
+		App:      app,
+		Policy:   app.Policy,
+		GRPC:     NewGRPCServer(cfg, app),
 	}, nil
 }
 
-// Run starts the HTTP server and listens for requests
+// Run starts the background workers and the gRPC server, then the HTTP server listens for
+// requests until the process is interrupted or terminated. The workers stop with the server.
 func (h *HTTPServer) Run() {
-	port := ":3010" // Define the port
-	log.Printf("Starting server on port %s", port)
-	if err := h.Engine.Run(port); err != nil {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go h.App.UserPurger.Run(ctx)
+	go h.App.EventRelay.Run(ctx)
+	go h.App.DeliveryWorker.Run(ctx)
+	go h.App.StreamFeeder.Run(ctx)
+	go h.GRPC.Run()
+
+	addr := h.Config.Server.Addr()
+	srv := &http.Server{Addr: addr, Handler: h.Engine}
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		<-ctx.Done()
+		log.Printf("Shutting down")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		h.GRPC.Server.GracefulStop()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Error shutting down the server: %v", err)
+		}
+	}()
+
+	log.Printf("Starting server on %s (env: %s)", addr, h.Config.Env)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		log.Fatalf("Failed to start server: %v", err)
 	}
+	// Serving stops as soon as the shutdown begins; wait for the requests in flight
+	<-stopped
 }
 
 func (h *HTTPServer) SetupRoutes() {
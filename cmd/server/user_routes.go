@@ -1,6 +1,7 @@
 package server
 
 import (
+	"github.com/Crud-application/pkg/domain/authAgg"
 	"github.com/gin-gonic/gin"
 )
 
@@ -10,12 +11,21 @@ func setupUserRoutes(r *gin.RouterGroup, s *HTTPServer) {
 	r.POST("",
 		s.Handlers.UserHandler.CreateUser)
 
+	//Verify an email with the token emailed to it
+	r.POST("/verify",
+		s.Handlers.AccountHandler.VerifyEmail)
+
 	//Delete a user
 	r.DELETE("/:userID",
 		s.Handlers.UserHandler.DeleteUser)
 
 	r.GET("", s.Handlers.UserHandler.GetUsers)
 
+	//Stream user changes as Server-Sent Events
+	r.GET("/stream",
+		s.authorize(authAgg.ActionWatchUsers),
+		s.Handlers.UserStreamHandler.StreamUsers)
+
 	//Get a specific user by ID
 	r.GET("/:userID",
 		s.Handlers.UserHandler.GetUser)
@@ -24,4 +34,20 @@ func setupUserRoutes(r *gin.RouterGroup, s *HTTPServer) {
 	r.PATCH("/:userID",
 		s.Handlers.UserHandler.UpdateUser)
 
+	//Restore a deleted user
+	r.POST("/:userID/restore",
+		s.Handlers.UserHandler.RestoreUser)
+
+	//Suspend, activate or deactivate a user
+	r.POST("/:userID/suspend",
+		s.Handlers.UserHandler.SuspendUser)
+	r.POST("/:userID/activate",
+		s.Handlers.UserHandler.ActivateUser)
+	r.POST("/:userID/deactivate",
+		s.Handlers.UserHandler.DeactivateUser)
+
+	//Get the change history of a user
+	r.GET("/:userID/history",
+		s.Handlers.UserHandler.GetUserHistory)
+
 }
@@ -0,0 +1,30 @@
+package server
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// setupWebhookRoutes registers the routes managing webhook subscriptions
+func setupWebhookRoutes(r *gin.RouterGroup, s *HTTPServer) {
+	//Subscribe a URL to user events
+	r.POST("",
+		s.Handlers.WebhookHandler.CreateWebhook)
+
+	r.GET("", s.Handlers.WebhookHandler.GetWebhooks)
+
+	//Get a specific webhook by ID
+	r.GET("/:webhookID",
+		s.Handlers.WebhookHandler.GetWebhook)
+
+	//Update a webhook
+	r.PATCH("/:webhookID",
+		s.Handlers.WebhookHandler.UpdateWebhook)
+
+	//Delete a webhook
+	r.DELETE("/:webhookID",
+		s.Handlers.WebhookHandler.DeleteWebhook)
+
+	//Get the delivery log of a webhook
+	r.GET("/:webhookID/deliveries",
+		s.Handlers.WebhookHandler.GetDeliveries)
+}
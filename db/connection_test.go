@@ -0,0 +1,31 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestRequireTransactions(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	tests := []struct {
+		id      int
+		name    string
+		hello   bson.D
+		wantErr bool
+	}{
+		{id: 1, name: "Replica set member - Success", hello: bson.D{{Key: "setName", Value: "rs0"}}},
+		{id: 2, name: "Sharded cluster - Success", hello: bson.D{{Key: "msg", Value: "isdbgrid"}}},
+		{id: 3, name: "Standalone server - Failure", hello: bson.D{{Key: "isWritablePrimary", Value: true}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			mt.AddMockResponses(mtest.CreateSuccessResponse(tt.hello...))
+			if err := requireTransactions(context.Background(), mt.Client); (err != nil) != tt.wantErr {
+				mt.Errorf("ID %v requireTransactions() error = %v, wantErr %v", tt.id, err, tt.wantErr)
+			}
+		})
+	}
+}
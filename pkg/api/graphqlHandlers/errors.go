@@ -0,0 +1,68 @@
+package graphqlHandlers
+
+import (
+	"errors"
+	"log"
+
+	"github.com/Crud-application/pkg/domain/domainErr"
+)
+
+// Codes reported in the extensions of GraphQL errors, so clients need not parse messages
+const (
+	CodeNotFound           = "NOT_FOUND"
+	CodeConflict           = "CONFLICT"
+	CodeBadUserInput       = "BAD_USER_INPUT"
+	CodePreconditionFailed = "PRECONDITION_FAILED"
+	CodeUnavailable        = "UNAVAILABLE"
+	CodeUnauthenticated    = "UNAUTHENTICATED"
+	CodeForbidden          = "FORBIDDEN"
+	CodeInternal           = "INTERNAL_SERVER_ERROR"
+)
+
+// resolverError is an error whose extensions graphql-go adds to the response
+type resolverError struct {
+	message    string
+	extensions map[string]any
+}
+
+func (e *resolverError) Error() string              { return e.message }
+func (e *resolverError) Extensions() map[string]any { return e.extensions }
+
+// CodeFromError maps domain errors to GraphQL error codes, like middleware.StatusFromError does to HTTP status codes
+func CodeFromError(err error) string {
+	switch {
+	case errors.Is(err, domainErr.ErrNotFound):
+		return CodeNotFound
+	case errors.Is(err, domainErr.ErrConflict):
+		return CodeConflict
+	case errors.Is(err, domainErr.ErrValidation):
+		return CodeBadUserInput
+	case errors.Is(err, domainErr.ErrPreconditionFailed):
+		return CodePreconditionFailed
+	case errors.Is(err, domainErr.ErrUnavailable):
+		return CodeUnavailable
+	case errors.Is(err, domainErr.ErrUnauthenticated):
+		return CodeUnauthenticated
+	case errors.Is(err, domainErr.ErrForbidden):
+		return CodeForbidden
+	default:
+		return CodeInternal
+	}
+}
+
+// toGraphQLError describes err with its code and invalid fields. Internal errors are logged rather than leaked.
+func toGraphQLError(err error) error {
+	code := CodeFromError(err)
+	if code == CodeInternal || code == CodeUnavailable {
+		log.Printf("GraphQL request failed: %v", err)
+		if code == CodeInternal {
+			return &resolverError{message: "internal error", extensions: map[string]any{"code": code}}
+		}
+	}
+
+	ext := map[string]any{"code": code}
+	if fields := domainErr.FieldErrors(err); len(fields) > 0 {
+		ext["fields"] = fields
+	}
+	return &resolverError{message: err.Error(), extensions: ext}
+}
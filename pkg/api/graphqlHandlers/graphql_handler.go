@@ -0,0 +1,77 @@
+package graphqlHandlers
+
+import (
+	_ "embed"
+	"html/template"
+	"net/http"
+
+	uService "github.com/Crud-application/pkg/application/services"
+	"github.com/gin-gonic/gin"
+	"github.com/graph-gophers/graphql-go"
+)
+
+// schemaSDL is the GraphQL schema, resolved by resolver
+//
+//go:embed schema.graphql
+var schemaSDL string
+
+// maxDepth bounds how deeply queries may nest, so one request cannot fan out without limit
+const maxDepth = 10
+
+type GraphQLHandler struct {
+	schema *graphql.Schema
+}
+
+func NewGraphQLHandler(userService uService.IUserService) *GraphQLHandler {
+	return &GraphQLHandler{
+		schema: graphql.MustParseSchema(schemaSDL, &resolver{userSvc: userService}, graphql.MaxDepth(maxDepth)),
+	}
+}
+
+// graphQLReq is a GraphQL request sent over HTTP as JSON
+type graphQLReq struct {
+	Query         string         `json:"query" binding:"required"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// Query executes a GraphQL query or mutation. Errors of the operation are part of
+// the 200 response; only a malformed request is answered with a problem.
+func (gh *GraphQLHandler) Query(c *gin.Context) {
+	var req graphQLReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	res := gh.schema.Exec(c.Request.Context(), req.Query, req.OperationName, req.Variables)
+	c.JSON(http.StatusOK, res)
+}
+
+var playgroundPage = template.Must(template.New("playground").Parse(`<!DOCTYPE html>
+<html>
+<head>
+  <title>GraphQL Playground</title>
+  <link rel="stylesheet" href="https://unpkg.com/graphiql@3/graphiql.min.css" />
+</head>
+<body style="margin: 0">
+  <div id="graphiql" style="height: 100vh"></div>
+  <script crossorigin src="https://unpkg.com/react@18/umd/react.production.min.js"></script>
+  <script crossorigin src="https://unpkg.com/react-dom@18/umd/react-dom.production.min.js"></script>
+  <script crossorigin src="https://unpkg.com/graphiql@3/graphiql.min.js"></script>
+  <script>
+    const fetcher = GraphiQL.createFetcher({ url: {{.}} });
+    ReactDOM.createRoot(document.getElementById("graphiql")).render(React.createElement(GraphiQL, { fetcher }));
+  </script>
+</body>
+</html>
+`))
+
+// Playground serves GraphiQL, sending queries to endpoint
+func Playground(endpoint string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Content-Type", "text/html; charset=utf-8")
+		c.Status(http.StatusOK)
+		_ = playgroundPage.Execute(c.Writer, endpoint)
+	}
+}
@@ -0,0 +1,149 @@
+package graphqlHandlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	mw "github.com/Crud-application/pkg/api/middleware"
+	"github.com/Crud-application/pkg/application/services/mocks"
+	"github.com/Crud-application/pkg/contracts/user"
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraphQLHandler_Query(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	at := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	got := user.GetUserRes{ID: "u1", Name: "alam", Email: "a@b.com", PhoneNumber: "+919876543210", Version: 2, CreatedAt: at, UpdatedAt: at, CreatedBy: "alice", UpdatedBy: "bob"}
+	name := "alam"
+
+	tests := []struct {
+		id         int
+		name       string
+		beforeTest func(svc *mocks.MockIUserService)
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			id:   1,
+			name: "Query the requested user fields - success",
+			beforeTest: func(svc *mocks.MockIUserService) {
+				svc.EXPECT().GetUser(gomock.Any(), "u1").Return(&got, nil)
+			},
+			body:       `{"query": "{ user(id: \"u1\") { id name createdAt deletedAt } }"}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"data": {"user": {"id": "u1", "name": "alam", "createdAt": "2024-05-01T10:00:00Z", "deletedAt": null}}}`,
+		},
+		{
+			id:   2,
+			name: "Query a missing user - null",
+			beforeTest: func(svc *mocks.MockIUserService) {
+				svc.EXPECT().GetUser(gomock.Any(), "u9").Return(nil, domainErr.NotFound("user %s not found", "u9"))
+			},
+			body:       `{"query": "query($id: ID!) { user(id: $id) { id } }", "variables": {"id": "u9"}}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"data": {"user": null}}`,
+		},
+		{
+			id:   3,
+			name: "Query a filtered page of users as a connection - success",
+			beforeTest: func(svc *mocks.MockIUserService) {
+				svc.EXPECT().GetAllUsers(gomock.Any(), &user.GetUsersReq{
+					Limit:  1,
+					Cursor: "dTA",
+					Search: "al",
+					Filters: map[string][]string{
+						"name~":       {"al"},
+						"email!":      {"x@b.com"},
+						"status":      {"suspended"},
+						"created_at>": {"2024-05-01T00:00:00Z"},
+					},
+					IncludeDeleted: true,
+				}).Return(&user.GetUsersRes{Users: []user.GetUserRes{got}, Total: 3, Limit: 1, NextCursor: "dTE"}, nil)
+			},
+			body: `{"query": "{ users(first: 1, after: \"dTA\", filter: {search: \"al\", name: {contains: \"al\"}, email: {ne: \"x@b.com\"}, status: {eq: \"suspended\"}, createdAt: {gte: \"2024-05-01T00:00:00Z\"}, includeDeleted: true}) ` +
+				`{ totalCount edges { cursor node { id } } pageInfo { hasNextPage endCursor } } }"}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"data": {"users": {"totalCount": 3, "edges": [{"cursor": "dTE", "node": {"id": "u1"}}], "pageInfo": {"hasNextPage": true, "endCursor": "dTE"}}}}`,
+		},
+		{
+			id:   4,
+			name: "Create an invalid user - bad user input with fields",
+			beforeTest: func(svc *mocks.MockIUserService) {
+				svc.EXPECT().CreateUser(gomock.Any(), &user.CreateUserReq{Name: "alam", Email: "nope", PhoneNumber: "+919876543210"}).
+					Return(nil, domainErr.Validation("invalid user", domainErr.FieldError{Field: "email", Message: "must be a valid email address"}))
+			},
+			body:       `{"query": "mutation { createUser(input: {name: \"alam\", email: \"nope\", phoneNumber: \"+919876543210\"}) { id } }"}`,
+			wantStatus: http.StatusOK,
+			wantBody: `{"data": null, "errors": [{"message": "invalid user: email must be a valid email address", "path": ["createUser"],
+				"extensions": {"code": "BAD_USER_INPUT", "fields": [{"field": "email", "message": "must be a valid email address"}]}}]}`,
+		},
+		{
+			id:   5,
+			name: "Update the given fields at the expected version - success",
+			beforeTest: func(svc *mocks.MockIUserService) {
+				svc.EXPECT().UpdateUser(gomock.Any(), "u1", &user.UpdateUserReq{ID: "u1", Name: &name, IfMatch: []int64{1}}).
+					Return(&user.UpdateUserRes{ID: "u1", Name: "alam", Version: 2}, nil)
+			},
+			body:       `{"query": "mutation { updateUser(id: \"u1\", input: {name: \"alam\"}, expectedVersion: 1) { name version } }"}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"data": {"updateUser": {"name": "alam", "version": 2}}}`,
+		},
+		{
+			id:   6,
+			name: "Delete a user - success",
+			beforeTest: func(svc *mocks.MockIUserService) {
+				svc.EXPECT().DeleteUser(gomock.Any(), "u1").Return(nil)
+			},
+			body:       `{"query": "mutation { deleteUser(id: \"u1\") }"}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"data": {"deleteUser": "u1"}}`,
+		},
+		{
+			id:   7,
+			name: "Unexpected failure - internal error without details",
+			beforeTest: func(svc *mocks.MockIUserService) {
+				svc.EXPECT().DeleteUser(gomock.Any(), "u1").Return(errors.New("socket closed"))
+			},
+			body:       `{"query": "mutation { deleteUser(id: \"u1\") }"}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"data": null, "errors": [{"message": "internal error", "path": ["deleteUser"], "extensions": {"code": "INTERNAL_SERVER_ERROR"}}]}`,
+		},
+		{
+			id:         8,
+			name:       "Missing query - problem",
+			beforeTest: func(svc *mocks.MockIUserService) {},
+			body:       `{"variables": {}}`,
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := mocks.NewMockIUserService(ctrl)
+			tt.beforeTest(svc)
+
+			r := gin.New()
+			r.Use(mw.Problems())
+			r.POST("/graphql", NewGraphQLHandler(svc).Query)
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code, "ID %v: %s", tt.id, w.Body)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, w.Body.String(), "ID %v", tt.id)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package graphqlHandlers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	uService "github.com/Crud-application/pkg/application/services"
+	"github.com/Crud-application/pkg/contracts/user"
+	"github.com/Crud-application/pkg/domain/domainErr"
+	uAgg "github.com/Crud-application/pkg/domain/userAgg"
+	"github.com/graph-gophers/graphql-go"
+)
+
+// resolver resolves the fields of the Query and Mutation root types
+type resolver struct {
+	userSvc uService.IUserService
+}
+
+// User resolves user(id), answering null for a user that does not exist
+func (r *resolver) User(ctx context.Context, args struct{ ID graphql.ID }) (*userResolver, error) {
+	res, err := r.userSvc.GetUser(ctx, string(args.ID))
+	if errors.Is(err, domainErr.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, toGraphQLError(err)
+	}
+	return &userResolver{u: res}, nil
+}
+
+type usersArgs struct {
+	Filter *userFilter
+	First  *int32
+	After  *string
+}
+
+// Users resolves users(filter, first, after) as a Relay connection
+func (r *resolver) Users(ctx context.Context, args usersArgs) (*userConnectionResolver, error) {
+	req := &user.GetUsersReq{}
+	if args.First != nil {
+		req.Limit = int(*args.First)
+	}
+	if args.After != nil {
+		req.Cursor = *args.After
+	}
+	if f := args.Filter; f != nil {
+		req.Filters = f.queryFilters()
+		if f.Search != nil {
+			req.Search = *f.Search
+		}
+		if f.IncludeDeleted != nil {
+			req.IncludeDeleted = *f.IncludeDeleted
+		}
+	}
+
+	res, err := r.userSvc.GetAllUsers(ctx, req)
+	if err != nil {
+		return nil, toGraphQLError(err)
+	}
+	return &userConnectionResolver{res: res}, nil
+}
+
+type createUserInput struct {
+	Name        string
+	Email       string
+	PhoneNumber string
+}
+
+// CreateUser resolves createUser(input)
+func (r *resolver) CreateUser(ctx context.Context, args struct{ Input createUserInput }) (*userResolver, error) {
+	res, err := r.userSvc.CreateUser(ctx, &user.CreateUserReq{
+		Name:        args.Input.Name,
+		Email:       args.Input.Email,
+		PhoneNumber: args.Input.PhoneNumber,
+	})
+	if err != nil {
+		return nil, toGraphQLError(err)
+	}
+	return &userResolver{u: fromCreateUserRes(res)}, nil
+}
+
+type updateUserArgs struct {
+	ID    graphql.ID
+	Input struct {
+		Name        *string
+		Email       *string
+		PhoneNumber *string
+	}
+	ExpectedVersion *int32
+}
+
+// UpdateUser resolves updateUser(id, input, expectedVersion)
+func (r *resolver) UpdateUser(ctx context.Context, args updateUserArgs) (*userResolver, error) {
+	req := &user.UpdateUserReq{
+		ID:          string(args.ID),
+		Name:        args.Input.Name,
+		Email:       args.Input.Email,
+		PhoneNumber: args.Input.PhoneNumber,
+	}
+	if args.ExpectedVersion != nil {
+		req.IfMatch = []int64{int64(*args.ExpectedVersion)}
+	}
+
+	res, err := r.userSvc.UpdateUser(ctx, string(args.ID), req)
+	if err != nil {
+		return nil, toGraphQLError(err)
+	}
+	return &userResolver{u: fromUpdateUserRes(res)}, nil
+}
+
+// DeleteUser resolves deleteUser(id)
+func (r *resolver) DeleteUser(ctx context.Context, args struct{ ID graphql.ID }) (graphql.ID, error) {
+	if err := r.userSvc.DeleteUser(ctx, string(args.ID)); err != nil {
+		return "", toGraphQLError(err)
+	}
+	return args.ID, nil
+}
+
+type stringFilter struct {
+	Eq       *string
+	Ne       *string
+	Contains *string
+}
+
+type timeFilter struct {
+	Gte *graphql.Time
+	Lte *graphql.Time
+}
+
+type userFilter struct {
+	Search         *string
+	Name           *stringFilter
+	Email          *stringFilter
+	PhoneNumber    *stringFilter
+	Status         *stringFilter
+	CreatedBy      *stringFilter
+	UpdatedBy      *stringFilter
+	CreatedAt      *timeFilter
+	UpdatedAt      *timeFilter
+	IncludeDeleted *bool
+}
+
+// queryFilters translates the filter to the query parameter syntax of the
+// REST listing, e.g. name contains "al" becomes "name~" => ["al"]
+func (f *userFilter) queryFilters() map[string][]string {
+	filters := map[string][]string{}
+	add := func(key string, value *string) {
+		if value != nil {
+			filters[key] = append(filters[key], *value)
+		}
+	}
+	addTime := func(key string, value *graphql.Time) {
+		if value != nil {
+			filters[key] = append(filters[key], value.Format(time.RFC3339Nano))
+		}
+	}
+
+	for field, sf := range map[string]*stringFilter{
+		uAgg.FieldName:        f.Name,
+		uAgg.FieldEmail:       f.Email,
+		uAgg.FieldPhoneNumber: f.PhoneNumber,
+		uAgg.FieldStatus:      f.Status,
+		uAgg.FieldCreatedBy:   f.CreatedBy,
+		uAgg.FieldUpdatedBy:   f.UpdatedBy,
+	} {
+		if sf != nil {
+			add(field, sf.Eq)
+			add(field+"!", sf.Ne)
+			add(field+"~", sf.Contains)
+		}
+	}
+	for field, tf := range map[string]*timeFilter{
+		uAgg.FieldCreatedAt: f.CreatedAt,
+		uAgg.FieldUpdatedAt: f.UpdatedAt,
+	} {
+		if tf != nil {
+			addTime(field+">", tf.Gte)
+			addTime(field+"<", tf.Lte)
+		}
+	}
+	return filters
+}
@@ -0,0 +1,113 @@
+package graphqlHandlers
+
+import (
+	"github.com/Crud-application/pkg/contracts/user"
+	"github.com/Crud-application/pkg/domain/query"
+	"github.com/graph-gophers/graphql-go"
+)
+
+// userResolver resolves the fields of User
+type userResolver struct {
+	u *user.GetUserRes
+}
+
+func (r *userResolver) ID() graphql.ID          { return graphql.ID(r.u.ID) }
+func (r *userResolver) Name() string            { return r.u.Name }
+func (r *userResolver) Email() string           { return r.u.Email }
+func (r *userResolver) PhoneNumber() string     { return r.u.PhoneNumber }
+func (r *userResolver) EmailVerified() bool     { return r.u.EmailVerified }
+func (r *userResolver) Status() string          { return r.u.Status }
+func (r *userResolver) Version() int32          { return int32(r.u.Version) }
+func (r *userResolver) CreatedAt() graphql.Time { return graphql.Time{Time: r.u.CreatedAt} }
+func (r *userResolver) UpdatedAt() graphql.Time { return graphql.Time{Time: r.u.UpdatedAt} }
+func (r *userResolver) CreatedBy() string       { return r.u.CreatedBy }
+func (r *userResolver) UpdatedBy() string       { return r.u.UpdatedBy }
+
+func (r *userResolver) StatusReason() *string {
+	if r.u.StatusReason == "" {
+		return nil
+	}
+	return &r.u.StatusReason
+}
+
+func (r *userResolver) DeletedAt() *graphql.Time {
+	if r.u.DeletedAt == nil {
+		return nil
+	}
+	return &graphql.Time{Time: *r.u.DeletedAt}
+}
+
+// userConnectionResolver resolves one page of users as a UserConnection
+type userConnectionResolver struct {
+	res *user.GetUsersRes
+}
+
+func (r *userConnectionResolver) Edges() []*userEdgeResolver {
+	edges := make([]*userEdgeResolver, 0, len(r.res.Users))
+	for i := range r.res.Users {
+		edges = append(edges, &userEdgeResolver{u: &r.res.Users[i]})
+	}
+	return edges
+}
+
+func (r *userConnectionResolver) PageInfo() *pageInfoResolver {
+	p := &pageInfoResolver{hasNextPage: r.res.NextCursor != ""}
+	if n := len(r.res.Users); n > 0 {
+		cursor := query.EncodeCursor(r.res.Users[n-1].ID)
+		p.endCursor = &cursor
+	}
+	return p
+}
+
+func (r *userConnectionResolver) TotalCount() int32 { return int32(r.res.Total) }
+
+// userEdgeResolver resolves a UserEdge. Its cursor continues the listing after
+// the user, like the next_cursor of the REST listing.
+type userEdgeResolver struct {
+	u *user.GetUserRes
+}
+
+func (r *userEdgeResolver) Cursor() string      { return query.EncodeCursor(r.u.ID) }
+func (r *userEdgeResolver) Node() *userResolver { return &userResolver{u: r.u} }
+
+type pageInfoResolver struct {
+	hasNextPage bool
+	endCursor   *string
+}
+
+func (r *pageInfoResolver) HasNextPage() bool  { return r.hasNextPage }
+func (r *pageInfoResolver) EndCursor() *string { return r.endCursor }
+
+func fromCreateUserRes(res *user.CreateUserRes) *user.GetUserRes {
+	return &user.GetUserRes{
+		ID:            res.ID,
+		Name:          res.Name,
+		Email:         res.Email,
+		PhoneNumber:   res.PhoneNumber,
+		EmailVerified: res.EmailVerified,
+		Status:        res.Status,
+		StatusReason:  res.StatusReason,
+		Version:       res.Version,
+		CreatedAt:     res.CreatedAt,
+		UpdatedAt:     res.UpdatedAt,
+		CreatedBy:     res.CreatedBy,
+		UpdatedBy:     res.UpdatedBy,
+	}
+}
+
+func fromUpdateUserRes(res *user.UpdateUserRes) *user.GetUserRes {
+	return &user.GetUserRes{
+		ID:            res.ID,
+		Name:          res.Name,
+		Email:         res.Email,
+		PhoneNumber:   res.PhoneNumber,
+		EmailVerified: res.EmailVerified,
+		Status:        res.Status,
+		StatusReason:  res.StatusReason,
+		Version:       res.Version,
+		CreatedAt:     res.CreatedAt,
+		UpdatedAt:     res.UpdatedAt,
+		CreatedBy:     res.CreatedBy,
+		UpdatedBy:     res.UpdatedBy,
+	}
+}
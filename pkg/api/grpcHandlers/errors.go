@@ -0,0 +1,67 @@
+package grpcHandlers
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CodeFromError maps domain errors to gRPC status codes, like middleware.StatusFromError does to HTTP ones.
+// Conflicts are told apart as gRPC clients expect: retry a stale version, fix an invalid state.
+func CodeFromError(err error) codes.Code {
+	switch {
+	case errors.Is(err, domainErr.ErrNotFound):
+		return codes.NotFound
+	case errors.Is(err, domainErr.ErrStaleVersion):
+		return codes.Aborted
+	case errors.Is(err, domainErr.ErrInvalidState):
+		return codes.FailedPrecondition
+	case errors.Is(err, domainErr.ErrConflict):
+		return codes.AlreadyExists
+	case errors.Is(err, domainErr.ErrValidation):
+		return codes.InvalidArgument
+	case errors.Is(err, domainErr.ErrPreconditionFailed):
+		return codes.FailedPrecondition
+	case errors.Is(err, domainErr.ErrUnavailable):
+		return codes.Unavailable
+	case errors.Is(err, domainErr.ErrUnauthenticated):
+		return codes.Unauthenticated
+	case errors.Is(err, domainErr.ErrForbidden):
+		return codes.PermissionDenied
+	case errors.Is(err, context.Canceled):
+		return codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return codes.DeadlineExceeded
+	default:
+		return codes.Internal
+	}
+}
+
+// toStatus describes err as a gRPC status. Invalid fields are attached as a
+// BadRequest detail, and internal errors are logged rather than leaked.
+func toStatus(err error) error {
+	code := CodeFromError(err)
+	if code == codes.Internal || code == codes.Unavailable {
+		log.Printf("gRPC request failed: %v", err)
+		if code == codes.Internal {
+			return status.Error(code, "internal error")
+		}
+	}
+
+	st := status.New(code, err.Error())
+	if fields := domainErr.FieldErrors(err); len(fields) > 0 {
+		br := &errdetails.BadRequest{}
+		for _, f := range fields {
+			br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{Field: f.Field, Description: f.Message})
+		}
+		if detailed, err := st.WithDetails(br); err == nil {
+			st = detailed
+		}
+	}
+	return st.Err()
+}
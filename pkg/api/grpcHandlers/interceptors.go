@@ -0,0 +1,97 @@
+package grpcHandlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	authApp "github.com/Crud-application/pkg/application/auth"
+	"github.com/Crud-application/pkg/application/requestCtx"
+	svcInter "github.com/Crud-application/pkg/application/services"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// RequestIDMetadata carries the request ID in request and response headers, like X-Request-ID over HTTP
+	RequestIDMetadata = "x-request-id"
+	// APIKeyMetadata carries static API keys, like X-API-Key over HTTP
+	APIKeyMetadata = "x-api-key"
+)
+
+// Logger logs the method, status code and duration of every call
+func Logger() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		res, err := handler(ctx, req)
+		log.Printf("[gRPC] %s | %s | %v", info.FullMethod, status.Code(err), time.Since(start))
+		return res, err
+	}
+}
+
+// RequestID reuses the caller's x-request-id or generates a new one, and sends
+// it back in the response header. The ID is also put in the context for the services.
+func RequestID() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		var requestID string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if ids := md.Get(RequestIDMetadata); len(ids) > 0 {
+				requestID = ids[0]
+			}
+		}
+		if requestID == "" {
+			requestID = uuid.New().String()
+		}
+		_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIDMetadata, requestID))
+		return handler(requestCtx.WithRequestID(ctx, requestID), req)
+	}
+}
+
+// Recovery turns panics into an Internal status
+func Recovery() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (res any, err error) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				log.Printf("Recovered from panic in %s: %v", info.FullMethod, recovered)
+				res, err = nil, status.Error(codes.Internal, "internal error")
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// Authenticate resolves the principal of every call from its authorization or
+// x-api-key metadata and puts it in the context for the services. Calls without
+// credentials are rejected as Unauthenticated unless their full method is public.
+func Authenticate(auth svcInter.IAuthenticator, public []string) grpc.UnaryServerInterceptor {
+	allowed := make(map[string]bool, len(public))
+	for _, method := range public {
+		allowed[method] = true
+	}
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		var authorization, apiKey string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get("authorization"); len(values) > 0 {
+				authorization = values[0]
+			}
+			if values := md.Get(APIKeyMetadata); len(values) > 0 {
+				apiKey = values[0]
+			}
+		}
+
+		p, err := auth.Authenticate(ctx, authorization, apiKey)
+		switch {
+		case err == nil:
+			ctx = requestCtx.WithPrincipal(ctx, p)
+		case errors.Is(err, authApp.ErrNoCredentials) && allowed[info.FullMethod]:
+		default:
+			return nil, toStatus(err)
+		}
+		return handler(ctx, req)
+	}
+}
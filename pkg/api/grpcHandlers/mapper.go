@@ -0,0 +1,65 @@
+package grpcHandlers
+
+import (
+	"github.com/Crud-application/pkg/contracts/user"
+	"github.com/Crud-application/pkg/contracts/userv1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func fromCreateUserRes(res *user.CreateUserRes) *userv1.User {
+	return &userv1.User{
+		Id:          res.ID,
+		Name:        res.Name,
+		Email:       res.Email,
+		PhoneNumber: res.PhoneNumber,
+		Version:     res.Version,
+		CreateTime:  timestamppb.New(res.CreatedAt),
+		UpdateTime:  timestamppb.New(res.UpdatedAt),
+		CreatedBy:   res.CreatedBy,
+		UpdatedBy:   res.UpdatedBy,
+	}
+}
+
+func fromGetUserRes(res *user.GetUserRes) *userv1.User {
+	u := &userv1.User{
+		Id:          res.ID,
+		Name:        res.Name,
+		Email:       res.Email,
+		PhoneNumber: res.PhoneNumber,
+		Version:     res.Version,
+		CreateTime:  timestamppb.New(res.CreatedAt),
+		UpdateTime:  timestamppb.New(res.UpdatedAt),
+		CreatedBy:   res.CreatedBy,
+		UpdatedBy:   res.UpdatedBy,
+	}
+	if res.DeletedAt != nil {
+		u.DeleteTime = timestamppb.New(*res.DeletedAt)
+	}
+	return u
+}
+
+func fromUpdateUserRes(res *user.UpdateUserRes) *userv1.User {
+	return &userv1.User{
+		Id:          res.ID,
+		Name:        res.Name,
+		Email:       res.Email,
+		PhoneNumber: res.PhoneNumber,
+		Version:     res.Version,
+		CreateTime:  timestamppb.New(res.CreatedAt),
+		UpdateTime:  timestamppb.New(res.UpdatedAt),
+		CreatedBy:   res.CreatedBy,
+		UpdatedBy:   res.UpdatedBy,
+	}
+}
+
+func fromGetUsersRes(res *user.GetUsersRes) *userv1.ListUsersResponse {
+	users := make([]*userv1.User, 0, len(res.Users))
+	for i := range res.Users {
+		users = append(users, fromGetUserRes(&res.Users[i]))
+	}
+	return &userv1.ListUsersResponse{
+		Users:         users,
+		NextPageToken: res.NextCursor,
+		TotalSize:     res.Total,
+	}
+}
@@ -0,0 +1,129 @@
+package grpcHandlers
+
+import (
+	"context"
+	"fmt"
+
+	uService "github.com/Crud-application/pkg/application/services"
+	"github.com/Crud-application/pkg/contracts/user"
+	"github.com/Crud-application/pkg/contracts/userv1"
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// UserServer serves the user.v1 UserService on top of the service layer of the REST API
+type UserServer struct {
+	userv1.UnimplementedUserServiceServer
+	userSvc uService.IUserService
+}
+
+func NewUserServer(userService uService.IUserService) *UserServer {
+	return &UserServer{
+		userSvc: userService,
+	}
+}
+
+// CreateUser creates a new user
+func (s *UserServer) CreateUser(ctx context.Context, req *userv1.CreateUserRequest) (*userv1.User, error) {
+	res, err := s.userSvc.CreateUser(ctx, &user.CreateUserReq{
+		Name:        req.GetName(),
+		Email:       req.GetEmail(),
+		PhoneNumber: req.GetPhoneNumber(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return fromCreateUserRes(res), nil
+}
+
+// GetUser returns a user by ID
+func (s *UserServer) GetUser(ctx context.Context, req *userv1.GetUserRequest) (*userv1.User, error) {
+	if req.GetId() == "" {
+		return nil, toStatus(errRequired("id"))
+	}
+	res, err := s.userSvc.GetUser(ctx, req.GetId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return fromGetUserRes(res), nil
+}
+
+// UpdateUser changes the fields of a user named in the update mask
+func (s *UserServer) UpdateUser(ctx context.Context, req *userv1.UpdateUserRequest) (*userv1.User, error) {
+	u := req.GetUser()
+	if u.GetId() == "" {
+		return nil, toStatus(errRequired("user.id"))
+	}
+
+	paths := req.GetUpdateMask().GetPaths()
+	if len(paths) == 0 {
+		// Without a mask every field that is set is updated
+		for path, value := range map[string]string{"name": u.GetName(), "email": u.GetEmail(), "phone_number": u.GetPhoneNumber()} {
+			if value != "" {
+				paths = append(paths, path)
+			}
+		}
+	}
+
+	upd := &user.UpdateUserReq{ID: u.GetId()}
+	for _, path := range paths {
+		switch path {
+		case "name":
+			upd.Name = &u.Name
+		case "email":
+			upd.Email = &u.Email
+		case "phone_number":
+			upd.PhoneNumber = &u.PhoneNumber
+		default:
+			return nil, toStatus(domainErr.Validation("invalid update mask",
+				domainErr.FieldError{Field: "update_mask", Message: fmt.Sprintf("has unknown path %q", path)}))
+		}
+	}
+	if req.GetExpectedVersion() != 0 {
+		upd.IfMatch = []int64{req.GetExpectedVersion()}
+	}
+
+	res, err := s.userSvc.UpdateUser(ctx, u.GetId(), upd)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return fromUpdateUserRes(res), nil
+}
+
+// DeleteUser soft-deletes a user by ID
+func (s *UserServer) DeleteUser(ctx context.Context, req *userv1.DeleteUserRequest) (*emptypb.Empty, error) {
+	if req.GetId() == "" {
+		return nil, toStatus(errRequired("id"))
+	}
+	if err := s.userSvc.DeleteUser(ctx, req.GetId()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// ListUsers returns one page of users
+func (s *UserServer) ListUsers(ctx context.Context, req *userv1.ListUsersRequest) (*userv1.ListUsersResponse, error) {
+	filters := make(map[string][]string, len(req.GetFilters()))
+	for k, v := range req.GetFilters() {
+		filters[k] = []string{v}
+	}
+
+	res, err := s.userSvc.GetAllUsers(ctx, &user.GetUsersReq{
+		Limit:          int(req.GetPageSize()),
+		Cursor:         req.GetPageToken(),
+		Offset:         int(req.GetSkip()),
+		Search:         req.GetSearch(),
+		Sort:           req.GetOrderBy(),
+		Filters:        filters,
+		IncludeDeleted: req.GetIncludeDeleted(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return fromGetUsersRes(res), nil
+}
+
+// errRequired reports a missing request field
+func errRequired(field string) error {
+	return domainErr.Validation("invalid request", domainErr.FieldError{Field: field, Message: "is required"})
+}
@@ -0,0 +1,344 @@
+package grpcHandlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	authApp "github.com/Crud-application/pkg/application/auth"
+	"github.com/Crud-application/pkg/application/requestCtx"
+	"github.com/Crud-application/pkg/application/services/mocks"
+	"github.com/Crud-application/pkg/contracts/user"
+	"github.com/Crud-application/pkg/contracts/userv1"
+	"github.com/Crud-application/pkg/domain/authAgg"
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// newClient serves svc over an in-memory connection, behind the given interceptors, and returns a client of it
+func newClient(t *testing.T, svc *mocks.MockIUserService, interceptors ...grpc.UnaryServerInterceptor) userv1.UserServiceClient {
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(grpc.ChainUnaryInterceptor(append([]grpc.UnaryServerInterceptor{RequestID(), Recovery()}, interceptors...)...))
+	userv1.RegisterUserServiceServer(srv, NewUserServer(svc))
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return userv1.NewUserServiceClient(conn)
+}
+
+func TestUserServer(t *testing.T) {
+	at := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	got := &user.GetUserRes{ID: "u1", Name: "alam", Email: "a@b.com", PhoneNumber: "+919876543210", Version: 2, CreatedAt: at, UpdatedAt: at, CreatedBy: "alice", UpdatedBy: "bob"}
+	want := &userv1.User{Id: "u1", Name: "alam", Email: "a@b.com", PhoneNumber: "+919876543210", Version: 2,
+		CreateTime: timestamppb.New(at), UpdateTime: timestamppb.New(at), CreatedBy: "alice", UpdatedBy: "bob"}
+	name, email := "alam", "a@b.com"
+
+	tests := []struct {
+		id         int
+		name       string
+		beforeTest func(svc *mocks.MockIUserService)
+		call       func(ctx context.Context, c userv1.UserServiceClient) (proto.Message, error)
+		want       proto.Message
+		wantCode   codes.Code
+		wantFields []*errdetails.BadRequest_FieldViolation
+	}{
+		{
+			id:   1,
+			name: "Create user - success",
+			beforeTest: func(svc *mocks.MockIUserService) {
+				svc.EXPECT().CreateUser(gomock.Any(), &user.CreateUserReq{Name: "alam", Email: "a@b.com", PhoneNumber: "+919876543210"}).
+					Return(&user.CreateUserRes{ID: "u1", Name: "alam", Email: "a@b.com", PhoneNumber: "+919876543210", Version: 2, CreatedAt: at, UpdatedAt: at, CreatedBy: "alice", UpdatedBy: "bob"}, nil)
+			},
+			call: func(ctx context.Context, c userv1.UserServiceClient) (proto.Message, error) {
+				return c.CreateUser(ctx, &userv1.CreateUserRequest{Name: "alam", Email: "a@b.com", PhoneNumber: "+919876543210"})
+			},
+			want: want,
+		},
+		{
+			id:   2,
+			name: "Create invalid user - invalid argument with field violations",
+			beforeTest: func(svc *mocks.MockIUserService) {
+				svc.EXPECT().CreateUser(gomock.Any(), gomock.Any()).
+					Return(nil, domainErr.Validation("invalid user", domainErr.FieldError{Field: "email", Message: "must be a valid email address"}))
+			},
+			call: func(ctx context.Context, c userv1.UserServiceClient) (proto.Message, error) {
+				return c.CreateUser(ctx, &userv1.CreateUserRequest{Name: "alam", Email: "nope"})
+			},
+			wantCode:   codes.InvalidArgument,
+			wantFields: []*errdetails.BadRequest_FieldViolation{{Field: "email", Description: "must be a valid email address"}},
+		},
+		{
+			id:   3,
+			name: "Get missing user - not found",
+			beforeTest: func(svc *mocks.MockIUserService) {
+				svc.EXPECT().GetUser(gomock.Any(), "u9").Return(nil, domainErr.NotFound("user %s not found", "u9"))
+			},
+			call: func(ctx context.Context, c userv1.UserServiceClient) (proto.Message, error) {
+				return c.GetUser(ctx, &userv1.GetUserRequest{Id: "u9"})
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			id:         4,
+			name:       "Get without ID - invalid argument",
+			beforeTest: func(svc *mocks.MockIUserService) {},
+			call: func(ctx context.Context, c userv1.UserServiceClient) (proto.Message, error) {
+				return c.GetUser(ctx, &userv1.GetUserRequest{})
+			},
+			wantCode:   codes.InvalidArgument,
+			wantFields: []*errdetails.BadRequest_FieldViolation{{Field: "id", Description: "is required"}},
+		},
+		{
+			id:   5,
+			name: "Update masked fields at the expected version - success",
+			beforeTest: func(svc *mocks.MockIUserService) {
+				svc.EXPECT().UpdateUser(gomock.Any(), "u1", &user.UpdateUserReq{ID: "u1", Name: &name, IfMatch: []int64{1}}).
+					Return(&user.UpdateUserRes{ID: "u1", Name: "alam", Email: "a@b.com", PhoneNumber: "+919876543210", Version: 2, CreatedAt: at, UpdatedAt: at, CreatedBy: "alice", UpdatedBy: "bob"}, nil)
+			},
+			call: func(ctx context.Context, c userv1.UserServiceClient) (proto.Message, error) {
+				return c.UpdateUser(ctx, &userv1.UpdateUserRequest{
+					User:            &userv1.User{Id: "u1", Name: "alam", Email: "ignored@b.com"},
+					UpdateMask:      &fieldmaskpb.FieldMask{Paths: []string{"name"}},
+					ExpectedVersion: 1,
+				})
+			},
+			want: want,
+		},
+		{
+			id:   6,
+			name: "Update without mask sets the fields that are set - success",
+			beforeTest: func(svc *mocks.MockIUserService) {
+				svc.EXPECT().UpdateUser(gomock.Any(), "u1", &user.UpdateUserReq{ID: "u1", Name: &name, Email: &email}).
+					Return(&user.UpdateUserRes{ID: "u1", Name: "alam", Email: "a@b.com", PhoneNumber: "+919876543210", Version: 2, CreatedAt: at, UpdatedAt: at, CreatedBy: "alice", UpdatedBy: "bob"}, nil)
+			},
+			call: func(ctx context.Context, c userv1.UserServiceClient) (proto.Message, error) {
+				return c.UpdateUser(ctx, &userv1.UpdateUserRequest{User: &userv1.User{Id: "u1", Name: "alam", Email: "a@b.com"}})
+			},
+			want: want,
+		},
+		{
+			id:         7,
+			name:       "Update unknown mask path - invalid argument",
+			beforeTest: func(svc *mocks.MockIUserService) {},
+			call: func(ctx context.Context, c userv1.UserServiceClient) (proto.Message, error) {
+				return c.UpdateUser(ctx, &userv1.UpdateUserRequest{
+					User:       &userv1.User{Id: "u1"},
+					UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"version"}},
+				})
+			},
+			wantCode:   codes.InvalidArgument,
+			wantFields: []*errdetails.BadRequest_FieldViolation{{Field: "update_mask", Description: `has unknown path "version"`}},
+		},
+		{
+			id:   8,
+			name: "Update stale version - failed precondition",
+			beforeTest: func(svc *mocks.MockIUserService) {
+				svc.EXPECT().UpdateUser(gomock.Any(), "u1", gomock.Any()).Return(nil, domainErr.PreconditionFailed("user %s is at version %d", "u1", 3))
+			},
+			call: func(ctx context.Context, c userv1.UserServiceClient) (proto.Message, error) {
+				return c.UpdateUser(ctx, &userv1.UpdateUserRequest{User: &userv1.User{Id: "u1", Name: "alam"}, ExpectedVersion: 1})
+			},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			id:   9,
+			name: "Create user with a taken email - already exists",
+			beforeTest: func(svc *mocks.MockIUserService) {
+				svc.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Return(nil, domainErr.Conflict("email %s is already in use", "a@b.com"))
+			},
+			call: func(ctx context.Context, c userv1.UserServiceClient) (proto.Message, error) {
+				return c.CreateUser(ctx, &userv1.CreateUserRequest{Name: "alam", Email: "a@b.com", PhoneNumber: "+919876543210"})
+			},
+			wantCode: codes.AlreadyExists,
+		},
+		{
+			id:   10,
+			name: "List users - success",
+			beforeTest: func(svc *mocks.MockIUserService) {
+				svc.EXPECT().GetAllUsers(gomock.Any(), &user.GetUsersReq{
+					Limit: 1, Cursor: "c1", Search: "al", Sort: "-name",
+					Filters: map[string][]string{"name~": {"al"}}, IncludeDeleted: true,
+				}).Return(&user.GetUsersRes{Users: []user.GetUserRes{*got}, Total: 3, Limit: 1, NextCursor: "c2"}, nil)
+			},
+			call: func(ctx context.Context, c userv1.UserServiceClient) (proto.Message, error) {
+				return c.ListUsers(ctx, &userv1.ListUsersRequest{
+					PageSize: 1, PageToken: "c1", Search: "al", OrderBy: "-name",
+					Filters: map[string]string{"name~": "al"}, IncludeDeleted: true,
+				})
+			},
+			want: &userv1.ListUsersResponse{Users: []*userv1.User{want}, NextPageToken: "c2", TotalSize: 3},
+		},
+		{
+			id:   11,
+			name: "Unexpected failure - internal without details",
+			beforeTest: func(svc *mocks.MockIUserService) {
+				svc.EXPECT().GetUser(gomock.Any(), "u1").Return(nil, errors.New("socket closed"))
+			},
+			call: func(ctx context.Context, c userv1.UserServiceClient) (proto.Message, error) {
+				return c.GetUser(ctx, &userv1.GetUserRequest{Id: "u1"})
+			},
+			wantCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := mocks.NewMockIUserService(ctrl)
+			tt.beforeTest(svc)
+			client := newClient(t, svc)
+
+			res, err := tt.call(context.Background(), client)
+
+			st := status.Convert(err)
+			if st.Code() != tt.wantCode {
+				t.Fatalf("ID %v code = %v, want %v: %v", tt.id, st.Code(), tt.wantCode, err)
+			}
+			if tt.wantCode == codes.OK {
+				assert.True(t, proto.Equal(tt.want, res), "ID %v got %v, want %v", tt.id, res, tt.want)
+				return
+			}
+			if tt.wantCode == codes.Internal {
+				assert.Equal(t, "internal error", st.Message())
+			}
+			var fields []*errdetails.BadRequest_FieldViolation
+			for _, d := range st.Details() {
+				if br, ok := d.(*errdetails.BadRequest); ok {
+					fields = br.GetFieldViolations()
+				}
+			}
+			assert.Equal(t, len(tt.wantFields), len(fields), "ID %v field violations", tt.id)
+			for i := range tt.wantFields {
+				assert.True(t, proto.Equal(tt.wantFields[i], fields[i]), "ID %v got %v, want %v", tt.id, fields[i], tt.wantFields[i])
+			}
+		})
+	}
+}
+
+func TestCodeFromError(t *testing.T) {
+	tests := []struct {
+		id   int
+		name string
+		err  error
+		want codes.Code
+	}{
+		{id: 1, name: "Taken email - already exists", err: domainErr.Conflict("email %s is already in use", "a@b.com"), want: codes.AlreadyExists},
+		{id: 2, name: "Concurrent update - aborted", err: domainErr.StaleVersion("user %s was modified concurrently, version %d is stale", "u1", 1), want: codes.Aborted},
+		{id: 3, name: "Status transition not allowed - failed precondition", err: domainErr.InvalidState("user %s cannot go from %s to %s", "u1", "active", "pending"), want: codes.FailedPrecondition},
+		{id: 4, name: "Wrapped stale version - aborted", err: fmt.Errorf("failed to update user: %w", domainErr.StaleVersion("stale")), want: codes.Aborted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CodeFromError(tt.err); got != tt.want {
+				t.Errorf("ID %v CodeFromError() = %v, want %v", tt.id, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	svc := mocks.NewMockIUserService(ctrl)
+	svc.EXPECT().DeleteUser(gomock.Any(), "u1").DoAndReturn(func(ctx context.Context, _ string) error {
+		assert.Equal(t, "req-1", requestCtx.RequestID(ctx))
+		return nil
+	})
+	client := newClient(t, svc)
+
+	var header metadata.MD
+	ctx := metadata.AppendToOutgoingContext(context.Background(), RequestIDMetadata, "req-1")
+	_, err := client.DeleteUser(ctx, &userv1.DeleteUserRequest{Id: "u1"}, grpc.Header(&header))
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"req-1"}, header.Get(RequestIDMetadata))
+}
+
+func TestAuthenticate(t *testing.T) {
+	tests := []struct {
+		id         int
+		name       string
+		md         metadata.MD
+		public     []string
+		beforeTest func(auth *mocks.MockIAuthenticator, svc *mocks.MockIUserService)
+		wantCode   codes.Code
+	}{
+		{
+			id:   1,
+			name: "Valid bearer token - served as its principal",
+			md:   metadata.Pairs("authorization", "Bearer token"),
+			beforeTest: func(auth *mocks.MockIAuthenticator, svc *mocks.MockIUserService) {
+				auth.EXPECT().Authenticate(gomock.Any(), "Bearer token", "").Return(&authAgg.Principal{ID: "42", Kind: authAgg.KindUser}, nil)
+				svc.EXPECT().DeleteUser(gomock.Any(), "u1").DoAndReturn(func(ctx context.Context, _ string) error {
+					assert.Equal(t, "42", requestCtx.Actor(ctx))
+					return nil
+				})
+			},
+			wantCode: codes.OK,
+		},
+		{
+			id:   2,
+			name: "No credentials - Unauthenticated",
+			beforeTest: func(auth *mocks.MockIAuthenticator, svc *mocks.MockIUserService) {
+				auth.EXPECT().Authenticate(gomock.Any(), "", "").Return(nil, authApp.ErrNoCredentials)
+			},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			id:   3,
+			name: "Revoked API key - Unauthenticated",
+			md:   metadata.Pairs(APIKeyMetadata, "crud_key"),
+			beforeTest: func(auth *mocks.MockIAuthenticator, svc *mocks.MockIUserService) {
+				auth.EXPECT().Authenticate(gomock.Any(), "", "crud_key").Return(nil, domainErr.Unauthenticated("invalid API key"))
+			},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			id:     4,
+			name:   "No credentials on a public method - served anonymously",
+			public: []string{userv1.UserService_DeleteUser_FullMethodName},
+			beforeTest: func(auth *mocks.MockIAuthenticator, svc *mocks.MockIUserService) {
+				auth.EXPECT().Authenticate(gomock.Any(), "", "").Return(nil, authApp.ErrNoCredentials)
+				svc.EXPECT().DeleteUser(gomock.Any(), "u1").Return(nil)
+			},
+			wantCode: codes.OK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := mocks.NewMockIUserService(ctrl)
+			auth := mocks.NewMockIAuthenticator(ctrl)
+			tt.beforeTest(auth, svc)
+			client := newClient(t, svc, Authenticate(auth, tt.public))
+
+			ctx := metadata.NewOutgoingContext(context.Background(), tt.md)
+			_, err := client.DeleteUser(ctx, &userv1.DeleteUserRequest{Id: "u1"})
+
+			assert.Equal(t, tt.wantCode, status.Code(err), "ID %v: %v", tt.id, err)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"net/http"
+
+	aService "github.com/Crud-application/pkg/application/services"
+	aCOntr "github.com/Crud-application/pkg/contracts/auth"
+	uCOntr "github.com/Crud-application/pkg/contracts/user"
+	"github.com/gin-gonic/gin"
+)
+
+type AccountHandler struct {
+	accountSvc aService.IAccountService
+}
+
+func NewAccountHandler(accountService aService.IAccountService) *AccountHandler {
+	return &AccountHandler{
+		accountSvc: accountService,
+	}
+}
+
+// VerifyEmail marks an email verified with the token emailed to it
+func (ah *AccountHandler) VerifyEmail(c *gin.Context) {
+	var req uCOntr.VerifyEmailReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	if err := ah.accountSvc.VerifyEmail(c.Request.Context(), &req); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Email verified successfully"})
+}
+
+// ForgotPassword emails a password reset link. The response is the same whether or
+// not there is a user with the email.
+func (ah *AccountHandler) ForgotPassword(c *gin.Context) {
+	var req aCOntr.ForgotPasswordReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	if err := ah.accountSvc.ForgotPassword(c.Request.Context(), &req); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "If a user with this email exists, a password reset link has been sent to it"})
+}
+
+// ResetPassword sets a new password with the token of a password reset link
+func (ah *AccountHandler) ResetPassword(c *gin.Context) {
+	var req aCOntr.ResetPasswordReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	if err := ah.accountSvc.ResetPassword(c.Request.Context(), &req); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, gin.H{"message": "Password reset successfully"})
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"net/http"
+
+	aService "github.com/Crud-application/pkg/application/services"
+	aCOntr "github.com/Crud-application/pkg/contracts/auth"
+	"github.com/gin-gonic/gin"
+)
+
+type AuthHandler struct {
+	sessionSvc aService.ISessionService
+}
+
+func NewAuthHandler(sessionService aService.ISessionService) *AuthHandler {
+	return &AuthHandler{
+		sessionSvc: sessionService,
+	}
+}
+
+// Login exchanges an email and password for an access and a refresh token
+func (ah *AuthHandler) Login(c *gin.Context) {
+	var req aCOntr.LoginReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	res, err := ah.sessionSvc.Login(c.Request.Context(), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, res)
+}
+
+// Refresh exchanges a refresh token for a new access token and its replacement
+func (ah *AuthHandler) Refresh(c *gin.Context) {
+	var req aCOntr.RefreshReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	res, err := ah.sessionSvc.Refresh(c.Request.Context(), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, res)
+}
+
+// Logout revokes the session of a refresh token
+func (ah *AuthHandler) Logout(c *gin.Context) {
+	var req aCOntr.RefreshReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	if err := ah.sessionSvc.Logout(c.Request.Context(), &req); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
+}
+
+// ChangePassword replaces the password of the caller, given their current one
+func (ah *AuthHandler) ChangePassword(c *gin.Context) {
+	var req aCOntr.ChangePasswordReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	if err := ah.sessionSvc.ChangePassword(c.Request.Context(), &req); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Password changed successfully"})
+}
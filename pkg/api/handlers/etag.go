@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Conditional request headers
+const (
+	ETagHeader    = "ETag"
+	IfMatchHeader = "If-Match"
+)
+
+// etag renders a resource version as a strong entity tag, e.g. "3"
+func etag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// parseIfMatch returns the versions listed in an If-Match header. An absent
+// header or "*" matches any version and yields nil. ok is false when the header
+// lists only tags that can never match, such as weak or foreign ones.
+func parseIfMatch(header string) (versions []int64, ok bool) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return nil, true
+	}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		// If-Match uses the strong comparison, weak tags never match
+		if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+			continue
+		}
+		if v, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 64); err == nil {
+			versions = append(versions, v)
+		}
+	}
+	return versions, len(versions) > 0
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseIfMatch(t *testing.T) {
+	tests := []struct {
+		id     int
+		name   string
+		header string
+		want   []int64
+		wantOk bool
+	}{
+		{id: 1, name: "Absent header matches any version", header: "", want: nil, wantOk: true},
+		{id: 2, name: "Wildcard matches any version", header: "*", want: nil, wantOk: true},
+		{id: 3, name: "Single strong tag", header: etag(3), want: []int64{3}, wantOk: true},
+		{id: 4, name: "List of tags skipping weak ones", header: `"3", W/"4" , "5"`, want: []int64{3, 5}, wantOk: true},
+		{id: 5, name: "Only weak or foreign tags never match", header: `W/"3", "abc"`, want: nil, wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseIfMatch(tt.header)
+			if ok != tt.wantOk || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ID %v parseIfMatch(%q) = %v, %v want %v, %v", tt.id, tt.header, got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
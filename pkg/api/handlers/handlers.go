@@ -1,11 +1,23 @@
 package handlers
 
+import gqlH "github.com/Crud-application/pkg/api/graphqlHandlers"
+
 type Handlers struct {
-	UserHandler *UserHandler
+	UserHandler       *UserHandler
+	UserStreamHandler *UserStreamHandler
+	WebhookHandler    *WebhookHandler
+	AuthHandler       *AuthHandler
+	AccountHandler    *AccountHandler
+	GraphQLHandler    *gqlH.GraphQLHandler
 }
 
-func NewHandlers(uh *UserHandler) *Handlers {
+func NewHandlers(uh *UserHandler, ush *UserStreamHandler, wh *WebhookHandler, ah *AuthHandler, ach *AccountHandler, gqh *gqlH.GraphQLHandler) *Handlers {
 	return &Handlers{
-		UserHandler: uh,
+		UserHandler:       uh,
+		UserStreamHandler: ush,
+		WebhookHandler:    wh,
+		AuthHandler:       ah,
+		AccountHandler:    ach,
+		GraphQLHandler:    gqh,
 	}
 }
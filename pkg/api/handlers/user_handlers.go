@@ -1,18 +1,23 @@
 package handlers
 
 import (
-	// "github.com/gin-gonic/gin"
-
-	"fmt"
+	"encoding/json"
+	"errors"
 	"net/http"
-	"strings"
 
 	uService "github.com/Crud-application/pkg/application/services"
+	"github.com/Crud-application/pkg/contracts/patch"
 	"github.com/Crud-application/pkg/contracts/user"
+	"github.com/Crud-application/pkg/domain/domainErr"
+	uAgg "github.com/Crud-application/pkg/domain/userAgg"
 	"github.com/gin-gonic/gin"
 )
 
-// var _ = eContr.ErrorRes{}
+// errUserIDRequired is reported when the userID path parameter is missing
+var errUserIDRequired = errors.New("user ID is required")
+
+// errPatchInvalid is reported when a patch document is not JSON
+var errPatchInvalid = errors.New("patch document is not valid JSON")
 
 type UserHandler struct {
 	userSvc uService.IUserService
@@ -30,24 +35,18 @@ func (uh *UserHandler) CreateUser(c *gin.Context) {
 	var newUser user.CreateUserReq
 
 	// Parse JSON body
-	if err := c.BindJSON(&newUser); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid input",
-			"details": err.Error(), // Include the error message
-		})
+	if err := c.ShouldBindJSON(&newUser); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 
-	fmt.Println("newUser", newUser)
-	//update email to lowercase
-	newUser.Email = strings.ToLower(newUser.Email)
-
 	user, err := uh.userSvc.CreateUser(c.Request.Context(), &newUser)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user"})
+		_ = c.Error(err)
 		return
 	}
 
+	c.Header(ETagHeader, etag(user.Version))
 	c.JSON(http.StatusCreated, user)
 }
 
@@ -58,96 +57,213 @@ func (uh *UserHandler) DeleteUser(c *gin.Context) {
 	userID := c.Param("userID")
 	//fmt.Println("userID", userID)
 	if userID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID is required"})
+		_ = c.Error(errUserIDRequired).SetType(gin.ErrorTypeBind)
 		return
 	}
 
 	// Pass the user ID to the service layer for deletion
 	err := uh.userSvc.DeleteUser(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete user"})
+		_ = c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "User deleted successfully"})
 }
 
+// RestoreUser undoes the deletion of a user by ID
+func (uh *UserHandler) RestoreUser(c *gin.Context) {
+	userID := c.Param("userID")
+	if userID == "" {
+		_ = c.Error(errUserIDRequired).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	user, err := uh.userSvc.RestoreUser(c.Request.Context(), userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.Header(ETagHeader, etag(user.Version))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "User restored successfully",
+		"user":    user,
+	})
+}
+
+// SuspendUser blocks a user for the reason in the body
+func (uh *UserHandler) SuspendUser(c *gin.Context) {
+	uh.changeUserStatus(c, uAgg.StatusSuspended, "User suspended successfully")
+}
+
+// ActivateUser lets a pending, suspended or deactivated user back in
+func (uh *UserHandler) ActivateUser(c *gin.Context) {
+	uh.changeUserStatus(c, uAgg.StatusActive, "User activated successfully")
+}
+
+// DeactivateUser closes the account of a user for the reason in the body
+func (uh *UserHandler) DeactivateUser(c *gin.Context) {
+	uh.changeUserStatus(c, uAgg.StatusDeactivated, "User deactivated successfully")
+}
+
+// changeUserStatus moves the user of the request to status and responds with message
+func (uh *UserHandler) changeUserStatus(c *gin.Context, status uAgg.Status, message string) {
+	userID := c.Param("userID")
+	if userID == "" {
+		_ = c.Error(errUserIDRequired).SetType(gin.ErrorTypeBind)
+		return
+	}
+	var req user.ChangeUserStatusReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+	req.Status = string(status)
+
+	res, err := uh.userSvc.ChangeUserStatus(c.Request.Context(), userID, &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.Header(ETagHeader, etag(res.Version))
+	c.JSON(http.StatusOK, gin.H{
+		"message": message,
+		"user":    res,
+	})
+}
+
 // GetUser retrieves a user by ID
 func (uh *UserHandler) GetUser(c *gin.Context) {
 	userID := c.Param("userID")
 	if userID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID is required"})
+		_ = c.Error(errUserIDRequired).SetType(gin.ErrorTypeBind)
 		return
 	}
 
 	user, err := uh.userSvc.GetUser(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "No user found with the given ID"})
+		_ = c.Error(err)
 		return
 	}
+	c.Header(ETagHeader, etag(user.Version))
 	c.JSON(http.StatusOK, gin.H{
 		"message": "User retrieved successfully",
 		"user":    user,
 	})
 }
 
-// GetUsers retrieves a list of all users
+// GetUsers retrieves one page of users matching the filters, search and sort in the query
 func (uh *UserHandler) GetUsers(c *gin.Context) {
-	users, err := uh.userSvc.GetAllUsers(c)
+	var req user.GetUsersReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+	// Every other query parameter is a filter such as name~=al or created_at>=2024-05-01
+	req.Filters = c.Request.URL.Query()
+	for _, p := range user.ListParams {
+		delete(req.Filters, p)
+	}
+
+	res, err := uh.userSvc.GetAllUsers(c.Request.Context(), &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve users"})
+		_ = c.Error(err)
 		return
 	}
 
-	mapUsers := make([]map[string]interface{}, 0)
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Users retrieved successfully",
+		"users":       res.Users,
+		"total":       res.Total,
+		"limit":       res.Limit,
+		"offset":      res.Offset,
+		"next_cursor": res.NextCursor,
+	})
+}
 
-	for _, user := range users {
-		mapUsers = append(mapUsers, map[string]interface{}{
-			"id":          user.ID,
-			"name":        user.Name,
-			"email":       user.Email,
-			"phoneNumber": user.PhoneNumber,
-		})
+// GetUserHistory retrieves one page of the recorded changes of a user, newest first
+func (uh *UserHandler) GetUserHistory(c *gin.Context) {
+	userID := c.Param("userID")
+	if userID == "" {
+		_ = c.Error(errUserIDRequired).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	var req user.GetUserHistoryReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	res, err := uh.userSvc.GetUserHistory(c.Request.Context(), userID, &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
 	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Users retrieved successfully",
-		"users":   mapUsers,
+		"message":     "User history retrieved successfully",
+		"entries":     res.Entries,
+		"total":       res.Total,
+		"limit":       res.Limit,
+		"offset":      res.Offset,
+		"next_cursor": res.NextCursor,
 	})
 }
 
-// PatchUser updates a user's details by ID
+// UpdateUser updates a user's details by ID. JSON bodies set the fields they contain;
+// merge patches and JSON Patches are applied to the user document.
 func (uh *UserHandler) UpdateUser(c *gin.Context) {
 	// Get the user ID from the URL parameters
 	userID := c.Param("userID")
-	fmt.Printf("userID: %s\n", userID)
 	if userID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID is required"})
+		_ = c.Error(errUserIDRequired).SetType(gin.ErrorTypeBind)
 		return
 	}
 
-	// Parse the JSON request body into UpdateUserReq
-	var req user.UpdateUserReq
-	if err := c.BindJSON(&req); err != nil {
-		fmt.Printf("Error binding JSON: %v\n", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invaliddd request body"})
+	// Only update the version the client last saw, when it says which one that is
+	ifMatch, ok := parseIfMatch(c.GetHeader(IfMatchHeader))
+	if !ok {
+		_ = c.Error(domainErr.PreconditionFailed("If-Match does not match any version of user %s", userID))
 		return
 	}
 
-	// Ensure the ID in the request body matches the ID in the URL
-	req.ID = userID
+	var updatedUser *user.UpdateUserRes
+	var err error
+	switch contentType := c.ContentType(); contentType {
+	case patch.MergePatchContentType, patch.JSONPatchContentType:
+		body, readErr := c.GetRawData()
+		if readErr != nil || !json.Valid(body) {
+			_ = c.Error(errPatchInvalid).SetType(gin.ErrorTypeBind)
+			return
+		}
+		updatedUser, err = uh.userSvc.PatchUser(c.Request.Context(), userID, &user.PatchUserReq{
+			ContentType: contentType,
+			Patch:       body,
+			IfMatch:     ifMatch,
+		})
+	default:
+		// Parse the JSON request body into UpdateUserReq
+		var req user.UpdateUserReq
+		if err := c.ShouldBindJSON(&req); err != nil {
+			_ = c.Error(err).SetType(gin.ErrorTypeBind)
+			return
+		}
 
-	// Call the service layer to update the user
-	updatedUser, err := uh.userSvc.UpdateUser(c.Request.Context(), userID, &req)
+		// Ensure the ID in the request body matches the ID in the URL
+		req.ID = userID
+		req.IfMatch = ifMatch
+
+		// Call the service layer to update the user
+		updatedUser, err = uh.userSvc.UpdateUser(c.Request.Context(), userID, &req)
+	}
 	if err != nil {
-		if err.Error() == "user not found" {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user"})
-		}
+		_ = c.Error(err)
 		return
 	}
 
 	// Respond with the updated user details
+	c.Header(ETagHeader, etag(updatedUser.Version))
 	c.JSON(http.StatusOK, gin.H{
 		"message": "User updated successfully",
 		"user":    updatedUser,
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	eCOntr "github.com/Crud-application/pkg/contracts/event"
+	"github.com/Crud-application/pkg/domain/domainEvent"
+	"github.com/gin-gonic/gin"
+)
+
+// LastEventIDHeader is sent by reconnecting EventSource clients with the ID of the last event they received
+const LastEventIDHeader = "Last-Event-ID"
+
+// streamRetry tells clients how long to wait before reconnecting, in milliseconds
+const streamRetry = 3000
+
+type UserStreamHandler struct {
+	stream    domainEvent.Stream
+	heartbeat time.Duration
+}
+
+func NewUserStreamHandler(stream domainEvent.Stream, heartbeat time.Duration) *UserStreamHandler {
+	return &UserStreamHandler{
+		stream:    stream,
+		heartbeat: heartbeat,
+	}
+}
+
+// StreamUsers pushes user events as Server-Sent Events until the client disconnects.
+// Clients resume with the Last-Event-ID header, or the last_event_id query parameter
+// where they cannot set headers. A "reset" event tells them that events were missed
+// and the users should be fetched again.
+func (sh *UserStreamHandler) StreamUsers(c *gin.Context) {
+	lastEventID := c.GetHeader(LastEventIDHeader)
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	sub, resumed := sh.stream.Subscribe(lastEventID)
+	defer sub.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Keep reverse proxies from buffering the stream
+	c.Status(http.StatusOK)
+
+	fmt.Fprintf(c.Writer, "retry: %d\n\n", streamRetry)
+	if !resumed {
+		fmt.Fprint(c.Writer, "event: reset\ndata: {}\n\n")
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(sh.heartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case e, ok := <-sub.Events:
+			if !ok {
+				// Dropped for falling behind; the client reconnects and resumes
+				return
+			}
+			if err := writeEvent(c.Writer, e); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			// A comment keeps idle connections from being closed by proxies
+			if _, err := fmt.Fprint(c.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+		c.Writer.Flush()
+	}
+}
+
+// writeEvent writes e in the text/event-stream format
+func writeEvent(w io.Writer, e domainEvent.Event) error {
+	data, err := json.Marshal(eCOntr.Event{
+		ID:          e.ID,
+		Type:        string(e.Type),
+		AggregateID: e.AggregateID,
+		Actor:       e.Actor,
+		OccurredAt:  e.OccurredAt,
+		Payload:     e.Payload,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+	return err
+}
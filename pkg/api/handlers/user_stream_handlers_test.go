@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Crud-application/pkg/domain/domainEvent"
+	"github.com/gin-gonic/gin"
+)
+
+// fakeStream hands out one subscription whose events are already queued
+type fakeStream struct {
+	events      []domainEvent.Event
+	resumed     bool
+	lastEventID string // What the handler subscribed with
+}
+
+func (s *fakeStream) Subscribe(lastEventID string) (*domainEvent.Subscription, bool) {
+	s.lastEventID = lastEventID
+	ch := make(chan domainEvent.Event, len(s.events))
+	for _, e := range s.events {
+		ch <- e
+	}
+	close(ch) // Ends the stream once the events are written
+	return &domainEvent.Subscription{Events: ch, Close: func() {}}, s.resumed
+}
+
+func TestUserStreamHandler_StreamUsers(t *testing.T) {
+	at := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	created := domainEvent.Event{ID: "e2", Type: "user.created", AggregateID: "u1", Actor: "alice", OccurredAt: at, Payload: map[string]any{"name": "alam"}}
+
+	tests := []struct {
+		id              int
+		name            string
+		header          string
+		target          string
+		resumed         bool
+		wantLastEventID string
+		wantBody        string
+	}{
+		{
+			id:              1,
+			name:            "Resume from the Last-Event-ID header",
+			header:          "e1",
+			target:          "/users/stream",
+			resumed:         true,
+			wantLastEventID: "e1",
+			wantBody: "retry: 3000\n\n" +
+				"id: e2\nevent: user.created\n" +
+				`data: {"id":"e2","type":"user.created","aggregate_id":"u1","actor":"alice","occurred_at":"2024-05-01T10:00:00Z","payload":{"name":"alam"}}` + "\n\n",
+		},
+		{
+			id:              2,
+			name:            "Missed events reset the client",
+			target:          "/users/stream?last_event_id=e0",
+			resumed:         false,
+			wantLastEventID: "e0",
+			wantBody:        "retry: 3000\n\nevent: reset\ndata: {}\n\nid: e2\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream := &fakeStream{events: []domainEvent.Event{created}, resumed: tt.resumed}
+			engine := gin.New()
+			engine.GET("/users/stream", NewUserStreamHandler(stream, time.Minute).StreamUsers)
+
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.header != "" {
+				req.Header.Set(LastEventIDHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			engine.ServeHTTP(rec, req)
+
+			if stream.lastEventID != tt.wantLastEventID {
+				t.Errorf("ID %v subscribed after %q, want %q", tt.id, stream.lastEventID, tt.wantLastEventID)
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != "text/event-stream" {
+				t.Errorf("ID %v Content-Type = %q, want text/event-stream", tt.id, ct)
+			}
+			if body := rec.Body.String(); !strings.HasPrefix(body, tt.wantBody) {
+				t.Errorf("ID %v body = %q, want it to start with %q", tt.id, body, tt.wantBody)
+			}
+		})
+	}
+}
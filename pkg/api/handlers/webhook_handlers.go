@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	wService "github.com/Crud-application/pkg/application/services"
+	"github.com/Crud-application/pkg/contracts/webhook"
+	"github.com/gin-gonic/gin"
+)
+
+// errWebhookIDRequired is reported when the webhookID path parameter is missing
+var errWebhookIDRequired = errors.New("webhook ID is required")
+
+type WebhookHandler struct {
+	webhookSvc wService.IWebhookService
+}
+
+func NewWebhookHandler(webhookService wService.IWebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookSvc: webhookService,
+	}
+}
+
+// CreateWebhook subscribes a URL to user events
+func (wh *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var req webhook.CreateWebhookReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	res, err := wh.webhookSvc.CreateWebhook(c.Request.Context(), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, res)
+}
+
+// GetWebhooks retrieves one page of webhooks
+func (wh *WebhookHandler) GetWebhooks(c *gin.Context) {
+	var req webhook.GetWebhooksReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	res, err := wh.webhookSvc.GetWebhooks(c.Request.Context(), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Webhooks retrieved successfully",
+		"webhooks":    res.Webhooks,
+		"total":       res.Total,
+		"limit":       res.Limit,
+		"offset":      res.Offset,
+		"next_cursor": res.NextCursor,
+	})
+}
+
+// GetWebhook retrieves a webhook by ID
+func (wh *WebhookHandler) GetWebhook(c *gin.Context) {
+	webhookID := c.Param("webhookID")
+	if webhookID == "" {
+		_ = c.Error(errWebhookIDRequired).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	res, err := wh.webhookSvc.GetWebhook(c.Request.Context(), webhookID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Webhook retrieved successfully",
+		"webhook": res,
+	})
+}
+
+// UpdateWebhook changes the URL, secret, event types or activation of a webhook
+func (wh *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	webhookID := c.Param("webhookID")
+	if webhookID == "" {
+		_ = c.Error(errWebhookIDRequired).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	var req webhook.UpdateWebhookReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	res, err := wh.webhookSvc.UpdateWebhook(c.Request.Context(), webhookID, &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Webhook updated successfully",
+		"webhook": res,
+	})
+}
+
+// DeleteWebhook deletes a webhook and its delivery log
+func (wh *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	webhookID := c.Param("webhookID")
+	if webhookID == "" {
+		_ = c.Error(errWebhookIDRequired).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	if err := wh.webhookSvc.DeleteWebhook(c.Request.Context(), webhookID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook deleted successfully"})
+}
+
+// GetDeliveries retrieves one page of the deliveries of a webhook, newest first
+func (wh *WebhookHandler) GetDeliveries(c *gin.Context) {
+	webhookID := c.Param("webhookID")
+	if webhookID == "" {
+		_ = c.Error(errWebhookIDRequired).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	var req webhook.GetDeliveriesReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	res, err := wh.webhookSvc.GetDeliveries(c.Request.Context(), webhookID, &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Webhook deliveries retrieved successfully",
+		"deliveries":  res.Deliveries,
+		"total":       res.Total,
+		"limit":       res.Limit,
+		"offset":      res.Offset,
+		"next_cursor": res.NextCursor,
+	})
+}
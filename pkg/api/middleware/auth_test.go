@@ -0,0 +1,164 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	authApp "github.com/Crud-application/pkg/application/auth"
+	"github.com/Crud-application/pkg/application/requestCtx"
+	mockSvc "github.com/Crud-application/pkg/application/services/mocks"
+	"github.com/Crud-application/pkg/domain/authAgg"
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthenticate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	principal := &authAgg.Principal{ID: "k1", Kind: authAgg.KindAPIKey}
+
+	tests := []struct {
+		id            int
+		name          string
+		target        string
+		header        http.Header
+		beforeTest    func(auth *mockSvc.MockIAuthenticator)
+		wantStatus    int
+		wantActor     string
+		wantChallenge bool
+	}{
+		{
+			id:     1,
+			name:   "Valid API key - served as its principal",
+			target: "/api/users",
+			header: http.Header{APIKeyHeader: {"crud_key"}},
+			beforeTest: func(auth *mockSvc.MockIAuthenticator) {
+				auth.EXPECT().Authenticate(gomock.Any(), "", "crud_key").Return(principal, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantActor:  "api_key:k1",
+		},
+		{
+			id:     2,
+			name:   "No credentials on a protected route - 401 with a challenge",
+			target: "/api/users",
+			beforeTest: func(auth *mockSvc.MockIAuthenticator) {
+				auth.EXPECT().Authenticate(gomock.Any(), "", "").Return(nil, authApp.ErrNoCredentials)
+			},
+			wantStatus:    http.StatusUnauthorized,
+			wantChallenge: true,
+		},
+		{
+			id:     3,
+			name:   "No credentials on a public route - served anonymously",
+			target: "/health",
+			beforeTest: func(auth *mockSvc.MockIAuthenticator) {
+				auth.EXPECT().Authenticate(gomock.Any(), "", "").Return(nil, authApp.ErrNoCredentials)
+			},
+			wantStatus: http.StatusOK,
+			wantActor:  requestCtx.AnonymousActor,
+		},
+		{
+			id:     4,
+			name:   "Invalid credentials on a public route - 401",
+			target: "/health",
+			header: http.Header{"Authorization": {"Bearer expired"}},
+			beforeTest: func(auth *mockSvc.MockIAuthenticator) {
+				auth.EXPECT().Authenticate(gomock.Any(), "Bearer expired", "").Return(nil, domainErr.Unauthenticated("invalid bearer token"))
+			},
+			wantStatus:    http.StatusUnauthorized,
+			wantChallenge: true,
+		},
+		{
+			id:     5,
+			name:   "Unknown route without credentials - 404",
+			target: "/nowhere",
+			beforeTest: func(auth *mockSvc.MockIAuthenticator) {
+				auth.EXPECT().Authenticate(gomock.Any(), "", "").Return(nil, authApp.ErrNoCredentials)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			id:     6,
+			name:   "Key store unavailable - 503",
+			target: "/api/users",
+			header: http.Header{APIKeyHeader: {"crud_key"}},
+			beforeTest: func(auth *mockSvc.MockIAuthenticator) {
+				auth.EXPECT().Authenticate(gomock.Any(), "", "crud_key").Return(nil, domainErr.Unavailable(errors.New("dial tcp"), "API key store unavailable"))
+			},
+			wantStatus: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			auth := mockSvc.NewMockIAuthenticator(ctrl)
+			auth.EXPECT().Schemes().Return([]string{"Bearer", "ApiKey"}).AnyTimes()
+			tt.beforeTest(auth)
+
+			var actor string
+			handler := func(c *gin.Context) {
+				actor = requestCtx.Actor(c.Request.Context())
+				c.Status(http.StatusOK)
+			}
+			engine := gin.New()
+			engine.Use(Problems(), Authenticate(auth, []string{"GET /health"}))
+			engine.NoRoute(NoRoute)
+			engine.GET("/health", handler)
+			engine.GET("/api/users", handler)
+
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			for k, v := range tt.header {
+				req.Header.Set(k, v[0])
+			}
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code, "id %d: %s", tt.id, w.Body.String())
+			assert.Equal(t, tt.wantActor, actor)
+			if tt.wantChallenge {
+				assert.Equal(t, []string{`Bearer realm="api"`, `ApiKey realm="api"`}, w.Header().Values("WWW-Authenticate"))
+			} else {
+				assert.Empty(t, w.Header().Values("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		id         int
+		name       string
+		principal  *authAgg.Principal
+		wantStatus int
+	}{
+		{id: 1, name: "Support - served", principal: &authAgg.Principal{ID: "k1", Kind: authAgg.KindAPIKey, Roles: []string{authAgg.RoleSupport}}, wantStatus: http.StatusOK},
+		{id: 2, name: "User without a role - 403", principal: &authAgg.Principal{ID: "u1", Kind: authAgg.KindUser}, wantStatus: http.StatusForbidden},
+		{id: 3, name: "Anonymous - 401", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := gin.New()
+			engine.Use(Problems(), func(c *gin.Context) {
+				if tt.principal != nil {
+					c.Request = c.Request.WithContext(requestCtx.WithPrincipal(c.Request.Context(), tt.principal))
+				}
+			})
+			engine.GET("/api/users/stream", Authorize(authAgg.DefaultPolicy(), authAgg.ActionWatchUsers), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/users/stream", nil))
+			assert.Equal(t, tt.wantStatus, w.Code, "id %d: %s", tt.id, w.Body.String())
+		})
+	}
+}
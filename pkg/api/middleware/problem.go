@@ -0,0 +1,177 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/Crud-application/pkg/contracts/problem"
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// problemTypes maps status codes to the problem type URI and title
+var problemTypes = map[int]struct{ slug, title string }{
+	http.StatusBadRequest:          {"bad-request", "Bad Request"},
+	http.StatusUnauthorized:        {"unauthorized", "Unauthorized"},
+	http.StatusForbidden:           {"forbidden", "Forbidden"},
+	http.StatusNotFound:            {"not-found", "Not Found"},
+	http.StatusMethodNotAllowed:    {"method-not-allowed", "Method Not Allowed"},
+	http.StatusConflict:            {"conflict", "Conflict"},
+	http.StatusPreconditionFailed:  {"precondition-failed", "Precondition Failed"},
+	http.StatusUnprocessableEntity: {"validation-error", "Validation Failed"},
+	http.StatusInternalServerError: {"internal-error", "Internal Server Error"},
+	http.StatusServiceUnavailable:  {"service-unavailable", "Service Unavailable"},
+}
+
+func init() {
+	// Report validation errors with JSON field names instead of Go struct field names
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(f reflect.StructField) string {
+			name := strings.SplitN(f.Tag.Get("json"), ",", 2)[0]
+			if name == "-" {
+				return ""
+			}
+			return name
+		})
+	}
+}
+
+// Problems renders the last error attached to the gin context as an
+// application/problem+json response, unless the handler already wrote one.
+// Handlers report failures with c.Error; binding failures use gin.ErrorTypeBind.
+func Problems() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		WriteProblem(c, FromError(c, c.Errors.Last()))
+	}
+}
+
+// Recovery turns panics into a 500 problem response
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecovery(func(c *gin.Context, recovered any) {
+		log.Printf("Recovered from panic: %v", recovered)
+		WriteProblem(c, New(c, http.StatusInternalServerError, ""))
+	})
+}
+
+// NoRoute answers unknown routes with a 404 problem response
+func NoRoute(c *gin.Context) {
+	WriteProblem(c, New(c, http.StatusNotFound, fmt.Sprintf("no route for %s %s", c.Request.Method, c.Request.URL.Path)))
+}
+
+// ProblemType returns the type URI and title of problems with the given status code
+func ProblemType(status int) (typ, title string) {
+	pt, ok := problemTypes[status]
+	if !ok {
+		return "about:blank", http.StatusText(status)
+	}
+	return "/problems/" + pt.slug, pt.title
+}
+
+// New builds a problem for the given status code and request
+func New(c *gin.Context, status int, detail string) *problem.Problem {
+	typ, title := ProblemType(status)
+	return &problem.Problem{
+		Type:      typ,
+		Title:     title,
+		Status:    status,
+		Detail:    detail,
+		Instance:  c.Request.URL.Path,
+		RequestID: c.GetString(RequestIDKey),
+	}
+}
+
+// FromError builds the problem describing err
+func FromError(c *gin.Context, err *gin.Error) *problem.Problem {
+	if err.IsType(gin.ErrorTypeBind) {
+		return fromBindError(c, err.Err)
+	}
+
+	status := StatusFromError(err.Err)
+	if status >= http.StatusInternalServerError {
+		log.Printf("Request %s failed: %v", c.GetString(RequestIDKey), err.Err)
+		if status == http.StatusInternalServerError {
+			return New(c, status, "")
+		}
+	}
+
+	p := New(c, status, err.Error())
+	for _, f := range domainErr.FieldErrors(err.Err) {
+		p.Errors = append(p.Errors, problem.FieldError{Field: f.Field, Message: f.Message})
+	}
+	return p
+}
+
+// StatusFromError maps domain errors to HTTP status codes
+func StatusFromError(err error) int {
+	switch {
+	case errors.Is(err, domainErr.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domainErr.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, domainErr.ErrValidation):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, domainErr.ErrPreconditionFailed):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, domainErr.ErrUnavailable):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, domainErr.ErrUnauthenticated):
+		return http.StatusUnauthorized
+	case errors.Is(err, domainErr.ErrForbidden):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// fromBindError describes a malformed request, listing invalid fields when known
+func fromBindError(c *gin.Context, err error) *problem.Problem {
+	var validationErrs validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+
+	switch {
+	case errors.As(err, &validationErrs):
+		p := New(c, http.StatusUnprocessableEntity, "request body failed validation")
+		for _, fe := range validationErrs {
+			p.Errors = append(p.Errors, problem.FieldError{Field: fe.Field(), Message: validationMessage(fe)})
+		}
+		return p
+	case errors.As(err, &typeErr):
+		p := New(c, http.StatusBadRequest, "request body has a field of the wrong type")
+		p.Errors = []problem.FieldError{{Field: typeErr.Field, Message: "must be a " + typeErr.Type.String()}}
+		return p
+	default:
+		return New(c, http.StatusBadRequest, err.Error())
+	}
+}
+
+// validationMessage renders a validator failure as a human readable message
+func validationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "numeric":
+		return "must be numeric"
+	default:
+		return fmt.Sprintf("failed the %q check", fe.Tag())
+	}
+}
+
+// WriteProblem writes p as an application/problem+json response and aborts the chain
+func WriteProblem(c *gin.Context, p *problem.Problem) {
+	c.Header("Content-Type", problem.ContentType)
+	c.AbortWithStatusJSON(p.Status, p)
+}
@@ -0,0 +1,170 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Crud-application/pkg/contracts/problem"
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusFromError(t *testing.T) {
+	tests := []struct {
+		id   int
+		name string
+		err  error
+		want int
+	}{
+		{
+			id:   1,
+			name: "Wrapped not found - 404",
+			err:  fmt.Errorf("failed to get user: %w", domainErr.NotFound("user %s not found", "42")),
+			want: http.StatusNotFound,
+		},
+		{
+			id:   2,
+			name: "Conflict - 409",
+			err:  domainErr.Conflict("user %s already exists", "42"),
+			want: http.StatusConflict,
+		},
+		{
+			id:   3,
+			name: "Validation - 422",
+			err:  domainErr.Validation("invalid user", domainErr.FieldError{Field: "email", Message: "is invalid"}),
+			want: http.StatusUnprocessableEntity,
+		},
+		{
+			id:   4,
+			name: "Unavailable - 503",
+			err:  fmt.Errorf("failed to update user: %w", domainErr.Unavailable(errors.New("dial tcp"), "user store unavailable")),
+			want: http.StatusServiceUnavailable,
+		},
+		{
+			id:   5,
+			name: "Unknown error - 500",
+			err:  errors.New("boom"),
+			want: http.StatusInternalServerError,
+		},
+		{
+			id:   6,
+			name: "Precondition failed - 412",
+			err:  fmt.Errorf("failed to update user: %w", domainErr.PreconditionFailed("user %s has changed", "42")),
+			want: http.StatusPreconditionFailed,
+		},
+		{
+			id:   7,
+			name: "Unauthenticated - 401",
+			err:  domainErr.Unauthenticated("invalid API key"),
+			want: http.StatusUnauthorized,
+		},
+		{
+			id:   8,
+			name: "Forbidden - 403",
+			err:  domainErr.Forbidden("only admins may delete users"),
+			want: http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StatusFromError(tt.err); got != tt.want {
+				t.Errorf("ID %v StatusFromError() = %v, want %v", tt.id, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProblems(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	type body struct {
+		Name  string `json:"name" binding:"required"`
+		Email string `json:"email" binding:"required,email"`
+	}
+
+	tests := []struct {
+		id      int
+		name    string
+		handler gin.HandlerFunc
+		reqBody string
+		want    problem.Problem
+	}{
+		{
+			id:   1,
+			name: "Domain error - not found problem",
+			handler: func(c *gin.Context) {
+				_ = c.Error(fmt.Errorf("failed to get user: %w", domainErr.NotFound("user %s not found", "42")))
+			},
+			want: problem.Problem{
+				Type:      "/problems/not-found",
+				Title:     "Not Found",
+				Status:    http.StatusNotFound,
+				Detail:    "failed to get user: user 42 not found",
+				Instance:  "/test",
+				RequestID: "req-1",
+			},
+		},
+		{
+			id:   2,
+			name: "Binding error - per-field validation problem",
+			handler: func(c *gin.Context) {
+				var b body
+				if err := c.ShouldBindJSON(&b); err != nil {
+					_ = c.Error(err).SetType(gin.ErrorTypeBind)
+				}
+			},
+			reqBody: `{"email": "not-an-email"}`,
+			want: problem.Problem{
+				Type:      "/problems/validation-error",
+				Title:     "Validation Failed",
+				Status:    http.StatusUnprocessableEntity,
+				Detail:    "request body failed validation",
+				Instance:  "/test",
+				RequestID: "req-1",
+				Errors: []problem.FieldError{
+					{Field: "name", Message: "is required"},
+					{Field: "email", Message: "must be a valid email address"},
+				},
+			},
+		},
+		{
+			id:   3,
+			name: "Unexpected error - detail hidden",
+			handler: func(c *gin.Context) {
+				_ = c.Error(errors.New("connection string leaked"))
+			},
+			want: problem.Problem{
+				Type:      "/problems/internal-error",
+				Title:     "Internal Server Error",
+				Status:    http.StatusInternalServerError,
+				Instance:  "/test",
+				RequestID: "req-1",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := gin.New()
+			engine.Use(RequestID(), Problems())
+			engine.POST("/test", tt.handler)
+
+			req := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(tt.reqBody))
+			req.Header.Set(RequestIDHeader, "req-1")
+			rec := httptest.NewRecorder()
+			engine.ServeHTTP(rec, req)
+
+			assert.Equal(t, problem.ContentType, rec.Header().Get("Content-Type"), "ID %v", tt.id)
+			var got problem.Problem
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("ID %v invalid problem body: %v", tt.id, err)
+			}
+			assert.Equal(t, tt.want, got, "ID %v", tt.id)
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"github.com/Crud-application/pkg/application/requestCtx"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// RequestIDHeader carries the request ID in requests and responses
+	RequestIDHeader = "X-Request-ID"
+	// RequestIDKey is the gin context key holding the request ID
+	RequestIDKey = "requestID"
+)
+
+// RequestID reuses the caller's X-Request-ID or generates a new one,
+// and echoes it back in the response. The ID is also put in the request context for the services.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" {
+			requestID = uuid.New().String()
+		}
+		c.Set(RequestIDKey, requestID)
+		c.Request = c.Request.WithContext(requestCtx.WithRequestID(c.Request.Context(), requestID))
+		c.Header(RequestIDHeader, requestID)
+		c.Next()
+	}
+}
@@ -0,0 +1,83 @@
+package openapi
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+)
+
+// ContractDirs are the packages, relative to pkg/, whose types appear in the document
+var ContractDirs = []string{"contracts/user", "contracts/webhook", "contracts/problem", "contracts/event", "contracts/patch", "contracts/auth"}
+
+// Docs holds the doc comments of contract types by type name, and of their
+// fields by "Type.Field". Reflection cannot see comments, so they are parsed from source.
+type Docs map[string]string
+
+// ParseDocs reads the doc comments of the types declared in the Go files of dirs
+func ParseDocs(dirs ...string) (Docs, error) {
+	docs := Docs{}
+	fset := token.NewFileSet()
+	for _, dir := range dirs {
+		files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range files {
+			if strings.HasSuffix(path, "_test.go") {
+				continue
+			}
+			f, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+			if err != nil {
+				return nil, err
+			}
+			collectDocs(f, docs)
+		}
+	}
+	return docs, nil
+}
+
+func collectDocs(f *ast.File, docs Docs) {
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts := spec.(*ast.TypeSpec)
+			doc := ts.Doc
+			if doc == nil && len(gd.Specs) == 1 {
+				doc = gd.Doc
+			}
+			if text := commentText(doc); text != "" {
+				docs[ts.Name.Name] = text
+			}
+
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+			for _, field := range st.Fields.List {
+				text := commentText(field.Comment)
+				if text == "" {
+					text = commentText(field.Doc)
+				}
+				for _, name := range field.Names {
+					if text != "" {
+						docs[ts.Name.Name+"."+name.Name] = text
+					}
+				}
+			}
+		}
+	}
+}
+
+// commentText joins the lines of a comment, dropping swag's @Description marker
+func commentText(cg *ast.CommentGroup) string {
+	if cg == nil {
+		return ""
+	}
+	text := strings.TrimPrefix(strings.TrimSpace(cg.Text()), "@Description ")
+	return strings.Join(strings.Fields(text), " ")
+}
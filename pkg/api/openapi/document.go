@@ -0,0 +1,148 @@
+// Package openapi describes the REST API as an OpenAPI 3 document. The document
+// is generated from the contracts and the operations declared in operations.go,
+// committed as openapi.json and served by the server.
+package openapi
+
+import "encoding/json"
+
+// Document is the subset of an OpenAPI 3.0 document the API needs
+type Document struct {
+	OpenAPI    string                          `json:"openapi"`
+	Info       Info                            `json:"info"`
+	Servers    []Server                        `json:"servers"`
+	Tags       []Tag                           `json:"tags"`
+	Paths      map[string]map[string]Operation `json:"paths"` // Operations by path, then by lower-case method
+	Components Components                      `json:"components"`
+	Security   []SecurityRequirement           `json:"security,omitempty"` // Applies to operations without their own
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type Server struct {
+	URL string `json:"url"`
+}
+
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	Headers         map[string]Header         `json:"headers"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme is a way callers can authenticate
+type SecurityScheme struct {
+	Type         string `json:"type"` // http or apiKey
+	Description  string `json:"description,omitempty"`
+	Scheme       string `json:"scheme,omitempty"` // Of http schemes, e.g. bearer
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	Name         string `json:"name,omitempty"` // Of apiKey schemes, the header carrying the key
+	In           string `json:"in,omitempty"`
+}
+
+// SecurityRequirement names the security schemes that together authenticate a request.
+// The empty requirement lets requests through without credentials.
+type SecurityRequirement map[string][]string
+
+type Operation struct {
+	Tags        []string               `json:"tags"`
+	Summary     string                 `json:"summary"`
+	Description string                 `json:"description,omitempty"`
+	OperationID string                 `json:"operationId"`
+	Parameters  []Parameter            `json:"parameters,omitempty"`
+	RequestBody *RequestBody           `json:"requestBody,omitempty"`
+	Responses   map[string]Response    `json:"responses"`          // By status code
+	Security    *[]SecurityRequirement `json:"security,omitempty"` // Overrides that of the document; empty for public operations
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"` // path, query or header
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Style       string  `json:"style,omitempty"`
+	Explode     bool    `json:"explode,omitempty"` // With style form, each property of an object is its own query parameter
+	Schema      *Schema `json:"schema"`
+	Example     any     `json:"example,omitempty"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema  *Schema `json:"schema"`
+	Example any     `json:"example,omitempty"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Headers     map[string]Header    `json:"headers,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// Header is a response header, or a reference to one in the components
+type Header struct {
+	Ref         string  `json:"$ref,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema,omitempty"`
+}
+
+// Schema is a JSON schema, or a reference to one in the components
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Additional        `json:"additionalProperties,omitempty"` // Any property is allowed when nil
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	Example              any                `json:"example,omitempty"`
+}
+
+// Additional are the properties an object allows besides the declared ones:
+// those matching Schema, or none when Schema is nil
+type Additional struct {
+	Schema *Schema
+}
+
+// allow returns the Additional allowing properties that match s
+func allow(s *Schema) *Additional {
+	return &Additional{Schema: s}
+}
+
+func (a Additional) MarshalJSON() ([]byte, error) {
+	if a.Schema == nil {
+		return []byte("false"), nil
+	}
+	return json.Marshal(a.Schema)
+}
+
+func (a *Additional) UnmarshalJSON(data []byte) error {
+	switch string(data) {
+	case "false":
+		a.Schema = nil
+		return nil
+	case "true":
+		a.Schema = &Schema{}
+		return nil
+	}
+	a.Schema = &Schema{}
+	return json.Unmarshal(data, a.Schema)
+}
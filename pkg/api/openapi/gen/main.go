@@ -0,0 +1,28 @@
+// Command gen writes openapi.json. Run it with go generate from pkg/api/openapi.
+package main
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/Crud-application/pkg/api/openapi"
+)
+
+func main() {
+	var dirs []string
+	for _, dir := range openapi.ContractDirs {
+		dirs = append(dirs, filepath.Join("..", "..", dir))
+	}
+	docs, err := openapi.ParseDocs(dirs...)
+	if err != nil {
+		log.Fatalf("Failed to read the contracts: %v", err)
+	}
+	spec, err := openapi.Generate(docs)
+	if err != nil {
+		log.Fatalf("Failed to generate the OpenAPI document: %v", err)
+	}
+	if err := os.WriteFile("openapi.json", spec, 0o644); err != nil {
+		log.Fatalf("Failed to write openapi.json: %v", err)
+	}
+}
@@ -0,0 +1,22 @@
+package openapi
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+//go:generate go run ./gen
+
+// Generate renders the document of the API as indented JSON, describing the contracts with docs
+func Generate(docs Docs) ([]byte, error) {
+	doc := builder{newSchemas(docs)}.document()
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+func lower(method string) string {
+	return strings.ToLower(method)
+}
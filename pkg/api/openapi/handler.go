@@ -0,0 +1,45 @@
+package openapi
+
+import (
+	_ "embed"
+	"html/template"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Spec is the generated OpenAPI document of the API
+//
+//go:embed openapi.json
+var Spec []byte
+
+// ServeSpec serves the OpenAPI document
+func ServeSpec(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json", Spec)
+}
+
+var docsPage = template.Must(template.New("docs").Parse(`<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8" />
+  <title>CRUD Application API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css" />
+</head>
+<body style="margin: 0">
+  <div id="swagger-ui"></div>
+  <script crossorigin src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    SwaggerUIBundle({ url: {{.}}, dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+`))
+
+// UI serves Swagger UI, rendering the document at specURL
+func UI(specURL string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Content-Type", "text/html; charset=utf-8")
+		c.Status(http.StatusOK)
+		_ = docsPage.Execute(c.Writer, specURL)
+	}
+}
@@ -0,0 +1,72 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+)
+
+func TestSpecUpToDate(t *testing.T) {
+	var dirs []string
+	for _, dir := range ContractDirs {
+		dirs = append(dirs, filepath.Join("..", "..", dir))
+	}
+	docs, err := ParseDocs(dirs...)
+	if err != nil {
+		t.Fatalf("ParseDocs() error = %v", err)
+	}
+	got, err := Generate(docs)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if !bytes.Equal(got, Spec) {
+		t.Error("openapi.json is stale, run go generate ./pkg/api/openapi")
+	}
+}
+
+func TestSpec(t *testing.T) {
+	var doc Document
+	if err := json.Unmarshal(Spec, &doc); err != nil {
+		t.Fatalf("openapi.json is not a document: %v", err)
+	}
+
+	ids := map[string]bool{}
+	for path, ops := range doc.Paths {
+		for method, op := range ops {
+			if op.OperationID == "" || ids[op.OperationID] {
+				t.Errorf("%s %s: operationId %q is missing or taken", method, path, op.OperationID)
+			}
+			ids[op.OperationID] = true
+			if len(op.Responses) == 0 {
+				t.Errorf("%s %s: no responses", method, path)
+			}
+		}
+	}
+
+	// Every reference resolves to a component
+	var refs func(v any)
+	refs = func(v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			if ref, ok := v["$ref"].(string); ok {
+				name := filepath.Base(ref)
+				_, schema := doc.Components.Schemas[name]
+				_, header := doc.Components.Headers[name]
+				if !schema && !header {
+					t.Errorf("%s does not resolve", ref)
+				}
+			}
+			for _, e := range v {
+				refs(e)
+			}
+		case []any:
+			for _, e := range v {
+				refs(e)
+			}
+		}
+	}
+	var raw any
+	_ = json.Unmarshal(Spec, &raw)
+	refs(raw)
+}
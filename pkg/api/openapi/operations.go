@@ -0,0 +1,521 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	mw "github.com/Crud-application/pkg/api/middleware"
+	"github.com/Crud-application/pkg/contracts/auth"
+	"github.com/Crud-application/pkg/contracts/event"
+	"github.com/Crud-application/pkg/contracts/patch"
+	"github.com/Crud-application/pkg/contracts/problem"
+	"github.com/Crud-application/pkg/contracts/user"
+	"github.com/Crud-application/pkg/contracts/webhook"
+)
+
+// Route is an operation served at a path relative to the API base path
+type Route struct {
+	Method string
+	Path   string // In OpenAPI form, e.g. /users/{userID}
+	Op     Operation
+}
+
+// builder declares the operations of the API with the schemas of the contracts
+type builder struct {
+	*schemas
+}
+
+// statusChange is the route moving a user to another status of their lifecycle with verb,
+// such as suspend, which leaves them in the state past
+func (b builder) statusChange(verb, past, summary, description string, userID Parameter, etag map[string]Header) Route {
+	return Route{http.MethodPost, "/users/{userID}/" + verb, Operation{
+		Tags:        []string{"Users"},
+		Summary:     summary,
+		Description: description + " The change is recorded with its reason and raises a `user.status_changed` event.",
+		OperationID: verb + "User",
+		Parameters:  []Parameter{userID},
+		RequestBody: b.jsonBody(user.ChangeUserStatusReq{}),
+		Responses: b.responses(map[string]Response{
+			"200": b.jsonResponse("The "+past+" user", envelope("User "+past+" successfully", "user", b.ref(user.GetUserRes{})), etag),
+			"404": b.problem(http.StatusNotFound, "The user does not exist or is deleted"),
+			"409": b.problem(http.StatusConflict, "The user cannot be "+past+" from their current status, or changed concurrently"),
+		}, http.StatusBadRequest, http.StatusUnprocessableEntity),
+	}}
+}
+
+// routes lists every documented operation
+func (b builder) routes() []Route {
+	userID := pathParam("userID", "ID of the user")
+	webhookID := pathParam("webhookID", "ID of the webhook")
+	etag := map[string]Header{"ETag": {Ref: "#/components/headers/ETag"}}
+
+	return []Route{
+		{http.MethodPost, "/users", Operation{
+			Tags:        []string{"Users"},
+			Summary:     "Create a user",
+			OperationID: "createUser",
+			RequestBody: b.jsonBody(user.CreateUserReq{}),
+			Responses: b.responses(map[string]Response{
+				"201": b.jsonResponse("The created user", b.ref(user.CreateUserRes{}), etag),
+				"409": b.problem(http.StatusConflict, "The email is already in use"),
+			}, http.StatusBadRequest, http.StatusUnprocessableEntity),
+		}},
+		{http.MethodGet, "/users", Operation{
+			Tags:    []string{"Users"},
+			Summary: "List users",
+			Description: "Returns one page of users in ID order, unless sorted. Every query parameter not listed here filters on a user field: " +
+				"`name=John` matches exactly, `name~=jo` matches a case-insensitive substring, `name!=John` excludes, " +
+				"and `created_at>=2024-05-01` and `created_at<=2024-05-31` bound a range. phone_number only supports `=` and `!=`. " +
+				"The filterable fields are id, name, email, phone_number, status, created_at, updated_at, created_by and updated_by.",
+			OperationID: "listUsers",
+			Parameters: append(b.queryParameters(user.GetUsersReq{}), Parameter{
+				Name:        "filter",
+				In:          "query",
+				Description: "Conditions on user fields, each its own query parameter",
+				Style:       "form",
+				Explode:     true,
+				Schema:      &Schema{Type: "object", AdditionalProperties: allow(&Schema{Type: "string"})},
+				Example:     map[string]string{"name~": "jo", "created_at>": "2024-05-01"},
+			}),
+			Responses: b.responses(map[string]Response{
+				"200": b.jsonResponse("One page of users", b.withMessage("Users retrieved successfully", b.ref(user.GetUsersRes{})), nil),
+			}, http.StatusBadRequest, http.StatusUnprocessableEntity),
+		}},
+		{http.MethodGet, "/users/stream", Operation{
+			Tags:    []string{"Users"},
+			Summary: "Stream user changes",
+			Description: "Keeps the connection open and sends every user event as a Server-Sent Event named after its type, " +
+				"with the Event JSON as data. Clients that reconnect with the ID of the last event they saw first receive the events they missed; " +
+				"when that event is no longer buffered a `reset` event tells them to reload. Idle connections get a `: heartbeat` comment.",
+			OperationID: "streamUsers",
+			Parameters: []Parameter{
+				{Name: "Last-Event-ID", In: "header", Description: "ID of the last event received, sent by EventSource when it reconnects", Schema: &Schema{Type: "string"}},
+				{Name: "last_event_id", In: "query", Description: "Same as Last-Event-ID, for clients that cannot set headers", Schema: &Schema{Type: "string"}},
+			},
+			Responses: b.responses(map[string]Response{
+				"200": {
+					Description: "A stream of user events",
+					Headers:     requestIDHeader(nil),
+					Content: map[string]MediaType{"text/event-stream": {
+						Schema:  &Schema{Type: "string"},
+						Example: "id: 6650c0ffee0000000000cafe\nevent: user.created\ndata: {\"id\":\"6650c0ffee0000000000cafe\",\"type\":\"user.created\",...}\n\n",
+					}},
+				},
+			}),
+		}},
+		{http.MethodPost, "/users/verify", Operation{
+			Tags:        []string{"Users"},
+			Summary:     "Verify an email",
+			Description: "Marks the email of a user verified with the token of the link emailed to it when the user was created or the email changed.",
+			OperationID: "verifyEmail",
+			Security:    &[]SecurityRequirement{},
+			RequestBody: b.jsonBody(user.VerifyEmailReq{}),
+			Responses: without(b.responses(map[string]Response{
+				"200": b.jsonResponse("The email was verified", envelope("Email verified successfully", "", nil), nil),
+				"422": b.problem(http.StatusUnprocessableEntity, "The token is invalid, expired, or was sent to another email"),
+			}, http.StatusBadRequest), http.StatusUnauthorized, http.StatusForbidden),
+		}},
+		{http.MethodGet, "/users/{userID}", Operation{
+			Tags:        []string{"Users"},
+			Summary:     "Get a user",
+			OperationID: "getUser",
+			Parameters:  []Parameter{userID},
+			Responses: b.responses(map[string]Response{
+				"200": b.jsonResponse("The user", envelope("User retrieved successfully", "user", b.ref(user.GetUserRes{})), etag),
+				"404": b.problem(http.StatusNotFound, "The user does not exist or is deleted"),
+			}),
+		}},
+		{http.MethodPatch, "/users/{userID}", Operation{
+			Tags:    []string{"Users"},
+			Summary: "Update a user",
+			Description: "An `application/json` body changes the fields it contains and leaves the others unchanged. " +
+				"An `application/merge-patch+json` (RFC 7396) or `application/json-patch+json` (RFC 6902) body is applied to the user as returned by GET, " +
+				"and `test` ops guard the patch. Only name, email and phone_number may change; they are required, so removing one with `null` in a merge patch " +
+				"or a `remove` op is a 422.",
+			OperationID: "updateUser",
+			Parameters: []Parameter{userID, {
+				Name:        "If-Match",
+				In:          "header",
+				Description: "ETags of the versions the update may apply to, `*` or absent for any",
+				Schema:      &Schema{Type: "string"},
+				Example:     `"3"`,
+			}},
+			RequestBody: b.patchBody(user.UpdateUserReq{}),
+			Responses: b.responses(map[string]Response{
+				"200": b.jsonResponse("The updated user", envelope("User updated successfully", "user", b.ref(user.UpdateUserRes{})), etag),
+				"404": b.problem(http.StatusNotFound, "The user does not exist or is deleted"),
+				"409": b.problem(http.StatusConflict, "The email is already in use, the user changed concurrently, or a JSON Patch test failed"),
+				"412": b.problem(http.StatusPreconditionFailed, "The user is not at a version listed in If-Match"),
+			}, http.StatusBadRequest, http.StatusUnprocessableEntity),
+		}},
+		{http.MethodDelete, "/users/{userID}", Operation{
+			Tags:        []string{"Users"},
+			Summary:     "Delete a user",
+			Description: "Soft-deletes the user, who can be restored until the retention period ends.",
+			OperationID: "deleteUser",
+			Parameters:  []Parameter{userID},
+			Responses: b.responses(map[string]Response{
+				"200": b.jsonResponse("The user was deleted", envelope("User deleted successfully", "", nil), nil),
+				"404": b.problem(http.StatusNotFound, "The user does not exist or is already deleted"),
+			}),
+		}},
+		{http.MethodPost, "/users/{userID}/restore", Operation{
+			Tags:        []string{"Users"},
+			Summary:     "Restore a deleted user",
+			OperationID: "restoreUser",
+			Parameters:  []Parameter{userID},
+			Responses: b.responses(map[string]Response{
+				"200": b.jsonResponse("The restored user", envelope("User restored successfully", "user", b.ref(user.GetUserRes{})), etag),
+				"404": b.problem(http.StatusNotFound, "The user does not exist or was purged"),
+				"409": b.problem(http.StatusConflict, "The user is not deleted, or another user took their email"),
+			}),
+		}},
+		b.statusChange("suspend", "suspended", "Suspend a user",
+			"Blocks the user: they can no longer log in or renew their session. Active and pending users can be suspended.", userID, etag),
+		b.statusChange("activate", "activated", "Activate a user",
+			"Lets a pending, suspended or deactivated user in again. Pending users are also activated by verifying their email.", userID, etag),
+		b.statusChange("deactivate", "deactivated", "Deactivate a user",
+			"Closes the account of the user, who can no longer log in, until it is activated again. Users of any other status can be deactivated.", userID, etag),
+		{http.MethodGet, "/users/{userID}/history", Operation{
+			Tags:        []string{"Users"},
+			Summary:     "List the changes of a user",
+			Description: "Returns one page of the audit log of the user, newest entry first. The history outlives the user.",
+			OperationID: "getUserHistory",
+			Parameters:  append([]Parameter{userID}, b.queryParameters(user.GetUserHistoryReq{})...),
+			Responses: b.responses(map[string]Response{
+				"200": b.jsonResponse("One page of audit entries", b.withMessage("User history retrieved successfully", b.ref(user.GetUserHistoryRes{})), nil),
+			}, http.StatusBadRequest, http.StatusUnprocessableEntity),
+		}},
+
+		{http.MethodPost, "/webhooks", Operation{
+			Tags:        []string{"Webhooks"},
+			Summary:     "Subscribe a URL to user events",
+			OperationID: "createWebhook",
+			RequestBody: b.jsonBody(webhook.CreateWebhookReq{}),
+			Responses: b.responses(map[string]Response{
+				"201": b.jsonResponse("The created webhook, with its signing secret", b.ref(webhook.CreateWebhookRes{}), nil),
+			}, http.StatusBadRequest, http.StatusUnprocessableEntity),
+		}},
+		{http.MethodGet, "/webhooks", Operation{
+			Tags:        []string{"Webhooks"},
+			Summary:     "List webhooks",
+			OperationID: "listWebhooks",
+			Parameters:  b.queryParameters(webhook.GetWebhooksReq{}),
+			Responses: b.responses(map[string]Response{
+				"200": b.jsonResponse("One page of webhooks", b.withMessage("Webhooks retrieved successfully", b.ref(webhook.GetWebhooksRes{})), nil),
+			}, http.StatusBadRequest, http.StatusUnprocessableEntity),
+		}},
+		{http.MethodGet, "/webhooks/{webhookID}", Operation{
+			Tags:        []string{"Webhooks"},
+			Summary:     "Get a webhook",
+			OperationID: "getWebhook",
+			Parameters:  []Parameter{webhookID},
+			Responses: b.responses(map[string]Response{
+				"200": b.jsonResponse("The webhook", envelope("Webhook retrieved successfully", "webhook", b.ref(webhook.WebhookRes{})), nil),
+				"404": b.problem(http.StatusNotFound, "The webhook does not exist"),
+			}),
+		}},
+		{http.MethodPatch, "/webhooks/{webhookID}", Operation{
+			Tags:        []string{"Webhooks"},
+			Summary:     "Update a webhook",
+			Description: "Changes the fields present in the body. Pending deliveries of a deactivated webhook are dead-lettered.",
+			OperationID: "updateWebhook",
+			Parameters:  []Parameter{webhookID},
+			RequestBody: b.jsonBody(webhook.UpdateWebhookReq{}),
+			Responses: b.responses(map[string]Response{
+				"200": b.jsonResponse("The updated webhook", envelope("Webhook updated successfully", "webhook", b.ref(webhook.WebhookRes{})), nil),
+				"404": b.problem(http.StatusNotFound, "The webhook does not exist"),
+			}, http.StatusBadRequest, http.StatusUnprocessableEntity),
+		}},
+		{http.MethodDelete, "/webhooks/{webhookID}", Operation{
+			Tags:        []string{"Webhooks"},
+			Summary:     "Delete a webhook",
+			Description: "Deletes the webhook along with its delivery log.",
+			OperationID: "deleteWebhook",
+			Parameters:  []Parameter{webhookID},
+			Responses: b.responses(map[string]Response{
+				"200": b.jsonResponse("The webhook was deleted", envelope("Webhook deleted successfully", "", nil), nil),
+				"404": b.problem(http.StatusNotFound, "The webhook does not exist"),
+			}),
+		}},
+		{http.MethodGet, "/webhooks/{webhookID}/deliveries", Operation{
+			Tags:        []string{"Webhooks"},
+			Summary:     "List the deliveries of a webhook",
+			Description: "Returns one page of the delivery log of the webhook, newest delivery first.",
+			OperationID: "getWebhookDeliveries",
+			Parameters:  append([]Parameter{webhookID}, b.queryParameters(webhook.GetDeliveriesReq{})...),
+			Responses: b.responses(map[string]Response{
+				"200": b.jsonResponse("One page of deliveries", b.withMessage("Webhook deliveries retrieved successfully", b.ref(webhook.GetDeliveriesRes{})), nil),
+				"404": b.problem(http.StatusNotFound, "The webhook does not exist"),
+			}, http.StatusBadRequest, http.StatusUnprocessableEntity),
+		}},
+
+		{http.MethodPost, "/auth/login", Operation{
+			Tags:        []string{"Auth"},
+			Summary:     "Log in with an email and password",
+			Description: "Starts a session, returning a short-lived access token to send as a bearer token and a refresh token to renew it.",
+			OperationID: "login",
+			Security:    &[]SecurityRequirement{},
+			RequestBody: b.jsonBody(auth.LoginReq{}),
+			Responses: b.responses(map[string]Response{
+				"200": b.jsonResponse("The tokens of the new session", b.ref(auth.TokenRes{}), nil),
+				"401": b.problem(http.StatusUnauthorized, "The email or password is wrong"),
+				"403": b.problem(http.StatusForbidden, "The account is suspended or deactivated"),
+				"503": b.problem(http.StatusServiceUnavailable, "The database is unreachable or no JWT secret is configured"),
+			}, http.StatusBadRequest, http.StatusUnprocessableEntity),
+		}},
+		{http.MethodPost, "/auth/refresh", Operation{
+			Tags:    []string{"Auth"},
+			Summary: "Renew an access token",
+			Description: "Exchanges a refresh token for a new access token and a new refresh token; the one sent stops working. " +
+				"Sending a refresh token that was already exchanged ends its session.",
+			OperationID: "refreshToken",
+			Security:    &[]SecurityRequirement{},
+			RequestBody: b.jsonBody(auth.RefreshReq{}),
+			Responses: without(b.responses(map[string]Response{
+				"200": b.jsonResponse("The renewed tokens", b.ref(auth.TokenRes{}), nil),
+				"401": b.problem(http.StatusUnauthorized, "The refresh token is unknown, expired or revoked"),
+				"503": b.problem(http.StatusServiceUnavailable, "The database is unreachable or no JWT secret is configured"),
+			}, http.StatusBadRequest, http.StatusUnprocessableEntity), http.StatusForbidden),
+		}},
+		{http.MethodPost, "/auth/logout", Operation{
+			Tags:        []string{"Auth"},
+			Summary:     "Log out",
+			Description: "Revokes the session of the refresh token. Unknown tokens are ignored. Access tokens already issued stay valid until they expire.",
+			OperationID: "logout",
+			Security:    &[]SecurityRequirement{},
+			RequestBody: b.jsonBody(auth.RefreshReq{}),
+			Responses: without(b.responses(map[string]Response{
+				"200": b.jsonResponse("The session was revoked", envelope("Logged out successfully", "", nil), nil),
+			}, http.StatusBadRequest, http.StatusUnprocessableEntity), http.StatusUnauthorized, http.StatusForbidden),
+		}},
+		{http.MethodPost, "/auth/password", Operation{
+			Tags:        []string{"Auth"},
+			Summary:     "Change the password of the caller",
+			Description: "Replaces the password of the calling user, given the current one, and revokes all their sessions.",
+			OperationID: "changePassword",
+			RequestBody: b.jsonBody(auth.ChangePasswordReq{}),
+			Responses: b.responses(map[string]Response{
+				"200": b.jsonResponse("The password was changed", envelope("Password changed successfully", "", nil), nil),
+				"403": b.problem(http.StatusForbidden, "The caller is not a user, such as an API key"),
+				"422": b.problem(http.StatusUnprocessableEntity, "The old password is incorrect or the new one invalid; errors lists the field"),
+			}, http.StatusBadRequest),
+		}},
+
+		{http.MethodPost, "/auth/forgot-password", Operation{
+			Tags:        []string{"Auth"},
+			Summary:     "Ask for a password reset link",
+			Description: "Emails a password reset link to the user with the email. The response does not tell whether there is one.",
+			OperationID: "forgotPassword",
+			Security:    &[]SecurityRequirement{},
+			RequestBody: b.jsonBody(auth.ForgotPasswordReq{}),
+			Responses: without(b.responses(map[string]Response{
+				"200": b.jsonResponse("The link was sent, if there is such a user", envelope("If a user with this email exists, a password reset link has been sent to it", "", nil), nil),
+				"503": b.problem(http.StatusServiceUnavailable, "The database or the mail server is unreachable"),
+			}, http.StatusBadRequest, http.StatusUnprocessableEntity), http.StatusUnauthorized, http.StatusForbidden),
+		}},
+		{http.MethodPost, "/auth/reset-password", Operation{
+			Tags:        []string{"Auth"},
+			Summary:     "Reset a password",
+			Description: "Sets a new password with the token of a password reset link and revokes all sessions of the user. The token works once.",
+			OperationID: "resetPassword",
+			Security:    &[]SecurityRequirement{},
+			RequestBody: b.jsonBody(auth.ResetPasswordReq{}),
+			Responses: without(b.responses(map[string]Response{
+				"200": b.jsonResponse("The password was reset", envelope("Password reset successfully", "", nil), nil),
+				"422": b.problem(http.StatusUnprocessableEntity, "The token is invalid, expired or used, or the new password is invalid; errors lists the field"),
+			}, http.StatusBadRequest), http.StatusUnauthorized, http.StatusForbidden),
+		}},
+
+		{http.MethodPost, "/graphql", Operation{
+			Tags:        []string{"GraphQL"},
+			Summary:     "Execute a GraphQL query or mutation",
+			Description: "Errors of the operation, including denied access, are reported in the `errors` of a 200 response, with `extensions.code` set.",
+			OperationID: "graphql",
+			RequestBody: &RequestBody{Required: true, Content: map[string]MediaType{"application/json": {Schema: &Schema{
+				Type: "object",
+				Properties: map[string]*Schema{
+					"query":         {Type: "string", Example: "{ user(id: \"5118863e-a240-44b9-9d3a-2f1e0c7b6a59\") { name email } }"},
+					"operationName": {Type: "string"},
+					"variables":     {Type: "object", AdditionalProperties: allow(&Schema{})},
+				},
+				Required: []string{"query"},
+			}}}},
+			Responses: without(b.responses(map[string]Response{
+				"200": b.jsonResponse("The result of the operation", &Schema{
+					Type: "object",
+					Properties: map[string]*Schema{
+						"data":   {Type: "object", Nullable: true, AdditionalProperties: allow(&Schema{})},
+						"errors": {Type: "array", Items: &Schema{Type: "object", AdditionalProperties: allow(&Schema{})}},
+					},
+				}, nil),
+			}, http.StatusBadRequest, http.StatusUnprocessableEntity), http.StatusForbidden),
+		}},
+		{http.MethodGet, "/graphql/playground", Operation{
+			Tags:        []string{"GraphQL"},
+			Summary:     "Explore the GraphQL schema",
+			Description: "Serves GraphiQL. Only available in the dev environment.",
+			OperationID: "graphqlPlayground",
+			Security:    &[]SecurityRequirement{},
+			Responses: map[string]Response{
+				"200": {Description: "The GraphiQL page", Content: map[string]MediaType{"text/html": {Schema: &Schema{Type: "string"}}}},
+			},
+		}},
+	}
+}
+
+// document assembles the routes into the OpenAPI document
+func (b builder) document() *Document {
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info: Info{
+			Title:       "CRUD Application API",
+			Description: "Manages users and the webhooks that are told about their changes. Every error is an RFC 7807 problem.",
+			Version:     "1.0.0",
+		},
+		Servers: []Server{{URL: "/api"}},
+		Tags: []Tag{
+			{Name: "Users", Description: "Users and their change history"},
+			{Name: "Webhooks", Description: "Subscriptions of partner URLs to user events"},
+			{Name: "Auth", Description: "Password login, the sessions it starts and password resets"},
+			{Name: "GraphQL", Description: "The GraphQL API over users"},
+		},
+		Paths: map[string]map[string]Operation{},
+		Components: Components{
+			Headers: map[string]Header{
+				"X-Request-ID": {Description: "ID of the request, taken from the request header when provided", Schema: &Schema{Type: "string"}},
+				"ETag":         {Description: "Version of the user as a strong entity tag, for If-Match", Schema: &Schema{Type: "string", Example: `"3"`}},
+			},
+			SecuritySchemes: map[string]SecurityScheme{
+				"bearerAuth": {
+					Type:         "http",
+					Scheme:       "bearer",
+					BearerFormat: "JWT",
+					Description:  "A JWT of the configured issuer, signed with HS256 or RS256, such as the access token of a login. Its subject is recorded as the actor of changes and its `roles` claim grants the admin and support roles.",
+				},
+				"apiKeyAuth": {
+					Type:        "apiKey",
+					In:          "header",
+					Name:        mw.APIKeyHeader,
+					Description: "A static API key, also accepted as `Authorization: ApiKey <key>`.",
+				},
+			},
+		},
+		// Either scheme authenticates a request
+		Security: []SecurityRequirement{{"bearerAuth": {}}, {"apiKeyAuth": {}}},
+	}
+	for _, r := range b.routes() {
+		if doc.Paths[r.Path] == nil {
+			doc.Paths[r.Path] = map[string]Operation{}
+		}
+		doc.Paths[r.Path][lower(r.Method)] = r.Op
+	}
+
+	// Webhook receivers get Events, which no operation returns
+	b.ref(event.Event{})
+	doc.Components.Schemas = b.components
+	return doc
+}
+
+// jsonBody describes a request body holding the struct of v, without unknown fields
+func (b builder) jsonBody(v any) *RequestBody {
+	return &RequestBody{Required: true, Content: map[string]MediaType{"application/json": {Schema: b.closed(v)}}}
+}
+
+func (b builder) jsonResponse(description string, schema *Schema, headers map[string]Header) Response {
+	return Response{
+		Description: description,
+		Headers:     requestIDHeader(headers),
+		Content:     map[string]MediaType{"application/json": {Schema: schema}},
+	}
+}
+
+// patchBody describes a PATCH body: the fields of v to set as JSON, or a merge patch or JSON Patch of the resource
+func (b builder) patchBody(v any) *RequestBody {
+	body := b.jsonBody(v)
+	body.Content[patch.MergePatchContentType] = MediaType{
+		Schema:  &Schema{Type: "object", Description: "Members to set on the resource; null removes a member that is optional"},
+		Example: map[string]any{"name": "New Name"},
+	}
+	body.Content[patch.JSONPatchContentType] = MediaType{
+		Schema: &Schema{Type: "array", Items: b.ref(patch.Operation{})},
+		Example: []patch.Operation{
+			{Op: "test", Path: "/version", Value: json.RawMessage(`3`)},
+			{Op: "replace", Path: "/email", Value: json.RawMessage(`"new@example.com"`)},
+		},
+	}
+	return body
+}
+
+// withMessage adds the message the handlers send along with the response of a service
+func (b builder) withMessage(message string, schema *Schema) *Schema {
+	return &Schema{AllOf: []*Schema{schema, envelope(message, "", nil)}}
+}
+
+// responses adds the problems every operation can answer with, and those of statuses, to rs
+func (b builder) responses(rs map[string]Response, statuses ...int) map[string]Response {
+	for _, status := range append(statuses, http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError, http.StatusServiceUnavailable) {
+		if _, ok := rs[strconv.Itoa(status)]; !ok {
+			rs[strconv.Itoa(status)] = b.problem(status, problemDescriptions[status])
+		}
+	}
+	return rs
+}
+
+// without removes the responses of statuses an operation never answers with
+func without(rs map[string]Response, statuses ...int) map[string]Response {
+	for _, status := range statuses {
+		delete(rs, strconv.Itoa(status))
+	}
+	return rs
+}
+
+// problemDescriptions describe the problems most operations share
+var problemDescriptions = map[int]string{
+	http.StatusBadRequest:          "The request is malformed",
+	http.StatusUnauthorized:        "Credentials are missing or invalid",
+	http.StatusForbidden:           "The roles of the caller do not allow the operation; detail gives the reason",
+	http.StatusUnprocessableEntity: "The request failed validation; errors lists the invalid fields",
+	http.StatusInternalServerError: "The server failed unexpectedly",
+	http.StatusServiceUnavailable:  "The database is unreachable",
+}
+
+func (b builder) problem(status int, description string) Response {
+	typ, title := mw.ProblemType(status)
+	return Response{
+		Description: description,
+		Headers:     requestIDHeader(nil),
+		Content: map[string]MediaType{problem.ContentType: {
+			Schema:  b.ref(problem.Problem{}),
+			Example: problem.Problem{Type: typ, Title: title, Status: status},
+		}},
+	}
+}
+
+// envelope describes a response object with a message and, unless name is empty, one more property
+func envelope(message, name string, schema *Schema) *Schema {
+	obj := &Schema{
+		Type:       "object",
+		Properties: map[string]*Schema{"message": {Type: "string", Example: message}},
+		Required:   []string{"message"},
+	}
+	if name != "" {
+		obj.Properties[name] = schema
+		obj.Required = append(obj.Required, name)
+	}
+	return obj
+}
+
+func pathParam(name, description string) Parameter {
+	return Parameter{Name: name, In: "path", Description: description, Required: true, Schema: &Schema{Type: "string"}}
+}
+
+// requestIDHeader adds the X-Request-ID header every response carries to headers
+func requestIDHeader(headers map[string]Header) map[string]Header {
+	all := map[string]Header{"X-Request-ID": {Ref: "#/components/headers/X-Request-ID"}}
+	for name, h := range headers {
+		all[name] = h
+	}
+	return all
+}
@@ -0,0 +1,230 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// schemas turns Go types into JSON schemas, collecting named structs as components
+type schemas struct {
+	docs       Docs
+	components map[string]*Schema
+	types      map[string]reflect.Type // Type registered under each component name
+}
+
+func newSchemas(docs Docs) *schemas {
+	return &schemas{docs: docs, components: map[string]*Schema{}, types: map[string]reflect.Type{}}
+}
+
+// ref returns a reference to the component describing the struct of v, registering it on first use
+func (s *schemas) ref(v any) *Schema {
+	return s.of(reflect.TypeOf(v), "")
+}
+
+// of returns the schema of t. Named structs become references to components.
+func (s *schemas) of(t reflect.Type, swaggerType string) *Schema {
+	if swaggerType != "" {
+		return &Schema{Type: swaggerType}
+	}
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case rawMessageType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return s.of(t.Elem(), "")
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: s.of(t.Elem(), "")}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: allow(s.of(t.Elem(), ""))}
+	case reflect.Interface:
+		return &Schema{} // Any value
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.object(t)
+		}
+		s.register(t)
+		return &Schema{Ref: "#/components/schemas/" + t.Name()}
+	default:
+		panic(fmt.Sprintf("openapi: cannot describe %s", t))
+	}
+}
+
+// register adds the component describing the named struct t
+func (s *schemas) register(t reflect.Type) {
+	if prev, ok := s.types[t.Name()]; ok {
+		if prev != t {
+			panic(fmt.Sprintf("openapi: %s and %s share the component name %s", prev, t, t.Name()))
+		}
+		return
+	}
+	s.types[t.Name()] = t
+	s.components[t.Name()] = nil // Reserve the name while the fields are described
+	obj := s.object(t)
+	obj.Description = s.docs[t.Name()]
+	s.components[t.Name()] = obj
+}
+
+// object describes the JSON object encoding/json produces for the struct t
+func (s *schemas) object(t reflect.Type) *Schema {
+	obj := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	s.addFields(obj, t)
+	return obj
+}
+
+func (s *schemas) addFields(obj *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" || !f.IsExported() {
+			continue
+		}
+		// Embedded structs without a JSON name are flattened, like encoding/json does
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			s.addFields(obj, f.Type)
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		prop := s.of(f.Type, f.Tag.Get("swaggertype"))
+		if prop.Ref != "" {
+			// Siblings of $ref are ignored in OpenAPI 3.0, so describe the reference through allOf
+			if doc := s.docs[t.Name()+"."+f.Name]; doc != "" {
+				prop = &Schema{AllOf: []*Schema{prop}, Description: doc}
+			}
+		} else {
+			prop.Description = s.docs[t.Name()+"."+f.Name]
+			prop.Nullable = f.Type.Kind() == reflect.Pointer
+			prop.Example = example(f.Type, f.Tag.Get("example"))
+			constrain(prop, f.Tag.Get("binding"))
+		}
+		obj.Properties[name] = prop
+		if required(f) {
+			obj.Required = append(obj.Required, name)
+		}
+	}
+}
+
+// closed returns a reference to the component describing the struct of v, which allows no undeclared properties
+func (s *schemas) closed(v any) *Schema {
+	ref := s.ref(v)
+	s.components[reflect.TypeOf(v).Name()].AdditionalProperties = &Additional{}
+	return ref
+}
+
+// required reports whether binding rejects requests without the field
+func required(f reflect.StructField) bool {
+	for _, rule := range strings.Split(f.Tag.Get("binding"), ",") {
+		if rule == "required" {
+			return true
+		}
+	}
+	return false
+}
+
+// constrain adds the checks of the binding rules of a field to its schema
+func constrain(prop *Schema, rules string) {
+	for _, rule := range strings.Split(rules, ",") {
+		name, arg, _ := strings.Cut(rule, "=")
+		switch name {
+		case "email":
+			prop.Format = "email"
+		case "url":
+			prop.Format = "uri"
+		case "oneof":
+			for _, v := range strings.Fields(arg) {
+				prop.Enum = append(prop.Enum, v)
+			}
+		case "min", "max":
+			n, err := strconv.Atoi(arg)
+			if err != nil {
+				panic(fmt.Sprintf("openapi: bad binding rule %q", rule))
+			}
+			bound := float64(n)
+			switch {
+			case prop.Type == "string" && name == "min":
+				prop.MinLength = &n
+			case prop.Type == "string":
+				prop.MaxLength = &n
+			case name == "min":
+				prop.Minimum = &bound
+			default:
+				prop.Maximum = &bound
+			}
+		}
+	}
+}
+
+// example converts a swag-style example tag to a value of the field's type.
+// Slices list their elements separated by commas.
+func example(t reflect.Type, tag string) any {
+	if tag == "" {
+		return nil
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		v, _ := strconv.ParseBool(tag)
+		return v
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, _ := strconv.ParseInt(tag, 10, 64)
+		return v
+	case reflect.Slice:
+		var items []any
+		for _, item := range strings.Split(tag, ",") {
+			items = append(items, example(t.Elem(), item))
+		}
+		return items
+	default:
+		return tag
+	}
+}
+
+// queryParameters describes the fields of the query struct of v as query parameters
+func (s *schemas) queryParameters(v any) []Parameter {
+	t := reflect.TypeOf(v)
+	var params []Parameter
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := f.Tag.Get("form")
+		if name == "" || name == "-" {
+			continue
+		}
+		schema := s.of(f.Type, "")
+		constrain(schema, f.Tag.Get("binding"))
+		params = append(params, Parameter{
+			Name:        name,
+			In:          "query",
+			Description: s.docs[t.Name()+"."+f.Name],
+			Schema:      schema,
+			Example:     example(f.Type, f.Tag.Get("example")),
+		})
+	}
+	return params
+}
@@ -0,0 +1,360 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"github.com/gin-gonic/gin"
+)
+
+// ginParam matches the parameters of gin route patterns, e.g. :userID
+var ginParam = regexp.MustCompile(`:(\w+)`)
+
+// mustBe describes the values of each schema type
+var mustBe = map[string]string{
+	"object":  "must be an object",
+	"array":   "must be an array",
+	"string":  "must be a string",
+	"integer": "must be an integer",
+	"number":  "must be a number",
+	"boolean": "must be a boolean",
+}
+
+// Validator checks requests against the operations of a document before the handlers run
+type Validator struct {
+	doc *Document
+	ops map[string]*Operation // By method and path, e.g. "PATCH /users/{userID}"
+}
+
+// NewValidator builds a validator for the JSON document spec
+func NewValidator(spec []byte) (*Validator, error) {
+	var doc Document
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		return nil, fmt.Errorf("openapi: invalid document: %w", err)
+	}
+	v := &Validator{doc: &doc, ops: map[string]*Operation{}}
+	for path, ops := range doc.Paths {
+		for method, op := range ops {
+			op := op
+			v.ops[strings.ToUpper(method)+" "+path] = &op
+		}
+	}
+	return v, nil
+}
+
+// Validate checks the path parameters, query parameters and JSON body of requests
+// against the embedded document, answering with a 422 problem listing every violation.
+// Routes of the group at basePath that the document does not describe are not checked.
+func Validate(basePath string) gin.HandlerFunc {
+	v, err := NewValidator(Spec)
+	if err != nil {
+		panic(err)
+	}
+	return v.Middleware(basePath)
+}
+
+// Middleware checks requests routed below basePath
+func (v *Validator) Middleware(basePath string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := ginParam.ReplaceAllString(strings.TrimPrefix(c.FullPath(), basePath), "{$1}")
+		op, ok := v.ops[c.Request.Method+" "+path]
+		if !ok {
+			c.Next()
+			return
+		}
+		if err := v.check(c, op); err != nil {
+			_ = c.Error(err)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// check validates the request of c against op. Malformed bodies are bind errors.
+func (v *Validator) check(c *gin.Context, op *Operation) *gin.Error {
+	var errs []domainErr.FieldError
+
+	query := c.Request.URL.Query()
+	var freeForm *Parameter // Collects the query parameters not declared on their own
+	for i := range op.Parameters {
+		p := &op.Parameters[i]
+		switch p.In {
+		case "path":
+			errs = v.checkParam(p, c.Param(p.Name), p.Name, errs)
+		case "query":
+			if p.Explode && v.resolve(p.Schema).Type == "object" {
+				freeForm = p
+				continue
+			}
+			values, ok := query[p.Name]
+			if !ok && p.Required {
+				errs = append(errs, domainErr.FieldError{Field: p.Name, Message: "is required"})
+			}
+			for _, value := range values {
+				errs = v.checkParam(p, value, p.Name, errs)
+			}
+			delete(query, p.Name)
+		}
+	}
+	for _, name := range sortedKeys(query) {
+		if freeForm == nil {
+			errs = append(errs, domainErr.FieldError{Field: name, Message: "is not a known parameter"})
+			continue
+		}
+		if additional := v.resolve(freeForm.Schema).AdditionalProperties; additional != nil && additional.Schema != nil {
+			for _, value := range query[name] {
+				errs = v.checkParam(&Parameter{Schema: additional.Schema}, value, name, errs)
+			}
+		}
+	}
+
+	if op.RequestBody != nil {
+		// Handlers decode JSON whatever the content type, unless it is one of the operation's own
+		media, ok := op.RequestBody.Content[c.ContentType()]
+		if !ok {
+			media, ok = op.RequestBody.Content["application/json"]
+		}
+		if ok {
+			body, err := v.readBody(c, op.RequestBody.Required)
+			if err != nil {
+				return &gin.Error{Err: err, Type: gin.ErrorTypeBind}
+			}
+			if body != nil {
+				errs = v.checkValue(media.Schema, body, "", errs)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return &gin.Error{Err: domainErr.Validation("request failed validation", errs...), Type: gin.ErrorTypePublic}
+	}
+	return nil
+}
+
+// readBody decodes the JSON body and puts it back for the handler. A missing optional body is nil.
+func (v *Validator) readBody(c *gin.Context, required bool) (any, error) {
+	if c.Request.Body == nil {
+		c.Request.Body = io.NopCloser(bytes.NewReader(nil))
+	}
+	raw, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return nil, err
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(raw))
+
+	if len(bytes.TrimSpace(raw)) == 0 {
+		if required {
+			return nil, errors.New("request body is required")
+		}
+		return nil, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var body any
+	if err := dec.Decode(&body); err != nil {
+		return nil, fmt.Errorf("request body is not valid JSON: %w", err)
+	}
+	if dec.More() {
+		return nil, errors.New("request body has data after the JSON value")
+	}
+	return body, nil
+}
+
+// checkParam converts the raw value of a parameter to the type of its schema and validates it
+func (v *Validator) checkParam(p *Parameter, raw, field string, errs []domainErr.FieldError) []domainErr.FieldError {
+	schema := v.resolve(p.Schema)
+	var value any = raw
+	switch schema.Type {
+	case "integer", "number":
+		if _, ok := new(big.Float).SetString(raw); ok {
+			value = json.Number(raw)
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(raw); err == nil {
+			value = b
+		}
+	}
+	return v.checkValue(schema, value, field, errs)
+}
+
+// checkValue validates a decoded JSON value against schema, appending a violation for each failed check
+func (v *Validator) checkValue(schema *Schema, value any, field string, errs []domainErr.FieldError) []domainErr.FieldError {
+	if schema == nil {
+		return errs
+	}
+	for _, s := range schema.AllOf {
+		errs = v.checkValue(s, value, field, errs)
+	}
+	schema = v.resolve(schema)
+	fail := func(message string) []domainErr.FieldError {
+		return append(errs, domainErr.FieldError{Field: field, Message: message})
+	}
+
+	if value == nil {
+		if schema.Type != "" && !schema.Nullable {
+			return fail("must not be null")
+		}
+		return errs
+	}
+
+	switch schema.Type {
+	case "object":
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return fail(mustBe[schema.Type])
+		}
+		for _, name := range schema.Required {
+			if _, ok := obj[name]; !ok {
+				errs = append(errs, domainErr.FieldError{Field: join(field, name), Message: "is required"})
+			}
+		}
+		for _, name := range sortedKeys(obj) {
+			prop, declared := schema.Properties[name]
+			switch {
+			case declared:
+				errs = v.checkValue(prop, obj[name], join(field, name), errs)
+			case schema.AdditionalProperties == nil:
+			case schema.AdditionalProperties.Schema == nil:
+				errs = append(errs, domainErr.FieldError{Field: join(field, name), Message: "is not a known field"})
+			default:
+				errs = v.checkValue(schema.AdditionalProperties.Schema, obj[name], join(field, name), errs)
+			}
+		}
+		return errs
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			return fail(mustBe[schema.Type])
+		}
+		for i, item := range items {
+			errs = v.checkValue(schema.Items, item, fmt.Sprintf("%s[%d]", field, i), errs)
+		}
+		return errs
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return fail(mustBe[schema.Type])
+		}
+		if message := checkString(schema, s); message != "" {
+			return fail(message)
+		}
+	case "integer", "number":
+		n, ok := value.(json.Number)
+		if !ok {
+			return fail(mustBe[schema.Type])
+		}
+		if message := checkNumber(schema, n); message != "" {
+			return fail(message)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fail(mustBe[schema.Type])
+		}
+	}
+
+	if len(schema.Enum) > 0 && !contains(schema.Enum, value) {
+		return fail(fmt.Sprintf("must be one of %v", schema.Enum))
+	}
+	return errs
+}
+
+// checkString returns why s does not satisfy the length and format of schema, if it does not
+func checkString(schema *Schema, s string) string {
+	n := utf8.RuneCountInString(s)
+	switch {
+	case schema.MinLength != nil && schema.MaxLength != nil && (n < *schema.MinLength || n > *schema.MaxLength):
+		return fmt.Sprintf("must be between %d and %d characters", *schema.MinLength, *schema.MaxLength)
+	case schema.MinLength != nil && *schema.MinLength == 1 && n == 0:
+		return "must not be empty"
+	case schema.MinLength != nil && n < *schema.MinLength:
+		return fmt.Sprintf("must be at least %d characters", *schema.MinLength)
+	case schema.MaxLength != nil && n > *schema.MaxLength:
+		return fmt.Sprintf("must be at most %d characters", *schema.MaxLength)
+	}
+
+	switch schema.Format {
+	case "email":
+		if addr, err := mail.ParseAddress(s); err != nil || addr.Address != s {
+			return "must be a valid email address"
+		}
+	case "uri":
+		if u, err := url.ParseRequestURI(s); err != nil || u.Scheme == "" || u.Host == "" {
+			return "must be an absolute URL"
+		}
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, s); err != nil {
+			return "must be an RFC 3339 date-time"
+		}
+	}
+	return ""
+}
+
+// checkNumber returns why n is not a number of the type and range of schema, if it is not
+func checkNumber(schema *Schema, n json.Number) string {
+	f, err := n.Float64()
+	if err != nil {
+		return mustBe[schema.Type]
+	}
+	if _, err := n.Int64(); err != nil && schema.Type == "integer" {
+		return mustBe[schema.Type]
+	}
+	switch {
+	case schema.Minimum != nil && schema.Maximum != nil && (f < *schema.Minimum || f > *schema.Maximum):
+		return fmt.Sprintf("must be between %v and %v", *schema.Minimum, *schema.Maximum)
+	case schema.Minimum != nil && f < *schema.Minimum:
+		return fmt.Sprintf("must be at least %v", *schema.Minimum)
+	case schema.Maximum != nil && f > *schema.Maximum:
+		return fmt.Sprintf("must be at most %v", *schema.Maximum)
+	}
+	return ""
+}
+
+// resolve follows the reference of s to its component
+func (v *Validator) resolve(s *Schema) *Schema {
+	for s != nil && s.Ref != "" {
+		s = v.doc.Components.Schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]
+	}
+	if s == nil {
+		return &Schema{}
+	}
+	return s
+}
+
+func join(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+func contains(values []any, value any) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
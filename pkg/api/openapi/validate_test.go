@@ -0,0 +1,214 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	mw "github.com/Crud-application/pkg/api/middleware"
+	"github.com/Crud-application/pkg/contracts/problem"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// validatedEngine routes a few API operations through the validator to a handler echoing the body
+func validatedEngine(t *testing.T) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	v, err := NewValidator(Spec)
+	if err != nil {
+		t.Fatalf("NewValidator() error = %v", err)
+	}
+	echo := func(c *gin.Context) {
+		var body map[string]any
+		_ = c.ShouldBindJSON(&body)
+		c.JSON(http.StatusOK, body)
+	}
+
+	engine := gin.New()
+	engine.Use(mw.Problems())
+	api := engine.Group("/api", v.Middleware("/api"))
+	api.POST("/users", echo)
+	api.GET("/users", echo)
+	api.PATCH("/users/:userID", echo)
+	api.GET("/users/:userID/history", echo)
+	api.POST("/webhooks", echo)
+	api.GET("/undocumented", echo)
+	return engine
+}
+
+func TestValidator(t *testing.T) {
+	tests := []struct {
+		id          int
+		name        string
+		method      string
+		target      string
+		contentType string // application/json when empty
+		body        string
+		wantStatus  int
+		wantErrors  []problem.FieldError
+		wantBody    string
+	}{
+		{
+			id:         1,
+			name:       "Valid create - passed to the handler with its body",
+			method:     http.MethodPost,
+			target:     "/api/users",
+			body:       `{"name":"Alam","email":"alam@example.com","phone_number":"+919876543210"}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"name":"Alam","email":"alam@example.com","phone_number":"+919876543210"}`,
+		},
+		{
+			id:         2,
+			name:       "Create with missing and malformed fields - 422 listing each",
+			method:     http.MethodPost,
+			target:     "/api/users",
+			body:       `{"email":"not-an-email","phone_number":9876543210}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantErrors: []problem.FieldError{
+				{Field: "name", Message: "is required"},
+				{Field: "email", Message: "must be a valid email address"},
+				{Field: "phone_number", Message: "must be a string"},
+			},
+		},
+		{
+			id:         3,
+			name:       "Create with an unknown field - 422",
+			method:     http.MethodPost,
+			target:     "/api/users",
+			body:       `{"name":"Alam","email":"alam@example.com","phone_number":"+919876543210","role":"admin"}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantErrors: []problem.FieldError{{Field: "role", Message: "is not a known field"}},
+		},
+		{
+			id:         4,
+			name:       "Update with an empty name and a malformed email - 422",
+			method:     http.MethodPatch,
+			target:     "/api/users/42",
+			body:       `{"name":"","email":"alam@"}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantErrors: []problem.FieldError{
+				{Field: "email", Message: "must be a valid email address"},
+				{Field: "name", Message: "must be between 1 and 100 characters"},
+			},
+		},
+		{
+			id:         5,
+			name:       "Update with some fields - passed to the handler",
+			method:     http.MethodPatch,
+			target:     "/api/users/42",
+			body:       `{"phone_number":"+919876543210"}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"phone_number":"+919876543210"}`,
+		},
+		{
+			id:         6,
+			name:       "Malformed JSON - 400",
+			method:     http.MethodPatch,
+			target:     "/api/users/42",
+			body:       `{"name":`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			id:         7,
+			name:       "Missing body - 400",
+			method:     http.MethodPost,
+			target:     "/api/users",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			id:         8,
+			name:       "Query parameters of the wrong type and out of range - 422",
+			method:     http.MethodGet,
+			target:     "/api/users?limit=500&offset=-1&include_deleted=maybe",
+			wantStatus: http.StatusUnprocessableEntity,
+			wantErrors: []problem.FieldError{
+				{Field: "limit", Message: "must be between 0 and 100"},
+				{Field: "offset", Message: "must be at least 0"},
+				{Field: "include_deleted", Message: "must be a boolean"},
+			},
+		},
+		{
+			id:         9,
+			name:       "List with filters - free-form parameters are allowed",
+			method:     http.MethodGet,
+			target:     "/api/users?limit=10&name~=al&created_at%3E=2024-05-01",
+			wantStatus: http.StatusOK,
+		},
+		{
+			id:         10,
+			name:       "Unknown query parameter - 422",
+			method:     http.MethodGet,
+			target:     "/api/users/42/history?limit=x&page=2",
+			wantStatus: http.StatusUnprocessableEntity,
+			wantErrors: []problem.FieldError{
+				{Field: "limit", Message: "must be an integer"},
+				{Field: "page", Message: "is not a known parameter"},
+			},
+		},
+		{
+			id:         11,
+			name:       "Webhook with a relative URL and a short secret - 422",
+			method:     http.MethodPost,
+			target:     "/api/webhooks",
+			body:       `{"url":"/hooks","secret":"short","events":["user.created",1]}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantErrors: []problem.FieldError{
+				{Field: "events[1]", Message: "must be a string"},
+				{Field: "secret", Message: "must be at least 16 characters"},
+				{Field: "url", Message: "must be an absolute URL"},
+			},
+		},
+		{
+			id:          12,
+			name:        "JSON Patch with an unknown op - 422",
+			method:      http.MethodPatch,
+			target:      "/api/users/42",
+			contentType: "application/json-patch+json",
+			body:        `[{"op":"replace","path":"/name","value":"Alam"},{"op":"merge","path":"/email"}]`,
+			wantStatus:  http.StatusUnprocessableEntity,
+			wantErrors:  []problem.FieldError{{Field: "[1].op", Message: "must be one of [add remove replace move copy test]"}},
+		},
+		{
+			id:          13,
+			name:        "Merge patch clearing a field - checked as an object only",
+			method:      http.MethodPatch,
+			target:      "/api/users/42",
+			contentType: "application/merge-patch+json",
+			body:        `{"phone_number":null,"role":"admin"}`,
+			wantStatus:  http.StatusOK,
+		},
+		{
+			id:         14,
+			name:       "Route without an operation - not checked",
+			method:     http.MethodGet,
+			target:     "/api/undocumented?anything=1",
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	engine := validatedEngine(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			contentType := "application/json"
+			if tt.contentType != "" {
+				contentType = tt.contentType
+			}
+			req.Header.Set("Content-Type", contentType)
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code, "id %d: %s", tt.id, w.Body.String())
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, w.Body.String())
+			}
+			if tt.wantStatus >= http.StatusBadRequest {
+				var p problem.Problem
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+				assert.Equal(t, tt.wantErrors, p.Errors)
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	uApp "github.com/Crud-application/pkg/application/user"
+	aCOntr "github.com/Crud-application/pkg/contracts/auth"
+	uCOntr "github.com/Crud-application/pkg/contracts/user"
+	"github.com/Crud-application/pkg/domain/auditAgg"
+	"github.com/Crud-application/pkg/domain/authAgg"
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"github.com/Crud-application/pkg/domain/mail"
+	repoInter "github.com/Crud-application/pkg/domain/persistence"
+	uAgg "github.com/Crud-application/pkg/domain/userAgg"
+)
+
+// Verify that AccountService implements EmailVerifier
+var _ uApp.EmailVerifier = (*AccountService)(nil)
+
+// fieldToken names the token of an emailed link in validation errors
+const fieldToken = "token"
+
+// Paths, under the link base URL, of the pages the emailed links open
+const (
+	verifyEmailPath   = "/verify-email"
+	resetPasswordPath = "/reset-password"
+)
+
+const verifyEmailBody = `Hello %s,
+
+Please confirm that this is your email address by opening this link:
+
+%s
+
+The link works for %s. If you did not sign up, you can ignore this email.
+`
+
+const resetPasswordBody = `Hello %s,
+
+Someone asked to reset the password of your account. To choose a new password, open this link:
+
+%s
+
+The link works for %s and only once. If you did not ask for it, you can ignore this email;
+your password stays the same.
+`
+
+// AccountService emails users signed links to verify their email and reset their password,
+// and acts on them. The tokens are not stored: they are bound to the state of the user.
+type AccountService struct {
+	users       repoInter.IUserRepository
+	tokens      repoInter.IRefreshTokenRepository
+	mailer      mail.Mailer
+	signer      *authAgg.LinkTokenSigner
+	linkBaseURL string
+	verifyTTL   time.Duration
+	resetTTL    time.Duration
+	now         uApp.Clock
+}
+
+func NewAccountService(users repoInter.IUserRepository, tokens repoInter.IRefreshTokenRepository,
+	mailer mail.Mailer, signer *authAgg.LinkTokenSigner, linkBaseURL string, verifyTTL, resetTTL time.Duration, now uApp.Clock) *AccountService {
+	return &AccountService{
+		users:       users,
+		tokens:      tokens,
+		mailer:      mailer,
+		signer:      signer,
+		linkBaseURL: strings.TrimSuffix(linkBaseURL, "/"),
+		verifyTTL:   verifyTTL,
+		resetTTL:    resetTTL,
+		now:         now,
+	}
+}
+
+// SendVerification emails the user a link verifying their email, unless it is verified already
+func (s *AccountService) SendVerification(ctx context.Context, user *uAgg.User) error {
+	if user.EmailVerified {
+		return nil
+	}
+	token := s.signer.Sign(authAgg.PurposeVerifyEmail, user.ID, user.Email.String(), s.now().Add(s.verifyTTL))
+	return s.mailer.Send(ctx, mail.Message{
+		To:      user.Email.String(),
+		Subject: "Verify your email",
+		Body:    fmt.Sprintf(verifyEmailBody, user.Name, s.link(verifyEmailPath, token), inWords(s.verifyTTL)),
+	})
+}
+
+// VerifyEmail marks the email a verification token was sent to as verified. The token
+// stops working once the email of the user changes.
+func (s *AccountService) VerifyEmail(ctx context.Context, req *uCOntr.VerifyEmailReq) error {
+	user, token, err := s.userOfToken(ctx, req.Token, authAgg.PurposeVerifyEmail)
+	if err != nil {
+		return err
+	}
+	if !s.signer.BoundTo(token, user.Email.String()) {
+		return invalidToken(authAgg.ErrLinkTokenInvalid)
+	}
+	if user.EmailVerified {
+		return nil
+	}
+
+	before, now := user.AuditSnapshot(), s.now()
+	user.VerifyEmail(now, user.ID)
+	uApp.RecordAudit(ctx, user, auditAgg.ActionUpdate, auditAgg.Diff(before, user.AuditSnapshot()))
+	if err := s.users.UpdateUser(ctx, user); err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+	return nil
+}
+
+// ForgotPassword emails a password reset link to the user with the given email, unless their
+// account is blocked. Nothing tells the caller whether there is one, so it cannot be used to
+// find out who has an account.
+func (s *AccountService) ForgotPassword(ctx context.Context, req *aCOntr.ForgotPasswordReq) error {
+	email, err := uAgg.NewEmail(req.Email)
+	if err != nil {
+		return nil
+	}
+	user, err := s.users.GetUserByEmail(ctx, email.String())
+	if errors.Is(err, domainErr.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.Status.Blocked() {
+		return nil
+	}
+
+	token := s.signer.Sign(authAgg.PurposeResetPassword, user.ID, resetState(user), s.now().Add(s.resetTTL))
+	err = s.mailer.Send(ctx, mail.Message{
+		To:      user.Email.String(),
+		Subject: "Reset your password",
+		Body:    fmt.Sprintf(resetPasswordBody, user.Name, s.link(resetPasswordPath, token), inWords(s.resetTTL)),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send password reset email: %w", err)
+	}
+	return nil
+}
+
+// ResetPassword sets the password of the user a reset token was sent to and ends all their
+// sessions, though access tokens already issued stay valid until they expire. Users
+// without a password get one. The token stops working once it is used, and
+// does not work while the account is blocked.
+func (s *AccountService) ResetPassword(ctx context.Context, req *aCOntr.ResetPasswordReq) error {
+	user, token, err := s.userOfToken(ctx, req.Token, authAgg.PurposeResetPassword)
+	if err != nil {
+		return err
+	}
+	if !s.signer.BoundTo(token, resetState(user)) || user.Status.Blocked() {
+		return invalidToken(authAgg.ErrLinkTokenInvalid)
+	}
+
+	now := s.now()
+	if err := user.ResetPassword(req.NewPassword, now, user.ID); err != nil {
+		return fmt.Errorf("request validation failed: %w", err)
+	}
+	uApp.RecordAudit(ctx, user, auditAgg.ActionUpdate, []auditAgg.FieldChange{{Field: uAgg.FieldPassword}})
+	if err := s.users.UpdateUser(ctx, user); err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+
+	if err := s.tokens.RevokeUserRefreshTokens(ctx, user.ID, now); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	return nil
+}
+
+// userOfToken checks a link token made for purpose and loads the user it names
+func (s *AccountService) userOfToken(ctx context.Context, raw string, purpose authAgg.Purpose) (*uAgg.User, *authAgg.LinkToken, error) {
+	token, err := s.signer.Parse(raw, purpose, s.now())
+	if err != nil {
+		return nil, nil, invalidToken(err)
+	}
+	user, err := s.users.GetUser(ctx, token.UserID)
+	if errors.Is(err, domainErr.ErrNotFound) {
+		return nil, nil, invalidToken(authAgg.ErrLinkTokenInvalid)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return user, token, nil
+}
+
+// link returns the URL of the page at path, carrying token
+func (s *AccountService) link(path, token string) string {
+	return s.linkBaseURL + path + "?token=" + url.QueryEscape(token)
+}
+
+// resetState is what a reset token is bound to: it must still be sent to the same
+// email, and setting a new password uses it up
+func resetState(user *uAgg.User) string {
+	return user.Email.String() + "\x00" + user.PasswordHash
+}
+
+// invalidToken reports a rejected link token as a validation error of the token field
+func invalidToken(reason error) error {
+	return domainErr.Validation("invalid token", domainErr.FieldError{Field: fieldToken, Message: reason.Error()})
+}
+
+// inWords tells how long d is for an email, in whole hours or else minutes
+func inWords(d time.Duration) string {
+	n, unit := int(d/time.Minute), "minute"
+	if d >= time.Hour && d%time.Hour == 0 {
+		n, unit = int(d/time.Hour), "hour"
+	}
+	if n != 1 {
+		unit += "s"
+	}
+	return strconv.Itoa(n) + " " + unit
+}
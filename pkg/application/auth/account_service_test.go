@@ -0,0 +1,239 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	aCOntr "github.com/Crud-application/pkg/contracts/auth"
+	uCOntr "github.com/Crud-application/pkg/contracts/user"
+	"github.com/Crud-application/pkg/domain/authAgg"
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"github.com/Crud-application/pkg/domain/mail"
+	mailMocks "github.com/Crud-application/pkg/domain/mail/mocks"
+	mockRepo "github.com/Crud-application/pkg/domain/persistence/mocks"
+	uAgg "github.com/Crud-application/pkg/domain/userAgg"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+const testLinkSecret = "0123456789abcdef0123456789abcdef"
+
+// accountMocks are the dependencies of an AccountService under test
+type accountMocks struct {
+	sessionMocks
+	mailer *mailMocks.MockMailer
+}
+
+func newAccountService(t *testing.T) (*AccountService, accountMocks) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+	m := accountMocks{
+		sessionMocks: sessionMocks{
+			users:  mockRepo.NewMockIUserRepository(ctrl),
+			tokens: mockRepo.NewMockIRefreshTokenRepository(ctrl),
+		},
+		mailer: mailMocks.NewMockMailer(ctrl),
+	}
+	s := NewAccountService(m.users, m.tokens, m.mailer, authAgg.NewLinkTokenSigner([]byte(testLinkSecret)),
+		"https://app.test/", 48*time.Hour, time.Hour, func() time.Time { return testNow })
+	return s, m
+}
+
+var linkToken = regexp.MustCompile(`https://app\.test/[a-z-]+\?token=(\S+)`)
+
+// tokenOf returns the token of the link in an email
+func tokenOf(t *testing.T, msg mail.Message) string {
+	match := linkToken.FindStringSubmatch(msg.Body)
+	if match == nil {
+		t.Fatalf("no link in %q", msg.Body)
+	}
+	token, err := url.QueryUnescape(match[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestAccountService_SendVerification(t *testing.T) {
+	s, m := newAccountService(t)
+	var sent mail.Message
+	m.mailer.EXPECT().Send(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, msg mail.Message) error {
+		sent = msg
+		return nil
+	})
+
+	if err := s.SendVerification(context.Background(), &uAgg.User{ID: "u1", Email: "alam@example.com"}); err != nil {
+		t.Fatalf("SendVerification() error = %v", err)
+	}
+	assert.Equal(t, "alam@example.com", sent.To)
+	assert.Contains(t, sent.Body, "https://app.test/verify-email?token=")
+	assert.Contains(t, sent.Body, "works for 48 hours")
+
+	token, err := s.signer.Parse(tokenOf(t, sent), authAgg.PurposeVerifyEmail, testNow)
+	if err != nil {
+		t.Fatalf("emailed token: %v", err)
+	}
+	assert.Equal(t, "u1", token.UserID)
+	assert.Equal(t, testNow.Add(48*time.Hour), token.ExpiresAt)
+
+	// A verified email gets no mail
+	if err := s.SendVerification(context.Background(), &uAgg.User{ID: "u1", EmailVerified: true}); err != nil {
+		t.Errorf("SendVerification() of a verified user error = %v", err)
+	}
+}
+
+func TestAccountService_VerifyEmail(t *testing.T) {
+	s, _ := newAccountService(t)
+	sign := func(purpose authAgg.Purpose, email string, expiresAt time.Time) string {
+		return s.signer.Sign(purpose, "u1", email, expiresAt)
+	}
+	valid := sign(authAgg.PurposeVerifyEmail, "alam@example.com", testNow.Add(time.Hour))
+
+	tests := []struct {
+		id         int
+		name       string
+		token      string
+		beforeTest func(m accountMocks)
+		wantErr    error
+	}{
+		{
+			id:    1,
+			name:  "Valid token - email verified",
+			token: valid,
+			beforeTest: func(m accountMocks) {
+				m.users.EXPECT().GetUser(gomock.Any(), "u1").Return(&uAgg.User{ID: "u1", Email: "alam@example.com"}, nil)
+				m.users.EXPECT().UpdateUser(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, u *uAgg.User) error {
+					assert.True(t, u.EmailVerified)
+					assert.Equal(t, "u1", u.UpdatedBy)
+					assert.Len(t, u.AuditEntries, 1)
+					return nil
+				})
+			},
+		},
+		{
+			id:    2,
+			name:  "Email verified already - nothing to do",
+			token: valid,
+			beforeTest: func(m accountMocks) {
+				m.users.EXPECT().GetUser(gomock.Any(), "u1").Return(&uAgg.User{ID: "u1", Email: "alam@example.com", EmailVerified: true}, nil)
+			},
+		},
+		{
+			id:    3,
+			name:  "Email changed since - validation error",
+			token: valid,
+			beforeTest: func(m accountMocks) {
+				m.users.EXPECT().GetUser(gomock.Any(), "u1").Return(&uAgg.User{ID: "u1", Email: "new@example.com"}, nil)
+			},
+			wantErr: domainErr.ErrValidation,
+		},
+		{
+			id:         4,
+			name:       "Expired token - validation error",
+			token:      sign(authAgg.PurposeVerifyEmail, "alam@example.com", testNow),
+			beforeTest: func(m accountMocks) {},
+			wantErr:    domainErr.ErrValidation,
+		},
+		{
+			id:         5,
+			name:       "Password reset token - validation error",
+			token:      sign(authAgg.PurposeResetPassword, "alam@example.com", testNow.Add(time.Hour)),
+			beforeTest: func(m accountMocks) {},
+			wantErr:    domainErr.ErrValidation,
+		},
+		{
+			id:    6,
+			name:  "User deleted - validation error",
+			token: valid,
+			beforeTest: func(m accountMocks) {
+				m.users.EXPECT().GetUser(gomock.Any(), "u1").Return(nil, domainErr.NotFound("user u1 not found"))
+			},
+			wantErr: domainErr.ErrValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, m := newAccountService(t)
+			tt.beforeTest(m)
+
+			if err := s.VerifyEmail(context.Background(), &uCOntr.VerifyEmailReq{Token: tt.token}); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ID %v VerifyEmail() error = %v, wantErr %v", tt.id, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAccountService_PasswordReset(t *testing.T) {
+	s, m := newAccountService(t)
+	user := userWithPassword(t)
+	var sent mail.Message
+	m.users.EXPECT().GetUserByEmail(gomock.Any(), "alam@example.com").Return(user, nil)
+	m.mailer.EXPECT().Send(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, msg mail.Message) error {
+		sent = msg
+		return nil
+	})
+
+	if err := s.ForgotPassword(context.Background(), &aCOntr.ForgotPasswordReq{Email: "Alam@Example.com"}); err != nil {
+		t.Fatalf("ForgotPassword() error = %v", err)
+	}
+	assert.Contains(t, sent.Body, "https://app.test/reset-password?token=")
+	assert.Contains(t, sent.Body, "works for 1 hour and")
+	token := tokenOf(t, sent)
+
+	m.users.EXPECT().GetUser(gomock.Any(), "u1").Return(user, nil).Times(2)
+	m.users.EXPECT().UpdateUser(gomock.Any(), user).Return(nil)
+	m.tokens.EXPECT().RevokeUserRefreshTokens(gomock.Any(), "u1", testNow).Return(nil)
+
+	if err := s.ResetPassword(context.Background(), &aCOntr.ResetPasswordReq{Token: token, NewPassword: "battery staple"}); err != nil {
+		t.Fatalf("ResetPassword() error = %v", err)
+	}
+	assert.True(t, user.CheckPassword("battery staple"))
+	if assert.Len(t, user.AuditEntries, 1) {
+		assert.Equal(t, "u1", user.AuditEntries[0].Actor)
+	}
+
+	// The new password uses the token up
+	err := s.ResetPassword(context.Background(), &aCOntr.ResetPasswordReq{Token: token, NewPassword: "another password"})
+	if !errors.Is(err, domainErr.ErrValidation) {
+		t.Errorf("ResetPassword() with a used token error = %v, want validation error", err)
+	}
+}
+
+func TestAccountService_ResetPassword_BlockedUser(t *testing.T) {
+	s, m := newAccountService(t)
+	user := userWithPassword(t)
+	token := s.signer.Sign(authAgg.PurposeResetPassword, user.ID, resetState(user), testNow.Add(time.Hour))
+	user.Status = uAgg.StatusSuspended
+	m.users.EXPECT().GetUser(gomock.Any(), "u1").Return(user, nil)
+
+	err := s.ResetPassword(context.Background(), &aCOntr.ResetPasswordReq{Token: token, NewPassword: "battery staple"})
+	if !errors.Is(err, domainErr.ErrValidation) {
+		t.Errorf("ResetPassword() of a suspended user error = %v, want validation error", err)
+	}
+	assert.False(t, user.CheckPassword("battery staple"))
+}
+
+func TestAccountService_ForgotPassword_UnknownEmail(t *testing.T) {
+	s, m := newAccountService(t)
+	m.users.EXPECT().GetUserByEmail(gomock.Any(), "nobody@example.com").Return(nil, domainErr.NotFound("no user"))
+
+	if err := s.ForgotPassword(context.Background(), &aCOntr.ForgotPasswordReq{Email: "nobody@example.com"}); err != nil {
+		t.Errorf("ForgotPassword() of an unknown email error = %v, want none", err)
+	}
+}
+
+func TestAccountService_ForgotPassword_BlockedUser(t *testing.T) {
+	s, m := newAccountService(t)
+	user := userWithPassword(t)
+	user.Status = uAgg.StatusSuspended
+	m.users.EXPECT().GetUserByEmail(gomock.Any(), "alam@example.com").Return(user, nil)
+
+	if err := s.ForgotPassword(context.Background(), &aCOntr.ForgotPasswordReq{Email: "alam@example.com"}); err != nil {
+		t.Errorf("ForgotPassword() of a suspended user error = %v, want none", err)
+	}
+}
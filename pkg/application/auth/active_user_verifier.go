@@ -0,0 +1,46 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Crud-application/pkg/domain/authAgg"
+	"github.com/Crud-application/pkg/domain/domainErr"
+	repoInter "github.com/Crud-application/pkg/domain/persistence"
+)
+
+// errInactiveUser is returned for the valid credentials of a user who may no longer call the API
+var errInactiveUser = domainErr.Unauthenticated("user is deleted, purged, suspended or deactivated")
+
+// ActiveUserVerifier checks the current state of the user a credential belongs to on
+// every request, so users deleted, suspended or deactivated after their token was issued
+// are locked out at once rather than when it expires. Subjects without a user here, such
+// as purged users, are rejected too; API keys belong to no user and are revoked on their own.
+type ActiveUserVerifier struct {
+	Verifier
+	users repoInter.IUserRepository
+}
+
+func NewActiveUserVerifier(v Verifier, users repoInter.IUserRepository) *ActiveUserVerifier {
+	return &ActiveUserVerifier{Verifier: v, users: users}
+}
+
+// Verify returns the principal of the credential unless its user is deleted or blocked
+func (v *ActiveUserVerifier) Verify(ctx context.Context, credential string) (*authAgg.Principal, error) {
+	p, err := v.Verifier.Verify(ctx, credential)
+	if err != nil || p.Kind != authAgg.KindUser {
+		return p, err
+	}
+	user, err := v.users.GetUserIncludingDeleted(ctx, p.ID)
+	if errors.Is(err, domainErr.ErrNotFound) {
+		return nil, errInactiveUser
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.DeletedAt != nil || user.Status.Blocked() {
+		return nil, errInactiveUser
+	}
+	return p, nil
+}
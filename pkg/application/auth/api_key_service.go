@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	uApp "github.com/Crud-application/pkg/application/user"
+	"github.com/Crud-application/pkg/domain/authAgg"
+	"github.com/Crud-application/pkg/domain/domainErr"
+	kRepo "github.com/Crud-application/pkg/domain/persistence"
+)
+
+// APIKeyService issues static API keys and verifies them by their hash
+type APIKeyService struct {
+	repo         kRepo.IAPIKeyRepository
+	generateUUID uApp.UUIDGenerator
+	now          uApp.Clock
+}
+
+func NewAPIKeyService(repo kRepo.IAPIKeyRepository, generateUUID uApp.UUIDGenerator, now uApp.Clock) *APIKeyService {
+	return &APIKeyService{
+		repo:         repo,
+		generateUUID: generateUUID,
+		now:          now,
+	}
+}
+
+// Scheme implements Verifier
+func (s *APIKeyService) Scheme() string {
+	return SchemeAPIKey
+}
+
+// Verify returns the principal of an active API key
+func (s *APIKeyService) Verify(ctx context.Context, key string) (*authAgg.Principal, error) {
+	if !strings.HasPrefix(key, authAgg.KeyPrefix) {
+		return nil, domainErr.Unauthenticated("invalid API key")
+	}
+	k, err := s.repo.GetAPIKeyByHash(ctx, authAgg.HashKey(key))
+	if errors.Is(err, domainErr.ErrNotFound) || (err == nil && !k.Active()) {
+		return nil, domainErr.Unauthenticated("invalid API key")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+	return k.Principal(), nil
+}
+
+// CreateAPIKey issues a key granting roles. The key is returned only this once.
+func (s *APIKeyService) CreateAPIKey(ctx context.Context, name string, roles []string) (*authAgg.APIKey, string, error) {
+	k, key, err := authAgg.NewAPIKey(s.generateUUID(), name, roles, s.now())
+	if err != nil {
+		return nil, "", err
+	}
+	if err := s.repo.AddAPIKey(ctx, k); err != nil {
+		return nil, "", fmt.Errorf("failed to add API key: %w", err)
+	}
+	return k, key, nil
+}
+
+// RevokeAPIKey stops the key with the given ID from authenticating
+func (s *APIKeyService) RevokeAPIKey(ctx context.Context, id string) error {
+	if err := s.repo.RevokeAPIKey(ctx, id, s.now()); err != nil {
+		return fmt.Errorf("failed to revoke API key: %w", err)
+	}
+	return nil
+}
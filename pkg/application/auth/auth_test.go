@@ -0,0 +1,505 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/Crud-application/pkg/domain/authAgg"
+	"github.com/Crud-application/pkg/domain/domainErr"
+	mockRepo "github.com/Crud-application/pkg/domain/persistence/mocks"
+	uAgg "github.com/Crud-application/pkg/domain/userAgg"
+	"github.com/Crud-application/pkg/infrastructure/persistence/audit"
+	"github.com/Crud-application/pkg/infrastructure/persistence/outbox"
+	uPersist "github.com/Crud-application/pkg/infrastructure/persistence/user"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+var testNow = time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+const (
+	testIssuer   = "https://auth.example.com"
+	testAudience = "crud-api"
+	testSecret   = "0123456789abcdef0123456789abcdef"
+)
+
+// sign issues a token for subject 42 from the test issuer, with changes made by edit
+func sign(t *testing.T, method jwt.SigningMethod, key any, kid string, edit func(c jwt.MapClaims)) string {
+	c := jwt.MapClaims{
+		"iss":   testIssuer,
+		"aud":   testAudience,
+		"sub":   "42",
+		"exp":   testNow.Add(time.Hour).Unix(),
+		"roles": []string{"admin"},
+	}
+	if edit != nil {
+		edit(c)
+	}
+	token := jwt.NewWithClaims(method, c)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func TestJWTVerifier_Verify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	v := NewJWTVerifier(testIssuer, testAudience, []byte(testSecret), map[string]*rsa.PublicKey{"k1": &rsaKey.PublicKey}, func() time.Time { return testNow })
+	hsOnly := NewJWTVerifier(testIssuer, "", []byte(testSecret), nil, func() time.Time { return testNow })
+
+	tests := []struct {
+		id       int
+		name     string
+		verifier *JWTVerifier
+		token    string
+		wantErr  error
+	}{
+		{
+			id:    1,
+			name:  "HS256 token - success",
+			token: sign(t, jwt.SigningMethodHS256, []byte(testSecret), "", nil),
+		},
+		{
+			id:    2,
+			name:  "RS256 token of a known key - success",
+			token: sign(t, jwt.SigningMethodRS256, rsaKey, "k1", nil),
+		},
+		{
+			id:      3,
+			name:    "RS256 token of an unknown key - failure",
+			token:   sign(t, jwt.SigningMethodRS256, otherKey, "k2", nil),
+			wantErr: domainErr.ErrUnauthenticated,
+		},
+		{
+			id:      4,
+			name:    "RS256 token signed with another key - failure",
+			token:   sign(t, jwt.SigningMethodRS256, otherKey, "k1", nil),
+			wantErr: domainErr.ErrUnauthenticated,
+		},
+		{
+			id:      5,
+			name:    "Expired token - failure",
+			token:   sign(t, jwt.SigningMethodHS256, []byte(testSecret), "", func(c jwt.MapClaims) { c["exp"] = testNow.Add(-time.Minute).Unix() }),
+			wantErr: domainErr.ErrUnauthenticated,
+		},
+		{
+			id:    6,
+			name:  "Expired within the allowed clock skew - success",
+			token: sign(t, jwt.SigningMethodHS256, []byte(testSecret), "", func(c jwt.MapClaims) { c["exp"] = testNow.Add(-10 * time.Second).Unix() }),
+		},
+		{
+			id:      7,
+			name:    "Token without an expiry - failure",
+			token:   sign(t, jwt.SigningMethodHS256, []byte(testSecret), "", func(c jwt.MapClaims) { delete(c, "exp") }),
+			wantErr: domainErr.ErrUnauthenticated,
+		},
+		{
+			id:      8,
+			name:    "Another issuer - failure",
+			token:   sign(t, jwt.SigningMethodHS256, []byte(testSecret), "", func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }),
+			wantErr: domainErr.ErrUnauthenticated,
+		},
+		{
+			id:      9,
+			name:    "Another audience - failure",
+			token:   sign(t, jwt.SigningMethodHS256, []byte(testSecret), "", func(c jwt.MapClaims) { c["aud"] = "other-api" }),
+			wantErr: domainErr.ErrUnauthenticated,
+		},
+		{
+			id:      10,
+			name:    "Token without a subject - failure",
+			token:   sign(t, jwt.SigningMethodHS256, []byte(testSecret), "", func(c jwt.MapClaims) { delete(c, "sub") }),
+			wantErr: domainErr.ErrUnauthenticated,
+		},
+		{
+			id:       11,
+			name:     "RS256 token when only HS256 is configured - failure",
+			verifier: hsOnly,
+			token:    sign(t, jwt.SigningMethodRS256, rsaKey, "k1", nil),
+			wantErr:  domainErr.ErrUnauthenticated,
+		},
+		{
+			id:      12,
+			name:    "Unsigned token - failure",
+			token:   sign(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, "", nil),
+			wantErr: domainErr.ErrUnauthenticated,
+		},
+		{
+			id:      13,
+			name:    "Malformed token - failure",
+			token:   "not.a.token",
+			wantErr: domainErr.ErrUnauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier := v
+			if tt.verifier != nil {
+				verifier = tt.verifier
+			}
+
+			got, err := verifier.Verify(context.Background(), tt.token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ID %v Verify() error = %v, wantErr %v", tt.id, err, tt.wantErr)
+			}
+			if err == nil {
+				assert.Equal(t, &authAgg.Principal{ID: "42", Kind: authAgg.KindUser, Roles: []string{"admin"}}, got)
+			}
+		})
+	}
+}
+
+func TestParseJWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := base64.RawURLEncoding.EncodeToString(rsaKey.N.Bytes())
+	e := base64.RawURLEncoding.EncodeToString(big.NewInt(int64(rsaKey.E)).Bytes())
+	data := fmt.Sprintf(`{"keys":[
+		{"kty":"RSA","kid":"k1","use":"sig","alg":"RS256","n":%q,"e":%q},
+		{"kty":"RSA","kid":"enc","use":"enc","n":%q,"e":%q},
+		{"kty":"EC","kid":"ec","crv":"P-256","x":"x","y":"y"}
+	]}`, n, e, n, e)
+
+	keys, err := ParseJWKS([]byte(data))
+	if err != nil {
+		t.Fatalf("ParseJWKS() error = %v", err)
+	}
+	if len(keys) != 1 || !keys["k1"].Equal(&rsaKey.PublicKey) {
+		t.Errorf("ParseJWKS() = %v, want only the RSA signing key k1", keys)
+	}
+
+	for _, bad := range []string{`{`, `{"keys":[]}`, `{"keys":[{"kty":"RSA","kid":"k1","n":"!","e":"AQAB"}]}`} {
+		if _, err := ParseJWKS([]byte(bad)); err == nil {
+			t.Errorf("ParseJWKS(%s) error = nil, want an error", bad)
+		}
+	}
+}
+
+func TestAPIKeyService_Verify(t *testing.T) {
+	const key = authAgg.KeyPrefix + "secret"
+	revokedAt := testNow.Add(-time.Hour)
+	tests := []struct {
+		id         int
+		name       string
+		key        string
+		beforeTest func(repo *mockRepo.MockIAPIKeyRepository)
+		wantErr    error
+	}{
+		{
+			id:   1,
+			name: "Active key - success",
+			key:  key,
+			beforeTest: func(repo *mockRepo.MockIAPIKeyRepository) {
+				repo.EXPECT().GetAPIKeyByHash(gomock.Any(), authAgg.HashKey(key)).
+					Return(&authAgg.APIKey{ID: "k1", Name: "ci", Roles: []string{"admin"}}, nil)
+			},
+		},
+		{
+			id:   2,
+			name: "Revoked key - failure",
+			key:  key,
+			beforeTest: func(repo *mockRepo.MockIAPIKeyRepository) {
+				repo.EXPECT().GetAPIKeyByHash(gomock.Any(), gomock.Any()).
+					Return(&authAgg.APIKey{ID: "k1", RevokedAt: &revokedAt}, nil)
+			},
+			wantErr: domainErr.ErrUnauthenticated,
+		},
+		{
+			id:   3,
+			name: "Unknown key - failure",
+			key:  key,
+			beforeTest: func(repo *mockRepo.MockIAPIKeyRepository) {
+				repo.EXPECT().GetAPIKeyByHash(gomock.Any(), gomock.Any()).Return(nil, domainErr.NotFound("API key not found"))
+			},
+			wantErr: domainErr.ErrUnauthenticated,
+		},
+		{
+			id:         4,
+			name:       "Key without the prefix is not looked up - failure",
+			key:        "secret",
+			beforeTest: func(repo *mockRepo.MockIAPIKeyRepository) {},
+			wantErr:    domainErr.ErrUnauthenticated,
+		},
+		{
+			id:   5,
+			name: "Store unavailable - failure",
+			key:  key,
+			beforeTest: func(repo *mockRepo.MockIAPIKeyRepository) {
+				repo.EXPECT().GetAPIKeyByHash(gomock.Any(), gomock.Any()).Return(nil, domainErr.Unavailable(errors.New("dial tcp"), "API key store unavailable"))
+			},
+			wantErr: domainErr.ErrUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := mockRepo.NewMockIAPIKeyRepository(ctrl)
+			s := NewAPIKeyService(repo, func() string { return "k1" }, func() time.Time { return testNow })
+			tt.beforeTest(repo)
+
+			got, err := s.Verify(context.Background(), tt.key)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ID %v Verify() error = %v, wantErr %v", tt.id, err, tt.wantErr)
+			}
+			if err == nil {
+				assert.Equal(t, &authAgg.Principal{ID: "k1", Kind: authAgg.KindAPIKey, Name: "ci", Roles: []string{"admin"}}, got)
+			}
+		})
+	}
+}
+
+func TestAPIKeyService_CreateAPIKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := mockRepo.NewMockIAPIKeyRepository(ctrl)
+	s := NewAPIKeyService(repo, func() string { return "k1" }, func() time.Time { return testNow })
+
+	var stored *authAgg.APIKey
+	repo.EXPECT().AddAPIKey(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, k *authAgg.APIKey) error {
+		stored = k
+		return nil
+	})
+	k, key, err := s.CreateAPIKey(context.Background(), "ci", []string{"admin"})
+	if err != nil {
+		t.Fatalf("CreateAPIKey() error = %v", err)
+	}
+	assert.Equal(t, stored, k)
+	assert.Equal(t, authAgg.HashKey(key), k.Hash)
+	assert.Equal(t, "k1", k.ID)
+	assert.Equal(t, testNow, k.CreatedAt)
+}
+
+// fakeVerifier accepts the credential "good" of its scheme
+type fakeVerifier string
+
+func (f fakeVerifier) Scheme() string { return string(f) }
+
+func (f fakeVerifier) Verify(ctx context.Context, credential string) (*authAgg.Principal, error) {
+	if credential != "good" {
+		return nil, domainErr.Unauthenticated("bad %s credential", f)
+	}
+	return &authAgg.Principal{ID: string(f)}, nil
+}
+
+func TestAuthenticator_Authenticate(t *testing.T) {
+	a := NewAuthenticator(fakeVerifier(SchemeBearer), fakeVerifier(SchemeAPIKey))
+	tests := []struct {
+		id            int
+		name          string
+		authorization string
+		apiKey        string
+		wantID        string
+		wantErr       error
+	}{
+		{
+			id:            1,
+			name:          "Bearer credential - success",
+			authorization: "Bearer good",
+			wantID:        SchemeBearer,
+		},
+		{
+			id:            2,
+			name:          "Scheme is case-insensitive - success",
+			authorization: "bearer  good",
+			wantID:        SchemeBearer,
+		},
+		{
+			id:     3,
+			name:   "API key header - success",
+			apiKey: "good",
+			wantID: SchemeAPIKey,
+		},
+		{
+			id:            4,
+			name:          "API key in the Authorization header - success",
+			authorization: "ApiKey good",
+			wantID:        SchemeAPIKey,
+		},
+		{
+			id:      5,
+			name:    "No credentials - failure",
+			wantErr: ErrNoCredentials,
+		},
+		{
+			id:            6,
+			name:          "Both headers - failure",
+			authorization: "Bearer good",
+			apiKey:        "good",
+			wantErr:       domainErr.ErrUnauthenticated,
+		},
+		{
+			id:            7,
+			name:          "Unsupported scheme - failure",
+			authorization: "Basic good",
+			wantErr:       domainErr.ErrUnauthenticated,
+		},
+		{
+			id:            8,
+			name:          "Scheme without a credential - failure",
+			authorization: "Bearer",
+			wantErr:       domainErr.ErrUnauthenticated,
+		},
+		{
+			id:            9,
+			name:          "Rejected credential - failure",
+			authorization: "Bearer bad",
+			wantErr:       domainErr.ErrUnauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := a.Authenticate(context.Background(), tt.authorization, tt.apiKey)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ID %v Authenticate() error = %v, wantErr %v", tt.id, err, tt.wantErr)
+			}
+			if err == nil && got.ID != tt.wantID {
+				t.Errorf("ID %v Authenticate() = %+v, want the principal of %s", tt.id, got, tt.wantID)
+			}
+		})
+	}
+	assert.Equal(t, []string{SchemeBearer, SchemeAPIKey}, a.Schemes())
+}
+
+func TestActiveUserVerifier_Verify(t *testing.T) {
+	deletedAt := testNow.Add(-time.Minute)
+	token := sign(t, jwt.SigningMethodHS256, []byte(testSecret), "", nil)
+	tests := []struct {
+		id         int
+		name       string
+		token      string
+		beforeTest func(users *mockRepo.MockIUserRepository)
+		wantErr    error
+		wantAnyErr bool // For errors that are not of a domain kind
+	}{
+		{
+			id:    1,
+			name:  "Active user - success",
+			token: token,
+			beforeTest: func(users *mockRepo.MockIUserRepository) {
+				users.EXPECT().GetUserIncludingDeleted(gomock.Any(), "42").Return(&uAgg.User{ID: "42", Status: uAgg.StatusActive}, nil)
+			},
+		},
+		{
+			id:    2,
+			name:  "Purged user - unauthenticated",
+			token: token,
+			beforeTest: func(users *mockRepo.MockIUserRepository) {
+				users.EXPECT().GetUserIncludingDeleted(gomock.Any(), "42").Return(nil, domainErr.NotFound("user 42 not found"))
+			},
+			wantErr: domainErr.ErrUnauthenticated,
+		},
+		{
+			id:    3,
+			name:  "Suspended user - unauthenticated",
+			token: token,
+			beforeTest: func(users *mockRepo.MockIUserRepository) {
+				users.EXPECT().GetUserIncludingDeleted(gomock.Any(), "42").Return(&uAgg.User{ID: "42", Status: uAgg.StatusSuspended}, nil)
+			},
+			wantErr: domainErr.ErrUnauthenticated,
+		},
+		{
+			id:    4,
+			name:  "Deleted user - unauthenticated",
+			token: token,
+			beforeTest: func(users *mockRepo.MockIUserRepository) {
+				users.EXPECT().GetUserIncludingDeleted(gomock.Any(), "42").Return(&uAgg.User{ID: "42", Status: uAgg.StatusActive, DeletedAt: &deletedAt}, nil)
+			},
+			wantErr: domainErr.ErrUnauthenticated,
+		},
+		{
+			id:         5,
+			name:       "Invalid token - unauthenticated without a lookup",
+			token:      "not a token",
+			beforeTest: func(users *mockRepo.MockIUserRepository) {},
+			wantErr:    domainErr.ErrUnauthenticated,
+		},
+		{
+			id:    6,
+			name:  "User store down - failure",
+			token: token,
+			beforeTest: func(users *mockRepo.MockIUserRepository) {
+				users.EXPECT().GetUserIncludingDeleted(gomock.Any(), "42").Return(nil, errors.New("connection refused"))
+			},
+			wantAnyErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			users := mockRepo.NewMockIUserRepository(ctrl)
+			tt.beforeTest(users)
+			v := NewActiveUserVerifier(NewJWTVerifier(testIssuer, testAudience, []byte(testSecret), nil, func() time.Time { return testNow }), users)
+
+			got, err := v.Verify(context.Background(), tt.token)
+			if tt.wantAnyErr {
+				if err == nil || errors.Is(err, domainErr.ErrUnauthenticated) {
+					t.Errorf("ID %v Verify() error = %v, want an internal error", tt.id, err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ID %v Verify() error = %v, wantErr %v", tt.id, err, tt.wantErr)
+			}
+			if err == nil {
+				assert.Equal(t, "42", got.ID)
+			}
+		})
+	}
+}
+
+func TestAuthenticator_SuspendedUserIsLockedOut(t *testing.T) {
+	ctx := context.Background()
+	now := func() time.Time { return testNow }
+	users := uPersist.NewInMemoryUserRepository(outbox.NewInMemoryOutboxRepository(), audit.NewInMemoryAuditRepository())
+	if err := users.AddUser(ctx, &uAgg.User{ID: "u1", Email: "alam@example.com", Status: uAgg.StatusActive}); err != nil {
+		t.Fatal(err)
+	}
+	token, _, err := NewTokenIssuer(testIssuer, testAudience, []byte(testSecret), time.Hour, now).Issue("u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := NewAuthenticator(NewActiveUserVerifier(NewJWTVerifier(testIssuer, testAudience, []byte(testSecret), nil, now), users))
+
+	if _, err := a.Authenticate(ctx, "Bearer "+token, ""); err != nil {
+		t.Fatalf("Authenticate() of an active user error = %v", err)
+	}
+
+	user, err := users.GetUser(ctx, "u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := user.ChangeStatus(uAgg.StatusSuspended, "abuse", testNow, "admin"); err != nil {
+		t.Fatal(err)
+	}
+	if err := users.UpdateUser(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := a.Authenticate(ctx, "Bearer "+token, ""); !errors.Is(err, domainErr.ErrUnauthenticated) {
+		t.Errorf("Authenticate() of a suspended user error = %v, want unauthenticated", err)
+	}
+}
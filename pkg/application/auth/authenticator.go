@@ -0,0 +1,77 @@
+// Package auth authenticates API callers from the credentials their requests carry:
+// bearer JWTs of a configured issuer and static API keys.
+package auth
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Crud-application/pkg/domain/authAgg"
+	"github.com/Crud-application/pkg/domain/domainErr"
+)
+
+// Schemes of the credentials requests may carry
+const (
+	SchemeBearer = "Bearer"
+	SchemeAPIKey = "ApiKey"
+)
+
+// ErrNoCredentials is returned for requests that carry no credentials at all
+var ErrNoCredentials = domainErr.Unauthenticated("authentication required")
+
+// Verifier checks credentials of one scheme
+type Verifier interface {
+	Scheme() string
+	// Verify returns the principal the credential belongs to, or an unauthenticated error
+	Verify(ctx context.Context, credential string) (*authAgg.Principal, error)
+}
+
+// Authenticator resolves the principal of a request with the verifier of the scheme it uses
+type Authenticator struct {
+	verifiers map[string]Verifier // By lower-case scheme
+	schemes   []string
+}
+
+// NewAuthenticator accepts the schemes of the given verifiers
+func NewAuthenticator(verifiers ...Verifier) *Authenticator {
+	a := &Authenticator{verifiers: make(map[string]Verifier, len(verifiers))}
+	for _, v := range verifiers {
+		a.verifiers[strings.ToLower(v.Scheme())] = v
+		a.schemes = append(a.schemes, v.Scheme())
+	}
+	return a
+}
+
+// Authenticate resolves the principal of a request from the value of its
+// Authorization header, "<scheme> <credential>", or of its API key header.
+// It returns ErrNoCredentials when both are empty.
+func (a *Authenticator) Authenticate(ctx context.Context, authorization, apiKey string) (*authAgg.Principal, error) {
+	switch {
+	case authorization == "" && apiKey == "":
+		return nil, ErrNoCredentials
+	case authorization != "" && apiKey != "":
+		return nil, domainErr.Unauthenticated("send either an Authorization header or an API key, not both")
+	case apiKey != "":
+		return a.verify(ctx, SchemeAPIKey, apiKey)
+	}
+
+	scheme, credential, _ := strings.Cut(strings.TrimSpace(authorization), " ")
+	credential = strings.TrimSpace(credential)
+	if credential == "" {
+		return nil, domainErr.Unauthenticated("malformed Authorization header")
+	}
+	return a.verify(ctx, scheme, credential)
+}
+
+func (a *Authenticator) verify(ctx context.Context, scheme, credential string) (*authAgg.Principal, error) {
+	v, ok := a.verifiers[strings.ToLower(scheme)]
+	if !ok {
+		return nil, domainErr.Unauthenticated("unsupported authentication scheme %q", scheme)
+	}
+	return v.Verify(ctx, credential)
+}
+
+// Schemes returns the accepted schemes, in the order their verifiers were given
+func (a *Authenticator) Schemes() []string {
+	return a.schemes
+}
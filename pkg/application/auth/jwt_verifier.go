@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"time"
+
+	uApp "github.com/Crud-application/pkg/application/user"
+	"github.com/Crud-application/pkg/domain/authAgg"
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// clockSkew is how far the clocks of the issuer and this server may drift apart
+const clockSkew = 30 * time.Second
+
+// claims are the claims read from bearer tokens
+type claims struct {
+	jwt.RegisteredClaims
+	Roles []string `json:"roles,omitempty"`
+}
+
+// JWTVerifier verifies bearer JWTs of one issuer, signed with HS256 and a shared
+// secret or with RS256 and one of the issuer's public keys
+type JWTVerifier struct {
+	parser *jwt.Parser
+	secret []byte
+	keys   map[string]*rsa.PublicKey // By key ID
+}
+
+// NewJWTVerifier accepts HS256 tokens when secret is set and RS256 tokens when keys are.
+// Tokens must carry an expiry, the issuer, and the audience when one is given.
+func NewJWTVerifier(issuer, audience string, secret []byte, keys map[string]*rsa.PublicKey, now uApp.Clock) *JWTVerifier {
+	var methods []string
+	if len(secret) > 0 {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if len(keys) > 0 {
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithIssuer(issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(clockSkew),
+		jwt.WithTimeFunc(now),
+	}
+	if audience != "" {
+		opts = append(opts, jwt.WithAudience(audience))
+	}
+	return &JWTVerifier{parser: jwt.NewParser(opts...), secret: secret, keys: keys}
+}
+
+// Scheme implements Verifier
+func (v *JWTVerifier) Scheme() string {
+	return SchemeBearer
+}
+
+// Verify returns the principal named by the subject of a valid token
+func (v *JWTVerifier) Verify(ctx context.Context, token string) (*authAgg.Principal, error) {
+	var c claims
+	if _, err := v.parser.ParseWithClaims(token, &c, v.key); err != nil {
+		return nil, domainErr.Unauthenticated("invalid bearer token: %v", err)
+	}
+	if c.Subject == "" {
+		return nil, domainErr.Unauthenticated("invalid bearer token: no subject")
+	}
+	return &authAgg.Principal{ID: c.Subject, Kind: authAgg.KindUser, Roles: c.Roles}, nil
+}
+
+// key returns the key verifying the signature of t. The signing method is
+// checked against the key type, so an RS256 public key is never used as an HMAC secret.
+func (v *JWTVerifier) key(t *jwt.Token) (any, error) {
+	switch t.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		return v.secret, nil
+	case *jwt.SigningMethodRSA:
+		kid, _ := t.Header["kid"].(string)
+		if key, ok := v.keys[kid]; ok {
+			return key, nil
+		}
+		if kid == "" && len(v.keys) == 1 {
+			for _, key := range v.keys {
+				return key, nil
+			}
+		}
+		return nil, fmt.Errorf("unknown key %q", kid)
+	default:
+		return nil, errors.New("unsupported signing method")
+	}
+}
@@ -0,0 +1,202 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Crud-application/pkg/application/requestCtx"
+	uApp "github.com/Crud-application/pkg/application/user"
+	aCOntr "github.com/Crud-application/pkg/contracts/auth"
+	"github.com/Crud-application/pkg/domain/auditAgg"
+	"github.com/Crud-application/pkg/domain/authAgg"
+	"github.com/Crud-application/pkg/domain/domainErr"
+	repoInter "github.com/Crud-application/pkg/domain/persistence"
+	uAgg "github.com/Crud-application/pkg/domain/userAgg"
+)
+
+// errInvalidLogin is returned for every failed login, so it does not tell which emails exist
+var errInvalidLogin = domainErr.Unauthenticated("invalid email or password")
+
+// errInvalidRefreshToken is returned for refresh tokens that are unknown, expired or revoked
+var errInvalidRefreshToken = domainErr.Unauthenticated("invalid refresh token")
+
+// SessionService logs users in with their password and keeps them logged in with
+// refresh tokens, which rotate on every use
+type SessionService struct {
+	users        repoInter.IUserRepository
+	tokens       repoInter.IRefreshTokenRepository
+	issuer       *TokenIssuer // Nil when no JWT secret is configured, which disables login
+	refreshTTL   time.Duration
+	generateUUID uApp.UUIDGenerator
+	now          uApp.Clock
+}
+
+func NewSessionService(users repoInter.IUserRepository, tokens repoInter.IRefreshTokenRepository,
+	issuer *TokenIssuer, refreshTTL time.Duration, generateUUID uApp.UUIDGenerator, now uApp.Clock) *SessionService {
+	return &SessionService{
+		users:        users,
+		tokens:       tokens,
+		issuer:       issuer,
+		refreshTTL:   refreshTTL,
+		generateUUID: generateUUID,
+		now:          now,
+	}
+}
+
+// Login checks the password of the user with the given email and starts a session
+func (s *SessionService) Login(ctx context.Context, req *aCOntr.LoginReq) (*aCOntr.TokenRes, error) {
+	if s.issuer == nil {
+		return nil, domainErr.Unavailable(nil, "password login needs a JWT secret")
+	}
+	email, err := uAgg.NewEmail(req.Email)
+	if err != nil {
+		return nil, errInvalidLogin
+	}
+	user, err := s.users.GetUserByEmail(ctx, email.String())
+	if errors.Is(err, domainErr.ErrNotFound) {
+		uAgg.CheckNoPassword(req.Password)
+		return nil, errInvalidLogin
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if !user.CheckPassword(req.Password) {
+		return nil, errInvalidLogin
+	}
+	// Only told to those who know the password
+	if user.Status.Blocked() {
+		return nil, domainErr.Forbidden("the account is %s", user.Status)
+	}
+	return s.issue(ctx, user.ID, "")
+}
+
+// Refresh rotates a refresh token: it is revoked and replaced, along with a new access token.
+// A token used a second time has leaked, so the whole session is revoked.
+func (s *SessionService) Refresh(ctx context.Context, req *aCOntr.RefreshReq) (*aCOntr.TokenRes, error) {
+	if s.issuer == nil {
+		return nil, domainErr.Unavailable(nil, "password login needs a JWT secret")
+	}
+	t, err := s.getRefreshToken(ctx, req.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	if t.Expired(now) {
+		return nil, errInvalidRefreshToken
+	}
+	if t.Revoked() {
+		return nil, s.revokeReused(ctx, t)
+	}
+	err = s.tokens.RevokeRefreshToken(ctx, t.ID, now)
+	if errors.Is(err, domainErr.ErrConflict) {
+		// Another request rotated the token first
+		return nil, s.revokeReused(ctx, t)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+
+	// Users deleted, suspended or deactivated since they logged in lose their session
+	user, err := s.users.GetUser(ctx, t.UserID)
+	if errors.Is(err, domainErr.ErrNotFound) {
+		return nil, errInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.Status.Blocked() {
+		return nil, errInvalidRefreshToken
+	}
+	return s.issue(ctx, t.UserID, t.FamilyID)
+}
+
+// Logout ends the session of a refresh token. Unknown tokens are ignored.
+func (s *SessionService) Logout(ctx context.Context, req *aCOntr.RefreshReq) error {
+	t, err := s.getRefreshToken(ctx, req.RefreshToken)
+	if errors.Is(err, domainErr.ErrUnauthenticated) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := s.tokens.RevokeRefreshTokenFamily(ctx, t.FamilyID, s.now()); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return nil
+}
+
+// ChangePassword replaces the password of the calling user, who must know the
+// current one, and ends all their sessions. Access tokens already issued are not
+// revoked and stay valid until they expire.
+func (s *SessionService) ChangePassword(ctx context.Context, req *aCOntr.ChangePasswordReq) error {
+	p := requestCtx.Principal(ctx)
+	if p == nil {
+		return domainErr.Unauthenticated("authentication required")
+	}
+	if p.Kind != authAgg.KindUser {
+		return domainErr.Forbidden("only users have a password")
+	}
+	user, err := s.users.GetUser(ctx, p.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	now := s.now()
+	if err := user.ChangePassword(req.OldPassword, req.NewPassword, now, requestCtx.Actor(ctx)); err != nil {
+		return fmt.Errorf("request validation failed: %w", err)
+	}
+	uApp.RecordAudit(ctx, user, auditAgg.ActionUpdate, []auditAgg.FieldChange{{Field: uAgg.FieldPassword}})
+	if err := s.users.UpdateUser(ctx, user); err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+
+	if err := s.tokens.RevokeUserRefreshTokens(ctx, user.ID, now); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	return nil
+}
+
+// getRefreshToken looks a refresh token up by its hash
+func (s *SessionService) getRefreshToken(ctx context.Context, token string) (*authAgg.RefreshToken, error) {
+	t, err := s.tokens.GetRefreshTokenByHash(ctx, authAgg.HashKey(token))
+	if errors.Is(err, domainErr.ErrNotFound) {
+		return nil, errInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+	return t, nil
+}
+
+// revokeReused ends the session of a refresh token that was used after it rotated
+func (s *SessionService) revokeReused(ctx context.Context, t *authAgg.RefreshToken) error {
+	log.Printf("Refresh token %s of user %s was reused; revoking its session", t.ID, t.UserID)
+	if err := s.tokens.RevokeRefreshTokenFamily(ctx, t.FamilyID, s.now()); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return errInvalidRefreshToken
+}
+
+// issue starts or continues the session familyID of a user with a new access token and refresh token
+func (s *SessionService) issue(ctx context.Context, userID, familyID string) (*aCOntr.TokenRes, error) {
+	access, expiresAt, err := s.issuer.Issue(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign access token: %w", err)
+	}
+	now := s.now()
+	t, refresh, err := authAgg.NewRefreshToken(s.generateUUID(), userID, familyID, now, s.refreshTTL)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.tokens.AddRefreshToken(ctx, t); err != nil {
+		return nil, fmt.Errorf("failed to add refresh token: %w", err)
+	}
+	return &aCOntr.TokenRes{
+		AccessToken:  access,
+		TokenType:    SchemeBearer,
+		ExpiresIn:    int64(expiresAt.Sub(now) / time.Second),
+		RefreshToken: refresh,
+	}, nil
+}
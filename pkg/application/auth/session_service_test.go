@@ -0,0 +1,327 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Crud-application/pkg/application/requestCtx"
+	aCOntr "github.com/Crud-application/pkg/contracts/auth"
+	"github.com/Crud-application/pkg/domain/authAgg"
+	"github.com/Crud-application/pkg/domain/domainErr"
+	mockRepo "github.com/Crud-application/pkg/domain/persistence/mocks"
+	uAgg "github.com/Crud-application/pkg/domain/userAgg"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+const testPassword = "correct horse"
+
+// sessionMocks are the repositories behind a SessionService under test
+type sessionMocks struct {
+	users  *mockRepo.MockIUserRepository
+	tokens *mockRepo.MockIRefreshTokenRepository
+}
+
+func newSessionService(t *testing.T, withIssuer bool) (*SessionService, sessionMocks) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+	m := sessionMocks{
+		users:  mockRepo.NewMockIUserRepository(ctrl),
+		tokens: mockRepo.NewMockIRefreshTokenRepository(ctrl),
+	}
+	now := func() time.Time { return testNow }
+	var issuer *TokenIssuer
+	if withIssuer {
+		issuer = NewTokenIssuer(testIssuer, testAudience, []byte(testSecret), 15*time.Minute, now)
+	}
+	return NewSessionService(m.users, m.tokens, issuer, 24*time.Hour, func() string { return "r2" }, now), m
+}
+
+// userWithPassword returns user u1 whose password is testPassword
+func userWithPassword(t *testing.T) *uAgg.User {
+	u := &uAgg.User{ID: "u1", Email: "alam@example.com", Version: 3}
+	if err := u.SetPassword(testPassword); err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+func TestSessionService_Login(t *testing.T) {
+	user := userWithPassword(t)
+	tests := []struct {
+		id         int
+		name       string
+		req        aCOntr.LoginReq
+		noIssuer   bool
+		beforeTest func(m sessionMocks)
+		wantErr    error
+	}{
+		{
+			id:   1,
+			name: "Right password - tokens issued",
+			req:  aCOntr.LoginReq{Email: " Alam@Example.com", Password: testPassword},
+			beforeTest: func(m sessionMocks) {
+				m.users.EXPECT().GetUserByEmail(gomock.Any(), "alam@example.com").Return(user, nil)
+				m.tokens.EXPECT().AddRefreshToken(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, tok *authAgg.RefreshToken) error {
+					assert.Equal(t, "r2", tok.FamilyID)
+					assert.Equal(t, testNow.Add(24*time.Hour), tok.ExpiresAt)
+					return nil
+				})
+			},
+		},
+		{
+			id:   2,
+			name: "Wrong password - unauthenticated",
+			req:  aCOntr.LoginReq{Email: "alam@example.com", Password: "wrong password"},
+			beforeTest: func(m sessionMocks) {
+				m.users.EXPECT().GetUserByEmail(gomock.Any(), gomock.Any()).Return(user, nil)
+			},
+			wantErr: domainErr.ErrUnauthenticated,
+		},
+		{
+			id:   3,
+			name: "Unknown email - unauthenticated",
+			req:  aCOntr.LoginReq{Email: "nobody@example.com", Password: testPassword},
+			beforeTest: func(m sessionMocks) {
+				m.users.EXPECT().GetUserByEmail(gomock.Any(), gomock.Any()).Return(nil, domainErr.NotFound("no user"))
+			},
+			wantErr: domainErr.ErrUnauthenticated,
+		},
+		{
+			id:   4,
+			name: "User without a password - unauthenticated",
+			req:  aCOntr.LoginReq{Email: "alam@example.com", Password: ""},
+			beforeTest: func(m sessionMocks) {
+				m.users.EXPECT().GetUserByEmail(gomock.Any(), gomock.Any()).Return(&uAgg.User{ID: "u1"}, nil)
+			},
+			wantErr: domainErr.ErrUnauthenticated,
+		},
+		{
+			id:   5,
+			name: "Suspended user - forbidden",
+			req:  aCOntr.LoginReq{Email: "alam@example.com", Password: testPassword},
+			beforeTest: func(m sessionMocks) {
+				suspended := userWithPassword(t)
+				suspended.Status = uAgg.StatusSuspended
+				m.users.EXPECT().GetUserByEmail(gomock.Any(), gomock.Any()).Return(suspended, nil)
+			},
+			wantErr: domainErr.ErrForbidden,
+		},
+		{
+			id:         6,
+			name:       "No JWT secret - unavailable",
+			req:        aCOntr.LoginReq{Email: "alam@example.com", Password: testPassword},
+			noIssuer:   true,
+			beforeTest: func(m sessionMocks) {},
+			wantErr:    domainErr.ErrUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, m := newSessionService(t, !tt.noIssuer)
+			tt.beforeTest(m)
+
+			got, err := s.Login(context.Background(), &tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ID %v Login() error = %v, wantErr %v", tt.id, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			assert.Equal(t, SchemeBearer, got.TokenType)
+			assert.Equal(t, int64(900), got.ExpiresIn)
+			p, err := NewJWTVerifier(testIssuer, testAudience, []byte(testSecret), nil, func() time.Time { return testNow }).Verify(context.Background(), got.AccessToken)
+			if err != nil || p.ID != "u1" {
+				t.Errorf("ID %v access token authenticates %+v, %v, want user u1", tt.id, p, err)
+			}
+		})
+	}
+}
+
+func TestSessionService_Refresh(t *testing.T) {
+	const token = authAgg.RefreshTokenPrefix + "secret"
+	active := func() *authAgg.RefreshToken {
+		return &authAgg.RefreshToken{ID: "r1", UserID: "u1", FamilyID: "r1", ExpiresAt: testNow.Add(time.Hour)}
+	}
+	revokedAt := testNow.Add(-time.Minute)
+
+	tests := []struct {
+		id         int
+		name       string
+		beforeTest func(m sessionMocks)
+		wantErr    error
+	}{
+		{
+			id:   1,
+			name: "Active token - rotated within its session",
+			beforeTest: func(m sessionMocks) {
+				m.tokens.EXPECT().GetRefreshTokenByHash(gomock.Any(), authAgg.HashKey(token)).Return(active(), nil)
+				m.tokens.EXPECT().RevokeRefreshToken(gomock.Any(), "r1", testNow).Return(nil)
+				m.users.EXPECT().GetUser(gomock.Any(), "u1").Return(&uAgg.User{ID: "u1"}, nil)
+				m.tokens.EXPECT().AddRefreshToken(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, tok *authAgg.RefreshToken) error {
+					assert.Equal(t, "r2", tok.ID)
+					assert.Equal(t, "r1", tok.FamilyID)
+					return nil
+				})
+			},
+		},
+		{
+			id:   2,
+			name: "Revoked token reused - session revoked",
+			beforeTest: func(m sessionMocks) {
+				tok := active()
+				tok.RevokedAt = &revokedAt
+				m.tokens.EXPECT().GetRefreshTokenByHash(gomock.Any(), gomock.Any()).Return(tok, nil)
+				m.tokens.EXPECT().RevokeRefreshTokenFamily(gomock.Any(), "r1", testNow).Return(nil)
+			},
+			wantErr: domainErr.ErrUnauthenticated,
+		},
+		{
+			id:   3,
+			name: "Token rotated concurrently - session revoked",
+			beforeTest: func(m sessionMocks) {
+				m.tokens.EXPECT().GetRefreshTokenByHash(gomock.Any(), gomock.Any()).Return(active(), nil)
+				m.tokens.EXPECT().RevokeRefreshToken(gomock.Any(), "r1", testNow).Return(domainErr.Conflict("already revoked"))
+				m.tokens.EXPECT().RevokeRefreshTokenFamily(gomock.Any(), "r1", testNow).Return(nil)
+			},
+			wantErr: domainErr.ErrUnauthenticated,
+		},
+		{
+			id:   4,
+			name: "Expired token - unauthenticated",
+			beforeTest: func(m sessionMocks) {
+				tok := active()
+				tok.ExpiresAt = testNow
+				m.tokens.EXPECT().GetRefreshTokenByHash(gomock.Any(), gomock.Any()).Return(tok, nil)
+			},
+			wantErr: domainErr.ErrUnauthenticated,
+		},
+		{
+			id:   5,
+			name: "Unknown token - unauthenticated",
+			beforeTest: func(m sessionMocks) {
+				m.tokens.EXPECT().GetRefreshTokenByHash(gomock.Any(), gomock.Any()).Return(nil, domainErr.NotFound("refresh token not found"))
+			},
+			wantErr: domainErr.ErrUnauthenticated,
+		},
+		{
+			id:   6,
+			name: "User deleted since login - unauthenticated",
+			beforeTest: func(m sessionMocks) {
+				m.tokens.EXPECT().GetRefreshTokenByHash(gomock.Any(), gomock.Any()).Return(active(), nil)
+				m.tokens.EXPECT().RevokeRefreshToken(gomock.Any(), "r1", testNow).Return(nil)
+				m.users.EXPECT().GetUser(gomock.Any(), "u1").Return(nil, domainErr.NotFound("user u1 not found"))
+			},
+			wantErr: domainErr.ErrUnauthenticated,
+		},
+		{
+			id:   7,
+			name: "User deactivated since login - unauthenticated",
+			beforeTest: func(m sessionMocks) {
+				m.tokens.EXPECT().GetRefreshTokenByHash(gomock.Any(), gomock.Any()).Return(active(), nil)
+				m.tokens.EXPECT().RevokeRefreshToken(gomock.Any(), "r1", testNow).Return(nil)
+				m.users.EXPECT().GetUser(gomock.Any(), "u1").Return(&uAgg.User{ID: "u1", Status: uAgg.StatusDeactivated}, nil)
+			},
+			wantErr: domainErr.ErrUnauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, m := newSessionService(t, true)
+			tt.beforeTest(m)
+
+			got, err := s.Refresh(context.Background(), &aCOntr.RefreshReq{RefreshToken: token})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ID %v Refresh() error = %v, wantErr %v", tt.id, err, tt.wantErr)
+			}
+			if err == nil && (got.AccessToken == "" || got.RefreshToken == token) {
+				t.Errorf("ID %v Refresh() = %+v, want new tokens", tt.id, got)
+			}
+		})
+	}
+}
+
+func TestSessionService_Logout(t *testing.T) {
+	s, m := newSessionService(t, true)
+	m.tokens.EXPECT().GetRefreshTokenByHash(gomock.Any(), authAgg.HashKey("known")).
+		Return(&authAgg.RefreshToken{ID: "r2", FamilyID: "r1"}, nil)
+	m.tokens.EXPECT().RevokeRefreshTokenFamily(gomock.Any(), "r1", testNow).Return(nil)
+	m.tokens.EXPECT().GetRefreshTokenByHash(gomock.Any(), authAgg.HashKey("unknown")).
+		Return(nil, domainErr.NotFound("refresh token not found"))
+
+	if err := s.Logout(context.Background(), &aCOntr.RefreshReq{RefreshToken: "known"}); err != nil {
+		t.Errorf("Logout() error = %v", err)
+	}
+	if err := s.Logout(context.Background(), &aCOntr.RefreshReq{RefreshToken: "unknown"}); err != nil {
+		t.Errorf("Logout() of an unknown token error = %v, want none", err)
+	}
+}
+
+func TestSessionService_ChangePassword(t *testing.T) {
+	alice := &authAgg.Principal{ID: "u1", Kind: authAgg.KindUser}
+	tests := []struct {
+		id         int
+		name       string
+		principal  *authAgg.Principal
+		req        aCOntr.ChangePasswordReq
+		beforeTest func(m sessionMocks)
+		wantErr    error
+	}{
+		{
+			id:        1,
+			name:      "Right old password - changed and sessions ended",
+			principal: alice,
+			req:       aCOntr.ChangePasswordReq{OldPassword: testPassword, NewPassword: "battery staple"},
+			beforeTest: func(m sessionMocks) {
+				m.users.EXPECT().GetUser(gomock.Any(), "u1").Return(userWithPassword(t), nil)
+				m.users.EXPECT().UpdateUser(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, u *uAgg.User) error {
+					assert.True(t, u.CheckPassword("battery staple"))
+					assert.Equal(t, int64(3), u.Version)
+					assert.Empty(t, u.Events)
+					if assert.Len(t, u.AuditEntries, 1) {
+						assert.Equal(t, "u1", u.AuditEntries[0].Actor)
+						assert.Contains(t, fmt.Sprint(u.AuditEntries[0]), uAgg.FieldPassword)
+						assert.NotContains(t, fmt.Sprint(u.AuditEntries[0]), "$2a$")
+					}
+					return nil
+				})
+				m.tokens.EXPECT().RevokeUserRefreshTokens(gomock.Any(), "u1", testNow).Return(nil)
+			},
+		},
+		{
+			id:        2,
+			name:      "Wrong old password - validation error",
+			principal: alice,
+			req:       aCOntr.ChangePasswordReq{OldPassword: "wrong password", NewPassword: "battery staple"},
+			beforeTest: func(m sessionMocks) {
+				m.users.EXPECT().GetUser(gomock.Any(), "u1").Return(userWithPassword(t), nil)
+			},
+			wantErr: domainErr.ErrValidation,
+		},
+		{
+			id:         3,
+			name:       "API key - forbidden",
+			principal:  &authAgg.Principal{ID: "k1", Kind: authAgg.KindAPIKey},
+			req:        aCOntr.ChangePasswordReq{OldPassword: testPassword, NewPassword: "battery staple"},
+			beforeTest: func(m sessionMocks) {},
+			wantErr:    domainErr.ErrForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, m := newSessionService(t, true)
+			tt.beforeTest(m)
+
+			ctx := requestCtx.WithPrincipal(context.Background(), tt.principal)
+			if err := s.ChangePassword(ctx, &tt.req); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ID %v ChangePassword() error = %v, wantErr %v", tt.id, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"time"
+
+	uApp "github.com/Crud-application/pkg/application/user"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// TokenIssuer signs the HS256 access tokens of logged-in users, which the
+// JWTVerifier of the same issuer, audience and secret accepts
+type TokenIssuer struct {
+	issuer   string
+	audience string
+	secret   []byte
+	ttl      time.Duration
+	now      uApp.Clock
+}
+
+// NewTokenIssuer issues tokens valid for ttl
+func NewTokenIssuer(issuer, audience string, secret []byte, ttl time.Duration, now uApp.Clock) *TokenIssuer {
+	return &TokenIssuer{issuer: issuer, audience: audience, secret: secret, ttl: ttl, now: now}
+}
+
+// Issue returns an access token naming userID as its subject, and when it expires
+func (i *TokenIssuer) Issue(userID string) (string, time.Time, error) {
+	now := i.now()
+	expiresAt := now.Add(i.ttl)
+	c := claims{RegisteredClaims: jwt.RegisteredClaims{
+		Issuer:    i.issuer,
+		Subject:   userID,
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	}}
+	if i.audience != "" {
+		c.Audience = jwt.ClaimStrings{i.audience}
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, c).SignedString(i.secret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, expiresAt, nil
+}
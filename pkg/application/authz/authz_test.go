@@ -0,0 +1,208 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Crud-application/pkg/application/requestCtx"
+	svcInter "github.com/Crud-application/pkg/application/services"
+	"github.com/Crud-application/pkg/application/services/mocks"
+	uCOntr "github.com/Crud-application/pkg/contracts/user"
+	wCOntr "github.com/Crud-application/pkg/contracts/webhook"
+	"github.com/Crud-application/pkg/domain/authAgg"
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"github.com/golang/mock/gomock"
+)
+
+var (
+	admin   = &authAgg.Principal{ID: "a1", Kind: authAgg.KindUser, Roles: []string{authAgg.RoleAdmin}}
+	support = &authAgg.Principal{ID: "k1", Kind: authAgg.KindAPIKey, Roles: []string{authAgg.RoleSupport}}
+	alice   = &authAgg.Principal{ID: "u1", Kind: authAgg.KindUser}
+)
+
+func TestUserService(t *testing.T) {
+	tests := []struct {
+		id         int
+		name       string
+		principal  *authAgg.Principal
+		call       func(ctx context.Context, s svcInter.IUserService) error
+		beforeTest func(next *mocks.MockIUserService)
+		wantErr    error
+	}{
+		{
+			id:        1,
+			name:      "User reads their own record - passed on",
+			principal: alice,
+			call: func(ctx context.Context, s svcInter.IUserService) error {
+				_, err := s.GetUser(ctx, "u1")
+				return err
+			},
+			beforeTest: func(next *mocks.MockIUserService) {
+				next.EXPECT().GetUser(gomock.Any(), "u1").Return(&uCOntr.GetUserRes{ID: "u1"}, nil)
+			},
+		},
+		{
+			id:        2,
+			name:      "User patches another record - forbidden",
+			principal: alice,
+			call: func(ctx context.Context, s svcInter.IUserService) error {
+				_, err := s.PatchUser(ctx, "u2", &uCOntr.PatchUserReq{})
+				return err
+			},
+			wantErr: domainErr.ErrForbidden,
+		},
+		{
+			id:        3,
+			name:      "User deletes their own record - forbidden",
+			principal: alice,
+			call: func(ctx context.Context, s svcInter.IUserService) error {
+				return s.DeleteUser(ctx, "u1")
+			},
+			wantErr: domainErr.ErrForbidden,
+		},
+		{
+			id:        4,
+			name:      "Support lists users - passed on",
+			principal: support,
+			call: func(ctx context.Context, s svcInter.IUserService) error {
+				_, err := s.GetAllUsers(ctx, &uCOntr.GetUsersReq{})
+				return err
+			},
+			beforeTest: func(next *mocks.MockIUserService) {
+				next.EXPECT().GetAllUsers(gomock.Any(), gomock.Any()).Return(&uCOntr.GetUsersRes{}, nil)
+			},
+		},
+		{
+			id:        5,
+			name:      "Support updates a user - forbidden",
+			principal: support,
+			call: func(ctx context.Context, s svcInter.IUserService) error {
+				_, err := s.UpdateUser(ctx, "u1", &uCOntr.UpdateUserReq{})
+				return err
+			},
+			wantErr: domainErr.ErrForbidden,
+		},
+		{
+			id:        6,
+			name:      "Admin restores a user - passed on",
+			principal: admin,
+			call: func(ctx context.Context, s svcInter.IUserService) error {
+				_, err := s.RestoreUser(ctx, "u1")
+				return err
+			},
+			beforeTest: func(next *mocks.MockIUserService) {
+				next.EXPECT().RestoreUser(gomock.Any(), "u1").Return(&uCOntr.GetUserRes{ID: "u1"}, nil)
+			},
+		},
+		{
+			id:        7,
+			name:      "User suspends themselves - forbidden",
+			principal: alice,
+			call: func(ctx context.Context, s svcInter.IUserService) error {
+				_, err := s.ChangeUserStatus(ctx, "u1", &uCOntr.ChangeUserStatusReq{Status: "suspended", Reason: "Spam"})
+				return err
+			},
+			wantErr: domainErr.ErrForbidden,
+		},
+		{
+			id:   8,
+			name: "Anonymous create - unauthenticated",
+			call: func(ctx context.Context, s svcInter.IUserService) error {
+				_, err := s.CreateUser(ctx, &uCOntr.CreateUserReq{})
+				return err
+			},
+			wantErr: domainErr.ErrUnauthenticated,
+		},
+		{
+			id:        9,
+			name:      "Support lists deleted users - forbidden",
+			principal: support,
+			call: func(ctx context.Context, s svcInter.IUserService) error {
+				_, err := s.GetAllUsers(ctx, &uCOntr.GetUsersReq{IncludeDeleted: true})
+				return err
+			},
+			wantErr: domainErr.ErrForbidden,
+		},
+		{
+			id:        10,
+			name:      "Admin lists deleted users - passed on",
+			principal: admin,
+			call: func(ctx context.Context, s svcInter.IUserService) error {
+				_, err := s.GetAllUsers(ctx, &uCOntr.GetUsersReq{IncludeDeleted: true})
+				return err
+			},
+			beforeTest: func(next *mocks.MockIUserService) {
+				next.EXPECT().GetAllUsers(gomock.Any(), &uCOntr.GetUsersReq{IncludeDeleted: true}).Return(&uCOntr.GetUsersRes{}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			next := mocks.NewMockIUserService(ctrl)
+			if tt.beforeTest != nil {
+				tt.beforeTest(next)
+			}
+
+			ctx := context.Background()
+			if tt.principal != nil {
+				ctx = requestCtx.WithPrincipal(ctx, tt.principal)
+			}
+			err := tt.call(ctx, NewUserService(next, authAgg.DefaultPolicy()))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ID %v error = %v, wantErr %v", tt.id, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWebhookService(t *testing.T) {
+	tests := []struct {
+		id         int
+		name       string
+		principal  *authAgg.Principal
+		beforeTest func(next *mocks.MockIWebhookService)
+		wantErr    error
+	}{
+		{
+			id:        1,
+			name:      "Admin - passed on",
+			principal: admin,
+			beforeTest: func(next *mocks.MockIWebhookService) {
+				next.EXPECT().GetWebhooks(gomock.Any(), gomock.Any()).Return(&wCOntr.GetWebhooksRes{}, nil)
+			},
+		},
+		{
+			id:        2,
+			name:      "Support - forbidden",
+			principal: support,
+			wantErr:   domainErr.ErrForbidden,
+		},
+		{
+			id:        3,
+			name:      "User - forbidden",
+			principal: alice,
+			wantErr:   domainErr.ErrForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			next := mocks.NewMockIWebhookService(ctrl)
+			if tt.beforeTest != nil {
+				tt.beforeTest(next)
+			}
+
+			ctx := requestCtx.WithPrincipal(context.Background(), tt.principal)
+			_, err := NewWebhookService(next, authAgg.DefaultPolicy()).GetWebhooks(ctx, &wCOntr.GetWebhooksReq{})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ID %v GetWebhooks() error = %v, wantErr %v", tt.id, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+// Package authz enforces the access policy in front of the application
+// services, so the REST, GraphQL and gRPC APIs share the same rules
+package authz
+
+import (
+	"context"
+
+	"github.com/Crud-application/pkg/application/requestCtx"
+	svcInter "github.com/Crud-application/pkg/application/services"
+	uCOntr "github.com/Crud-application/pkg/contracts/user"
+	"github.com/Crud-application/pkg/domain/authAgg"
+)
+
+// Verify that UserService implements IUserService
+var _ svcInter.IUserService = (*UserService)(nil)
+
+// UserService checks every call against the policy before passing it on
+type UserService struct {
+	next   svcInter.IUserService
+	policy authAgg.Policy
+}
+
+func NewUserService(next svcInter.IUserService, policy authAgg.Policy) *UserService {
+	return &UserService{next: next, policy: policy}
+}
+
+// authorize checks the principal of ctx may perform action on the user with ID userID
+func authorize(ctx context.Context, policy authAgg.Policy, action authAgg.Action, userID string) error {
+	return policy.Authorize(requestCtx.Principal(ctx), action, userID)
+}
+
+func (s *UserService) CreateUser(ctx context.Context, req *uCOntr.CreateUserReq) (*uCOntr.CreateUserRes, error) {
+	if err := authorize(ctx, s.policy, authAgg.ActionCreateUser, ""); err != nil {
+		return nil, err
+	}
+	return s.next.CreateUser(ctx, req)
+}
+
+func (s *UserService) DeleteUser(ctx context.Context, userID string) error {
+	if err := authorize(ctx, s.policy, authAgg.ActionDeleteUser, userID); err != nil {
+		return err
+	}
+	return s.next.DeleteUser(ctx, userID)
+}
+
+func (s *UserService) RestoreUser(ctx context.Context, userID string) (*uCOntr.GetUserRes, error) {
+	if err := authorize(ctx, s.policy, authAgg.ActionRestoreUser, userID); err != nil {
+		return nil, err
+	}
+	return s.next.RestoreUser(ctx, userID)
+}
+
+func (s *UserService) ChangeUserStatus(ctx context.Context, userID string, req *uCOntr.ChangeUserStatusReq) (*uCOntr.GetUserRes, error) {
+	if err := authorize(ctx, s.policy, authAgg.ActionChangeStatus, userID); err != nil {
+		return nil, err
+	}
+	return s.next.ChangeUserStatus(ctx, userID, req)
+}
+
+func (s *UserService) GetUser(ctx context.Context, userID string) (*uCOntr.GetUserRes, error) {
+	if err := authorize(ctx, s.policy, authAgg.ActionReadUser, userID); err != nil {
+		return nil, err
+	}
+	return s.next.GetUser(ctx, userID)
+}
+
+func (s *UserService) UpdateUser(ctx context.Context, userID string, req *uCOntr.UpdateUserReq) (*uCOntr.UpdateUserRes, error) {
+	if err := authorize(ctx, s.policy, authAgg.ActionUpdateUser, userID); err != nil {
+		return nil, err
+	}
+	return s.next.UpdateUser(ctx, userID, req)
+}
+
+func (s *UserService) PatchUser(ctx context.Context, userID string, req *uCOntr.PatchUserReq) (*uCOntr.UpdateUserRes, error) {
+	if err := authorize(ctx, s.policy, authAgg.ActionUpdateUser, userID); err != nil {
+		return nil, err
+	}
+	return s.next.PatchUser(ctx, userID, req)
+}
+
+func (s *UserService) GetAllUsers(ctx context.Context, req *uCOntr.GetUsersReq) (*uCOntr.GetUsersRes, error) {
+	if err := authorize(ctx, s.policy, authAgg.ActionListUsers, ""); err != nil {
+		return nil, err
+	}
+	if req.IncludeDeleted {
+		if err := authorize(ctx, s.policy, authAgg.ActionListDeleted, ""); err != nil {
+			return nil, err
+		}
+	}
+	return s.next.GetAllUsers(ctx, req)
+}
+
+func (s *UserService) GetUserHistory(ctx context.Context, userID string, req *uCOntr.GetUserHistoryReq) (*uCOntr.GetUserHistoryRes, error) {
+	if err := authorize(ctx, s.policy, authAgg.ActionReadHistory, userID); err != nil {
+		return nil, err
+	}
+	return s.next.GetUserHistory(ctx, userID, req)
+}
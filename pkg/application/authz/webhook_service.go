@@ -0,0 +1,65 @@
+package authz
+
+import (
+	"context"
+
+	svcInter "github.com/Crud-application/pkg/application/services"
+	wCOntr "github.com/Crud-application/pkg/contracts/webhook"
+	"github.com/Crud-application/pkg/domain/authAgg"
+)
+
+// Verify that WebhookService implements IWebhookService
+var _ svcInter.IWebhookService = (*WebhookService)(nil)
+
+// WebhookService lets only the principals allowed to manage webhooks through.
+// Webhooks receive every user change, so they are not scoped to a single user.
+type WebhookService struct {
+	next   svcInter.IWebhookService
+	policy authAgg.Policy
+}
+
+func NewWebhookService(next svcInter.IWebhookService, policy authAgg.Policy) *WebhookService {
+	return &WebhookService{next: next, policy: policy}
+}
+
+func (s *WebhookService) CreateWebhook(ctx context.Context, req *wCOntr.CreateWebhookReq) (*wCOntr.CreateWebhookRes, error) {
+	if err := authorize(ctx, s.policy, authAgg.ActionManageWebhooks, ""); err != nil {
+		return nil, err
+	}
+	return s.next.CreateWebhook(ctx, req)
+}
+
+func (s *WebhookService) GetWebhook(ctx context.Context, webhookID string) (*wCOntr.WebhookRes, error) {
+	if err := authorize(ctx, s.policy, authAgg.ActionManageWebhooks, ""); err != nil {
+		return nil, err
+	}
+	return s.next.GetWebhook(ctx, webhookID)
+}
+
+func (s *WebhookService) GetWebhooks(ctx context.Context, req *wCOntr.GetWebhooksReq) (*wCOntr.GetWebhooksRes, error) {
+	if err := authorize(ctx, s.policy, authAgg.ActionManageWebhooks, ""); err != nil {
+		return nil, err
+	}
+	return s.next.GetWebhooks(ctx, req)
+}
+
+func (s *WebhookService) UpdateWebhook(ctx context.Context, webhookID string, req *wCOntr.UpdateWebhookReq) (*wCOntr.WebhookRes, error) {
+	if err := authorize(ctx, s.policy, authAgg.ActionManageWebhooks, ""); err != nil {
+		return nil, err
+	}
+	return s.next.UpdateWebhook(ctx, webhookID, req)
+}
+
+func (s *WebhookService) DeleteWebhook(ctx context.Context, webhookID string) error {
+	if err := authorize(ctx, s.policy, authAgg.ActionManageWebhooks, ""); err != nil {
+		return err
+	}
+	return s.next.DeleteWebhook(ctx, webhookID)
+}
+
+func (s *WebhookService) GetDeliveries(ctx context.Context, webhookID string, req *wCOntr.GetDeliveriesReq) (*wCOntr.GetDeliveriesRes, error) {
+	if err := authorize(ctx, s.policy, authAgg.ActionManageWebhooks, ""); err != nil {
+		return nil, err
+	}
+	return s.next.GetDeliveries(ctx, webhookID, req)
+}
@@ -0,0 +1,215 @@
+// Package patch applies JSON Merge Patch (RFC 7396) and JSON Patch (RFC 6902)
+// documents to JSON documents, independently of what the documents describe.
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	pContr "github.com/Crud-application/pkg/contracts/patch"
+	"github.com/Crud-application/pkg/domain/domainErr"
+)
+
+// Apply applies a patch of the given content type to doc and returns the patched document.
+// Patches that cannot be applied are validation errors; failed JSON Patch tests are conflicts.
+func Apply(contentType string, doc, patch []byte) ([]byte, error) {
+	switch contentType {
+	case pContr.MergePatchContentType:
+		return Merge(doc, patch)
+	case pContr.JSONPatchContentType:
+		return JSONPatch(doc, patch)
+	default:
+		return nil, domainErr.Validation("unsupported patch type " + contentType)
+	}
+}
+
+// Merge applies the JSON Merge Patch patch to doc (RFC 7396)
+func Merge(doc, patch []byte) ([]byte, error) {
+	target, err := decode(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the document: %w", err)
+	}
+	p, err := decode(patch)
+	if err != nil {
+		return nil, domainErr.Validation("the merge patch is not JSON")
+	}
+	return json.Marshal(merge(target, p))
+}
+
+// merge is the MergePatch function of RFC 7396
+func merge(target, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = map[string]any{}
+	}
+	for name, value := range p {
+		if value == nil {
+			delete(t, name)
+		} else {
+			t[name] = merge(t[name], value)
+		}
+	}
+	return t
+}
+
+// JSONPatch applies the operations of the JSON Patch patch to doc in order (RFC 6902).
+// Either every operation applies or the document is left as it was.
+func JSONPatch(doc, patch []byte) ([]byte, error) {
+	target, err := decode(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the document: %w", err)
+	}
+	var ops []pContr.Operation
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return nil, domainErr.Validation("the JSON patch is not an array of operations")
+	}
+	for i, op := range ops {
+		if target, err = apply(target, op); err != nil {
+			if errors.Is(err, domainErr.ErrConflict) {
+				return nil, err
+			}
+			return nil, domainErr.Validation("invalid JSON patch", domainErr.FieldError{Field: fmt.Sprintf("[%d]", i), Message: err.Error()})
+		}
+	}
+	return json.Marshal(target)
+}
+
+// apply applies a single operation to doc and returns the resulting document
+func apply(doc any, op pContr.Operation) (any, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return nil, fmt.Errorf("%s needs a value", op.Op)
+		}
+		value, err := decode(op.Value)
+		if err != nil {
+			return nil, errors.New("value is not JSON")
+		}
+		switch op.Op {
+		case "add":
+			return path.add(doc, value)
+		case "replace":
+			if doc, _, err = path.remove(doc); err != nil {
+				return nil, err
+			}
+			return path.add(doc, value)
+		default:
+			current, err := path.get(doc)
+			if err != nil {
+				return nil, err
+			}
+			if !equal(current, value) {
+				return nil, domainErr.Conflict("test of %s failed", op.Path)
+			}
+			return doc, nil
+		}
+	case "remove":
+		doc, _, err = path.remove(doc)
+		return doc, err
+	case "move", "copy":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, fmt.Errorf("from: %w", err)
+		}
+		var value any
+		if op.Op == "move" {
+			if from.isPrefixOf(path) {
+				return nil, fmt.Errorf("cannot move %s into itself", op.From)
+			}
+			if doc, value, err = from.remove(doc); err != nil {
+				return nil, fmt.Errorf("from: %w", err)
+			}
+		} else {
+			if value, err = from.get(doc); err != nil {
+				return nil, fmt.Errorf("from: %w", err)
+			}
+			value = clone(value)
+		}
+		return path.add(doc, value)
+	default:
+		return nil, fmt.Errorf("unknown op %q", op.Op)
+	}
+}
+
+// decode parses JSON, keeping numbers exact so they survive the patch unchanged
+func decode(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, errors.New("data after the JSON value")
+	}
+	return v, nil
+}
+
+// equal reports whether two decoded JSON values are the same, comparing numbers by value
+func equal(a, b any) bool {
+	switch a := a.(type) {
+	case map[string]any:
+		b, ok := b.(map[string]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for k, v := range a {
+			if w, ok := b[k]; !ok || !equal(v, w) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		b, ok := b.([]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !equal(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case json.Number:
+		b, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		x, errA := a.Float64()
+		y, errB := b.Float64()
+		return errA == nil && errB == nil && x == y
+	default:
+		return a == b
+	}
+}
+
+// clone deep-copies a decoded JSON value
+func clone(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		c := make(map[string]any, len(v))
+		for k, e := range v {
+			c[k] = clone(e)
+		}
+		return c
+	case []any:
+		c := make([]any, len(v))
+		for i, e := range v {
+			c[i] = clone(e)
+		}
+		return c
+	default:
+		return v
+	}
+}
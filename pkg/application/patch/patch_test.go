@@ -0,0 +1,177 @@
+package patch
+
+import (
+	"errors"
+	"testing"
+
+	pContr "github.com/Crud-application/pkg/contracts/patch"
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		id    int
+		name  string
+		doc   string
+		patch string
+		want  string
+	}{
+		{
+			id:    1,
+			name:  "Replace, remove and add members",
+			doc:   `{"a":"b","c":{"d":"e","f":"g"}}`,
+			patch: `{"a":"z","c":{"f":null},"h":1}`,
+			want:  `{"a":"z","c":{"d":"e"},"h":1}`,
+		},
+		{
+			id:    2,
+			name:  "Arrays are replaced as a whole",
+			doc:   `{"a":["b","c"]}`,
+			patch: `{"a":["d"]}`,
+			want:  `{"a":["d"]}`,
+		},
+		{
+			id:    3,
+			name:  "Null removes, and nulls nested in added objects are dropped",
+			doc:   `{"a":"b"}`,
+			patch: `{"a":null,"e":{"f":null}}`,
+			want:  `{"e":{}}`,
+		},
+		{
+			id:    4,
+			name:  "A non-object patch replaces the document",
+			doc:   `{"a":"b"}`,
+			patch: `["c"]`,
+			want:  `["c"]`,
+		},
+		{
+			id:    5,
+			name:  "Large numbers are kept exactly",
+			doc:   `{"version":9007199254740993}`,
+			patch: `{}`,
+			want:  `{"version":9007199254740993}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Apply(pContr.MergePatchContentType, []byte(tt.doc), []byte(tt.patch))
+			assert.NoError(t, err, "id %d", tt.id)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestJSONPatch(t *testing.T) {
+	tests := []struct {
+		id        int
+		name      string
+		doc       string
+		patch     string
+		want      string
+		wantErr   error
+		wantField string
+	}{
+		{
+			id:    1,
+			name:  "Add an object member and insert into an array",
+			doc:   `{"foo":["bar","baz"]}`,
+			patch: `[{"op":"add","path":"/foo/1","value":"qux"},{"op":"add","path":"/foo/-","value":"end"},{"op":"add","path":"/n","value":null}]`,
+			want:  `{"foo":["bar","qux","baz","end"],"n":null}`,
+		},
+		{
+			id:    2,
+			name:  "Remove and replace",
+			doc:   `{"baz":"qux","foo":["bar","qux","baz"]}`,
+			patch: `[{"op":"remove","path":"/foo/1"},{"op":"replace","path":"/baz","value":"boo"}]`,
+			want:  `{"baz":"boo","foo":["bar","baz"]}`,
+		},
+		{
+			id:    3,
+			name:  "Move and copy",
+			doc:   `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`,
+			patch: `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"},{"op":"copy","from":"/qux","path":"/copy"}]`,
+			want:  `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"},"copy":{"corge":"grault","thud":"fred"}}`,
+		},
+		{
+			id:    4,
+			name:  "Passing test, with escaped pointers and numbers compared by value",
+			doc:   `{"a/b":{"m~n":10}}`,
+			patch: `[{"op":"test","path":"/a~1b/m~0n","value":10.0},{"op":"replace","path":"/a~1b/m~0n","value":11}]`,
+			want:  `{"a/b":{"m~n":11}}`,
+		},
+		{
+			id:      5,
+			name:    "Failed test - conflict, nothing applied",
+			doc:     `{"version":3}`,
+			patch:   `[{"op":"test","path":"/version","value":2}]`,
+			wantErr: domainErr.ErrConflict,
+		},
+		{
+			id:        6,
+			name:      "Replace of a missing member - validation error naming the operation",
+			doc:       `{"a":1}`,
+			patch:     `[{"op":"test","path":"/a","value":1},{"op":"replace","path":"/b","value":2}]`,
+			wantErr:   domainErr.ErrValidation,
+			wantField: "[1]",
+		},
+		{
+			id:        7,
+			name:      "Add without a value",
+			doc:       `{}`,
+			patch:     `[{"op":"add","path":"/a"}]`,
+			wantErr:   domainErr.ErrValidation,
+			wantField: "[0]",
+		},
+		{
+			id:        8,
+			name:      "Array index out of range",
+			doc:       `{"a":[1]}`,
+			patch:     `[{"op":"add","path":"/a/2","value":3}]`,
+			wantErr:   domainErr.ErrValidation,
+			wantField: "[0]",
+		},
+		{
+			id:        9,
+			name:      "Move into its own child",
+			doc:       `{"a":{"b":{}}}`,
+			patch:     `[{"op":"move","from":"/a","path":"/a/b/c"}]`,
+			wantErr:   domainErr.ErrValidation,
+			wantField: "[0]",
+		},
+		{
+			id:        10,
+			name:      "Unknown op",
+			doc:       `{}`,
+			patch:     `[{"op":"merge","path":"/a","value":1}]`,
+			wantErr:   domainErr.ErrValidation,
+			wantField: "[0]",
+		},
+		{
+			id:      11,
+			name:    "Not an array of operations",
+			doc:     `{}`,
+			patch:   `{"op":"add","path":"/a","value":1}`,
+			wantErr: domainErr.ErrValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Apply(pContr.JSONPatchContentType, []byte(tt.doc), []byte(tt.patch))
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr), "id %d: got %v", tt.id, err)
+				if tt.wantField != "" {
+					fields := domainErr.FieldErrors(err)
+					if assert.Len(t, fields, 1) {
+						assert.Equal(t, tt.wantField, fields[0].Field)
+					}
+				}
+				return
+			}
+			assert.NoError(t, err, "id %d", tt.id)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
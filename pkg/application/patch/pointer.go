@@ -0,0 +1,156 @@
+package patch
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// pointer is a parsed JSON Pointer (RFC 6901); the empty pointer is the whole document
+type pointer []string
+
+func parsePointer(s string) (pointer, error) {
+	if s == "" {
+		return pointer{}, nil
+	}
+	if !strings.HasPrefix(s, "/") {
+		return nil, fmt.Errorf("path %q does not start with /", s)
+	}
+	tokens := strings.Split(s[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func (p pointer) String() string {
+	var b strings.Builder
+	for _, t := range p {
+		b.WriteString("/" + strings.ReplaceAll(strings.ReplaceAll(t, "~", "~0"), "/", "~1"))
+	}
+	return b.String()
+}
+
+// isPrefixOf reports whether p is a proper prefix of q
+func (p pointer) isPrefixOf(q pointer) bool {
+	if len(p) >= len(q) {
+		return false
+	}
+	for i := range p {
+		if p[i] != q[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// get returns the value p points to in doc
+func (p pointer) get(doc any) (any, error) {
+	for i, t := range p {
+		switch v := doc.(type) {
+		case map[string]any:
+			e, ok := v[t]
+			if !ok {
+				return nil, fmt.Errorf("%s does not exist", p[:i+1])
+			}
+			doc = e
+		case []any:
+			idx, err := index(t, len(v)-1)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", p[:i+1], err)
+			}
+			doc = v[idx]
+		default:
+			return nil, fmt.Errorf("%s is not an object or array", p[:i])
+		}
+	}
+	return doc, nil
+}
+
+// add sets the member p points to, inserting into arrays, and returns the updated document
+func (p pointer) add(doc, value any) (any, error) {
+	if len(p) == 0 {
+		return value, nil
+	}
+	parent, err := p[:len(p)-1].get(doc)
+	if err != nil {
+		return nil, err
+	}
+	last := p[len(p)-1]
+	switch v := parent.(type) {
+	case map[string]any:
+		v[last] = value
+	case []any:
+		idx := len(v)
+		if last != "-" {
+			if idx, err = index(last, len(v)); err != nil {
+				return nil, fmt.Errorf("%s: %w", p, err)
+			}
+		}
+		v = append(v, nil)
+		copy(v[idx+1:], v[idx:])
+		v[idx] = value
+		return p[:len(p)-1].set(doc, v)
+	default:
+		return nil, fmt.Errorf("%s is not an object or array", p[:len(p)-1])
+	}
+	return doc, nil
+}
+
+// remove deletes the member p points to and returns the updated document and the removed value
+func (p pointer) remove(doc any) (any, any, error) {
+	if len(p) == 0 {
+		return nil, doc, nil
+	}
+	value, err := p.get(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	parent, _ := p[:len(p)-1].get(doc)
+	last := p[len(p)-1]
+	switch v := parent.(type) {
+	case map[string]any:
+		delete(v, last)
+	case []any:
+		idx, _ := index(last, len(v)-1)
+		doc, err = p[:len(p)-1].set(doc, append(v[:idx:idx], v[idx+1:]...))
+	}
+	return doc, value, err
+}
+
+// set replaces the value p points to, which exists, and returns the updated document.
+// Arrays change length on insertion and removal, so their parents must be updated.
+func (p pointer) set(doc, value any) (any, error) {
+	if len(p) == 0 {
+		return value, nil
+	}
+	parent, err := p[:len(p)-1].get(doc)
+	if err != nil {
+		return nil, err
+	}
+	last := p[len(p)-1]
+	switch v := parent.(type) {
+	case map[string]any:
+		v[last] = value
+	case []any:
+		idx, _ := index(last, len(v)-1)
+		v[idx] = value
+	}
+	return doc, nil
+}
+
+// index parses an array index token, which must be at most max
+func index(token string, max int) (int, error) {
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, errors.New("is not an array index")
+	}
+	idx, err := strconv.Atoi(token)
+	if err != nil || idx < 0 {
+		return 0, errors.New("is not an array index")
+	}
+	if idx > max {
+		return 0, errors.New("is out of range")
+	}
+	return idx, nil
+}
@@ -0,0 +1,58 @@
+// Package requestCtx carries request-scoped metadata, such as who is acting
+// and the request ID, from the API layer to the application services.
+package requestCtx
+
+import (
+	"context"
+
+	"github.com/Crud-application/pkg/domain/authAgg"
+)
+
+// Actors used when no authenticated principal is acting
+const (
+	AnonymousActor = "anonymous" // Requests that did not authenticate
+	SystemActor    = "system"    // Background jobs
+)
+
+type ctxKey int
+
+const (
+	actorKey ctxKey = iota
+	requestIDKey
+	principalKey
+)
+
+// WithActor returns a copy of ctx acting on behalf of actor
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// Actor returns who ctx acts on behalf of, AnonymousActor if nobody
+func Actor(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey).(string); ok && actor != "" {
+		return actor
+	}
+	return AnonymousActor
+}
+
+// WithPrincipal returns a copy of ctx acting on behalf of the authenticated principal p
+func WithPrincipal(ctx context.Context, p *authAgg.Principal) context.Context {
+	return WithActor(context.WithValue(ctx, principalKey, p), p.Actor())
+}
+
+// Principal returns the authenticated principal of ctx, nil for anonymous requests
+func Principal(ctx context.Context) *authAgg.Principal {
+	p, _ := ctx.Value(principalKey).(*authAgg.Principal)
+	return p
+}
+
+// WithRequestID returns a copy of ctx carrying the ID of the request it serves
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestID returns the ID of the request ctx serves, if any
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"context"
+
+	authApp "github.com/Crud-application/pkg/application/auth"
+	aCOntr "github.com/Crud-application/pkg/contracts/auth"
+	uCOntr "github.com/Crud-application/pkg/contracts/user"
+	"github.com/Crud-application/pkg/domain/authAgg"
+)
+
+// Verify that Authenticator implements IAuthenticator
+var _ IAuthenticator = (*authApp.Authenticator)(nil)
+
+// Verify that SessionService implements ISessionService
+var _ ISessionService = (*authApp.SessionService)(nil)
+
+// Verify that AccountService implements IAccountService
+var _ IAccountService = (*authApp.AccountService)(nil)
+
+type IAuthenticator interface {
+	// Authenticate resolves the principal of a request from its Authorization and API key headers
+	Authenticate(ctx context.Context, authorization, apiKey string) (*authAgg.Principal, error)
+	// Schemes returns the accepted authentication schemes
+	Schemes() []string
+}
+
+type ISessionService interface {
+	// Login starts a session for the user with the given email and password
+	Login(ctx context.Context, req *aCOntr.LoginReq) (*aCOntr.TokenRes, error)
+	// Refresh rotates a refresh token, returning a new access token along with its replacement
+	Refresh(ctx context.Context, req *aCOntr.RefreshReq) (*aCOntr.TokenRes, error)
+	// Logout ends the session of a refresh token
+	Logout(ctx context.Context, req *aCOntr.RefreshReq) error
+	// ChangePassword replaces the password of the calling user and ends all their sessions
+	ChangePassword(ctx context.Context, req *aCOntr.ChangePasswordReq) error
+}
+
+type IAccountService interface {
+	// VerifyEmail marks the email a verification token was sent to as verified
+	VerifyEmail(ctx context.Context, req *uCOntr.VerifyEmailReq) error
+	// ForgotPassword emails a password reset link to the user with the given email, if there is one
+	ForgotPassword(ctx context.Context, req *aCOntr.ForgotPasswordReq) error
+	// ResetPassword sets the password of the user a reset token was sent to and ends all their sessions
+	ResetPassword(ctx context.Context, req *aCOntr.ResetPasswordReq) error
+}
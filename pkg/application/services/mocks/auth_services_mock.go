@@ -0,0 +1,213 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/application/services/auth_services.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	auth "github.com/Crud-application/pkg/contracts/auth"
+	user "github.com/Crud-application/pkg/contracts/user"
+	authAgg "github.com/Crud-application/pkg/domain/authAgg"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIAuthenticator is a mock of IAuthenticator interface.
+type MockIAuthenticator struct {
+	ctrl     *gomock.Controller
+	recorder *MockIAuthenticatorMockRecorder
+}
+
+// MockIAuthenticatorMockRecorder is the mock recorder for MockIAuthenticator.
+type MockIAuthenticatorMockRecorder struct {
+	mock *MockIAuthenticator
+}
+
+// NewMockIAuthenticator creates a new mock instance.
+func NewMockIAuthenticator(ctrl *gomock.Controller) *MockIAuthenticator {
+	mock := &MockIAuthenticator{ctrl: ctrl}
+	mock.recorder = &MockIAuthenticatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIAuthenticator) EXPECT() *MockIAuthenticatorMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *MockIAuthenticator) Authenticate(ctx context.Context, authorization, apiKey string) (*authAgg.Principal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", ctx, authorization, apiKey)
+	ret0, _ := ret[0].(*authAgg.Principal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockIAuthenticatorMockRecorder) Authenticate(ctx, authorization, apiKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockIAuthenticator)(nil).Authenticate), ctx, authorization, apiKey)
+}
+
+// Schemes mocks base method.
+func (m *MockIAuthenticator) Schemes() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Schemes")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// Schemes indicates an expected call of Schemes.
+func (mr *MockIAuthenticatorMockRecorder) Schemes() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Schemes", reflect.TypeOf((*MockIAuthenticator)(nil).Schemes))
+}
+
+// MockISessionService is a mock of ISessionService interface.
+type MockISessionService struct {
+	ctrl     *gomock.Controller
+	recorder *MockISessionServiceMockRecorder
+}
+
+// MockISessionServiceMockRecorder is the mock recorder for MockISessionService.
+type MockISessionServiceMockRecorder struct {
+	mock *MockISessionService
+}
+
+// NewMockISessionService creates a new mock instance.
+func NewMockISessionService(ctrl *gomock.Controller) *MockISessionService {
+	mock := &MockISessionService{ctrl: ctrl}
+	mock.recorder = &MockISessionServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockISessionService) EXPECT() *MockISessionServiceMockRecorder {
+	return m.recorder
+}
+
+// ChangePassword mocks base method.
+func (m *MockISessionService) ChangePassword(ctx context.Context, req *auth.ChangePasswordReq) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockISessionServiceMockRecorder) ChangePassword(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockISessionService)(nil).ChangePassword), ctx, req)
+}
+
+// Login mocks base method.
+func (m *MockISessionService) Login(ctx context.Context, req *auth.LoginReq) (*auth.TokenRes, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Login", ctx, req)
+	ret0, _ := ret[0].(*auth.TokenRes)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Login indicates an expected call of Login.
+func (mr *MockISessionServiceMockRecorder) Login(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockISessionService)(nil).Login), ctx, req)
+}
+
+// Logout mocks base method.
+func (m *MockISessionService) Logout(ctx context.Context, req *auth.RefreshReq) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockISessionServiceMockRecorder) Logout(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockISessionService)(nil).Logout), ctx, req)
+}
+
+// Refresh mocks base method.
+func (m *MockISessionService) Refresh(ctx context.Context, req *auth.RefreshReq) (*auth.TokenRes, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", ctx, req)
+	ret0, _ := ret[0].(*auth.TokenRes)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *MockISessionServiceMockRecorder) Refresh(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockISessionService)(nil).Refresh), ctx, req)
+}
+
+// MockIAccountService is a mock of IAccountService interface.
+type MockIAccountService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIAccountServiceMockRecorder
+}
+
+// MockIAccountServiceMockRecorder is the mock recorder for MockIAccountService.
+type MockIAccountServiceMockRecorder struct {
+	mock *MockIAccountService
+}
+
+// NewMockIAccountService creates a new mock instance.
+func NewMockIAccountService(ctrl *gomock.Controller) *MockIAccountService {
+	mock := &MockIAccountService{ctrl: ctrl}
+	mock.recorder = &MockIAccountServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIAccountService) EXPECT() *MockIAccountServiceMockRecorder {
+	return m.recorder
+}
+
+// ForgotPassword mocks base method.
+func (m *MockIAccountService) ForgotPassword(ctx context.Context, req *auth.ForgotPasswordReq) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForgotPassword", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForgotPassword indicates an expected call of ForgotPassword.
+func (mr *MockIAccountServiceMockRecorder) ForgotPassword(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForgotPassword", reflect.TypeOf((*MockIAccountService)(nil).ForgotPassword), ctx, req)
+}
+
+// ResetPassword mocks base method.
+func (m *MockIAccountService) ResetPassword(ctx context.Context, req *auth.ResetPasswordReq) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockIAccountServiceMockRecorder) ResetPassword(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockIAccountService)(nil).ResetPassword), ctx, req)
+}
+
+// VerifyEmail mocks base method.
+func (m *MockIAccountService) VerifyEmail(ctx context.Context, req *user.VerifyEmailReq) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyEmail", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyEmail indicates an expected call of VerifyEmail.
+func (mr *MockIAccountServiceMockRecorder) VerifyEmail(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmail", reflect.TypeOf((*MockIAccountService)(nil).VerifyEmail), ctx, req)
+}
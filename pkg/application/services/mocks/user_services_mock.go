@@ -35,6 +35,21 @@ func (m *MockIUserService) EXPECT() *MockIUserServiceMockRecorder {
 	return m.recorder
 }
 
+// ChangeUserStatus mocks base method.
+func (m *MockIUserService) ChangeUserStatus(ctx context.Context, userID string, req *user.ChangeUserStatusReq) (*user.GetUserRes, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeUserStatus", ctx, userID, req)
+	ret0, _ := ret[0].(*user.GetUserRes)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangeUserStatus indicates an expected call of ChangeUserStatus.
+func (mr *MockIUserServiceMockRecorder) ChangeUserStatus(ctx, userID, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeUserStatus", reflect.TypeOf((*MockIUserService)(nil).ChangeUserStatus), ctx, userID, req)
+}
+
 // CreateUser mocks base method.
 func (m *MockIUserService) CreateUser(ctx context.Context, req *user.CreateUserReq) (*user.CreateUserRes, error) {
 	m.ctrl.T.Helper()
@@ -65,18 +80,18 @@ func (mr *MockIUserServiceMockRecorder) DeleteUser(ctx, userID interface{}) *gom
 }
 
 // GetAllUsers mocks base method.
-func (m *MockIUserService) GetAllUsers(ctx context.Context) ([]user.GetUserRes, error) {
+func (m *MockIUserService) GetAllUsers(ctx context.Context, req *user.GetUsersReq) (*user.GetUsersRes, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllUsers", ctx)
-	ret0, _ := ret[0].([]user.GetUserRes)
+	ret := m.ctrl.Call(m, "GetAllUsers", ctx, req)
+	ret0, _ := ret[0].(*user.GetUsersRes)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAllUsers indicates an expected call of GetAllUsers.
-func (mr *MockIUserServiceMockRecorder) GetAllUsers(ctx interface{}) *gomock.Call {
+func (mr *MockIUserServiceMockRecorder) GetAllUsers(ctx, req interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllUsers", reflect.TypeOf((*MockIUserService)(nil).GetAllUsers), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllUsers", reflect.TypeOf((*MockIUserService)(nil).GetAllUsers), ctx, req)
 }
 
 // GetUser mocks base method.
@@ -94,6 +109,51 @@ func (mr *MockIUserServiceMockRecorder) GetUser(ctx, userID interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockIUserService)(nil).GetUser), ctx, userID)
 }
 
+// GetUserHistory mocks base method.
+func (m *MockIUserService) GetUserHistory(ctx context.Context, userID string, req *user.GetUserHistoryReq) (*user.GetUserHistoryRes, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserHistory", ctx, userID, req)
+	ret0, _ := ret[0].(*user.GetUserHistoryRes)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserHistory indicates an expected call of GetUserHistory.
+func (mr *MockIUserServiceMockRecorder) GetUserHistory(ctx, userID, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserHistory", reflect.TypeOf((*MockIUserService)(nil).GetUserHistory), ctx, userID, req)
+}
+
+// PatchUser mocks base method.
+func (m *MockIUserService) PatchUser(ctx context.Context, userID string, req *user.PatchUserReq) (*user.UpdateUserRes, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchUser", ctx, userID, req)
+	ret0, _ := ret[0].(*user.UpdateUserRes)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchUser indicates an expected call of PatchUser.
+func (mr *MockIUserServiceMockRecorder) PatchUser(ctx, userID, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchUser", reflect.TypeOf((*MockIUserService)(nil).PatchUser), ctx, userID, req)
+}
+
+// RestoreUser mocks base method.
+func (m *MockIUserService) RestoreUser(ctx context.Context, userID string) (*user.GetUserRes, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreUser", ctx, userID)
+	ret0, _ := ret[0].(*user.GetUserRes)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreUser indicates an expected call of RestoreUser.
+func (mr *MockIUserServiceMockRecorder) RestoreUser(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreUser", reflect.TypeOf((*MockIUserService)(nil).RestoreUser), ctx, userID)
+}
+
 // UpdateUser mocks base method.
 func (m *MockIUserService) UpdateUser(ctx context.Context, userID string, req *user.UpdateUserReq) (*user.UpdateUserRes, error) {
 	m.ctrl.T.Helper()
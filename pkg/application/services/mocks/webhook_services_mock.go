@@ -0,0 +1,125 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/application/services/webhook_services.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	webhook "github.com/Crud-application/pkg/contracts/webhook"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIWebhookService is a mock of IWebhookService interface.
+type MockIWebhookService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIWebhookServiceMockRecorder
+}
+
+// MockIWebhookServiceMockRecorder is the mock recorder for MockIWebhookService.
+type MockIWebhookServiceMockRecorder struct {
+	mock *MockIWebhookService
+}
+
+// NewMockIWebhookService creates a new mock instance.
+func NewMockIWebhookService(ctrl *gomock.Controller) *MockIWebhookService {
+	mock := &MockIWebhookService{ctrl: ctrl}
+	mock.recorder = &MockIWebhookServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIWebhookService) EXPECT() *MockIWebhookServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateWebhook mocks base method.
+func (m *MockIWebhookService) CreateWebhook(ctx context.Context, req *webhook.CreateWebhookReq) (*webhook.CreateWebhookRes, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, req)
+	ret0, _ := ret[0].(*webhook.CreateWebhookRes)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockIWebhookServiceMockRecorder) CreateWebhook(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockIWebhookService)(nil).CreateWebhook), ctx, req)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockIWebhookService) DeleteWebhook(ctx context.Context, webhookID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, webhookID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockIWebhookServiceMockRecorder) DeleteWebhook(ctx, webhookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockIWebhookService)(nil).DeleteWebhook), ctx, webhookID)
+}
+
+// GetDeliveries mocks base method.
+func (m *MockIWebhookService) GetDeliveries(ctx context.Context, webhookID string, req *webhook.GetDeliveriesReq) (*webhook.GetDeliveriesRes, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeliveries", ctx, webhookID, req)
+	ret0, _ := ret[0].(*webhook.GetDeliveriesRes)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeliveries indicates an expected call of GetDeliveries.
+func (mr *MockIWebhookServiceMockRecorder) GetDeliveries(ctx, webhookID, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveries", reflect.TypeOf((*MockIWebhookService)(nil).GetDeliveries), ctx, webhookID, req)
+}
+
+// GetWebhook mocks base method.
+func (m *MockIWebhookService) GetWebhook(ctx context.Context, webhookID string) (*webhook.WebhookRes, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhook", ctx, webhookID)
+	ret0, _ := ret[0].(*webhook.WebhookRes)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhook indicates an expected call of GetWebhook.
+func (mr *MockIWebhookServiceMockRecorder) GetWebhook(ctx, webhookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhook", reflect.TypeOf((*MockIWebhookService)(nil).GetWebhook), ctx, webhookID)
+}
+
+// GetWebhooks mocks base method.
+func (m *MockIWebhookService) GetWebhooks(ctx context.Context, req *webhook.GetWebhooksReq) (*webhook.GetWebhooksRes, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhooks", ctx, req)
+	ret0, _ := ret[0].(*webhook.GetWebhooksRes)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhooks indicates an expected call of GetWebhooks.
+func (mr *MockIWebhookServiceMockRecorder) GetWebhooks(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhooks", reflect.TypeOf((*MockIWebhookService)(nil).GetWebhooks), ctx, req)
+}
+
+// UpdateWebhook mocks base method.
+func (m *MockIWebhookService) UpdateWebhook(ctx context.Context, webhookID string, req *webhook.UpdateWebhookReq) (*webhook.WebhookRes, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhook", ctx, webhookID, req)
+	ret0, _ := ret[0].(*webhook.WebhookRes)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWebhook indicates an expected call of UpdateWebhook.
+func (mr *MockIWebhookServiceMockRecorder) UpdateWebhook(ctx, webhookID, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhook", reflect.TypeOf((*MockIWebhookService)(nil).UpdateWebhook), ctx, webhookID, req)
+}
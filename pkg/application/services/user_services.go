@@ -16,7 +16,11 @@ type IUserService interface {
 	//all the user interface methods are defined here
 	CreateUser(ctx context.Context, req *uCOntr.CreateUserReq) (*uCOntr.CreateUserRes, error)
 	DeleteUser(ctx context.Context, userID string) error
+	RestoreUser(ctx context.Context, userID string) (*uCOntr.GetUserRes, error)
+	ChangeUserStatus(ctx context.Context, userID string, req *uCOntr.ChangeUserStatusReq) (*uCOntr.GetUserRes, error)
 	GetUser(ctx context.Context, userID string) (*uCOntr.GetUserRes, error)
 	UpdateUser(ctx context.Context, userID string, req *uCOntr.UpdateUserReq) (*uCOntr.UpdateUserRes, error)
-	GetAllUsers(ctx context.Context) ([]uCOntr.GetUserRes, error)
+	PatchUser(ctx context.Context, userID string, req *uCOntr.PatchUserReq) (*uCOntr.UpdateUserRes, error)
+	GetAllUsers(ctx context.Context, req *uCOntr.GetUsersReq) (*uCOntr.GetUsersRes, error)
+	GetUserHistory(ctx context.Context, userID string, req *uCOntr.GetUserHistoryReq) (*uCOntr.GetUserHistoryRes, error)
 }
@@ -0,0 +1,20 @@
+package services
+
+import (
+	"context"
+
+	wApp "github.com/Crud-application/pkg/application/webhook"
+	wCOntr "github.com/Crud-application/pkg/contracts/webhook"
+)
+
+// Verify that WebhookService implements IWebhookService
+var _ IWebhookService = (*wApp.WebhookService)(nil)
+
+type IWebhookService interface {
+	CreateWebhook(ctx context.Context, req *wCOntr.CreateWebhookReq) (*wCOntr.CreateWebhookRes, error)
+	GetWebhook(ctx context.Context, webhookID string) (*wCOntr.WebhookRes, error)
+	GetWebhooks(ctx context.Context, req *wCOntr.GetWebhooksReq) (*wCOntr.GetWebhooksRes, error)
+	UpdateWebhook(ctx context.Context, webhookID string, req *wCOntr.UpdateWebhookReq) (*wCOntr.WebhookRes, error)
+	DeleteWebhook(ctx context.Context, webhookID string) error
+	GetDeliveries(ctx context.Context, webhookID string, req *wCOntr.GetDeliveriesReq) (*wCOntr.GetDeliveriesRes, error)
+}
@@ -0,0 +1,12 @@
+package user
+
+import (
+	"context"
+
+	uAgg "github.com/Crud-application/pkg/domain/userAgg"
+)
+
+// EmailVerifier sends users the link that verifies their email
+type EmailVerifier interface {
+	SendVerification(ctx context.Context, user *uAgg.User) error
+}
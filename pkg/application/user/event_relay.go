@@ -0,0 +1,73 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Crud-application/pkg/domain/domainEvent"
+	uRepo "github.com/Crud-application/pkg/domain/persistence"
+)
+
+// EventRelay periodically publishes the domain events waiting in the outbox, oldest first.
+// An event is marked published only after the publisher accepted it, so a crash in
+// between publishes it again: subscribers must tolerate duplicates.
+type EventRelay struct {
+	outbox    uRepo.IOutboxRepository
+	publisher domainEvent.EventPublisher
+	interval  time.Duration
+	batchSize int
+	now       Clock
+}
+
+func NewEventRelay(outbox uRepo.IOutboxRepository, publisher domainEvent.EventPublisher, now Clock, interval time.Duration, batchSize int) *EventRelay {
+	return &EventRelay{
+		outbox:    outbox,
+		publisher: publisher,
+		interval:  interval,
+		batchSize: batchSize,
+		now:       now,
+	}
+}
+
+// Run relays once per interval until ctx is done
+func (r *EventRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.Relay(ctx); err != nil {
+			log.Printf("Error relaying events: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Relay publishes pending events until none are left and returns how many were published.
+// It stops at the first failure so that events are never published out of order.
+func (r *EventRelay) Relay(ctx context.Context) (int, error) {
+	published := 0
+	for {
+		events, err := r.outbox.GetPending(ctx, r.batchSize)
+		if err != nil {
+			return published, fmt.Errorf("failed to get pending events: %w", err)
+		}
+		for _, e := range events {
+			if err := r.publisher.Publish(ctx, e); err != nil {
+				return published, fmt.Errorf("failed to publish event %s: %w", e.ID, err)
+			}
+			if err := r.outbox.MarkPublished(ctx, e.ID, r.now()); err != nil {
+				return published, fmt.Errorf("failed to mark event %s published: %w", e.ID, err)
+			}
+			published++
+		}
+		if len(events) < r.batchSize {
+			return published, nil
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Crud-application/pkg/domain/domainEvent"
+	mockEvent "github.com/Crud-application/pkg/domain/domainEvent/mocks"
+	mockRepo "github.com/Crud-application/pkg/domain/persistence/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+func TestEventRelay_Relay(t *testing.T) {
+	e1 := domainEvent.Event{ID: "e1", Type: "user.created", AggregateID: "u1"}
+	e2 := domainEvent.Event{ID: "e2", Type: "user.updated", AggregateID: "u1"}
+	e3 := domainEvent.Event{ID: "e3", Type: "user.deleted", AggregateID: "u1"}
+
+	type relayFields struct {
+		outbox    *mockRepo.MockIOutboxRepository
+		publisher *mockEvent.MockEventPublisher
+	}
+	tests := []struct {
+		id         int
+		name       string
+		beforeTest func(f *relayFields)
+		want       int
+		wantErr    bool
+	}{
+		{
+			id:   1,
+			name: "Pending events are published in order, batch after batch",
+			beforeTest: func(f *relayFields) {
+				gomock.InOrder(
+					f.outbox.EXPECT().GetPending(gomock.Any(), 2).Return([]domainEvent.Event{e1, e2}, nil),
+					f.publisher.EXPECT().Publish(gomock.Any(), e1).Return(nil),
+					f.outbox.EXPECT().MarkPublished(gomock.Any(), "e1", testNow).Return(nil),
+					f.publisher.EXPECT().Publish(gomock.Any(), e2).Return(nil),
+					f.outbox.EXPECT().MarkPublished(gomock.Any(), "e2", testNow).Return(nil),
+					f.outbox.EXPECT().GetPending(gomock.Any(), 2).Return([]domainEvent.Event{e3}, nil),
+					f.publisher.EXPECT().Publish(gomock.Any(), e3).Return(nil),
+					f.outbox.EXPECT().MarkPublished(gomock.Any(), "e3", testNow).Return(nil),
+				)
+			},
+			want: 3,
+		},
+		{
+			id:   2,
+			name: "A failed publish stops the relay before later events",
+			beforeTest: func(f *relayFields) {
+				gomock.InOrder(
+					f.outbox.EXPECT().GetPending(gomock.Any(), 2).Return([]domainEvent.Event{e1, e2}, nil),
+					f.publisher.EXPECT().Publish(gomock.Any(), e1).Return(errors.New("broker down")),
+				)
+			},
+			want:    0,
+			wantErr: true,
+		},
+		{
+			id:   3,
+			name: "Outbox error",
+			beforeTest: func(f *relayFields) {
+				f.outbox.EXPECT().GetPending(gomock.Any(), 2).Return(nil, errors.New("outbox error"))
+			},
+			want:    0,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			f := relayFields{
+				outbox:    mockRepo.NewMockIOutboxRepository(ctrl),
+				publisher: mockEvent.NewMockEventPublisher(ctrl),
+			}
+			tt.beforeTest(&f)
+
+			r := NewEventRelay(f.outbox, f.publisher, mockClock, time.Second, 2)
+
+			got, err := r.Relay(context.Background())
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("ID %v Relay() = %v, %v want %v, wantErr %v", tt.id, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,54 +1,171 @@
 package user
 
 import (
+	"encoding/json"
+	"reflect"
+	"sort"
+
 	uCOntr "github.com/Crud-application/pkg/contracts/user"
+	"github.com/Crud-application/pkg/domain/auditAgg"
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"github.com/Crud-application/pkg/domain/query"
 	uAgg "github.com/Crud-application/pkg/domain/userAgg"
 )
 
 func fromCreateUserReq(userID string, req *uCOntr.CreateUserReq) (*uAgg.User, error) {
-	return uAgg.NewUser(
+	u, err := uAgg.NewUser(
 		userID,
 		req.Name,
 		req.Email,
 		req.PhoneNumber,
 	)
+	if err != nil || req.Password == "" {
+		return u, err
+	}
+	if err := u.SetPassword(req.Password); err != nil {
+		return nil, err
+	}
+	return u, nil
 }
 func toCreateUserRes(user *uAgg.User) *uCOntr.CreateUserRes {
 	return &uCOntr.CreateUserRes{
-		ID:          user.ID,
-		Name:        user.Name,
-		Email:       user.Email,
-		PhoneNumber: user.PhoneNumber,
+		ID:            user.ID,
+		Name:          user.Name,
+		Email:         user.Email.String(),
+		PhoneNumber:   user.PhoneNumber.String(),
+		EmailVerified: user.EmailVerified,
+		Status:        string(user.Status),
+		StatusReason:  user.StatusReason,
+		Version:       user.Version,
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
+		CreatedBy:     user.CreatedBy,
+		UpdatedBy:     user.UpdatedBy,
 	}
 }
 
 func toGetUserRes(user *uAgg.User) *uCOntr.GetUserRes {
 	userRes := &uCOntr.GetUserRes{
-		ID:          user.ID,
-		Name:        user.Name,
-		Email:       user.Email,
-		PhoneNumber: user.PhoneNumber,
+		ID:            user.ID,
+		Name:          user.Name,
+		Email:         user.Email.String(),
+		PhoneNumber:   user.PhoneNumber.String(),
+		EmailVerified: user.EmailVerified,
+		Status:        string(user.Status),
+		StatusReason:  user.StatusReason,
+		Version:       user.Version,
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
+		CreatedBy:     user.CreatedBy,
+		UpdatedBy:     user.UpdatedBy,
+		DeletedAt:     user.DeletedAt,
 	}
 	return userRes
 }
 
+// fromPatchedUser reads the name, email and phone number of a patched user document.
+// They are required, so a patch cannot clear them. The other fields are read-only and
+// must be as they are in the original document doc.
+func fromPatchedUser(doc, patched []byte) (name, email, phoneNumber string, err error) {
+	var before, after map[string]any
+	if err := json.Unmarshal(doc, &before); err != nil {
+		return "", "", "", err
+	}
+	if err := json.Unmarshal(patched, &after); err != nil {
+		return "", "", "", domainErr.Validation("the patched user is not an object")
+	}
+
+	var errs []domainErr.FieldError
+	mutable := []struct {
+		field string
+		dst   *string
+	}{{uAgg.FieldName, &name}, {uAgg.FieldEmail, &email}, {uAgg.FieldPhoneNumber, &phoneNumber}}
+	for _, m := range mutable {
+		switch v := after[m.field].(type) {
+		case string:
+			*m.dst = v
+		case nil:
+			errs = append(errs, domainErr.FieldError{Field: m.field, Message: "is required and cannot be cleared"})
+		default:
+			errs = append(errs, domainErr.FieldError{Field: m.field, Message: "must be a string"})
+		}
+		delete(before, m.field)
+		delete(after, m.field)
+	}
+
+	fields := make([]string, 0, len(before)+len(after))
+	for field := range before {
+		fields = append(fields, field)
+	}
+	for field := range after {
+		if _, ok := before[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		if !reflect.DeepEqual(before[field], after[field]) {
+			errs = append(errs, domainErr.FieldError{Field: field, Message: "is read-only"})
+		}
+	}
+
+	if len(errs) > 0 {
+		return "", "", "", domainErr.Validation("invalid user patch", errs...)
+	}
+	return name, email, phoneNumber, nil
+}
+
 func toUpdateUserRes(user *uAgg.User) *uCOntr.UpdateUserRes {
 	return &uCOntr.UpdateUserRes{
-		ID:          user.ID,
-		Name:        user.Name,
-		Email:       user.Email,
-		PhoneNumber: user.PhoneNumber,
+		ID:            user.ID,
+		Name:          user.Name,
+		Email:         user.Email.String(),
+		PhoneNumber:   user.PhoneNumber.String(),
+		EmailVerified: user.EmailVerified,
+		Status:        string(user.Status),
+		StatusReason:  user.StatusReason,
+		Version:       user.Version,
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
+		CreatedBy:     user.CreatedBy,
+		UpdatedBy:     user.UpdatedBy,
+	}
+}
+func toGetUsersRes(page *uAgg.UserPage) *uCOntr.GetUsersRes {
+	res := &uCOntr.GetUsersRes{
+		Users:      make([]uCOntr.GetUserRes, 0, len(page.Users)),
+		Total:      page.Page.Total,
+		Limit:      page.Page.Limit,
+		Offset:     page.Page.Offset,
+		NextCursor: query.EncodeCursor(page.Page.NextCursor),
+	}
+	for _, user := range page.Users {
+		res.Users = append(res.Users, *toGetUserRes(&user))
 	}
+	return res
 }
-func toGetAllUsers(users []uAgg.User) []uCOntr.GetUserRes {
-	var res []uCOntr.GetUserRes
-	for _, user := range users {
-		res = append(res, uCOntr.GetUserRes{
-			ID:          user.ID,
-			Name:        user.Name,
-			Email:       user.Email,
-			PhoneNumber: user.PhoneNumber,
-		})
+
+func toGetUserHistoryRes(page *auditAgg.EntryPage) *uCOntr.GetUserHistoryRes {
+	res := &uCOntr.GetUserHistoryRes{
+		Entries:    make([]uCOntr.AuditEntryRes, 0, len(page.Entries)),
+		Total:      page.Page.Total,
+		Limit:      page.Page.Limit,
+		Offset:     page.Page.Offset,
+		NextCursor: query.EncodeCursor(page.Page.NextCursor),
+	}
+	for _, e := range page.Entries {
+		entry := uCOntr.AuditEntryRes{
+			ID:        e.ID,
+			Action:    string(e.Action),
+			Actor:     e.Actor,
+			RequestID: e.RequestID,
+			Timestamp: e.Timestamp,
+			Changes:   make([]uCOntr.FieldChangeRes, 0, len(e.Changes)),
+		}
+		for _, c := range e.Changes {
+			entry.Changes = append(entry.Changes, uCOntr.FieldChangeRes{Field: c.Field, Before: c.Before, After: c.After})
+		}
+		res.Entries = append(res.Entries, entry)
 	}
 	return res
 }
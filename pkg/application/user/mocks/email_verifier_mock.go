@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/application/user/email_verifier.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	userAgg "github.com/Crud-application/pkg/domain/userAgg"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockEmailVerifier is a mock of EmailVerifier interface.
+type MockEmailVerifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockEmailVerifierMockRecorder
+}
+
+// MockEmailVerifierMockRecorder is the mock recorder for MockEmailVerifier.
+type MockEmailVerifierMockRecorder struct {
+	mock *MockEmailVerifier
+}
+
+// NewMockEmailVerifier creates a new mock instance.
+func NewMockEmailVerifier(ctrl *gomock.Controller) *MockEmailVerifier {
+	mock := &MockEmailVerifier{ctrl: ctrl}
+	mock.recorder = &MockEmailVerifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEmailVerifier) EXPECT() *MockEmailVerifierMockRecorder {
+	return m.recorder
+}
+
+// SendVerification mocks base method.
+func (m *MockEmailVerifier) SendVerification(ctx context.Context, user *userAgg.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendVerification", ctx, user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendVerification indicates an expected call of SendVerification.
+func (mr *MockEmailVerifierMockRecorder) SendVerification(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendVerification", reflect.TypeOf((*MockEmailVerifier)(nil).SendVerification), ctx, user)
+}
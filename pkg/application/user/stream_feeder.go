@@ -0,0 +1,43 @@
+package user
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Crud-application/pkg/domain/domainEvent"
+	uRepo "github.com/Crud-application/pkg/domain/persistence"
+)
+
+// StreamFeeder feeds the user change stream from the outbox of every instance,
+// rather than only from the events this instance relays. Without a watcher it does nothing.
+type StreamFeeder struct {
+	watcher uRepo.IOutboxWatcher
+	bus     domainEvent.EventPublisher
+	retry   time.Duration
+}
+
+func NewStreamFeeder(watcher uRepo.IOutboxWatcher, bus domainEvent.EventPublisher, retry time.Duration) *StreamFeeder {
+	return &StreamFeeder{
+		watcher: watcher,
+		bus:     bus,
+		retry:   retry,
+	}
+}
+
+// Run watches the outbox until ctx is done, watching again after retry when it fails
+func (f *StreamFeeder) Run(ctx context.Context) {
+	if f.watcher == nil {
+		return
+	}
+	for {
+		if err := f.watcher.Watch(ctx, f.bus); err != nil {
+			log.Printf("Error watching the outbox: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(f.retry):
+		}
+	}
+}
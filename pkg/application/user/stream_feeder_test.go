@@ -0,0 +1,45 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Crud-application/pkg/domain/domainEvent"
+	mockEvent "github.com/Crud-application/pkg/domain/domainEvent/mocks"
+	mockRepo "github.com/Crud-application/pkg/domain/persistence/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+func TestStreamFeeder_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	watcher := mockRepo.NewMockIOutboxWatcher(ctrl)
+	bus := mockEvent.NewMockEventPublisher(ctrl)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// A failed watch is retried; the feeder stops once ctx is done
+	gomock.InOrder(
+		watcher.EXPECT().Watch(gomock.Any(), bus).Return(errors.New("not a replica set")),
+		watcher.EXPECT().Watch(gomock.Any(), bus).DoAndReturn(func(ctx context.Context, _ domainEvent.EventPublisher) error {
+			cancel()
+			return nil
+		}),
+	)
+
+	done := make(chan struct{})
+	go func() {
+		NewStreamFeeder(watcher, bus, time.Millisecond).Run(ctx)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run() did not stop after ctx was done")
+	}
+
+	// Without a watcher the relay feeds the stream and Run returns at once
+	NewStreamFeeder(nil, bus, time.Millisecond).Run(context.Background())
+}
@@ -1,26 +1,91 @@
 package user
 
-import "github.com/Crud-application/pkg/domain/userAgg"
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/Crud-application/pkg/domain/auditAgg"
+	"github.com/Crud-application/pkg/domain/domainEvent"
+	"github.com/Crud-application/pkg/domain/userAgg"
+	"github.com/golang/mock/gomock"
+)
+
+// testNow is the time told by the clock of the services under test
+var testNow = time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+func mockClock() time.Time {
+	return testNow
+}
 
 var userAggData1 = CreateSampleUser(
 	"mocked-uuid1",
 	"shaharyar alam",
 	"shaharyar@example.com",
-	123456789,
+	"+91123456789",
 )
 
 var userAggData2 = CreateSampleUser(
 	"mocked-uuid2",
 	"John Doe",
 	"johndoe@example.com",
-	987654321,
+	"+91987654321",
 )
 
-func CreateSampleUser(id, name, email string, phone_number int64) userAgg.User {
+func CreateSampleUser(id, name, email, phone_number string) userAgg.User {
 	return userAgg.User{
 		ID:          id,
 		Name:        name,
-		Email:       email,
-		PhoneNumber: phone_number,
+		Email:       userAgg.Email(email),
+		PhoneNumber: userAgg.PhoneNumber(phone_number),
 	}
 }
+
+// withEvent adds the event of type t that the user raised when it was last
+// created or updated, to build the user the repository is expected to receive
+func withEvent(u *userAgg.User, t domainEvent.Type) *userAgg.User {
+	u.Events = append(u.Events, domainEvent.Event{
+		Type:        t,
+		AggregateID: u.ID,
+		Actor:       u.UpdatedBy,
+		OccurredAt:  u.UpdatedAt,
+		Payload: map[string]any{
+			"id":             u.ID,
+			"name":           u.Name,
+			"email":          u.Email.String(),
+			"phone_number":   u.PhoneNumber.String(),
+			"email_verified": u.EmailVerified,
+			"status":         string(u.Status),
+		},
+	})
+	return u
+}
+
+// withAudit adds the audit entry of the change the user was last marked with,
+// to build the user the repository is expected to receive
+func withAudit(u *userAgg.User, action auditAgg.Action, changes ...auditAgg.FieldChange) *userAgg.User {
+	u.AuditEntries = append(u.AuditEntries, auditAgg.Entry{
+		ResourceID: u.ID,
+		Action:     action,
+		Actor:      u.UpdatedBy,
+		Timestamp:  u.UpdatedAt,
+		Changes:    changes,
+	})
+	return u
+}
+
+// auditedAs matches a user carrying only the given audit entry, whatever its other fields
+func auditedAs(entry auditAgg.Entry) gomock.Matcher {
+	return auditMatcher{entry}
+}
+
+type auditMatcher []auditAgg.Entry
+
+func (m auditMatcher) Matches(x any) bool {
+	u, ok := x.(*userAgg.User)
+	return ok && reflect.DeepEqual(u.AuditEntries, []auditAgg.Entry(m))
+}
+
+func (m auditMatcher) String() string {
+	return fmt.Sprintf("has audit entries %v", []auditAgg.Entry(m))
+}
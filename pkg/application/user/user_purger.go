@@ -0,0 +1,57 @@
+package user
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Crud-application/pkg/application/requestCtx"
+	uRepo "github.com/Crud-application/pkg/domain/persistence"
+)
+
+// UserPurger periodically hard-deletes users that were soft-deleted longer ago than the retention period
+type UserPurger struct {
+	uRepo     uRepo.IUserRepository
+	retention time.Duration
+	interval  time.Duration
+	now       Clock
+}
+
+func NewUserPurger(uRepo uRepo.IUserRepository, now Clock, retention, interval time.Duration) *UserPurger {
+	return &UserPurger{
+		uRepo:     uRepo,
+		retention: retention,
+		interval:  interval,
+		now:       now,
+	}
+}
+
+// Run purges once per interval until ctx is done
+func (p *UserPurger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := p.Purge(ctx); err != nil {
+			log.Printf("Error purging deleted users: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Purge hard-deletes the users past retention and returns how many were removed
+func (p *UserPurger) Purge(ctx context.Context) (int64, error) {
+	now := p.now()
+	n, err := p.uRepo.PurgeDeletedUsers(ctx, now.Add(-p.retention), now, requestCtx.SystemActor)
+	if err != nil {
+		return 0, err
+	}
+	if n > 0 {
+		log.Printf("Purged %d deleted users", n)
+	}
+	return n, nil
+}
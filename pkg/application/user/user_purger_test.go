@@ -0,0 +1,61 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Crud-application/pkg/application/requestCtx"
+	mockRepo "github.com/Crud-application/pkg/domain/persistence/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+func TestUserPurger_Purge(t *testing.T) {
+	now := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		id         int
+		name       string
+		beforeTest func(f *fields)
+		want       int64
+		wantErr    bool
+	}{
+		{
+			id:   1,
+			name: "Users deleted before the retention period are purged",
+			beforeTest: func(f *fields) {
+				f.uRepoMocks.EXPECT().
+					PurgeDeletedUsers(gomock.Any(), now.Add(-30*24*time.Hour), now, requestCtx.SystemActor).
+					Return(int64(2), nil).Times(1)
+			},
+			want: 2,
+		},
+		{
+			id:   2,
+			name: "Repository error",
+			beforeTest: func(f *fields) {
+				f.uRepoMocks.EXPECT().
+					PurgeDeletedUsers(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(int64(0), errors.New("repository error")).Times(1)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			f := fields{uRepoMocks: mockRepo.NewMockIUserRepository(ctrl)}
+			tt.beforeTest(&f)
+
+			p := NewUserPurger(f.uRepoMocks, func() time.Time { return now }, 30*24*time.Hour, time.Hour)
+
+			got, err := p.Purge(context.Background())
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("ID %v Purge() = %v, %v want %v, wantErr %v", tt.id, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
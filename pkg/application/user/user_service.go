@@ -2,52 +2,126 @@ package user
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"slices"
+	"time"
 
+	"github.com/Crud-application/pkg/application/patch"
+	"github.com/Crud-application/pkg/application/requestCtx"
 	uCOntr "github.com/Crud-application/pkg/contracts/user"
+	"github.com/Crud-application/pkg/domain/auditAgg"
+	"github.com/Crud-application/pkg/domain/domainErr"
 	uRepo "github.com/Crud-application/pkg/domain/persistence"
+	"github.com/Crud-application/pkg/domain/query"
+	uAgg "github.com/Crud-application/pkg/domain/userAgg"
 )
 
 type UserService struct {
 	uRepo        uRepo.IUserRepository
+	aRepo        uRepo.IAuditRepository
+	verifier     EmailVerifier
 	generateUUID UUIDGenerator
+	now          Clock
 }
 
-func NewUserService(uRepo uRepo.IUserRepository, generateUUID UUIDGenerator) *UserService {
+func NewUserService(uRepo uRepo.IUserRepository, aRepo uRepo.IAuditRepository, verifier EmailVerifier, generateUUID UUIDGenerator, now Clock) *UserService {
 	return &UserService{
 		uRepo:        uRepo,
+		aRepo:        aRepo,
+		verifier:     verifier,
 		generateUUID: generateUUID,
+		now:          now,
 	}
 }
 
 type UUIDGenerator func() string
 
+// statusActions are the audit actions of the changes to each status
+var statusActions = map[uAgg.Status]auditAgg.Action{
+	uAgg.StatusActive:      auditAgg.ActionActivate,
+	uAgg.StatusSuspended:   auditAgg.ActionSuspend,
+	uAgg.StatusDeactivated: auditAgg.ActionDeactivate,
+}
+
+// Clock tells the current time, injected so tests can fix it
+type Clock func() time.Time
+
 func (us *UserService) CreateUser(ctx context.Context, req *uCOntr.CreateUserReq) (*uCOntr.CreateUserRes, error) {
 	userID := us.generateUUID()
-	//userID := uuid.New().String() //--> getting problem while mocking
-	fmt.Printf("hello")
-	// Create a new user
-	fmt.Println(userID)
 	newUser, err := fromCreateUserReq(userID, req)
-	//fmt.Printf("hello")
 	if err != nil {
-		return nil, fmt.Errorf("request validation failed: %v", err)
+		return nil, fmt.Errorf("request validation failed: %w", err)
 	}
+	newUser.MarkCreated(us.now(), requestCtx.Actor(ctx))
+	RecordAudit(ctx, newUser, auditAgg.ActionCreate, auditAgg.Diff(nil, newUser.AuditSnapshot()))
 	err = us.uRepo.AddUser(ctx, newUser)
 	if err != nil {
 		return nil, err
 	}
+	us.sendVerification(ctx, newUser)
 	return toCreateUserRes(newUser), nil
 }
 
+// DeleteUser soft-deletes a user, who can be restored until the purge removes them
 func (us *UserService) DeleteUser(ctx context.Context, userID string) error {
-	err := us.uRepo.DeleteUser(ctx, userID)
+	user, err := us.uRepo.GetUser(ctx, userID)
 	if err != nil {
-		return fmt.Errorf("failed to delete user: %v", err)
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+	before := user.AuditSnapshot()
+	user.MarkDeleted(us.now(), requestCtx.Actor(ctx))
+	RecordAudit(ctx, user, auditAgg.ActionDelete, auditAgg.Diff(before, user.AuditSnapshot()))
+
+	err = us.uRepo.DeleteUser(ctx, user)
+	if err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
 	}
 	return nil
 }
 
+// RestoreUser undoes the deletion of a user and returns it
+func (us *UserService) RestoreUser(ctx context.Context, userID string) (*uCOntr.GetUserRes, error) {
+	user, err := us.uRepo.GetUserIncludingDeleted(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore user: %w", err)
+	}
+	before := user.AuditSnapshot()
+	if err := user.MarkRestored(us.now(), requestCtx.Actor(ctx)); err != nil {
+		return nil, fmt.Errorf("failed to restore user: %w", err)
+	}
+	RecordAudit(ctx, user, auditAgg.ActionRestore, auditAgg.Diff(before, user.AuditSnapshot()))
+	if err := us.uRepo.RestoreUser(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to restore user: %w", err)
+	}
+	return toGetUserRes(user), nil
+}
+
+// ChangeUserStatus moves a user to the status of the request, e.g. to suspend them,
+// as far as the lifecycle of users allows, and returns it
+func (us *UserService) ChangeUserStatus(ctx context.Context, userID string, req *uCOntr.ChangeUserStatusReq) (*uCOntr.GetUserRes, error) {
+	status, err := uAgg.ParseStatus(req.Status)
+	if err != nil {
+		return nil, domainErr.Validation("invalid status change", domainErr.FieldError{Field: uAgg.FieldStatus, Message: err.Error()})
+	}
+	user, err := us.uRepo.GetUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	before := user.AuditSnapshot()
+	if err := user.ChangeStatus(status, req.Reason, us.now(), requestCtx.Actor(ctx)); err != nil {
+		return nil, err
+	}
+	RecordAudit(ctx, user, statusActions[status], auditAgg.Diff(before, user.AuditSnapshot()))
+
+	if err := us.uRepo.UpdateUser(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to change user status: %w", err)
+	}
+	return toGetUserRes(user), nil
+}
+
 func (us *UserService) GetUser(ctx context.Context, userID string) (*uCOntr.GetUserRes, error) {
 	user, err := us.uRepo.GetUser(ctx, userID)
 	if err != nil {
@@ -60,32 +134,121 @@ func (us *UserService) UpdateUser(ctx context.Context, userID string, req *uCOnt
 	// Fetch the existing user from the repository
 	existingUser, err := us.uRepo.GetUser(ctx, userID)
 	if err != nil {
-		return nil, fmt.Errorf("user not found: %v", err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if len(req.IfMatch) > 0 && !slices.Contains(req.IfMatch, existingUser.Version) {
+		return nil, domainErr.PreconditionFailed("user %s is at version %d", userID, existingUser.Version)
+	}
+	// Update fields only if they are provided in the request, with the same invariants as NewUser
+	res, err := us.update(ctx, existingUser, req.Name, req.Email, req.PhoneNumber)
+	return res, ifMatchFailed(err, req.IfMatch, userID)
+}
+
+// PatchUser applies a merge patch or JSON Patch to the document of a user, as returned
+// by GetUser, and stores the result. Only the name, email and phone number may change.
+func (us *UserService) PatchUser(ctx context.Context, userID string, req *uCOntr.PatchUserReq) (*uCOntr.UpdateUserRes, error) {
+	existingUser, err := us.uRepo.GetUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if len(req.IfMatch) > 0 && !slices.Contains(req.IfMatch, existingUser.Version) {
+		return nil, domainErr.PreconditionFailed("user %s is at version %d", userID, existingUser.Version)
+	}
+
+	doc, err := json.Marshal(toGetUserRes(existingUser))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode user: %w", err)
+	}
+	patched, err := patch.Apply(req.ContentType, doc, req.Patch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch user %s: %w", userID, err)
 	}
-	// Update fields only if they are provided in the request
-	if req.Name != nil {
-		existingUser.Name = *req.Name
+	name, email, phoneNumber, err := fromPatchedUser(doc, patched)
+	if err != nil {
+		return nil, fmt.Errorf("request validation failed: %w", err)
 	}
-	if req.Email != nil {
-		existingUser.Email = *req.Email
+	res, err := us.update(ctx, existingUser, &name, &email, &phoneNumber)
+	return res, ifMatchFailed(err, req.IfMatch, userID)
+}
+
+// ifMatchFailed reports losing the race to store a user whose version was checked against
+// If-Match as a failed precondition, like a version that did not match in the first place
+func ifMatchFailed(err error, ifMatch []int64, userID string) error {
+	if len(ifMatch) > 0 && errors.Is(err, domainErr.ErrStaleVersion) {
+		return domainErr.PreconditionFailed("user %s was modified concurrently", userID)
 	}
-	if req.PhoneNumber != nil {
-		existingUser.PhoneNumber = *req.PhoneNumber
+	return err
+}
+
+// update changes the fields of user that are not nil, stores it and records the change
+func (us *UserService) update(ctx context.Context, user *uAgg.User, name, email, phoneNumber *string) (*uCOntr.UpdateUserRes, error) {
+	before, oldEmail := user.AuditSnapshot(), user.Email
+	if err := user.Update(name, email, phoneNumber); err != nil {
+		return nil, fmt.Errorf("request validation failed: %w", err)
 	}
+	user.MarkUpdated(us.now(), requestCtx.Actor(ctx))
+	RecordAudit(ctx, user, auditAgg.ActionUpdate, auditAgg.Diff(before, user.AuditSnapshot()))
 
 	// Save the updated user back to the repository
-	err = us.uRepo.UpdateUser(ctx, existingUser)
+	if err := us.uRepo.UpdateUser(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+	if user.Email != oldEmail {
+		us.sendVerification(ctx, user)
+	}
+	return toUpdateUserRes(user), nil
+}
+
+func (us *UserService) GetAllUsers(ctx context.Context, req *uCOntr.GetUsersReq) (*uCOntr.GetUsersRes, error) {
+	page, err := query.NewPageRequest(req.Limit, req.Cursor, req.Offset)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update user: %v", err)
+		return nil, err
+	}
+	spec, err := uAgg.NewQuerySpec(req.Filters, req.Search, req.Sort, page)
+	if err != nil {
+		return nil, err
+	}
+	spec.IncludeDeleted = req.IncludeDeleted
+
+	// Get one page of matching users
+	users, err := us.uRepo.GetAllUser(ctx, spec)
+	if err != nil {
+		return nil, err
 	}
-	return toUpdateUserRes(existingUser), nil
+	return toGetUsersRes(users), nil
 }
 
-func (us *UserService) GetAllUsers(ctx context.Context) ([]uCOntr.GetUserRes, error) {
-	// Get users
-	users, err := us.uRepo.GetAllUser(ctx)
+// GetUserHistory returns one page of the audit entries of a user, newest first.
+// The history outlives the user, so it is available after deletion and purge.
+func (us *UserService) GetUserHistory(ctx context.Context, userID string, req *uCOntr.GetUserHistoryReq) (*uCOntr.GetUserHistoryRes, error) {
+	page, err := query.NewPageRequest(req.Limit, req.Cursor, req.Offset)
 	if err != nil {
 		return nil, err
 	}
-	return toGetAllUsers(users), nil
+	entries, err := us.aRepo.GetEntries(ctx, userID, page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user history: %w", err)
+	}
+	return toGetUserHistoryRes(entries), nil
+}
+
+// RecordAudit records the last change to user, made by its UpdatedBy at its UpdatedAt,
+// for the repository to store atomically with the user
+func RecordAudit(ctx context.Context, user *uAgg.User, action auditAgg.Action, changes []auditAgg.FieldChange) {
+	user.AuditEntries = append(user.AuditEntries, auditAgg.Entry{
+		ResourceID: user.ID,
+		Action:     action,
+		Actor:      user.UpdatedBy,
+		RequestID:  requestCtx.RequestID(ctx),
+		Timestamp:  user.UpdatedAt,
+		Changes:    changes,
+	})
+}
+
+// sendVerification emails the user a link verifying their new email. A failure is
+// logged rather than returned, since the change is already stored.
+func (us *UserService) sendVerification(ctx context.Context, user *uAgg.User) {
+	if err := us.verifier.SendVerification(ctx, user); err != nil {
+		log.Printf("Error sending verification email to user %s: %v", user.ID, err)
+	}
 }
@@ -5,18 +5,34 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/Crud-application/pkg/application/requestCtx"
+	uMocks "github.com/Crud-application/pkg/application/user/mocks"
+	"github.com/Crud-application/pkg/contracts/patch"
 	uContr "github.com/Crud-application/pkg/contracts/user"
+	"github.com/Crud-application/pkg/domain/auditAgg"
+	"github.com/Crud-application/pkg/domain/authAgg"
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"github.com/Crud-application/pkg/domain/domainEvent"
+	uRepo "github.com/Crud-application/pkg/domain/persistence"
 	mockRepo "github.com/Crud-application/pkg/domain/persistence/mocks"
+	"github.com/Crud-application/pkg/domain/query"
 	"github.com/Crud-application/pkg/domain/userAgg"
 	uAgg "github.com/Crud-application/pkg/domain/userAgg"
+	"github.com/Crud-application/pkg/infrastructure/persistence/audit"
+	"github.com/Crud-application/pkg/infrastructure/persistence/outbox"
+	uPersist "github.com/Crud-application/pkg/infrastructure/persistence/user"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 )
 
 type fields struct {
 	uRepoMocks *mockRepo.MockIUserRepository
+	aRepoMocks *mockRepo.MockIAuditRepository
+	verifier   *uMocks.MockEmailVerifier
 }
 
 // Test function for CreateUser
@@ -24,7 +40,14 @@ func TestUserService_CreateUser(t *testing.T) {
 	req := uContr.CreateUserReq{
 		Name:        "John Doe",
 		Email:       "johndoe@example.com",
-		PhoneNumber: 123456789,
+		PhoneNumber: "+91123456789",
+	}
+	createdChanges := []auditAgg.FieldChange{
+		{Field: "email", After: "johndoe@example.com"},
+		{Field: "email_verified", After: false},
+		{Field: "name", After: "John Doe"},
+		{Field: "phone_number", After: "+91123456789"},
+		{Field: "status", After: "pending"},
 	}
 	type args struct {
 		ctx context.Context
@@ -50,19 +73,32 @@ func TestUserService_CreateUser(t *testing.T) {
 			},
 			beforeTest: func(f *fields, t *test) {
 				f.uRepoMocks.EXPECT().
-					AddUser(gomock.Any(), &uAgg.User{
+					AddUser(gomock.Any(), withAudit(withEvent(&uAgg.User{
 						ID:          "mocked-uuid",
 						Name:        "John Doe",
 						Email:       "johndoe@example.com",
-						PhoneNumber: 123456789,
-					}).
+						PhoneNumber: "+91123456789",
+						Version:     1,
+						Status:      uAgg.StatusPending,
+						CreatedAt:   testNow,
+						UpdatedAt:   testNow,
+						CreatedBy:   requestCtx.AnonymousActor,
+						UpdatedBy:   requestCtx.AnonymousActor,
+					}, uAgg.EventUserCreated), auditAgg.ActionCreate, createdChanges...)).
 					Return(nil).Times(1)
+				f.verifier.EXPECT().SendVerification(gomock.Any(), gomock.Any()).Return(nil).Times(1)
 			},
 			expectedRes: &uContr.CreateUserRes{
 				ID:          "mocked-uuid",
 				Name:        "John Doe",
 				Email:       "johndoe@example.com",
-				PhoneNumber: 123456789,
+				PhoneNumber: "+91123456789",
+				Status:      "pending",
+				Version:     1,
+				CreatedAt:   testNow,
+				UpdatedAt:   testNow,
+				CreatedBy:   requestCtx.AnonymousActor,
+				UpdatedBy:   requestCtx.AnonymousActor,
 			},
 			wantErr: false,
 		},
@@ -78,22 +114,195 @@ func TestUserService_CreateUser(t *testing.T) {
 				// 	ID:          "mocked-uuid",
 				// 	Name:        "John Doe",
 				// 	Email:       "johndoe@example.com",
-				// 	PhoneNumber: 123456789,
+				// 	PhoneNumber: "+91123456789",
 				// }
 
 				// Mock AddUser behavior to return error
 				f.uRepoMocks.EXPECT().
-					AddUser(gomock.Any(), &uAgg.User{
+					AddUser(gomock.Any(), withAudit(withEvent(&uAgg.User{
 						ID:          "mocked-uuid",
 						Name:        "John Doe",
+						Version:     1,
+						Status:      uAgg.StatusPending,
+						CreatedAt:   testNow,
+						UpdatedAt:   testNow,
+						CreatedBy:   requestCtx.AnonymousActor,
+						UpdatedBy:   requestCtx.AnonymousActor,
 						Email:       "johndoe@example.com",
-						PhoneNumber: 123456789,
-					}).
+						PhoneNumber: "+91123456789",
+					}, uAgg.EventUserCreated), auditAgg.ActionCreate, createdChanges...)).
 					Return(errors.New("repository error")).Times(1)
 			},
 			expectedRes: nil,
 			wantErr:     true,
 		},
+		{
+			id:   3,
+			name: "CreateUser - email stored lowercased",
+			args: args{
+				ctx: context.Background(),
+				req: &uContr.CreateUserReq{
+					Name:        "John Doe",
+					Email:       "JohnDoe@Example.com",
+					PhoneNumber: "+91123456789",
+				},
+			},
+			beforeTest: func(f *fields, t *test) {
+				f.uRepoMocks.EXPECT().
+					AddUser(gomock.Any(), withAudit(withEvent(&uAgg.User{
+						ID:          "mocked-uuid",
+						Name:        "John Doe",
+						Email:       "johndoe@example.com",
+						PhoneNumber: "+91123456789",
+						Version:     1,
+						Status:      uAgg.StatusPending,
+						CreatedAt:   testNow,
+						UpdatedAt:   testNow,
+						CreatedBy:   requestCtx.AnonymousActor,
+						UpdatedBy:   requestCtx.AnonymousActor,
+					}, uAgg.EventUserCreated), auditAgg.ActionCreate, createdChanges...)).
+					Return(nil).Times(1)
+				f.verifier.EXPECT().SendVerification(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+			},
+			expectedRes: &uContr.CreateUserRes{
+				ID:          "mocked-uuid",
+				Name:        "John Doe",
+				Email:       "johndoe@example.com",
+				PhoneNumber: "+91123456789",
+				Status:      "pending",
+				Version:     1,
+				CreatedAt:   testNow,
+				UpdatedAt:   testNow,
+				CreatedBy:   requestCtx.AnonymousActor,
+				UpdatedBy:   requestCtx.AnonymousActor,
+			},
+			wantErr: false,
+		},
+		{
+			id:   4,
+			name: "CreateUser - duplicate email",
+			args: args{
+				ctx: context.Background(),
+				req: &req,
+			},
+			beforeTest: func(f *fields, t *test) {
+				f.uRepoMocks.EXPECT().
+					AddUser(gomock.Any(), gomock.Any()).
+					Return(domainErr.Conflict("email %s is already in use", "johndoe@example.com")).Times(1)
+			},
+			expectedRes: nil,
+			wantErr:     true,
+		},
+		{
+			id:   5,
+			name: "CreateUser - invalid fields never reach the repository",
+			args: args{
+				ctx: context.Background(),
+				req: &uContr.CreateUserReq{
+					Name:        "John 3000",
+					Email:       "johndoe@example",
+					PhoneNumber: "123456789",
+				},
+			},
+			beforeTest: func(f *fields, t *test) {
+				f.uRepoMocks.EXPECT().AddUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedRes: nil,
+			wantErr:     true,
+		},
+		{
+			id:   6,
+			name: "CreateUser - records the acting user",
+			args: args{
+				ctx: requestCtx.WithActor(context.Background(), "admin@example.com"),
+				req: &req,
+			},
+			beforeTest: func(f *fields, t *test) {
+				f.uRepoMocks.EXPECT().
+					AddUser(gomock.Any(), auditedAs(auditAgg.Entry{
+						ResourceID: "mocked-uuid",
+						Action:     auditAgg.ActionCreate,
+						Actor:      "admin@example.com",
+						Timestamp:  testNow,
+						Changes:    createdChanges,
+					})).
+					Return(nil).Times(1)
+				f.verifier.EXPECT().SendVerification(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+			},
+			expectedRes: &uContr.CreateUserRes{
+				ID:          "mocked-uuid",
+				Name:        "John Doe",
+				Email:       "johndoe@example.com",
+				PhoneNumber: "+91123456789",
+				Status:      "pending",
+				Version:     1,
+				CreatedAt:   testNow,
+				UpdatedAt:   testNow,
+				CreatedBy:   "admin@example.com",
+				UpdatedBy:   "admin@example.com",
+			},
+			wantErr: false,
+		},
+		{
+			id:   7,
+			name: "CreateUser - records the authenticated API key",
+			args: args{
+				ctx: requestCtx.WithPrincipal(context.Background(), &authAgg.Principal{ID: "k1", Kind: authAgg.KindAPIKey}),
+				req: &req,
+			},
+			beforeTest: func(f *fields, t *test) {
+				f.uRepoMocks.EXPECT().
+					AddUser(gomock.Any(), auditedAs(auditAgg.Entry{
+						ResourceID: "mocked-uuid",
+						Action:     auditAgg.ActionCreate,
+						Actor:      "api_key:k1",
+						Timestamp:  testNow,
+						Changes:    createdChanges,
+					})).
+					Return(nil).Times(1)
+				f.verifier.EXPECT().SendVerification(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+			},
+			expectedRes: &uContr.CreateUserRes{
+				ID:          "mocked-uuid",
+				Name:        "John Doe",
+				Email:       "johndoe@example.com",
+				PhoneNumber: "+91123456789",
+				Status:      "pending",
+				Version:     1,
+				CreatedAt:   testNow,
+				UpdatedAt:   testNow,
+				CreatedBy:   "api_key:k1",
+				UpdatedBy:   "api_key:k1",
+			},
+			wantErr: false,
+		},
+		{
+			id:   8,
+			name: "CreateUser - a failed verification email does not fail the creation",
+			args: args{
+				ctx: context.Background(),
+				req: &req,
+			},
+			beforeTest: func(f *fields, t *test) {
+				f.uRepoMocks.EXPECT().AddUser(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				f.verifier.EXPECT().
+					SendVerification(gomock.Any(), gomock.Any()).
+					Return(domainErr.Unavailable(errors.New("connection refused"), "failed to send email")).Times(1)
+			},
+			expectedRes: &uContr.CreateUserRes{
+				ID:          "mocked-uuid",
+				Name:        "John Doe",
+				Email:       "johndoe@example.com",
+				PhoneNumber: "+91123456789",
+				Status:      "pending",
+				Version:     1,
+				CreatedAt:   testNow,
+				UpdatedAt:   testNow,
+				CreatedBy:   requestCtx.AnonymousActor,
+				UpdatedBy:   requestCtx.AnonymousActor,
+			},
+			wantErr: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -103,6 +312,8 @@ func TestUserService_CreateUser(t *testing.T) {
 
 			f := fields{
 				uRepoMocks: mockRepo.NewMockIUserRepository(ctrl),
+				aRepoMocks: mockRepo.NewMockIAuditRepository(ctrl),
+				verifier:   uMocks.NewMockEmailVerifier(ctrl),
 			}
 
 			if tt.beforeTest != nil {
@@ -111,7 +322,7 @@ func TestUserService_CreateUser(t *testing.T) {
 
 			// Create the UserService instance with the mocked UUID function
 			mockUUID := func() string { return "mocked-uuid" } // This is your mocked UUID generator function
-			us := NewUserService(f.uRepoMocks, mockUUID)
+			us := NewUserService(f.uRepoMocks, f.aRepoMocks, f.verifier, mockUUID, mockClock)
 
 			// Call the function under test
 			got, err := us.CreateUser(tt.args.ctx, tt.args.req)
@@ -169,7 +380,7 @@ func TestUserService_GetUser(t *testing.T) {
 				ID:          "mocked-uuid1",
 				Name:        "shaharyar alam",
 				Email:       "shaharyar@example.com",
-				PhoneNumber: 123456789,
+				PhoneNumber: "+91123456789",
 			},
 			wantErr: false,
 		},
@@ -214,6 +425,8 @@ func TestUserService_GetUser(t *testing.T) {
 
 			f := fields{
 				uRepoMocks: mockRepo.NewMockIUserRepository(ctrl),
+				aRepoMocks: mockRepo.NewMockIAuditRepository(ctrl),
+				verifier:   uMocks.NewMockEmailVerifier(ctrl),
 			}
 
 			if tt.beforeTest != nil {
@@ -221,7 +434,7 @@ func TestUserService_GetUser(t *testing.T) {
 			}
 
 			// Create the UserService instance
-			us := NewUserService(f.uRepoMocks, nil)
+			us := NewUserService(f.uRepoMocks, f.aRepoMocks, f.verifier, nil, mockClock)
 
 			// Call the function under test
 			got, err := us.GetUser(tt.args.ctx, tt.args.userID)
@@ -260,9 +473,34 @@ func TestUserService_DeleteUser(t *testing.T) {
 				userID: "mocked-uuid",
 			},
 			beforeTest: func(f *fields, t *test) {
-				// Mock DeleteUser behavior
 				f.uRepoMocks.EXPECT().
-					DeleteUser(gomock.Any(), "mocked-uuid").
+					GetUser(gomock.Any(), "mocked-uuid").
+					Return(&uAgg.User{ID: "mocked-uuid", Name: "John Doe", Version: 2}, nil).Times(1)
+				// Mock DeleteUser behavior, the user carries its deletion and the event it raised
+				deletedAt := testNow
+				f.uRepoMocks.EXPECT().
+					DeleteUser(gomock.Any(), &uAgg.User{
+						ID:        "mocked-uuid",
+						Name:      "John Doe",
+						Version:   2,
+						DeletedAt: &deletedAt,
+						UpdatedAt: testNow,
+						UpdatedBy: requestCtx.AnonymousActor,
+						Events: []domainEvent.Event{{
+							Type:        uAgg.EventUserDeleted,
+							AggregateID: "mocked-uuid",
+							Actor:       requestCtx.AnonymousActor,
+							OccurredAt:  testNow,
+							Payload:     map[string]any{"id": "mocked-uuid", "deleted_at": testNow},
+						}},
+						AuditEntries: []auditAgg.Entry{{
+							ResourceID: "mocked-uuid",
+							Action:     auditAgg.ActionDelete,
+							Actor:      requestCtx.AnonymousActor,
+							Timestamp:  testNow,
+							Changes:    []auditAgg.FieldChange{{Field: "deleted_at", After: testNow}},
+						}},
+					}).
 					Times(1).
 					Return(nil)
 			},
@@ -276,11 +514,11 @@ func TestUserService_DeleteUser(t *testing.T) {
 				userID: "non-existent-uuid",
 			},
 			beforeTest: func(f *fields, t *test) {
-				// Mock DeleteUser behavior to simulate "user not found" error
+				// Mock GetUser behavior to simulate "user not found" error
 				f.uRepoMocks.EXPECT().
-					DeleteUser(gomock.Any(), "non-existent-uuid").
+					GetUser(gomock.Any(), "non-existent-uuid").
 					Times(1).
-					Return(errors.New("user not found"))
+					Return(nil, errors.New("user not found"))
 			},
 			expectedError: fmt.Errorf("failed to delete user: %v", errors.New("user not found")),
 		},
@@ -292,9 +530,12 @@ func TestUserService_DeleteUser(t *testing.T) {
 				userID: "mocked-uuid",
 			},
 			beforeTest: func(f *fields, t *test) {
+				f.uRepoMocks.EXPECT().
+					GetUser(gomock.Any(), "mocked-uuid").
+					Return(&uAgg.User{ID: "mocked-uuid"}, nil).Times(1)
 				// Mock DeleteUser behavior to simulate a repository error
 				f.uRepoMocks.EXPECT().
-					DeleteUser(gomock.Any(), "mocked-uuid").
+					DeleteUser(gomock.Any(), gomock.Any()).
 					Times(1).
 					Return(errors.New("repository error"))
 			},
@@ -310,6 +551,8 @@ func TestUserService_DeleteUser(t *testing.T) {
 			// Mock the IUserRepository dependency
 			f := fields{
 				uRepoMocks: mockRepo.NewMockIUserRepository(ctrl),
+				aRepoMocks: mockRepo.NewMockIAuditRepository(ctrl),
+				verifier:   uMocks.NewMockEmailVerifier(ctrl),
 			}
 
 			// Set up mock expectations
@@ -318,7 +561,7 @@ func TestUserService_DeleteUser(t *testing.T) {
 			}
 
 			// Create the UserService instance
-			us := NewUserService(f.uRepoMocks, nil)
+			us := NewUserService(f.uRepoMocks, f.aRepoMocks, f.verifier, nil, mockClock)
 
 			// Call the function under test
 			err := us.DeleteUser(tt.args.ctx, tt.args.userID)
@@ -334,11 +577,10 @@ func TestUserService_DeleteUser(t *testing.T) {
 	}
 }
 
-func TestUserService_UpdateUser(t *testing.T) {
+func TestUserService_RestoreUser(t *testing.T) {
 	type args struct {
 		ctx    context.Context
 		userID string
-		req    *uContr.UpdateUserReq
 	}
 
 	type test struct {
@@ -346,113 +588,98 @@ func TestUserService_UpdateUser(t *testing.T) {
 		name          string
 		args          args
 		beforeTest    func(f *fields, t *test)
-		expectedRes   *uContr.UpdateUserRes
+		expectedRes   *uContr.GetUserRes
 		expectedError error
 	}
 
 	tests := []test{
 		{
 			id:   1,
-			name: "UpdateUser - success",
+			name: "RestoreUser - success",
 			args: args{
 				ctx:    context.Background(),
 				userID: "mocked-uuid",
-				req: &uContr.UpdateUserReq{
-					Name:        strPtr("Updated Name"),
-					Email:       strPtr("updated@example.com"),
-					PhoneNumber: intPtr(987654321),
-				},
 			},
 			beforeTest: func(f *fields, t *test) {
-				// Mock GetUser behavior to return the existing user
-				existingUser := &uAgg.User{
-					ID:          "mocked-uuid",
-					Name:        "Old Name",
-					Email:       "old@example.com",
-					PhoneNumber: 123456789,
-				}
-
-				// Mock the GetUser and UpdateUser behavior
-				f.uRepoMocks.EXPECT().
-					GetUser(gomock.Any(), "mocked-uuid").
-					Return(existingUser, nil).Times(1)
-
-				updatedUser := &uAgg.User{
-					ID:          "mocked-uuid",
-					Name:        "Updated Name",
-					Email:       "updated@example.com",
-					PhoneNumber: 987654321,
-				}
-
-				f.uRepoMocks.EXPECT().
-					UpdateUser(gomock.Any(), gomock.Eq(updatedUser)).
-					Return(nil).Times(1)
+				deletedAt := testNow.Add(-time.Hour)
+				gomock.InOrder(
+					f.uRepoMocks.EXPECT().
+						GetUserIncludingDeleted(gomock.Any(), "mocked-uuid").
+						Return(&uAgg.User{ID: "mocked-uuid", Name: "John Doe", Email: "johndoe@example.com", Version: 3, DeletedAt: &deletedAt}, nil).Times(1),
+					// The user is restored by the caller, at the version that was read
+					f.uRepoMocks.EXPECT().
+						RestoreUser(gomock.Any(), &uAgg.User{
+							ID:        "mocked-uuid",
+							Name:      "John Doe",
+							Email:     "johndoe@example.com",
+							Version:   3,
+							UpdatedAt: testNow,
+							UpdatedBy: requestCtx.AnonymousActor,
+							Events: []domainEvent.Event{{
+								Type:        uAgg.EventUserRestored,
+								AggregateID: "mocked-uuid",
+								Actor:       requestCtx.AnonymousActor,
+								OccurredAt:  testNow,
+								Payload: map[string]any{
+									"id": "mocked-uuid", "name": "John Doe", "email": "johndoe@example.com",
+									"phone_number": "", "email_verified": false, "status": "",
+								},
+							}},
+							// The history keeps when the user had been deleted
+							AuditEntries: []auditAgg.Entry{{
+								ResourceID: "mocked-uuid",
+								Action:     auditAgg.ActionRestore,
+								Actor:      requestCtx.AnonymousActor,
+								Timestamp:  testNow,
+								Changes:    []auditAgg.FieldChange{{Field: "deleted_at", Before: deletedAt}},
+							}},
+						}).
+						DoAndReturn(func(_ context.Context, u *uAgg.User) error {
+							u.Version++
+							return nil
+						}).Times(1),
+				)
 			},
-			expectedRes: &uContr.UpdateUserRes{
-				ID:          "mocked-uuid",
-				Name:        "Updated Name",
-				Email:       "updated@example.com",
-				PhoneNumber: 987654321,
+			expectedRes: &uContr.GetUserRes{
+				ID:        "mocked-uuid",
+				Name:      "John Doe",
+				Email:     "johndoe@example.com",
+				Version:   4,
+				UpdatedAt: testNow,
+				UpdatedBy: requestCtx.AnonymousActor,
 			},
 			expectedError: nil,
 		},
 		{
 			id:   2,
-			name: "UpdateUser - user not found",
+			name: "RestoreUser - user is not deleted",
 			args: args{
 				ctx:    context.Background(),
-				userID: "non-existent-uuid",
-				req:    &uContr.UpdateUserReq{},
+				userID: "mocked-uuid",
 			},
 			beforeTest: func(f *fields, t *test) {
-				// Mock GetUser behavior to return "user not found"
 				f.uRepoMocks.EXPECT().
-					GetUser(gomock.Any(), "non-existent-uuid").
-					Return(nil, errors.New("user not found"))
+					GetUserIncludingDeleted(gomock.Any(), "mocked-uuid").
+					Return(&uAgg.User{ID: "mocked-uuid", Version: 3}, nil).Times(1)
+				f.uRepoMocks.EXPECT().RestoreUser(gomock.Any(), gomock.Any()).Times(0)
 			},
 			expectedRes:   nil,
-			expectedError: fmt.Errorf("user not found: %v", errors.New("user not found")),
+			expectedError: errors.New("failed to restore user: user mocked-uuid is not deleted"),
 		},
 		{
 			id:   3,
-			name: "UpdateUser - repository error on update",
+			name: "RestoreUser - user purged",
 			args: args{
 				ctx:    context.Background(),
 				userID: "mocked-uuid",
-				req: &uContr.UpdateUserReq{
-					Name:        strPtr("Updated Name"),
-					Email:       strPtr("updated@example.com"),
-					PhoneNumber: intPtr(987654321),
-				},
 			},
 			beforeTest: func(f *fields, t *test) {
-				// Mock GetUser behavior to return the existing user
-				existingUser := &uAgg.User{
-					ID:          "mocked-uuid",
-					Name:        "Old Name",
-					Email:       "old@example.com",
-					PhoneNumber: 123456789,
-				}
-
-				// Mock the GetUser and UpdateUser behavior
-				f.uRepoMocks.EXPECT().
-					GetUser(gomock.Any(), "mocked-uuid").
-					Return(existingUser, nil).Times(1)
-
-				updatedUser := &uAgg.User{
-					ID:          "mocked-uuid",
-					Name:        "Updated Name",
-					Email:       "updated@example.com",
-					PhoneNumber: 987654321,
-				}
-
-				// Simulate repository error while updating
 				f.uRepoMocks.EXPECT().
-					UpdateUser(gomock.Any(), gomock.Eq(updatedUser)).
-					Return(errors.New("repository error")).Times(1)
+					GetUserIncludingDeleted(gomock.Any(), "mocked-uuid").
+					Return(nil, domainErr.NotFound("user %s not found", "mocked-uuid")).Times(1)
 			},
 			expectedRes:   nil,
-			expectedError: fmt.Errorf("failed to update user: %v", errors.New("repository error")),
+			expectedError: errors.New("failed to restore user: user mocked-uuid not found"),
 		},
 	}
 
@@ -461,23 +688,18 @@ func TestUserService_UpdateUser(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 
-			// Mock the IUserRepository dependency
 			f := fields{
 				uRepoMocks: mockRepo.NewMockIUserRepository(ctrl),
+				aRepoMocks: mockRepo.NewMockIAuditRepository(ctrl),
+				verifier:   uMocks.NewMockEmailVerifier(ctrl),
 			}
-
-			// Set up mock expectations
 			if tt.beforeTest != nil {
 				tt.beforeTest(&f, &tt)
 			}
 
-			// Create the UserService instance
-			us := NewUserService(f.uRepoMocks, nil)
-
-			// Call the function under test
-			got, err := us.UpdateUser(tt.args.ctx, tt.args.userID, tt.args.req)
+			us := NewUserService(f.uRepoMocks, f.aRepoMocks, f.verifier, nil, mockClock)
+			got, err := us.RestoreUser(tt.args.ctx, tt.args.userID)
 
-			// Assertions
 			if tt.expectedError != nil {
 				assert.Error(t, err)
 				assert.Equal(t, tt.expectedError.Error(), err.Error())
@@ -489,9 +711,11 @@ func TestUserService_UpdateUser(t *testing.T) {
 	}
 }
 
-func TestUserService_GetAllUsers(t *testing.T) {
+func TestUserService_ChangeUserStatus(t *testing.T) {
 	type args struct {
-		ctx context.Context
+		ctx    context.Context
+		userID string
+		req    *uContr.ChangeUserStatusReq
 	}
 
 	type test struct {
@@ -499,61 +723,99 @@ func TestUserService_GetAllUsers(t *testing.T) {
 		name          string
 		args          args
 		beforeTest    func(f *fields, t *test)
-		expectedRes   []uContr.GetUserRes
+		expectedRes   *uContr.GetUserRes
 		expectedError error
 	}
 
+	activeUser := func() *uAgg.User {
+		return &uAgg.User{ID: "mocked-uuid", Name: "John Doe", Email: "johndoe@example.com", Version: 3, Status: uAgg.StatusActive}
+	}
+
 	tests := []test{
 		{
 			id:   1,
-			name: "GetAllUsers - success",
+			name: "ChangeUserStatus - suspend an active user",
 			args: args{
-				ctx: context.Background(),
+				ctx:    requestCtx.WithActor(context.Background(), "admin@example.com"),
+				userID: "mocked-uuid",
+				req:    &uContr.ChangeUserStatusReq{Status: "suspended", Reason: "Reported for spam"},
 			},
 			beforeTest: func(f *fields, t *test) {
-				// Mock GetAllUser behavior to return a list of users
+				f.uRepoMocks.EXPECT().GetUser(gomock.Any(), "mocked-uuid").Return(activeUser(), nil).Times(1)
 				f.uRepoMocks.EXPECT().
-					GetAllUser(gomock.Any()).
-					Return([]userAgg.User{
-						userAggData1,
-						userAggData2,
-					}, nil).Times(1)
-
-				// f.uRepoMocks.EXPECT().
-				// 	GetAllUser(gomock.Any()).
-				// 	Return(&userAggData2, nil).Times(1)
+					UpdateUser(gomock.Any(), &uAgg.User{
+						ID: "mocked-uuid", Name: "John Doe", Email: "johndoe@example.com", Version: 3,
+						Status: uAgg.StatusSuspended, StatusReason: "Reported for spam",
+						UpdatedAt: testNow, UpdatedBy: "admin@example.com",
+						Events: []domainEvent.Event{{
+							Type: uAgg.EventUserStatusChanged, AggregateID: "mocked-uuid", Actor: "admin@example.com", OccurredAt: testNow,
+							Payload: map[string]any{"id": "mocked-uuid", "status": "suspended", "previous_status": "active", "status_reason": "Reported for spam"},
+						}},
+						AuditEntries: []auditAgg.Entry{{
+							ResourceID: "mocked-uuid",
+							Action:     auditAgg.ActionSuspend,
+							Actor:      "admin@example.com",
+							Timestamp:  testNow,
+							Changes: []auditAgg.FieldChange{
+								{Field: "status", Before: "active", After: "suspended"},
+								{Field: "status_reason", After: "Reported for spam"},
+							},
+						}},
+					}).
+					Return(nil).Times(1)
 			},
-
-			expectedRes: []uContr.GetUserRes{
-				{
-					ID:          "mocked-uuid1",
-					Name:        "shaharyar alam",
-					Email:       "shaharyar@example.com",
-					PhoneNumber: 123456789,
-				},
-				{
-					ID:          "mocked-uuid2",
-					Name:        "John Doe",
-					Email:       "johndoe@example.com",
-					PhoneNumber: 987654321,
-				},
+			expectedRes: &uContr.GetUserRes{
+				ID:           "mocked-uuid",
+				Name:         "John Doe",
+				Email:        "johndoe@example.com",
+				Status:       "suspended",
+				StatusReason: "Reported for spam",
+				Version:      3,
+				UpdatedAt:    testNow,
+				UpdatedBy:    "admin@example.com",
 			},
-			expectedError: nil,
 		},
 		{
 			id:   2,
-			name: "GetAllUsers - repository error",
+			name: "ChangeUserStatus - transition not allowed",
 			args: args{
-				ctx: context.Background(),
+				ctx:    context.Background(),
+				userID: "mocked-uuid",
+				req:    &uContr.ChangeUserStatusReq{Status: "active", Reason: "Again"},
+			},
+			beforeTest: func(f *fields, t *test) {
+				f.uRepoMocks.EXPECT().GetUser(gomock.Any(), "mocked-uuid").Return(activeUser(), nil).Times(1)
+				f.uRepoMocks.EXPECT().UpdateUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedError: errors.New("user mocked-uuid cannot go from active to active"),
+		},
+		{
+			id:   3,
+			name: "ChangeUserStatus - unknown status",
+			args: args{
+				ctx:    context.Background(),
+				userID: "mocked-uuid",
+				req:    &uContr.ChangeUserStatusReq{Status: "banned", Reason: "Spam"},
+			},
+			beforeTest: func(f *fields, t *test) {
+				f.uRepoMocks.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedError: errors.New("invalid status change: status must be one of pending, active, suspended or deactivated"),
+		},
+		{
+			id:   4,
+			name: "ChangeUserStatus - user not found",
+			args: args{
+				ctx:    context.Background(),
+				userID: "mocked-uuid",
+				req:    &uContr.ChangeUserStatusReq{Status: "deactivated", Reason: "Asked to close"},
 			},
 			beforeTest: func(f *fields, t *test) {
-				// Mock the GetAllUser method to return an error
 				f.uRepoMocks.EXPECT().
-					GetAllUser(gomock.Any()).
-					Return(nil, errors.New("repository error")).Times(1)
+					GetUser(gomock.Any(), "mocked-uuid").
+					Return(nil, domainErr.NotFound("user %s not found", "mocked-uuid")).Times(1)
 			},
-			expectedRes:   nil,
-			expectedError: errors.New("repository error"),
+			expectedError: errors.New("failed to get user: user mocked-uuid not found"),
 		},
 	}
 
@@ -562,23 +824,18 @@ func TestUserService_GetAllUsers(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 
-			// Mock the IUserRepository dependency
 			f := fields{
 				uRepoMocks: mockRepo.NewMockIUserRepository(ctrl),
+				aRepoMocks: mockRepo.NewMockIAuditRepository(ctrl),
+				verifier:   uMocks.NewMockEmailVerifier(ctrl),
 			}
-
-			// Set up mock expectations
 			if tt.beforeTest != nil {
 				tt.beforeTest(&f, &tt)
 			}
 
-			// Create the UserService instance
-			us := NewUserService(f.uRepoMocks, nil)
-
-			// Call the function under test
-			got, err := us.GetAllUsers(tt.args.ctx)
+			us := NewUserService(f.uRepoMocks, f.aRepoMocks, f.verifier, nil, mockClock)
+			got, err := us.ChangeUserStatus(tt.args.ctx, tt.args.userID, tt.args.req)
 
-			// Assertions
 			if tt.expectedError != nil {
 				assert.Error(t, err)
 				assert.Equal(t, tt.expectedError.Error(), err.Error())
@@ -590,11 +847,897 @@ func TestUserService_GetAllUsers(t *testing.T) {
 	}
 }
 
-// Helper functions for pointer dereferencing
-func strPtr(s string) *string {
-	return &s
-}
+func TestUserService_UpdateUser(t *testing.T) {
+	type args struct {
+		ctx    context.Context
+		userID string
+		req    *uContr.UpdateUserReq
+	}
+
+	type test struct {
+		id            int
+		name          string
+		args          args
+		beforeTest    func(f *fields, t *test)
+		expectedRes   *uContr.UpdateUserRes
+		expectedError error
+	}
 
-func intPtr(i int64) *int64 {
-	return &i
+	tests := []test{
+		{
+			id:   1,
+			name: "UpdateUser - success",
+			args: args{
+				ctx:    context.Background(),
+				userID: "mocked-uuid",
+				req: &uContr.UpdateUserReq{
+					Name:        strPtr("Updated Name"),
+					Email:       strPtr("updated@example.com"),
+					PhoneNumber: strPtr("+91 98765 4321"),
+				},
+			},
+			beforeTest: func(f *fields, t *test) {
+				// Mock GetUser behavior to return the existing user
+				existingUser := &uAgg.User{
+					ID:            "mocked-uuid",
+					Name:          "Old Name",
+					Email:         "old@example.com",
+					PhoneNumber:   "+91123456789",
+					EmailVerified: true,
+				}
+
+				// Mock the GetUser and UpdateUser behavior
+				f.uRepoMocks.EXPECT().
+					GetUser(gomock.Any(), "mocked-uuid").
+					Return(existingUser, nil).Times(1)
+
+				updatedUser := withAudit(withEvent(&uAgg.User{
+					ID:          "mocked-uuid",
+					Name:        "Updated Name",
+					Email:       "updated@example.com",
+					PhoneNumber: "+91987654321",
+					UpdatedAt:   testNow,
+					UpdatedBy:   requestCtx.AnonymousActor,
+				}, uAgg.EventUserUpdated), auditAgg.ActionUpdate,
+					auditAgg.FieldChange{Field: "email", Before: "old@example.com", After: "updated@example.com"},
+					auditAgg.FieldChange{Field: "email_verified", Before: true, After: false},
+					auditAgg.FieldChange{Field: "name", Before: "Old Name", After: "Updated Name"},
+					auditAgg.FieldChange{Field: "phone_number", Before: "+91123456789", After: "+91987654321"},
+				)
+
+				f.uRepoMocks.EXPECT().
+					UpdateUser(gomock.Any(), gomock.Eq(updatedUser)).
+					Return(nil).Times(1)
+				f.verifier.EXPECT().SendVerification(gomock.Any(), gomock.Eq(updatedUser)).Return(nil).Times(1)
+			},
+			expectedRes: &uContr.UpdateUserRes{
+				ID:          "mocked-uuid",
+				Name:        "Updated Name",
+				Email:       "updated@example.com",
+				PhoneNumber: "+91987654321",
+				UpdatedAt:   testNow,
+				UpdatedBy:   requestCtx.AnonymousActor,
+			},
+			expectedError: nil,
+		},
+		{
+			id:   2,
+			name: "UpdateUser - user not found",
+			args: args{
+				ctx:    context.Background(),
+				userID: "non-existent-uuid",
+				req:    &uContr.UpdateUserReq{},
+			},
+			beforeTest: func(f *fields, t *test) {
+				// Mock GetUser behavior to return "user not found"
+				f.uRepoMocks.EXPECT().
+					GetUser(gomock.Any(), "non-existent-uuid").
+					Return(nil, errors.New("user not found"))
+			},
+			expectedRes:   nil,
+			expectedError: fmt.Errorf("failed to get user: %v", errors.New("user not found")),
+		},
+		{
+			id:   3,
+			name: "UpdateUser - repository error on update",
+			args: args{
+				ctx:    context.Background(),
+				userID: "mocked-uuid",
+				req: &uContr.UpdateUserReq{
+					Name:        strPtr("Updated Name"),
+					Email:       strPtr("updated@example.com"),
+					PhoneNumber: strPtr("+91 98765 4321"),
+				},
+			},
+			beforeTest: func(f *fields, t *test) {
+				// Mock GetUser behavior to return the existing user
+				existingUser := &uAgg.User{
+					ID:          "mocked-uuid",
+					Name:        "Old Name",
+					Email:       "old@example.com",
+					PhoneNumber: "+91123456789",
+				}
+
+				// Mock the GetUser and UpdateUser behavior
+				f.uRepoMocks.EXPECT().
+					GetUser(gomock.Any(), "mocked-uuid").
+					Return(existingUser, nil).Times(1)
+
+				updatedUser := withAudit(withEvent(&uAgg.User{
+					ID:          "mocked-uuid",
+					Name:        "Updated Name",
+					Email:       "updated@example.com",
+					PhoneNumber: "+91987654321",
+					UpdatedAt:   testNow,
+					UpdatedBy:   requestCtx.AnonymousActor,
+				}, uAgg.EventUserUpdated), auditAgg.ActionUpdate,
+					auditAgg.FieldChange{Field: "email", Before: "old@example.com", After: "updated@example.com"},
+					auditAgg.FieldChange{Field: "name", Before: "Old Name", After: "Updated Name"},
+					auditAgg.FieldChange{Field: "phone_number", Before: "+91123456789", After: "+91987654321"},
+				)
+
+				// Simulate repository error while updating
+				f.uRepoMocks.EXPECT().
+					UpdateUser(gomock.Any(), gomock.Eq(updatedUser)).
+					Return(errors.New("repository error")).Times(1)
+			},
+			expectedRes:   nil,
+			expectedError: fmt.Errorf("failed to update user: %v", errors.New("repository error")),
+		},
+		{
+			id:   4,
+			name: "UpdateUser - email already in use",
+			args: args{
+				ctx:    context.Background(),
+				userID: "mocked-uuid",
+				req: &uContr.UpdateUserReq{
+					Email: strPtr("Taken@Example.com"),
+				},
+			},
+			beforeTest: func(f *fields, t *test) {
+				f.uRepoMocks.EXPECT().
+					GetUser(gomock.Any(), "mocked-uuid").
+					Return(&uAgg.User{ID: "mocked-uuid", Name: "Old Name", Email: "old@example.com"}, nil).Times(1)
+
+				// The email is lowercased before it reaches the unique index
+				f.uRepoMocks.EXPECT().
+					UpdateUser(gomock.Any(), gomock.Eq(withAudit(withEvent(&uAgg.User{ID: "mocked-uuid", Name: "Old Name", Email: "taken@example.com", UpdatedAt: testNow, UpdatedBy: requestCtx.AnonymousActor}, uAgg.EventUserUpdated),
+						auditAgg.ActionUpdate, auditAgg.FieldChange{Field: "email", Before: "old@example.com", After: "taken@example.com"}))).
+					Return(domainErr.Conflict("email %s is already in use", "taken@example.com")).Times(1)
+			},
+			expectedRes:   nil,
+			expectedError: errors.New("failed to update user: email taken@example.com is already in use"),
+		},
+		{
+			id:   5,
+			name: "UpdateUser - invalid phone number",
+			args: args{
+				ctx:    context.Background(),
+				userID: "mocked-uuid",
+				req: &uContr.UpdateUserReq{
+					PhoneNumber: strPtr("98765"),
+				},
+			},
+			beforeTest: func(f *fields, t *test) {
+				f.uRepoMocks.EXPECT().
+					GetUser(gomock.Any(), "mocked-uuid").
+					Return(&uAgg.User{ID: "mocked-uuid", Name: "Old Name", Email: "old@example.com"}, nil).Times(1)
+				f.uRepoMocks.EXPECT().UpdateUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedRes:   nil,
+			expectedError: errors.New("request validation failed: invalid user: phone_number must start with + and the country code"),
+		},
+		{
+			id:   6,
+			name: "UpdateUser - If-Match matches the current version",
+			args: args{
+				ctx:    context.Background(),
+				userID: "mocked-uuid",
+				req: &uContr.UpdateUserReq{
+					Name:    strPtr("New Name"),
+					IfMatch: []int64{2, 3},
+				},
+			},
+			beforeTest: func(f *fields, t *test) {
+				f.uRepoMocks.EXPECT().
+					GetUser(gomock.Any(), "mocked-uuid").
+					Return(&uAgg.User{ID: "mocked-uuid", Name: "Old Name", Email: "old@example.com", Version: 3}, nil).Times(1)
+				f.uRepoMocks.EXPECT().
+					UpdateUser(gomock.Any(), gomock.Eq(withAudit(withEvent(&uAgg.User{ID: "mocked-uuid", Name: "New Name", Email: "old@example.com", Version: 3, UpdatedAt: testNow, UpdatedBy: requestCtx.AnonymousActor}, uAgg.EventUserUpdated),
+						auditAgg.ActionUpdate, auditAgg.FieldChange{Field: "name", Before: "Old Name", After: "New Name"}))).
+					DoAndReturn(func(_ context.Context, u *uAgg.User) error {
+						u.Version++
+						return nil
+					}).Times(1)
+			},
+			expectedRes: &uContr.UpdateUserRes{
+				ID:        "mocked-uuid",
+				Name:      "New Name",
+				Email:     "old@example.com",
+				Version:   4,
+				UpdatedAt: testNow,
+				UpdatedBy: requestCtx.AnonymousActor,
+			},
+			expectedError: nil,
+		},
+		{
+			id:   7,
+			name: "UpdateUser - If-Match names a stale version",
+			args: args{
+				ctx:    context.Background(),
+				userID: "mocked-uuid",
+				req: &uContr.UpdateUserReq{
+					Name:    strPtr("New Name"),
+					IfMatch: []int64{2},
+				},
+			},
+			beforeTest: func(f *fields, t *test) {
+				f.uRepoMocks.EXPECT().
+					GetUser(gomock.Any(), "mocked-uuid").
+					Return(&uAgg.User{ID: "mocked-uuid", Name: "Old Name", Email: "old@example.com", Version: 3}, nil).Times(1)
+				f.uRepoMocks.EXPECT().UpdateUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedRes:   nil,
+			expectedError: errors.New("user mocked-uuid is at version 3"),
+		},
+		{
+			id:   8,
+			name: "UpdateUser - concurrent update wins the race",
+			args: args{
+				ctx:    context.Background(),
+				userID: "mocked-uuid",
+				req: &uContr.UpdateUserReq{
+					Name: strPtr("New Name"),
+				},
+			},
+			beforeTest: func(f *fields, t *test) {
+				f.uRepoMocks.EXPECT().
+					GetUser(gomock.Any(), "mocked-uuid").
+					Return(&uAgg.User{ID: "mocked-uuid", Name: "Old Name", Email: "old@example.com", Version: 3}, nil).Times(1)
+				f.uRepoMocks.EXPECT().
+					UpdateUser(gomock.Any(), gomock.Any()).
+					Return(domainErr.Conflict("user %s was modified concurrently, version %d is stale", "mocked-uuid", 3)).Times(1)
+			},
+			expectedRes:   nil,
+			expectedError: errors.New("failed to update user: user mocked-uuid was modified concurrently, version 3 is stale"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// Mock the IUserRepository dependency
+			f := fields{
+				uRepoMocks: mockRepo.NewMockIUserRepository(ctrl),
+				aRepoMocks: mockRepo.NewMockIAuditRepository(ctrl),
+				verifier:   uMocks.NewMockEmailVerifier(ctrl),
+			}
+
+			// Set up mock expectations
+			if tt.beforeTest != nil {
+				tt.beforeTest(&f, &tt)
+			}
+
+			// Create the UserService instance
+			us := NewUserService(f.uRepoMocks, f.aRepoMocks, f.verifier, nil, mockClock)
+
+			// Call the function under test
+			got, err := us.UpdateUser(tt.args.ctx, tt.args.userID, tt.args.req)
+
+			// Assertions
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedRes, got)
+			}
+		})
+	}
+}
+
+func TestUserService_PatchUser(t *testing.T) {
+	type args struct {
+		ctx    context.Context
+		userID string
+		req    *uContr.PatchUserReq
+	}
+
+	type test struct {
+		id            int
+		name          string
+		args          args
+		beforeTest    func(f *fields, t *test)
+		expectedRes   *uContr.UpdateUserRes
+		expectedError error
+	}
+
+	existingUser := func() *uAgg.User {
+		return &uAgg.User{ID: "mocked-uuid", Name: "Old Name", Email: "old@example.com", PhoneNumber: "+91123456789", Version: 3}
+	}
+
+	tests := []test{
+		{
+			id:   1,
+			name: "PatchUser - merge patch changes the name",
+			args: args{
+				ctx:    context.Background(),
+				userID: "mocked-uuid",
+				req: &uContr.PatchUserReq{
+					ContentType: patch.MergePatchContentType,
+					Patch:       []byte(`{"name":"New Name","email":"old@example.com"}`),
+				},
+			},
+			beforeTest: func(f *fields, t *test) {
+				f.uRepoMocks.EXPECT().GetUser(gomock.Any(), "mocked-uuid").Return(existingUser(), nil).Times(1)
+				f.uRepoMocks.EXPECT().
+					UpdateUser(gomock.Any(), gomock.Eq(withAudit(withEvent(&uAgg.User{ID: "mocked-uuid", Name: "New Name", Email: "old@example.com", PhoneNumber: "+91123456789", Version: 3, UpdatedAt: testNow, UpdatedBy: requestCtx.AnonymousActor}, uAgg.EventUserUpdated),
+						// Only the fields that changed are recorded
+						auditAgg.ActionUpdate, auditAgg.FieldChange{Field: "name", Before: "Old Name", After: "New Name"}))).
+					Return(nil).Times(1)
+			},
+			expectedRes: &uContr.UpdateUserRes{
+				ID:          "mocked-uuid",
+				Name:        "New Name",
+				Email:       "old@example.com",
+				PhoneNumber: "+91123456789",
+				Version:     3,
+				UpdatedAt:   testNow,
+				UpdatedBy:   requestCtx.AnonymousActor,
+			},
+			expectedError: nil,
+		},
+		{
+			id:   2,
+			name: "PatchUser - JSON Patch testing the version",
+			args: args{
+				ctx:    context.Background(),
+				userID: "mocked-uuid",
+				req: &uContr.PatchUserReq{
+					ContentType: patch.JSONPatchContentType,
+					Patch:       []byte(`[{"op":"test","path":"/version","value":3},{"op":"replace","path":"/email","value":"New@Example.com"}]`),
+				},
+			},
+			beforeTest: func(f *fields, t *test) {
+				f.uRepoMocks.EXPECT().GetUser(gomock.Any(), "mocked-uuid").Return(existingUser(), nil).Times(1)
+				f.uRepoMocks.EXPECT().
+					UpdateUser(gomock.Any(), gomock.Eq(withAudit(withEvent(&uAgg.User{ID: "mocked-uuid", Name: "Old Name", Email: "new@example.com", PhoneNumber: "+91123456789", Version: 3, UpdatedAt: testNow, UpdatedBy: requestCtx.AnonymousActor}, uAgg.EventUserUpdated),
+						auditAgg.ActionUpdate, auditAgg.FieldChange{Field: "email", Before: "old@example.com", After: "new@example.com"}))).
+					Return(nil).Times(1)
+				f.verifier.EXPECT().SendVerification(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+			},
+			expectedRes: &uContr.UpdateUserRes{
+				ID:          "mocked-uuid",
+				Name:        "Old Name",
+				Email:       "new@example.com",
+				PhoneNumber: "+91123456789",
+				Version:     3,
+				UpdatedAt:   testNow,
+				UpdatedBy:   requestCtx.AnonymousActor,
+			},
+			expectedError: nil,
+		},
+		{
+			id:   3,
+			name: "PatchUser - failed JSON Patch test",
+			args: args{
+				ctx:    context.Background(),
+				userID: "mocked-uuid",
+				req: &uContr.PatchUserReq{
+					ContentType: patch.JSONPatchContentType,
+					Patch:       []byte(`[{"op":"test","path":"/version","value":2},{"op":"replace","path":"/name","value":"New Name"}]`),
+				},
+			},
+			beforeTest: func(f *fields, t *test) {
+				f.uRepoMocks.EXPECT().GetUser(gomock.Any(), "mocked-uuid").Return(existingUser(), nil).Times(1)
+				f.uRepoMocks.EXPECT().UpdateUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedRes:   nil,
+			expectedError: errors.New("failed to patch user mocked-uuid: test of /version failed"),
+		},
+		{
+			id:   4,
+			name: "PatchUser - merge patch clears a required field",
+			args: args{
+				ctx:    context.Background(),
+				userID: "mocked-uuid",
+				req: &uContr.PatchUserReq{
+					ContentType: patch.MergePatchContentType,
+					Patch:       []byte(`{"phone_number":null}`),
+				},
+			},
+			beforeTest: func(f *fields, t *test) {
+				f.uRepoMocks.EXPECT().GetUser(gomock.Any(), "mocked-uuid").Return(existingUser(), nil).Times(1)
+				f.uRepoMocks.EXPECT().UpdateUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedRes:   nil,
+			expectedError: errors.New("request validation failed: invalid user patch: phone_number is required and cannot be cleared"),
+		},
+		{
+			id:   5,
+			name: "PatchUser - read-only and unknown fields",
+			args: args{
+				ctx:    context.Background(),
+				userID: "mocked-uuid",
+				req: &uContr.PatchUserReq{
+					ContentType: patch.JSONPatchContentType,
+					Patch:       []byte(`[{"op":"replace","path":"/version","value":9},{"op":"add","path":"/role","value":"admin"},{"op":"replace","path":"/name","value":1}]`),
+				},
+			},
+			beforeTest: func(f *fields, t *test) {
+				f.uRepoMocks.EXPECT().GetUser(gomock.Any(), "mocked-uuid").Return(existingUser(), nil).Times(1)
+				f.uRepoMocks.EXPECT().UpdateUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedRes:   nil,
+			expectedError: errors.New("request validation failed: invalid user patch: name must be a string; role is read-only; version is read-only"),
+		},
+		{
+			id:   6,
+			name: "PatchUser - patched email is invalid",
+			args: args{
+				ctx:    context.Background(),
+				userID: "mocked-uuid",
+				req: &uContr.PatchUserReq{
+					ContentType: patch.MergePatchContentType,
+					Patch:       []byte(`{"email":"nope"}`),
+				},
+			},
+			beforeTest: func(f *fields, t *test) {
+				f.uRepoMocks.EXPECT().GetUser(gomock.Any(), "mocked-uuid").Return(existingUser(), nil).Times(1)
+				f.uRepoMocks.EXPECT().UpdateUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedRes:   nil,
+			expectedError: errors.New("request validation failed: invalid user: email must be a valid email address"),
+		},
+		{
+			id:   7,
+			name: "PatchUser - If-Match names a stale version",
+			args: args{
+				ctx:    context.Background(),
+				userID: "mocked-uuid",
+				req: &uContr.PatchUserReq{
+					ContentType: patch.MergePatchContentType,
+					Patch:       []byte(`{"name":"New Name"}`),
+					IfMatch:     []int64{2},
+				},
+			},
+			beforeTest: func(f *fields, t *test) {
+				f.uRepoMocks.EXPECT().GetUser(gomock.Any(), "mocked-uuid").Return(existingUser(), nil).Times(1)
+				f.uRepoMocks.EXPECT().UpdateUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedRes:   nil,
+			expectedError: errors.New("user mocked-uuid is at version 3"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			f := fields{
+				uRepoMocks: mockRepo.NewMockIUserRepository(ctrl),
+				aRepoMocks: mockRepo.NewMockIAuditRepository(ctrl),
+				verifier:   uMocks.NewMockEmailVerifier(ctrl),
+			}
+			if tt.beforeTest != nil {
+				tt.beforeTest(&f, &tt)
+			}
+
+			us := NewUserService(f.uRepoMocks, f.aRepoMocks, f.verifier, nil, mockClock)
+			got, err := us.PatchUser(tt.args.ctx, tt.args.userID, tt.args.req)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedRes, got)
+			}
+		})
+	}
+}
+
+func TestUserService_GetAllUsers(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		req *uContr.GetUsersReq
+	}
+
+	type test struct {
+		id            int
+		name          string
+		args          args
+		beforeTest    func(f *fields, t *test)
+		expectedRes   *uContr.GetUsersRes
+		expectedError error
+	}
+
+	tests := []test{
+		{
+			id:   1,
+			name: "GetAllUsers - success",
+			args: args{
+				ctx: context.Background(),
+				req: &uContr.GetUsersReq{Limit: 2},
+			},
+			beforeTest: func(f *fields, t *test) {
+				// Mock GetAllUser behavior to return a page of users
+				f.uRepoMocks.EXPECT().
+					GetAllUser(gomock.Any(), query.Spec{Page: query.PageRequest{Limit: 2}}).
+					Return(&userAgg.UserPage{
+						Users: []userAgg.User{
+							userAggData1,
+							userAggData2,
+						},
+						Page: query.PageInfo{Total: 3, Limit: 2, NextCursor: "mocked-uuid2"},
+					}, nil).Times(1)
+			},
+
+			expectedRes: &uContr.GetUsersRes{
+				Users: []uContr.GetUserRes{
+					{
+						ID:          "mocked-uuid1",
+						Name:        "shaharyar alam",
+						Email:       "shaharyar@example.com",
+						PhoneNumber: "+91123456789",
+					},
+					{
+						ID:          "mocked-uuid2",
+						Name:        "John Doe",
+						Email:       "johndoe@example.com",
+						PhoneNumber: "+91987654321",
+					},
+				},
+				Total:      3,
+				Limit:      2,
+				NextCursor: query.EncodeCursor("mocked-uuid2"),
+			},
+			expectedError: nil,
+		},
+		{
+			id:   2,
+			name: "GetAllUsers - cursor and default limit",
+			args: args{
+				ctx: context.Background(),
+				req: &uContr.GetUsersReq{Cursor: query.EncodeCursor("mocked-uuid1")},
+			},
+			beforeTest: func(f *fields, t *test) {
+				f.uRepoMocks.EXPECT().
+					GetAllUser(gomock.Any(), query.Spec{Page: query.PageRequest{Limit: query.DefaultLimit, Cursor: "mocked-uuid1"}}).
+					Return(&userAgg.UserPage{
+						Users: []userAgg.User{userAggData2},
+						Page:  query.PageInfo{Total: 2, Limit: query.DefaultLimit},
+					}, nil).Times(1)
+			},
+			expectedRes: &uContr.GetUsersRes{
+				Users: []uContr.GetUserRes{
+					{
+						ID:          "mocked-uuid2",
+						Name:        "John Doe",
+						Email:       "johndoe@example.com",
+						PhoneNumber: "+91987654321",
+					},
+				},
+				Total: 2,
+				Limit: query.DefaultLimit,
+			},
+			expectedError: nil,
+		},
+		{
+			id:   3,
+			name: "GetAllUsers - invalid page request",
+			args: args{
+				ctx: context.Background(),
+				req: &uContr.GetUsersReq{Limit: 500},
+			},
+			expectedRes:   nil,
+			expectedError: errors.New("invalid page request: limit must be between 1 and 100"),
+		},
+		{
+			id:   4,
+			name: "GetAllUsers - filters, search and sort",
+			args: args{
+				ctx: context.Background(),
+				req: &uContr.GetUsersReq{
+					Search:  "doe",
+					Sort:    "-name",
+					Filters: map[string][]string{"phone_number": {"+91987654321"}, "email": {"johndoe@example.com"}},
+				},
+			},
+			beforeTest: func(f *fields, t *test) {
+				f.uRepoMocks.EXPECT().
+					GetAllUser(gomock.Any(), query.Spec{
+						Filters: []query.Filter{
+							{Field: "email", Op: query.OpEq, Value: "johndoe@example.com"},
+							{Field: "phone_number", Op: query.OpEq, Value: "+91987654321"},
+						},
+						Search: "doe",
+						Sort:   []query.SortField{{Field: "name", Desc: true}},
+						Page:   query.PageRequest{Limit: query.DefaultLimit},
+					}).
+					Return(&userAgg.UserPage{Page: query.PageInfo{Limit: query.DefaultLimit}}, nil).Times(1)
+			},
+			expectedRes: &uContr.GetUsersRes{
+				Users: []uContr.GetUserRes{},
+				Limit: query.DefaultLimit,
+			},
+			expectedError: nil,
+		},
+		{
+			id:   5,
+			name: "GetAllUsers - unknown filter field",
+			args: args{
+				ctx: context.Background(),
+				req: &uContr.GetUsersReq{Filters: map[string][]string{"password": {"x"}}},
+			},
+			expectedRes:   nil,
+			expectedError: errors.New("invalid query: password is not a filterable field"),
+		},
+		{
+			id:   6,
+			name: "GetAllUsers - repository error",
+			args: args{
+				ctx: context.Background(),
+				req: &uContr.GetUsersReq{},
+			},
+			beforeTest: func(f *fields, t *test) {
+				// Mock the GetAllUser method to return an error
+				f.uRepoMocks.EXPECT().
+					GetAllUser(gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("repository error")).Times(1)
+			},
+			expectedRes:   nil,
+			expectedError: errors.New("repository error"),
+		},
+		{
+			id:   7,
+			name: "GetAllUsers - timestamp filters",
+			args: args{
+				ctx: context.Background(),
+				req: &uContr.GetUsersReq{
+					Sort:    "-created_at",
+					Filters: map[string][]string{"created_at>": {"2024-05-01"}, "updated_at<": {"2024-06-01T12:00:00+02:00"}},
+				},
+			},
+			beforeTest: func(f *fields, t *test) {
+				f.uRepoMocks.EXPECT().
+					GetAllUser(gomock.Any(), query.Spec{
+						Filters: []query.Filter{
+							{Field: "created_at", Op: query.OpGte, Value: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+							{Field: "updated_at", Op: query.OpLte, Value: time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)},
+						},
+						Sort: []query.SortField{{Field: "created_at", Desc: true}},
+						Page: query.PageRequest{Limit: query.DefaultLimit},
+					}).
+					Return(&userAgg.UserPage{Page: query.PageInfo{Limit: query.DefaultLimit}}, nil).Times(1)
+			},
+			expectedRes: &uContr.GetUsersRes{
+				Users: []uContr.GetUserRes{},
+				Limit: query.DefaultLimit,
+			},
+			expectedError: nil,
+		},
+		{
+			id:   8,
+			name: "GetAllUsers - malformed timestamp",
+			args: args{
+				ctx: context.Background(),
+				req: &uContr.GetUsersReq{Filters: map[string][]string{"created_at>": {"yesterday"}}},
+			},
+			expectedRes:   nil,
+			expectedError: errors.New("invalid query: created_at must be an RFC 3339 timestamp or a YYYY-MM-DD date"),
+		},
+		{
+			id:   9,
+			name: "GetAllUsers - phone number range",
+			args: args{
+				ctx: context.Background(),
+				req: &uContr.GetUsersReq{Filters: map[string][]string{"phone_number>": {"+91"}}},
+			},
+			expectedRes:   nil,
+			expectedError: errors.New("invalid query: phone_number supports only the = and != operators"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// Mock the IUserRepository dependency
+			f := fields{
+				uRepoMocks: mockRepo.NewMockIUserRepository(ctrl),
+				aRepoMocks: mockRepo.NewMockIAuditRepository(ctrl),
+				verifier:   uMocks.NewMockEmailVerifier(ctrl),
+			}
+
+			// Set up mock expectations
+			if tt.beforeTest != nil {
+				tt.beforeTest(&f, &tt)
+			}
+
+			// Create the UserService instance
+			us := NewUserService(f.uRepoMocks, f.aRepoMocks, f.verifier, nil, mockClock)
+
+			// Call the function under test
+			got, err := us.GetAllUsers(tt.args.ctx, tt.args.req)
+
+			// Assertions
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedRes, got)
+			}
+		})
+	}
+}
+
+// Helper functions for pointer dereferencing
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestUserService_GetUserHistory(t *testing.T) {
+	type args struct {
+		ctx    context.Context
+		userID string
+		req    *uContr.GetUserHistoryReq
+	}
+
+	type test struct {
+		id            int
+		name          string
+		args          args
+		beforeTest    func(f *fields, t *test)
+		expectedRes   *uContr.GetUserHistoryRes
+		expectedError error
+	}
+
+	tests := []test{
+		{
+			id:   1,
+			name: "GetUserHistory - success",
+			args: args{
+				ctx:    context.Background(),
+				userID: "mocked-uuid",
+				req:    &uContr.GetUserHistoryReq{Limit: 1},
+			},
+			beforeTest: func(f *fields, t *test) {
+				f.aRepoMocks.EXPECT().
+					GetEntries(gomock.Any(), "mocked-uuid", query.PageRequest{Limit: 1}).
+					Return(&auditAgg.EntryPage{
+						Entries: []auditAgg.Entry{{
+							ID:         "entry-2",
+							ResourceID: "mocked-uuid",
+							Action:     auditAgg.ActionUpdate,
+							Actor:      "admin@example.com",
+							RequestID:  "req-1",
+							Timestamp:  testNow,
+							Changes:    []auditAgg.FieldChange{{Field: "name", Before: "Old Name", After: "New Name"}},
+						}},
+						Page: query.PageInfo{Total: 2, Limit: 1, NextCursor: "entry-2"},
+					}, nil).Times(1)
+			},
+			expectedRes: &uContr.GetUserHistoryRes{
+				Entries: []uContr.AuditEntryRes{{
+					ID:        "entry-2",
+					Action:    "update",
+					Actor:     "admin@example.com",
+					RequestID: "req-1",
+					Timestamp: testNow,
+					Changes:   []uContr.FieldChangeRes{{Field: "name", Before: "Old Name", After: "New Name"}},
+				}},
+				Total:      2,
+				Limit:      1,
+				NextCursor: query.EncodeCursor("entry-2"),
+			},
+			expectedError: nil,
+		},
+		{
+			id:   2,
+			name: "GetUserHistory - invalid page request",
+			args: args{
+				ctx:    context.Background(),
+				userID: "mocked-uuid",
+				req:    &uContr.GetUserHistoryReq{Limit: 500},
+			},
+			expectedRes:   nil,
+			expectedError: errors.New("invalid page request: limit must be between 1 and 100"),
+		},
+		{
+			id:   3,
+			name: "GetUserHistory - repository error",
+			args: args{
+				ctx:    context.Background(),
+				userID: "mocked-uuid",
+				req:    &uContr.GetUserHistoryReq{},
+			},
+			beforeTest: func(f *fields, t *test) {
+				f.aRepoMocks.EXPECT().
+					GetEntries(gomock.Any(), "mocked-uuid", query.PageRequest{Limit: query.DefaultLimit}).
+					Return(nil, errors.New("database error")).Times(1)
+			},
+			expectedRes:   nil,
+			expectedError: errors.New("failed to get user history: database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			f := fields{
+				uRepoMocks: mockRepo.NewMockIUserRepository(ctrl),
+				aRepoMocks: mockRepo.NewMockIAuditRepository(ctrl),
+				verifier:   uMocks.NewMockEmailVerifier(ctrl),
+			}
+
+			if tt.beforeTest != nil {
+				tt.beforeTest(&f, &tt)
+			}
+
+			us := NewUserService(f.uRepoMocks, f.aRepoMocks, f.verifier, nil, mockClock)
+
+			got, err := us.GetUserHistory(tt.args.ctx, tt.args.userID, tt.args.req)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedRes, got)
+			}
+		})
+	}
+}
+
+// readBarrier holds every GetUser until both racing updates have read the user
+type readBarrier struct {
+	uRepo.IUserRepository
+	read *sync.WaitGroup
+}
+
+func (r readBarrier) GetUser(ctx context.Context, userID string) (*uAgg.User, error) {
+	u, err := r.IUserRepository.GetUser(ctx, userID)
+	r.read.Done()
+	r.read.Wait()
+	return u, err
+}
+
+func TestUserService_ConditionalUpdatesRace(t *testing.T) {
+	ctx := context.Background()
+	repo := uPersist.NewInMemoryUserRepository(outbox.NewInMemoryOutboxRepository(), audit.NewInMemoryAuditRepository())
+	if err := repo.AddUser(ctx, &uAgg.User{ID: "u1", Name: "Old Name", Email: "old@example.com", PhoneNumber: "+91123456789"}); err != nil {
+		t.Fatal(err)
+	}
+	var read sync.WaitGroup
+	read.Add(2)
+	us := NewUserService(readBarrier{IUserRepository: repo, read: &read}, nil, nil, nil, mockClock)
+
+	// Both updates read version 0 and pass If-Match; only one can store its change
+	errs := make(chan error, 2)
+	go func() {
+		_, err := us.UpdateUser(ctx, "u1", &uContr.UpdateUserReq{Name: strPtr("Update"), IfMatch: []int64{0}})
+		errs <- err
+	}()
+	go func() {
+		_, err := us.PatchUser(ctx, "u1", &uContr.PatchUserReq{ContentType: patch.MergePatchContentType, Patch: []byte(`{"name":"Patch"}`), IfMatch: []int64{0}})
+		errs <- err
+	}()
+
+	var failed []error
+	for range 2 {
+		if err := <-errs; err != nil {
+			failed = append(failed, err)
+		}
+	}
+	if len(failed) != 1 || !errors.Is(failed[0], domainErr.ErrPreconditionFailed) {
+		t.Errorf("racing conditional updates failed with %v, want one failed precondition", failed)
+	}
 }
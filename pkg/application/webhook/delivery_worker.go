@@ -0,0 +1,131 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	uApp "github.com/Crud-application/pkg/application/user"
+	"github.com/Crud-application/pkg/domain/domainErr"
+	wRepo "github.com/Crud-application/pkg/domain/persistence"
+	"github.com/Crud-application/pkg/domain/webhookAgg"
+)
+
+// userAgent identifies the sender of webhook deliveries
+const userAgent = "Crud-application-Webhooks/1.0"
+
+// DeliveryWorker periodically POSTs the due deliveries to their subscriptions.
+// Failed attempts are retried with exponential backoff until the attempts run
+// out and the delivery is dead-lettered. Receivers may see an event more than
+// once and should deduplicate on its ID.
+type DeliveryWorker struct {
+	repo      wRepo.IWebhookRepository
+	client    *http.Client
+	backoff   webhookAgg.Backoff
+	interval  time.Duration
+	batchSize int
+	now       uApp.Clock
+}
+
+func NewDeliveryWorker(repo wRepo.IWebhookRepository, client *http.Client, now uApp.Clock, backoff webhookAgg.Backoff, interval time.Duration, batchSize int) *DeliveryWorker {
+	return &DeliveryWorker{
+		repo:      repo,
+		client:    client,
+		backoff:   backoff,
+		interval:  interval,
+		batchSize: batchSize,
+		now:       now,
+	}
+}
+
+// Run delivers once per interval until ctx is done
+func (w *DeliveryWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := w.Deliver(ctx); err != nil {
+			log.Printf("Error delivering webhooks: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Deliver attempts one batch of due deliveries and returns how many succeeded
+func (w *DeliveryWorker) Deliver(ctx context.Context) (int, error) {
+	due, err := w.repo.GetDueDeliveries(ctx, w.now(), w.batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get due deliveries: %w", err)
+	}
+
+	succeeded := 0
+	for _, d := range due {
+		w.attempt(ctx, &d)
+		if d.Status == webhookAgg.DeliverySucceeded {
+			succeeded++
+		}
+		// A delivery whose outcome is lost is attempted again, which receivers tolerate
+		if err := w.repo.UpdateDelivery(ctx, &d); err != nil {
+			log.Printf("Error recording webhook delivery %s: %v", d.ID, err)
+		}
+	}
+	return succeeded, nil
+}
+
+// attempt sends d to its subscription and records the outcome on d
+func (w *DeliveryWorker) attempt(ctx context.Context, d *webhookAgg.Delivery) {
+	sub, err := w.repo.GetSubscription(ctx, d.SubscriptionID)
+	switch {
+	case errors.Is(err, domainErr.ErrNotFound):
+		d.Abandon("webhook was deleted")
+		return
+	case err != nil:
+		d.Failed(w.now(), 0, err.Error(), w.backoff)
+		return
+	case !sub.Active:
+		d.Abandon("webhook is inactive")
+		return
+	}
+
+	at := w.now()
+	statusCode, err := w.send(ctx, sub, d, at)
+	switch {
+	case err != nil:
+		d.Failed(at, 0, err.Error(), w.backoff)
+	case statusCode < 200 || statusCode > 299:
+		d.Failed(at, statusCode, fmt.Sprintf("receiver answered %d %s", statusCode, http.StatusText(statusCode)), w.backoff)
+	default:
+		d.Succeeded(at, statusCode)
+	}
+}
+
+// send POSTs the signed body of d and returns the status code of the response
+func (w *DeliveryWorker) send(ctx context.Context, sub *webhookAgg.Subscription, d *webhookAgg.Delivery, at time.Time) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(d.Body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(webhookAgg.EventHeader, string(d.EventType))
+	req.Header.Set(webhookAgg.DeliveryHeader, d.ID)
+	req.Header.Set(webhookAgg.SignatureHeader, webhookAgg.Sign(sub.Secret, at, d.Body))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// Drain a little of the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	return resp.StatusCode, nil
+}
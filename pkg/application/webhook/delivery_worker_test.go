@@ -0,0 +1,238 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	eCOntr "github.com/Crud-application/pkg/contracts/event"
+	"github.com/Crud-application/pkg/domain/domainEvent"
+	"github.com/Crud-application/pkg/domain/query"
+	"github.com/Crud-application/pkg/domain/webhookAgg"
+	wRepo "github.com/Crud-application/pkg/infrastructure/persistence/webhook"
+)
+
+const testSecret = "0123456789abcdef"
+
+var testNow = time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+var testEvent = domainEvent.Event{
+	ID:          "e1",
+	Type:        "user.created",
+	AggregateID: "u1",
+	Actor:       "alice",
+	OccurredAt:  testNow,
+	Payload:     map[string]any{"name": "alam"},
+}
+
+// receiver is a local webhook endpoint answering with status and counting
+// the deliveries signed no longer than a minute before the time now tells
+type receiver struct {
+	*httptest.Server
+	hits     atomic.Int32
+	verified atomic.Int32
+	event    atomic.Value // The last eCOntr.Event received
+}
+
+func newReceiver(t *testing.T, status int, now func() time.Time) *receiver {
+	r := &receiver{}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		r.hits.Add(1)
+		body, _ := io.ReadAll(req.Body)
+		if webhookAgg.Verify(testSecret, req.Header.Get(webhookAgg.SignatureHeader), body, now(), time.Minute) &&
+			req.Header.Get(webhookAgg.EventHeader) == "user.created" && req.Header.Get(webhookAgg.DeliveryHeader) != "" {
+			r.verified.Add(1)
+		}
+		var e eCOntr.Event
+		if json.Unmarshal(body, &e) == nil {
+			r.event.Store(e)
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+// subscribe stores a subscription to url and queues the delivery of testEvent to it
+func subscribe(t *testing.T, repo *wRepo.InMemoryWebhookRepository, url string, active bool, now func() time.Time) string {
+	s, err := webhookAgg.NewSubscription("w1", url, testSecret, nil)
+	if err != nil {
+		t.Fatalf("NewSubscription() error = %v", err)
+	}
+	s.Active = active
+	_ = repo.AddSubscription(context.Background(), s)
+
+	// Queue the delivery while active, the way it happens before a webhook is deactivated
+	if !active {
+		s.Active = true
+		_ = repo.UpdateSubscription(context.Background(), s)
+	}
+	if err := NewDispatcher(repo, now).Publish(context.Background(), testEvent); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if !active {
+		s.Active = false
+		_ = repo.UpdateSubscription(context.Background(), s)
+	}
+	return s.ID
+}
+
+func lastDelivery(t *testing.T, repo *wRepo.InMemoryWebhookRepository, webhookID string) webhookAgg.Delivery {
+	page, _ := repo.GetDeliveries(context.Background(), webhookID, query.PageRequest{Limit: 1})
+	if len(page.Deliveries) != 1 {
+		t.Fatalf("GetDeliveries() = %+v, want one delivery", page)
+	}
+	return page.Deliveries[0]
+}
+
+func TestDeliveryWorker_Deliver(t *testing.T) {
+	backoff := webhookAgg.Backoff{MaxAttempts: 3, Base: time.Minute, Max: time.Hour}
+	tests := []struct {
+		id          int
+		name        string
+		status      int  // Answered by the receiver
+		active      bool // Whether the webhook is active when the delivery is due
+		unreachable bool
+		guarded     bool // Whether receivers on private addresses are refused
+		want        int
+		wantStatus  webhookAgg.DeliveryStatus
+		wantCode    int
+		wantHits    int32
+	}{
+		{
+			id:         1,
+			name:       "Signed delivery accepted by the receiver",
+			status:     http.StatusNoContent,
+			active:     true,
+			want:       1,
+			wantStatus: webhookAgg.DeliverySucceeded,
+			wantCode:   http.StatusNoContent,
+			wantHits:   1,
+		},
+		{
+			id:         2,
+			name:       "Receiver error is retried later",
+			status:     http.StatusServiceUnavailable,
+			active:     true,
+			want:       0,
+			wantStatus: webhookAgg.DeliveryPending,
+			wantCode:   http.StatusServiceUnavailable,
+			wantHits:   1,
+		},
+		{
+			id:          3,
+			name:        "Unreachable receiver is retried later",
+			active:      true,
+			unreachable: true,
+			want:        0,
+			wantStatus:  webhookAgg.DeliveryPending,
+			wantCode:    0,
+			wantHits:    0,
+		},
+		{
+			id:         4,
+			name:       "Delivery to a deactivated webhook is dead-lettered unsent",
+			status:     http.StatusOK,
+			active:     false,
+			want:       0,
+			wantStatus: webhookAgg.DeliveryDead,
+			wantHits:   0,
+		},
+		{
+			id:         5,
+			name:       "Receiver on a loopback address is refused outside dev",
+			status:     http.StatusOK,
+			active:     true,
+			guarded:    true,
+			want:       0,
+			wantStatus: webhookAgg.DeliveryPending,
+			wantCode:   0,
+			wantHits:   0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := func() time.Time { return testNow }
+			rcv := newReceiver(t, tt.status, now)
+			url := rcv.URL
+			if tt.unreachable {
+				url = "http://127.0.0.1:1"
+			}
+			repo := wRepo.NewInMemoryWebhookRepository()
+			webhookID := subscribe(t, repo, url, tt.active, now)
+			client := rcv.Client()
+			if tt.guarded {
+				client = NewTargetGuard(true).Client(time.Second)
+			}
+			w := NewDeliveryWorker(repo, client, now, backoff, time.Second, 10)
+
+			got, err := w.Deliver(context.Background())
+			if err != nil || got != tt.want {
+				t.Fatalf("ID %v Deliver() = %v, %v want %v", tt.id, got, err, tt.want)
+			}
+			d := lastDelivery(t, repo, webhookID)
+			if d.Status != tt.wantStatus || d.LastStatusCode != tt.wantCode {
+				t.Errorf("ID %v Deliver() delivery = %+v, want status %s and code %d", tt.id, d, tt.wantStatus, tt.wantCode)
+			}
+			if rcv.hits.Load() != tt.wantHits || rcv.verified.Load() != tt.wantHits {
+				t.Errorf("ID %v receiver got %d deliveries, %d verified, want %d", tt.id, rcv.hits.Load(), rcv.verified.Load(), tt.wantHits)
+			}
+			if tt.wantStatus == webhookAgg.DeliveryPending && !d.NextAttemptAt.Equal(testNow.Add(time.Minute)) {
+				t.Errorf("ID %v Deliver() next attempt at %v, want after the base backoff", tt.id, d.NextAttemptAt)
+			}
+		})
+	}
+}
+
+func TestDeliveryWorker_RetriesThenDeadLetters(t *testing.T) {
+	now := testNow
+	clock := func() time.Time { return now }
+	rcv := newReceiver(t, http.StatusInternalServerError, clock)
+	repo := wRepo.NewInMemoryWebhookRepository()
+	webhookID := subscribe(t, repo, rcv.URL, true, clock)
+	w := NewDeliveryWorker(repo, rcv.Client(), clock, webhookAgg.Backoff{MaxAttempts: 3, Base: time.Minute, Max: time.Hour}, time.Second, 10)
+
+	// Attempts are due after 0, 1 and 2 more minutes; the worker runs every 30 seconds
+	for range 8 {
+		_, _ = w.Deliver(context.Background())
+		now = now.Add(30 * time.Second)
+	}
+
+	d := lastDelivery(t, repo, webhookID)
+	if d.Status != webhookAgg.DeliveryDead || d.Attempts != 3 || rcv.hits.Load() != 3 || rcv.verified.Load() != 3 {
+		t.Errorf("delivery = %+v after %d requests, want dead after 3 attempts", d, rcv.hits.Load())
+	}
+	if e, _ := rcv.event.Load().(eCOntr.Event); e.ID != testEvent.ID || e.Payload["name"] != "alam" || e.Actor != "alice" {
+		t.Errorf("receiver got event %+v, want %+v", e, testEvent)
+	}
+}
+
+func TestDispatcher_Publish(t *testing.T) {
+	ctx := context.Background()
+	now := func() time.Time { return testNow }
+	repo := wRepo.NewInMemoryWebhookRepository()
+	d := NewDispatcher(repo, now)
+
+	deletedOnly, _ := webhookAgg.NewSubscription("w1", "http://a.test", testSecret, []string{"user.deleted"})
+	all, _ := webhookAgg.NewSubscription("w2", "http://b.test", testSecret, nil)
+	_ = repo.AddSubscription(ctx, deletedOnly)
+	_ = repo.AddSubscription(ctx, all)
+
+	// The relay publishes an event again when it could not mark it published
+	for range 2 {
+		if err := d.Publish(ctx, testEvent); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+
+	due, _ := repo.GetDueDeliveries(ctx, testNow, 10)
+	if len(due) != 1 || due[0].SubscriptionID != "w2" || due[0].EventID != testEvent.ID {
+		t.Errorf("Publish() queued %+v, want one delivery to the webhook of every event", due)
+	}
+}
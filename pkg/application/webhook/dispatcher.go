@@ -0,0 +1,50 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	uApp "github.com/Crud-application/pkg/application/user"
+	"github.com/Crud-application/pkg/domain/domainEvent"
+	wRepo "github.com/Crud-application/pkg/domain/persistence"
+	"github.com/Crud-application/pkg/domain/webhookAgg"
+)
+
+var _ domainEvent.EventPublisher = (*Dispatcher)(nil)
+
+// Dispatcher turns the domain events relayed from the outbox into pending
+// deliveries, one per subscription that wants the event. The DeliveryWorker sends them.
+type Dispatcher struct {
+	repo wRepo.IWebhookRepository
+	now  uApp.Clock
+}
+
+func NewDispatcher(repo wRepo.IWebhookRepository, now uApp.Clock) *Dispatcher {
+	return &Dispatcher{repo: repo, now: now}
+}
+
+// Publish queues a delivery of the event to every interested subscription.
+// Publishing the same event again queues no further deliveries.
+func (d *Dispatcher) Publish(ctx context.Context, event domainEvent.Event) error {
+	subs, err := d.repo.GetSubscribers(ctx, event.Type)
+	if err != nil {
+		return fmt.Errorf("failed to get webhooks for event %s: %w", event.ID, err)
+	}
+	if len(subs) == 0 {
+		return nil
+	}
+	body, err := toEventBody(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event %s: %w", event.ID, err)
+	}
+
+	at := d.now()
+	deliveries := make([]*webhookAgg.Delivery, 0, len(subs))
+	for _, s := range subs {
+		deliveries = append(deliveries, webhookAgg.NewDelivery(&s, event, body, at))
+	}
+	if err := d.repo.AddDeliveries(ctx, deliveries); err != nil {
+		return fmt.Errorf("failed to queue deliveries of event %s: %w", event.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package webhook
+
+import (
+	"encoding/json"
+
+	eCOntr "github.com/Crud-application/pkg/contracts/event"
+	wCOntr "github.com/Crud-application/pkg/contracts/webhook"
+	"github.com/Crud-application/pkg/domain/domainEvent"
+	"github.com/Crud-application/pkg/domain/query"
+	"github.com/Crud-application/pkg/domain/webhookAgg"
+)
+
+func toWebhookRes(s *webhookAgg.Subscription) *wCOntr.WebhookRes {
+	res := &wCOntr.WebhookRes{
+		ID:        s.ID,
+		URL:       s.URL,
+		Events:    make([]string, 0, len(s.Events)),
+		Active:    s.Active,
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+	}
+	for _, e := range s.Events {
+		res.Events = append(res.Events, string(e))
+	}
+	return res
+}
+
+func toGetWebhooksRes(page *webhookAgg.SubscriptionPage) *wCOntr.GetWebhooksRes {
+	res := &wCOntr.GetWebhooksRes{
+		Webhooks:   make([]wCOntr.WebhookRes, 0, len(page.Subscriptions)),
+		Total:      page.Page.Total,
+		Limit:      page.Page.Limit,
+		Offset:     page.Page.Offset,
+		NextCursor: query.EncodeCursor(page.Page.NextCursor),
+	}
+	for _, s := range page.Subscriptions {
+		res.Webhooks = append(res.Webhooks, *toWebhookRes(&s))
+	}
+	return res
+}
+
+func toDeliveryRes(d *webhookAgg.Delivery) wCOntr.DeliveryRes {
+	res := wCOntr.DeliveryRes{
+		ID:             d.ID,
+		EventID:        d.EventID,
+		EventType:      string(d.EventType),
+		Status:         string(d.Status),
+		Attempts:       d.Attempts,
+		LastAttemptAt:  d.LastAttemptAt,
+		LastStatusCode: d.LastStatusCode,
+		LastError:      d.LastError,
+		Body:           json.RawMessage(d.Body),
+		CreatedAt:      d.CreatedAt,
+	}
+	if d.Status == webhookAgg.DeliveryPending {
+		next := d.NextAttemptAt
+		res.NextAttemptAt = &next
+	}
+	return res
+}
+
+func toGetDeliveriesRes(page *webhookAgg.DeliveryPage) *wCOntr.GetDeliveriesRes {
+	res := &wCOntr.GetDeliveriesRes{
+		Deliveries: make([]wCOntr.DeliveryRes, 0, len(page.Deliveries)),
+		Total:      page.Page.Total,
+		Limit:      page.Page.Limit,
+		Offset:     page.Page.Offset,
+		NextCursor: query.EncodeCursor(page.Page.NextCursor),
+	}
+	for _, d := range page.Deliveries {
+		res.Deliveries = append(res.Deliveries, toDeliveryRes(&d))
+	}
+	return res
+}
+
+// toEventBody encodes the body that is signed and sent to receivers
+func toEventBody(e domainEvent.Event) ([]byte, error) {
+	return json.Marshal(eCOntr.Event{
+		ID:          e.ID,
+		Type:        string(e.Type),
+		AggregateID: e.AggregateID,
+		Actor:       e.Actor,
+		OccurredAt:  e.OccurredAt,
+		Payload:     e.Payload,
+	})
+}
@@ -0,0 +1,82 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"github.com/Crud-application/pkg/domain/webhookAgg"
+)
+
+// errPrivateTarget is why a receiver on the networks of the server is refused
+var errPrivateTarget = domainErr.FieldError{Field: webhookAgg.FieldURL, Message: "must not point to a loopback, private or link-local address"}
+
+// TargetGuard keeps webhook receivers off the networks of the server, so subscriptions
+// cannot be used to reach internal services. Receivers are checked when they are
+// registered and again on every connection, which also covers redirects and DNS
+// records changed after registration. A disabled guard lets everything through.
+type TargetGuard struct {
+	enabled  bool
+	resolver *net.Resolver
+}
+
+func NewTargetGuard(enabled bool) *TargetGuard {
+	return &TargetGuard{enabled: enabled, resolver: net.DefaultResolver}
+}
+
+// CheckURL rejects a receiver URL whose host is, or resolves to, an address that is not public
+func (g *TargetGuard) CheckURL(ctx context.Context, rawURL string) error {
+	if !g.enabled {
+		return nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return domainErr.Validation("invalid webhook", domainErr.FieldError{Field: webhookAgg.FieldURL, Message: "must be an absolute http or https URL"})
+	}
+	addrs, err := g.resolver.LookupNetIP(ctx, "ip", u.Hostname())
+	if err != nil {
+		return domainErr.Validation("invalid webhook", domainErr.FieldError{Field: webhookAgg.FieldURL, Message: "has a host that cannot be resolved"})
+	}
+	for _, addr := range addrs {
+		if !webhookAgg.PublicAddr(addr) {
+			return domainErr.Validation("invalid webhook", errPrivateTarget)
+		}
+	}
+	return nil
+}
+
+// control refuses connections to addresses that are not public. It runs after name
+// resolution, on the address actually dialed.
+func (g *TargetGuard) control(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	if !webhookAgg.PublicAddr(addr) {
+		return fmt.Errorf("receiver address %s is not public", addr)
+	}
+	return nil
+}
+
+// Client returns an HTTP client for deliveries, which connects only where the guard allows
+func (g *TargetGuard) Client(timeout time.Duration) *http.Client {
+	if !g.enabled {
+		return &http.Client{Timeout: timeout}
+	}
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Control: g.control}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	// A proxy would connect on our behalf, past the check
+	transport.Proxy = nil
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
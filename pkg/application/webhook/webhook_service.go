@@ -0,0 +1,133 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	uApp "github.com/Crud-application/pkg/application/user"
+	wCOntr "github.com/Crud-application/pkg/contracts/webhook"
+	wRepo "github.com/Crud-application/pkg/domain/persistence"
+	"github.com/Crud-application/pkg/domain/query"
+	"github.com/Crud-application/pkg/domain/webhookAgg"
+)
+
+// WebhookService manages webhook subscriptions and exposes their delivery logs
+type WebhookService struct {
+	repo         wRepo.IWebhookRepository
+	guard        *TargetGuard
+	generateUUID uApp.UUIDGenerator
+	now          uApp.Clock
+}
+
+func NewWebhookService(repo wRepo.IWebhookRepository, guard *TargetGuard, generateUUID uApp.UUIDGenerator, now uApp.Clock) *WebhookService {
+	return &WebhookService{
+		repo:         repo,
+		guard:        guard,
+		generateUUID: generateUUID,
+		now:          now,
+	}
+}
+
+// CreateWebhook subscribes a URL to user events. A signing secret is generated
+// when none is given; this is the only time it is returned.
+func (ws *WebhookService) CreateWebhook(ctx context.Context, req *wCOntr.CreateWebhookReq) (*wCOntr.CreateWebhookRes, error) {
+	secret := req.Secret
+	if secret == "" {
+		var err error
+		if secret, err = newSecret(); err != nil {
+			return nil, err
+		}
+	}
+	sub, err := webhookAgg.NewSubscription(ws.generateUUID(), req.URL, secret, req.Events)
+	if err != nil {
+		return nil, fmt.Errorf("request validation failed: %w", err)
+	}
+	if err := ws.guard.CheckURL(ctx, sub.URL); err != nil {
+		return nil, fmt.Errorf("request validation failed: %w", err)
+	}
+	sub.CreatedAt = ws.now()
+	sub.UpdatedAt = sub.CreatedAt
+
+	if err := ws.repo.AddSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+	return &wCOntr.CreateWebhookRes{WebhookRes: *toWebhookRes(sub), Secret: sub.Secret}, nil
+}
+
+func (ws *WebhookService) GetWebhook(ctx context.Context, webhookID string) (*wCOntr.WebhookRes, error) {
+	sub, err := ws.repo.GetSubscription(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	return toWebhookRes(sub), nil
+}
+
+func (ws *WebhookService) GetWebhooks(ctx context.Context, req *wCOntr.GetWebhooksReq) (*wCOntr.GetWebhooksRes, error) {
+	page, err := query.NewPageRequest(req.Limit, req.Cursor, req.Offset)
+	if err != nil {
+		return nil, err
+	}
+	subs, err := ws.repo.GetSubscriptions(ctx, page)
+	if err != nil {
+		return nil, err
+	}
+	return toGetWebhooksRes(subs), nil
+}
+
+// UpdateWebhook changes the fields given in req. Deactivating a webhook
+// dead-letters its pending deliveries.
+func (ws *WebhookService) UpdateWebhook(ctx context.Context, webhookID string, req *wCOntr.UpdateWebhookReq) (*wCOntr.WebhookRes, error) {
+	sub, err := ws.repo.GetSubscription(ctx, webhookID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+	if err := sub.Update(req.URL, req.Secret, req.Events, req.Active); err != nil {
+		return nil, fmt.Errorf("request validation failed: %w", err)
+	}
+	if req.URL != nil {
+		if err := ws.guard.CheckURL(ctx, sub.URL); err != nil {
+			return nil, fmt.Errorf("request validation failed: %w", err)
+		}
+	}
+	sub.UpdatedAt = ws.now()
+
+	if err := ws.repo.UpdateSubscription(ctx, sub); err != nil {
+		return nil, fmt.Errorf("failed to update webhook: %w", err)
+	}
+	return toWebhookRes(sub), nil
+}
+
+// DeleteWebhook removes a webhook along with its delivery log
+func (ws *WebhookService) DeleteWebhook(ctx context.Context, webhookID string) error {
+	if err := ws.repo.DeleteSubscription(ctx, webhookID); err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	return nil
+}
+
+// GetDeliveries returns one page of the delivery log of a webhook, newest first
+func (ws *WebhookService) GetDeliveries(ctx context.Context, webhookID string, req *wCOntr.GetDeliveriesReq) (*wCOntr.GetDeliveriesRes, error) {
+	page, err := query.NewPageRequest(req.Limit, req.Cursor, req.Offset)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ws.repo.GetSubscription(ctx, webhookID); err != nil {
+		return nil, err
+	}
+	deliveries, err := ws.repo.GetDeliveries(ctx, webhookID, page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook deliveries: %w", err)
+	}
+	return toGetDeliveriesRes(deliveries), nil
+}
+
+// newSecret generates a random signing secret of 32 bytes, hex encoded
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
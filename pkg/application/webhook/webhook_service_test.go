@@ -0,0 +1,225 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	wCOntr "github.com/Crud-application/pkg/contracts/webhook"
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"github.com/Crud-application/pkg/domain/domainEvent"
+	mockRepo "github.com/Crud-application/pkg/domain/persistence/mocks"
+	"github.com/Crud-application/pkg/domain/query"
+	"github.com/Crud-application/pkg/domain/webhookAgg"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestService(ctrl *gomock.Controller) (*WebhookService, *mockRepo.MockIWebhookRepository) {
+	repo := mockRepo.NewMockIWebhookRepository(ctrl)
+	ws := NewWebhookService(repo, NewTargetGuard(false), func() string { return "w1" }, func() time.Time { return testNow })
+	return ws, repo
+}
+
+func TestWebhookService_CreateWebhook(t *testing.T) {
+	tests := []struct {
+		id         int
+		name       string
+		req        *wCOntr.CreateWebhookReq
+		beforeTest func(repo *mockRepo.MockIWebhookRepository)
+		wantSecret string // A generated secret is expected when empty
+		wantErr    error
+	}{
+		{
+			id:   1,
+			name: "Webhook with its own secret - success",
+			req:  &wCOntr.CreateWebhookReq{URL: "https://partner.example.com/hook", Secret: testSecret, Events: []string{"user.created"}},
+			beforeTest: func(repo *mockRepo.MockIWebhookRepository) {
+				repo.EXPECT().AddSubscription(gomock.Any(), &webhookAgg.Subscription{
+					ID:        "w1",
+					URL:       "https://partner.example.com/hook",
+					Secret:    testSecret,
+					Events:    []domainEvent.Type{"user.created"},
+					Active:    true,
+					CreatedAt: testNow,
+					UpdatedAt: testNow,
+				}).Return(nil)
+			},
+			wantSecret: testSecret,
+		},
+		{
+			id:   2,
+			name: "Secret is generated when missing - success",
+			req:  &wCOntr.CreateWebhookReq{URL: "https://partner.example.com/hook"},
+			beforeTest: func(repo *mockRepo.MockIWebhookRepository) {
+				repo.EXPECT().AddSubscription(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			id:         3,
+			name:       "Invalid URL - failure",
+			req:        &wCOntr.CreateWebhookReq{URL: "not a url", Secret: testSecret},
+			beforeTest: func(repo *mockRepo.MockIWebhookRepository) {},
+			wantErr:    domainErr.ErrValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			ws, repo := newTestService(ctrl)
+			tt.beforeTest(repo)
+
+			got, err := ws.CreateWebhook(context.Background(), tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ID %v CreateWebhook() error = %v, wantErr %v", tt.id, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			assert.Equal(t, "w1", got.ID)
+			if tt.wantSecret != "" {
+				assert.Equal(t, tt.wantSecret, got.Secret)
+			} else {
+				assert.Len(t, got.Secret, 64) // 32 random bytes, hex encoded
+			}
+		})
+	}
+}
+
+func TestWebhookService_PrivateTargets(t *testing.T) {
+	tests := []struct {
+		id      int
+		name    string
+		url     string
+		wantErr error
+	}{
+		{id: 1, name: "Public address - success", url: "https://93.184.216.34/hook"},
+		{id: 2, name: "Loopback - failure", url: "http://127.0.0.1:8080/hook", wantErr: domainErr.ErrValidation},
+		{id: 3, name: "IPv6 loopback - failure", url: "http://[::1]/hook", wantErr: domainErr.ErrValidation},
+		{id: 4, name: "Private network - failure", url: "https://10.0.0.5/hook", wantErr: domainErr.ErrValidation},
+		{id: 5, name: "Cloud metadata service - failure", url: "http://169.254.169.254/latest/meta-data", wantErr: domainErr.ErrValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := mockRepo.NewMockIWebhookRepository(ctrl)
+			ws := NewWebhookService(repo, NewTargetGuard(true), func() string { return "w1" }, func() time.Time { return testNow })
+			if tt.wantErr == nil {
+				repo.EXPECT().AddSubscription(gomock.Any(), gomock.Any()).Return(nil)
+			} else {
+				repo.EXPECT().GetSubscription(gomock.Any(), "w1").Return(&webhookAgg.Subscription{ID: "w1", URL: "https://93.184.216.34/hook", Secret: testSecret, Active: true}, nil)
+			}
+
+			_, err := ws.CreateWebhook(context.Background(), &wCOntr.CreateWebhookReq{URL: tt.url, Secret: testSecret})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ID %v CreateWebhook() error = %v, wantErr %v", tt.id, err, tt.wantErr)
+			}
+			if tt.wantErr == nil {
+				return
+			}
+			_, err = ws.UpdateWebhook(context.Background(), "w1", &wCOntr.UpdateWebhookReq{URL: &tt.url})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ID %v UpdateWebhook() error = %v, wantErr %v", tt.id, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWebhookService_UpdateWebhook(t *testing.T) {
+	inactive := false
+	shortSecret := "short"
+	stored := func() *webhookAgg.Subscription {
+		return &webhookAgg.Subscription{ID: "w1", URL: "http://a.test", Secret: testSecret, Active: true}
+	}
+	tests := []struct {
+		id         int
+		name       string
+		req        *wCOntr.UpdateWebhookReq
+		beforeTest func(repo *mockRepo.MockIWebhookRepository)
+		want       *wCOntr.WebhookRes
+		wantErr    error
+	}{
+		{
+			id:   1,
+			name: "Deactivate and filter events - success",
+			req:  &wCOntr.UpdateWebhookReq{Events: []string{"user.deleted"}, Active: &inactive},
+			beforeTest: func(repo *mockRepo.MockIWebhookRepository) {
+				repo.EXPECT().GetSubscription(gomock.Any(), "w1").Return(stored(), nil)
+				repo.EXPECT().UpdateSubscription(gomock.Any(), &webhookAgg.Subscription{
+					ID:        "w1",
+					URL:       "http://a.test",
+					Secret:    testSecret,
+					Events:    []domainEvent.Type{"user.deleted"},
+					UpdatedAt: testNow,
+				}).Return(nil)
+			},
+			want: &wCOntr.WebhookRes{ID: "w1", URL: "http://a.test", Events: []string{"user.deleted"}, UpdatedAt: testNow},
+		},
+		{
+			id:   2,
+			name: "Short secret - failure",
+			req:  &wCOntr.UpdateWebhookReq{Secret: &shortSecret},
+			beforeTest: func(repo *mockRepo.MockIWebhookRepository) {
+				repo.EXPECT().GetSubscription(gomock.Any(), "w1").Return(stored(), nil)
+			},
+			wantErr: domainErr.ErrValidation,
+		},
+		{
+			id:   3,
+			name: "Unknown webhook - failure",
+			req:  &wCOntr.UpdateWebhookReq{Active: &inactive},
+			beforeTest: func(repo *mockRepo.MockIWebhookRepository) {
+				repo.EXPECT().GetSubscription(gomock.Any(), "w1").Return(nil, domainErr.NotFound("webhook w1 not found"))
+			},
+			wantErr: domainErr.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			ws, repo := newTestService(ctrl)
+			tt.beforeTest(repo)
+
+			got, err := ws.UpdateWebhook(context.Background(), "w1", tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ID %v UpdateWebhook() error = %v, wantErr %v", tt.id, err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWebhookService_GetDeliveries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ws, repo := newTestService(ctrl)
+
+	repo.EXPECT().GetSubscription(gomock.Any(), "w1").Return(&webhookAgg.Subscription{ID: "w1"}, nil)
+	repo.EXPECT().GetDeliveries(gomock.Any(), "w1", query.PageRequest{Limit: query.DefaultLimit}).Return(&webhookAgg.DeliveryPage{
+		Deliveries: []webhookAgg.Delivery{
+			{ID: "d2", EventType: "user.updated", Status: webhookAgg.DeliveryPending, NextAttemptAt: testNow, Body: []byte(`{}`)},
+			{ID: "d1", EventType: "user.created", Status: webhookAgg.DeliverySucceeded, NextAttemptAt: testNow, Body: []byte(`{}`)},
+		},
+		Page: query.PageInfo{Total: 2, Limit: query.DefaultLimit},
+	}, nil)
+
+	got, err := ws.GetDeliveries(context.Background(), "w1", &wCOntr.GetDeliveriesReq{})
+	if err != nil || len(got.Deliveries) != 2 || got.Total != 2 {
+		t.Fatalf("GetDeliveries() = %+v, %v", got, err)
+	}
+	if got.Deliveries[0].NextAttemptAt == nil || got.Deliveries[1].NextAttemptAt != nil {
+		t.Errorf("GetDeliveries() next attempts = %v, %v, want one only for the pending delivery", got.Deliveries[0].NextAttemptAt, got.Deliveries[1].NextAttemptAt)
+	}
+
+	repo.EXPECT().GetSubscription(gomock.Any(), "w2").Return(nil, domainErr.NotFound("webhook w2 not found"))
+	if _, err := ws.GetDeliveries(context.Background(), "w2", &wCOntr.GetDeliveriesReq{}); !errors.Is(err, domainErr.ErrNotFound) {
+		t.Errorf("GetDeliveries() of an unknown webhook error = %v, want not found", err)
+	}
+}
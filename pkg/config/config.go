@@ -0,0 +1,688 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Supported deployment environments
+const (
+	EnvDev     = "dev"
+	EnvStaging = "staging"
+	EnvProd    = "prod"
+)
+
+// Supported user repository backends
+const (
+	RepoBackendMongo  = "mongo"
+	RepoBackendMemory = "memory"
+)
+
+// Supported event sinks
+const (
+	EventSinkLog     = "log"
+	EventSinkChannel = "channel"
+)
+
+// Supported sources of the user change stream
+const (
+	EventStreamRelay        = "relay"        // Events relayed by this instance
+	EventStreamChangeStream = "changestream" // Events stored by any instance, watched with a MongoDB change stream
+)
+
+// Supported mailers
+const (
+	MailerLog  = "log"  // Writes emails to the log, for dev
+	MailerFile = "file" // Appends emails to a file, for dev
+	MailerSMTP = "smtp"
+)
+
+// envConfigFile names the environment variable pointing at an optional config file
+const envConfigFile = "CRUD_CONFIG_FILE"
+
+// Config holds every runtime setting of the application.
+// Values are resolved in order: defaults, config file, environment variables, CLI flags.
+type Config struct {
+	Env        string           `json:"env" yaml:"env"`
+	Server     ServerConfig     `json:"server" yaml:"server"`
+	Mongo      MongoConfig      `json:"mongo" yaml:"mongo"`
+	Repository RepositoryConfig `json:"repository" yaml:"repository"`
+	Users      UsersConfig      `json:"users" yaml:"users"`
+	Events     EventsConfig     `json:"events" yaml:"events"`
+	Webhooks   WebhooksConfig   `json:"webhooks" yaml:"webhooks"`
+	Auth       AuthConfig       `json:"auth" yaml:"auth"`
+	Mail       MailConfig       `json:"mail" yaml:"mail"`
+}
+
+// ServerConfig configures the HTTP and gRPC servers
+type ServerConfig struct {
+	Port     int `json:"port" yaml:"port"`
+	GRPCPort int `json:"grpc_port" yaml:"grpc_port"`
+}
+
+// Addr returns the listen address for the HTTP server
+func (s ServerConfig) Addr() string {
+	return fmt.Sprintf(":%d", s.Port)
+}
+
+// GRPCAddr returns the listen address for the gRPC server
+func (s ServerConfig) GRPCAddr() string {
+	return fmt.Sprintf(":%d", s.GRPCPort)
+}
+
+// MongoConfig configures the MongoDB connection and collections
+type MongoConfig struct {
+	URI                       string   `json:"uri" yaml:"uri"` // A replica set or sharded cluster, as users are stored in transactions
+	Database                  string   `json:"database" yaml:"database"`
+	UserCollection            string   `json:"user_collection" yaml:"user_collection"`
+	AuditCollection           string   `json:"audit_collection" yaml:"audit_collection"`
+	OutboxCollection          string   `json:"outbox_collection" yaml:"outbox_collection"`
+	WebhookCollection         string   `json:"webhook_collection" yaml:"webhook_collection"`
+	WebhookDeliveryCollection string   `json:"webhook_delivery_collection" yaml:"webhook_delivery_collection"`
+	APIKeyCollection          string   `json:"api_key_collection" yaml:"api_key_collection"`
+	RefreshTokenCollection    string   `json:"refresh_token_collection" yaml:"refresh_token_collection"`
+	ConnectTimeout            Duration `json:"connect_timeout" yaml:"connect_timeout"`
+}
+
+// RepositoryConfig selects the persistence backend
+type RepositoryConfig struct {
+	Backend string `json:"backend" yaml:"backend"`
+}
+
+// UsersConfig configures the lifecycle of deleted users
+type UsersConfig struct {
+	DeletedRetention Duration `json:"deleted_retention" yaml:"deleted_retention"` // How long soft-deleted users can be restored
+	PurgeInterval    Duration `json:"purge_interval" yaml:"purge_interval"`       // How often users past retention are hard-deleted
+}
+
+// EventsConfig configures how domain events are relayed from the outbox
+type EventsConfig struct {
+	Sink           string   `json:"sink" yaml:"sink"`                         // Where events are published: log, or channel in dev
+	RelayInterval  Duration `json:"relay_interval" yaml:"relay_interval"`     // How often the outbox is checked for pending events
+	RelayBatchSize int      `json:"relay_batch_size" yaml:"relay_batch_size"` // How many pending events are read at once
+
+	StreamSource    string   `json:"stream_source" yaml:"stream_source"`       // What feeds the user change stream: relay or changestream
+	StreamBuffer    int      `json:"stream_buffer" yaml:"stream_buffer"`       // How many events the change stream can replay
+	StreamHeartbeat Duration `json:"stream_heartbeat" yaml:"stream_heartbeat"` // How often idle change stream connections get a comment
+}
+
+// WebhooksConfig configures how webhook deliveries are sent and retried
+type WebhooksConfig struct {
+	Interval    Duration `json:"interval" yaml:"interval"`         // How often due deliveries are sent
+	BatchSize   int      `json:"batch_size" yaml:"batch_size"`     // How many due deliveries are read at once
+	Timeout     Duration `json:"timeout" yaml:"timeout"`           // How long a receiver has to answer
+	MaxAttempts int      `json:"max_attempts" yaml:"max_attempts"` // Attempts before a delivery is dead-lettered
+	BaseBackoff Duration `json:"base_backoff" yaml:"base_backoff"` // Wait after the first failure, doubled after each further one
+	MaxBackoff  Duration `json:"max_backoff" yaml:"max_backoff"`   // Longest wait between attempts
+}
+
+// AuthConfig configures how API callers are authenticated. Bearer tokens are
+// accepted when a JWT secret or JWKS file is set; API keys always are.
+type AuthConfig struct {
+	Enabled     bool   `json:"enabled" yaml:"enabled"`           // Reject requests without valid credentials; only dev may turn it off
+	JWTIssuer   string `json:"jwt_issuer" yaml:"jwt_issuer"`     // Required iss claim of bearer tokens
+	JWTAudience string `json:"jwt_audience" yaml:"jwt_audience"` // Required aud claim of bearer tokens, when set
+	JWTSecret   string `json:"jwt_secret" yaml:"jwt_secret"`     // Shared secret of HS256 tokens
+	JWKSFile    string `json:"jwks_file" yaml:"jwks_file"`       // JWKS file holding the public keys of RS256 tokens
+
+	AccessTokenTTL  Duration `json:"access_token_ttl" yaml:"access_token_ttl"`   // How long the access tokens issued at login are valid
+	RefreshTokenTTL Duration `json:"refresh_token_ttl" yaml:"refresh_token_ttl"` // How long a login session lasts without being refreshed
+}
+
+// MailConfig configures the emails sent to users and the signed links they carry
+type MailConfig struct {
+	Mailer string `json:"mailer" yaml:"mailer"` // How emails are sent: log, file or smtp
+	From   string `json:"from" yaml:"from"`     // Sender address of every email
+	File   string `json:"file" yaml:"file"`     // File the file mailer appends emails to
+
+	SMTPHost     string `json:"smtp_host" yaml:"smtp_host"`
+	SMTPPort     int    `json:"smtp_port" yaml:"smtp_port"`
+	SMTPUsername string `json:"smtp_username" yaml:"smtp_username"` // PLAIN auth is used when set
+	SMTPPassword string `json:"smtp_password" yaml:"smtp_password"`
+
+	LinkBaseURL string `json:"link_base_url" yaml:"link_base_url"` // Base URL of the pages the links in emails open
+	// Signs the tokens of verification and password reset links. Required outside dev;
+	// in dev a random secret is made at startup, so links stop working on restart.
+	TokenSecret    string   `json:"token_secret" yaml:"token_secret"`
+	VerifyTokenTTL Duration `json:"verify_token_ttl" yaml:"verify_token_ttl"` // How long email verification links work
+	ResetTokenTTL  Duration `json:"reset_token_ttl" yaml:"reset_token_ttl"`   // How long password reset links work
+}
+
+// minJWTSecretLength is the shortest HS256 secret accepted, in bytes
+const minJWTSecretLength = 32
+
+// LoginEnabled reports whether users can log in with a password. The access tokens
+// issued at login are signed with the JWT secret.
+func (a AuthConfig) LoginEnabled() bool {
+	return a.JWTSecret != ""
+}
+
+// JWTEnabled reports whether bearer tokens are accepted
+func (a AuthConfig) JWTEnabled() bool {
+	return a.JWTSecret != "" || a.JWKSFile != ""
+}
+
+// Default returns the configuration used when nothing else is provided
+func Default() *Config {
+	return &Config{
+		Env: EnvDev,
+		Server: ServerConfig{
+			Port:     3010,
+			GRPCPort: 3011,
+		},
+		Mongo: MongoConfig{
+			URI:                       "mongodb://localhost:27017",
+			Database:                  "crud",
+			UserCollection:            "users",
+			AuditCollection:           "user_audit",
+			OutboxCollection:          "outbox",
+			WebhookCollection:         "webhooks",
+			WebhookDeliveryCollection: "webhook_deliveries",
+			APIKeyCollection:          "api_keys",
+			RefreshTokenCollection:    "refresh_tokens",
+			ConnectTimeout:            Duration(10 * time.Second),
+		},
+		Repository: RepositoryConfig{
+			Backend: RepoBackendMongo,
+		},
+		Users: UsersConfig{
+			DeletedRetention: Duration(30 * 24 * time.Hour),
+			PurgeInterval:    Duration(time.Hour),
+		},
+		Events: EventsConfig{
+			Sink:           EventSinkLog,
+			RelayInterval:  Duration(time.Second),
+			RelayBatchSize: 100,
+
+			StreamSource:    EventStreamRelay,
+			StreamBuffer:    1000,
+			StreamHeartbeat: Duration(15 * time.Second),
+		},
+		Webhooks: WebhooksConfig{
+			Interval:    Duration(time.Second),
+			BatchSize:   50,
+			Timeout:     Duration(10 * time.Second),
+			MaxAttempts: 8,
+			BaseBackoff: Duration(10 * time.Second),
+			MaxBackoff:  Duration(time.Hour),
+		},
+		Auth: AuthConfig{
+			Enabled:         true,
+			AccessTokenTTL:  Duration(15 * time.Minute),
+			RefreshTokenTTL: Duration(30 * 24 * time.Hour),
+		},
+		Mail: MailConfig{
+			Mailer:         MailerLog,
+			From:           "no-reply@localhost",
+			SMTPPort:       587,
+			LinkBaseURL:    "http://localhost:3000",
+			VerifyTokenTTL: Duration(48 * time.Hour),
+			ResetTokenTTL:  Duration(time.Hour),
+		},
+	}
+}
+
+// binding ties a setting to its environment variable and CLI flag
+type binding struct {
+	env   string
+	flag  string
+	usage string
+	set   func(c *Config, v string) error
+}
+
+var bindings = []binding{
+	{"CRUD_ENV", "env", "deployment environment (dev, staging, prod)", func(c *Config, v string) error {
+		c.Env = v
+		return nil
+	}},
+	{"CRUD_SERVER_PORT", "port", "HTTP server port", func(c *Config, v string) error {
+		return setInt(&c.Server.Port, v)
+	}},
+	{"CRUD_SERVER_GRPC_PORT", "grpc-port", "gRPC server port", func(c *Config, v string) error {
+		return setInt(&c.Server.GRPCPort, v)
+	}},
+	{"CRUD_MONGO_URI", "mongo-uri", "MongoDB connection URI", func(c *Config, v string) error {
+		c.Mongo.URI = v
+		return nil
+	}},
+	{"CRUD_MONGO_DATABASE", "mongo-database", "MongoDB database name", func(c *Config, v string) error {
+		c.Mongo.Database = v
+		return nil
+	}},
+	{"CRUD_MONGO_USER_COLLECTION", "mongo-user-collection", "MongoDB collection holding users", func(c *Config, v string) error {
+		c.Mongo.UserCollection = v
+		return nil
+	}},
+	{"CRUD_MONGO_AUDIT_COLLECTION", "mongo-audit-collection", "MongoDB collection holding the user audit log", func(c *Config, v string) error {
+		c.Mongo.AuditCollection = v
+		return nil
+	}},
+	{"CRUD_MONGO_OUTBOX_COLLECTION", "mongo-outbox-collection", "MongoDB collection holding unpublished domain events", func(c *Config, v string) error {
+		c.Mongo.OutboxCollection = v
+		return nil
+	}},
+	{"CRUD_MONGO_WEBHOOK_COLLECTION", "mongo-webhook-collection", "MongoDB collection holding webhook subscriptions", func(c *Config, v string) error {
+		c.Mongo.WebhookCollection = v
+		return nil
+	}},
+	{"CRUD_MONGO_WEBHOOK_DELIVERY_COLLECTION", "mongo-webhook-delivery-collection", "MongoDB collection holding webhook deliveries", func(c *Config, v string) error {
+		c.Mongo.WebhookDeliveryCollection = v
+		return nil
+	}},
+	{"CRUD_MONGO_API_KEY_COLLECTION", "mongo-api-key-collection", "MongoDB collection holding API key hashes", func(c *Config, v string) error {
+		c.Mongo.APIKeyCollection = v
+		return nil
+	}},
+	{"CRUD_MONGO_REFRESH_TOKEN_COLLECTION", "mongo-refresh-token-collection", "MongoDB collection holding refresh token hashes", func(c *Config, v string) error {
+		c.Mongo.RefreshTokenCollection = v
+		return nil
+	}},
+	{"CRUD_MONGO_CONNECT_TIMEOUT", "mongo-connect-timeout", "MongoDB connect timeout (e.g. 10s)", func(c *Config, v string) error {
+		return c.Mongo.ConnectTimeout.UnmarshalText([]byte(v))
+	}},
+	{"CRUD_REPO_BACKEND", "repo-backend", "user repository backend (mongo, memory)", func(c *Config, v string) error {
+		c.Repository.Backend = v
+		return nil
+	}},
+	{"CRUD_USERS_DELETED_RETENTION", "users-deleted-retention", "how long deleted users can be restored (e.g. 720h)", func(c *Config, v string) error {
+		return c.Users.DeletedRetention.UnmarshalText([]byte(v))
+	}},
+	{"CRUD_USERS_PURGE_INTERVAL", "users-purge-interval", "how often deleted users past retention are purged (e.g. 1h)", func(c *Config, v string) error {
+		return c.Users.PurgeInterval.UnmarshalText([]byte(v))
+	}},
+	{"CRUD_EVENTS_SINK", "events-sink", "where domain events are published (log, channel)", func(c *Config, v string) error {
+		c.Events.Sink = v
+		return nil
+	}},
+	{"CRUD_EVENTS_RELAY_INTERVAL", "events-relay-interval", "how often pending domain events are published (e.g. 1s)", func(c *Config, v string) error {
+		return c.Events.RelayInterval.UnmarshalText([]byte(v))
+	}},
+	{"CRUD_EVENTS_RELAY_BATCH_SIZE", "events-relay-batch-size", "how many pending domain events are read at once", func(c *Config, v string) error {
+		return setInt(&c.Events.RelayBatchSize, v)
+	}},
+	{"CRUD_EVENTS_STREAM_SOURCE", "events-stream-source", "what feeds the user change stream (relay, changestream)", func(c *Config, v string) error {
+		c.Events.StreamSource = v
+		return nil
+	}},
+	{"CRUD_EVENTS_STREAM_BUFFER", "events-stream-buffer", "how many events the user change stream can replay", func(c *Config, v string) error {
+		return setInt(&c.Events.StreamBuffer, v)
+	}},
+	{"CRUD_EVENTS_STREAM_HEARTBEAT", "events-stream-heartbeat", "how often idle change stream connections get a heartbeat (e.g. 15s)", func(c *Config, v string) error {
+		return c.Events.StreamHeartbeat.UnmarshalText([]byte(v))
+	}},
+	{"CRUD_WEBHOOKS_INTERVAL", "webhooks-interval", "how often due webhook deliveries are sent (e.g. 1s)", func(c *Config, v string) error {
+		return c.Webhooks.Interval.UnmarshalText([]byte(v))
+	}},
+	{"CRUD_WEBHOOKS_BATCH_SIZE", "webhooks-batch-size", "how many due webhook deliveries are read at once", func(c *Config, v string) error {
+		return setInt(&c.Webhooks.BatchSize, v)
+	}},
+	{"CRUD_WEBHOOKS_TIMEOUT", "webhooks-timeout", "how long webhook receivers have to answer (e.g. 10s)", func(c *Config, v string) error {
+		return c.Webhooks.Timeout.UnmarshalText([]byte(v))
+	}},
+	{"CRUD_WEBHOOKS_MAX_ATTEMPTS", "webhooks-max-attempts", "attempts before a webhook delivery is dead-lettered", func(c *Config, v string) error {
+		return setInt(&c.Webhooks.MaxAttempts, v)
+	}},
+	{"CRUD_WEBHOOKS_BASE_BACKOFF", "webhooks-base-backoff", "wait after the first failed webhook delivery (e.g. 10s)", func(c *Config, v string) error {
+		return c.Webhooks.BaseBackoff.UnmarshalText([]byte(v))
+	}},
+	{"CRUD_WEBHOOKS_MAX_BACKOFF", "webhooks-max-backoff", "longest wait between webhook delivery attempts (e.g. 1h)", func(c *Config, v string) error {
+		return c.Webhooks.MaxBackoff.UnmarshalText([]byte(v))
+	}},
+	{"CRUD_AUTH_ENABLED", "auth-enabled", "reject API requests without valid credentials (true, false)", func(c *Config, v string) error {
+		return setBool(&c.Auth.Enabled, v)
+	}},
+	{"CRUD_AUTH_JWT_ISSUER", "auth-jwt-issuer", "required issuer of bearer tokens", func(c *Config, v string) error {
+		c.Auth.JWTIssuer = v
+		return nil
+	}},
+	{"CRUD_AUTH_JWT_AUDIENCE", "auth-jwt-audience", "required audience of bearer tokens", func(c *Config, v string) error {
+		c.Auth.JWTAudience = v
+		return nil
+	}},
+	{"CRUD_AUTH_JWT_SECRET", "auth-jwt-secret", "shared secret of HS256 bearer tokens", func(c *Config, v string) error {
+		c.Auth.JWTSecret = v
+		return nil
+	}},
+	{"CRUD_AUTH_JWKS_FILE", "auth-jwks-file", "JWKS file with the public keys of RS256 bearer tokens", func(c *Config, v string) error {
+		c.Auth.JWKSFile = v
+		return nil
+	}},
+	{"CRUD_AUTH_ACCESS_TOKEN_TTL", "auth-access-token-ttl", "how long access tokens issued at login are valid (e.g. 15m)", func(c *Config, v string) error {
+		return c.Auth.AccessTokenTTL.UnmarshalText([]byte(v))
+	}},
+	{"CRUD_AUTH_REFRESH_TOKEN_TTL", "auth-refresh-token-ttl", "how long a login session lasts without being refreshed (e.g. 720h)", func(c *Config, v string) error {
+		return c.Auth.RefreshTokenTTL.UnmarshalText([]byte(v))
+	}},
+	{"CRUD_MAIL_MAILER", "mail-mailer", "how emails are sent (log, file, smtp)", func(c *Config, v string) error {
+		c.Mail.Mailer = v
+		return nil
+	}},
+	{"CRUD_MAIL_FROM", "mail-from", "sender address of emails", func(c *Config, v string) error {
+		c.Mail.From = v
+		return nil
+	}},
+	{"CRUD_MAIL_FILE", "mail-file", "file the file mailer appends emails to", func(c *Config, v string) error {
+		c.Mail.File = v
+		return nil
+	}},
+	{"CRUD_MAIL_SMTP_HOST", "mail-smtp-host", "SMTP server host", func(c *Config, v string) error {
+		c.Mail.SMTPHost = v
+		return nil
+	}},
+	{"CRUD_MAIL_SMTP_PORT", "mail-smtp-port", "SMTP server port", func(c *Config, v string) error {
+		return setInt(&c.Mail.SMTPPort, v)
+	}},
+	{"CRUD_MAIL_SMTP_USERNAME", "mail-smtp-username", "SMTP username", func(c *Config, v string) error {
+		c.Mail.SMTPUsername = v
+		return nil
+	}},
+	{"CRUD_MAIL_SMTP_PASSWORD", "mail-smtp-password", "SMTP password", func(c *Config, v string) error {
+		c.Mail.SMTPPassword = v
+		return nil
+	}},
+	{"CRUD_MAIL_LINK_BASE_URL", "mail-link-base-url", "base URL of the pages the links in emails open", func(c *Config, v string) error {
+		c.Mail.LinkBaseURL = v
+		return nil
+	}},
+	{"CRUD_MAIL_TOKEN_SECRET", "mail-token-secret", "secret signing verification and password reset links", func(c *Config, v string) error {
+		c.Mail.TokenSecret = v
+		return nil
+	}},
+	{"CRUD_MAIL_VERIFY_TOKEN_TTL", "mail-verify-token-ttl", "how long email verification links work (e.g. 48h)", func(c *Config, v string) error {
+		return c.Mail.VerifyTokenTTL.UnmarshalText([]byte(v))
+	}},
+	{"CRUD_MAIL_RESET_TOKEN_TTL", "mail-reset-token-ttl", "how long password reset links work (e.g. 1h)", func(c *Config, v string) error {
+		return c.Mail.ResetTokenTTL.UnmarshalText([]byte(v))
+	}},
+}
+
+// Load builds the configuration from defaults, an optional config file,
+// environment variables and the given command line arguments, then validates it.
+func Load(args []string) (*Config, error) {
+	cfg := Default()
+
+	fs := flag.NewFlagSet("crud", flag.ContinueOnError)
+	file := fs.String("config", os.Getenv(envConfigFile), "path to a YAML or JSON config file")
+	for _, b := range bindings {
+		fs.String(b.flag, "", b.usage)
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if *file != "" {
+		if err := loadFile(cfg, *file); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, b := range bindings {
+		if v, ok := os.LookupEnv(b.env); ok {
+			if err := b.set(cfg, v); err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", b.env, err)
+			}
+		}
+	}
+
+	var flagErr error
+	fs.Visit(func(f *flag.Flag) {
+		for _, b := range bindings {
+			if b.flag == f.Name && flagErr == nil {
+				if err := b.set(cfg, f.Value.String()); err != nil {
+					flagErr = fmt.Errorf("invalid -%s: %w", b.flag, err)
+				}
+			}
+		}
+	})
+	if flagErr != nil {
+		return nil, flagErr
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// loadFile overlays the YAML or JSON file at path onto cfg
+func loadFile(cfg *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, cfg)
+	case ".json":
+		err = json.Unmarshal(data, cfg)
+	default:
+		return fmt.Errorf("unsupported config file extension %q", ext)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// Validate reports every invalid setting at once
+func (c *Config) Validate() error {
+	var errs []error
+
+	switch c.Env {
+	case EnvDev, EnvStaging, EnvProd:
+	default:
+		errs = append(errs, fmt.Errorf("env must be one of %s, %s, %s; got %q", EnvDev, EnvStaging, EnvProd, c.Env))
+	}
+
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		errs = append(errs, fmt.Errorf("server.port must be between 1 and 65535; got %d", c.Server.Port))
+	}
+	if c.Server.GRPCPort < 1 || c.Server.GRPCPort > 65535 {
+		errs = append(errs, fmt.Errorf("server.grpc_port must be between 1 and 65535; got %d", c.Server.GRPCPort))
+	} else if c.Server.GRPCPort == c.Server.Port {
+		errs = append(errs, fmt.Errorf("server.grpc_port must differ from server.port; both are %d", c.Server.Port))
+	}
+
+	switch c.Repository.Backend {
+	case RepoBackendMemory:
+	case RepoBackendMongo:
+		if c.Mongo.URI == "" {
+			errs = append(errs, errors.New("mongo.uri is required"))
+		}
+		if c.Mongo.Database == "" {
+			errs = append(errs, errors.New("mongo.database is required"))
+		}
+		if c.Mongo.UserCollection == "" {
+			errs = append(errs, errors.New("mongo.user_collection is required"))
+		}
+		if c.Mongo.AuditCollection == "" {
+			errs = append(errs, errors.New("mongo.audit_collection is required"))
+		}
+		if c.Mongo.OutboxCollection == "" {
+			errs = append(errs, errors.New("mongo.outbox_collection is required"))
+		}
+		if c.Mongo.WebhookCollection == "" {
+			errs = append(errs, errors.New("mongo.webhook_collection is required"))
+		}
+		if c.Mongo.WebhookDeliveryCollection == "" {
+			errs = append(errs, errors.New("mongo.webhook_delivery_collection is required"))
+		}
+		if c.Mongo.APIKeyCollection == "" {
+			errs = append(errs, errors.New("mongo.api_key_collection is required"))
+		}
+		if c.Mongo.RefreshTokenCollection == "" {
+			errs = append(errs, errors.New("mongo.refresh_token_collection is required"))
+		}
+		if c.Mongo.ConnectTimeout <= 0 {
+			errs = append(errs, errors.New("mongo.connect_timeout must be positive"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("repository.backend must be %s or %s; got %q", RepoBackendMongo, RepoBackendMemory, c.Repository.Backend))
+	}
+
+	if c.Users.DeletedRetention <= 0 {
+		errs = append(errs, errors.New("users.deleted_retention must be positive"))
+	}
+	if c.Users.PurgeInterval <= 0 {
+		errs = append(errs, errors.New("users.purge_interval must be positive"))
+	}
+
+	switch c.Events.Sink {
+	case EventSinkLog:
+	case EventSinkChannel:
+		// Nothing in the server reads the channel, so events are dropped once it is full
+		if !c.IsDev() {
+			errs = append(errs, fmt.Errorf("events.sink %s can only be used in %s", EventSinkChannel, EnvDev))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("events.sink must be %s or %s; got %q", EventSinkLog, EventSinkChannel, c.Events.Sink))
+	}
+	if c.Events.RelayInterval <= 0 {
+		errs = append(errs, errors.New("events.relay_interval must be positive"))
+	}
+	if c.Events.RelayBatchSize < 1 {
+		errs = append(errs, errors.New("events.relay_batch_size must be at least 1"))
+	}
+	switch c.Events.StreamSource {
+	case EventStreamRelay:
+	case EventStreamChangeStream:
+		if c.Repository.Backend != RepoBackendMongo {
+			errs = append(errs, fmt.Errorf("events.stream_source %s needs the %s repository backend", EventStreamChangeStream, RepoBackendMongo))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("events.stream_source must be %s or %s; got %q", EventStreamRelay, EventStreamChangeStream, c.Events.StreamSource))
+	}
+	if c.Events.StreamBuffer < 1 {
+		errs = append(errs, errors.New("events.stream_buffer must be at least 1"))
+	}
+	if c.Events.StreamHeartbeat <= 0 {
+		errs = append(errs, errors.New("events.stream_heartbeat must be positive"))
+	}
+
+	if c.Webhooks.Interval <= 0 {
+		errs = append(errs, errors.New("webhooks.interval must be positive"))
+	}
+	if c.Webhooks.BatchSize < 1 {
+		errs = append(errs, errors.New("webhooks.batch_size must be at least 1"))
+	}
+	if c.Webhooks.Timeout <= 0 {
+		errs = append(errs, errors.New("webhooks.timeout must be positive"))
+	}
+	if c.Webhooks.MaxAttempts < 1 {
+		errs = append(errs, errors.New("webhooks.max_attempts must be at least 1"))
+	}
+	if c.Webhooks.BaseBackoff <= 0 || c.Webhooks.MaxBackoff < c.Webhooks.BaseBackoff {
+		errs = append(errs, errors.New("webhooks.base_backoff must be positive and at most webhooks.max_backoff"))
+	}
+
+	if !c.Auth.Enabled && !c.IsDev() {
+		errs = append(errs, fmt.Errorf("auth.enabled can only be turned off in %s", EnvDev))
+	}
+	if c.Auth.JWTEnabled() && c.Auth.JWTIssuer == "" {
+		errs = append(errs, errors.New("auth.jwt_issuer is required to accept bearer tokens"))
+	}
+	if c.Auth.JWTSecret != "" && len(c.Auth.JWTSecret) < minJWTSecretLength {
+		errs = append(errs, fmt.Errorf("auth.jwt_secret must be at least %d bytes", minJWTSecretLength))
+	}
+	if c.Auth.AccessTokenTTL <= 0 || c.Auth.RefreshTokenTTL < c.Auth.AccessTokenTTL {
+		errs = append(errs, errors.New("auth.access_token_ttl must be positive and at most auth.refresh_token_ttl"))
+	}
+
+	switch c.Mail.Mailer {
+	case MailerLog:
+	case MailerFile:
+		if c.Mail.File == "" {
+			errs = append(errs, fmt.Errorf("mail.file is required by the %s mailer", MailerFile))
+		}
+	case MailerSMTP:
+		if c.Mail.SMTPHost == "" {
+			errs = append(errs, fmt.Errorf("mail.smtp_host is required by the %s mailer", MailerSMTP))
+		}
+		if c.Mail.SMTPPort < 1 || c.Mail.SMTPPort > 65535 {
+			errs = append(errs, fmt.Errorf("mail.smtp_port must be between 1 and 65535; got %d", c.Mail.SMTPPort))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("mail.mailer must be one of %s, %s, %s; got %q", MailerLog, MailerFile, MailerSMTP, c.Mail.Mailer))
+	}
+	if _, err := mail.ParseAddress(c.Mail.From); err != nil {
+		errs = append(errs, fmt.Errorf("mail.from must be an email address; got %q", c.Mail.From))
+	}
+	if u, err := url.Parse(c.Mail.LinkBaseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs = append(errs, fmt.Errorf("mail.link_base_url must be an absolute http(s) URL; got %q", c.Mail.LinkBaseURL))
+	}
+	if c.Mail.TokenSecret == "" && !c.IsDev() {
+		errs = append(errs, fmt.Errorf("mail.token_secret can only be left empty in %s", EnvDev))
+	}
+	if c.Mail.TokenSecret != "" && len(c.Mail.TokenSecret) < minJWTSecretLength {
+		errs = append(errs, fmt.Errorf("mail.token_secret must be at least %d bytes", minJWTSecretLength))
+	}
+	if c.Mail.VerifyTokenTTL <= 0 {
+		errs = append(errs, errors.New("mail.verify_token_ttl must be positive"))
+	}
+	if c.Mail.ResetTokenTTL <= 0 {
+		errs = append(errs, errors.New("mail.reset_token_ttl must be positive"))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// IsDev reports whether the application runs in the dev environment
+func (c *Config) IsDev() bool {
+	return c.Env == EnvDev
+}
+
+func setInt(dst *int, v string) error {
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return err
+	}
+	*dst = n
+	return nil
+}
+
+func setBool(dst *bool, v string) error {
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return err
+	}
+	*dst = b
+	return nil
+}
+
+// Duration is a time.Duration that reads and writes as a string like "10s"
+type Duration time.Duration
+
+// Std returns the value as a time.Duration
+func (d Duration) Std() time.Duration {
+	return time.Duration(d)
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
@@ -0,0 +1,352 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	type test struct {
+		id         int
+		name       string
+		beforeTest func(t *testing.T) []string
+		want       func() *Config
+		wantErr    bool
+	}
+
+	tests := []test{
+		{
+			id:   1,
+			name: "Defaults - success",
+			beforeTest: func(t *testing.T) []string {
+				return nil
+			},
+			want:    Default,
+			wantErr: false,
+		},
+		{
+			id:   2,
+			name: "File, env and flags in order of precedence - success",
+			beforeTest: func(t *testing.T) []string {
+				path := filepath.Join(t.TempDir(), "config.yaml")
+				content := "env: staging\nserver:\n  port: 4000\nmongo:\n  uri: mongodb://file:27017\n  database: filedb\n  connect_timeout: 5s\nmail:\n  token_secret: 0123456789abcdef0123456789abcdef\n"
+				if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+					t.Fatal(err)
+				}
+				t.Setenv("CRUD_MONGO_DATABASE", "envdb")
+				t.Setenv("CRUD_SERVER_PORT", "5000")
+				return []string{"-config", path, "-port", "6000"}
+			},
+			want: func() *Config {
+				cfg := Default()
+				cfg.Env = EnvStaging
+				cfg.Server.Port = 6000
+				cfg.Mongo.URI = "mongodb://file:27017"
+				cfg.Mongo.Database = "envdb"
+				cfg.Mongo.ConnectTimeout = Duration(5 * time.Second)
+				cfg.Mail.TokenSecret = "0123456789abcdef0123456789abcdef"
+				return cfg
+			},
+			wantErr: false,
+		},
+		{
+			id:   3,
+			name: "JSON file - success",
+			beforeTest: func(t *testing.T) []string {
+				path := filepath.Join(t.TempDir(), "config.json")
+				content := `{"repository": {"backend": "memory"}, "mongo": {"uri": ""}}`
+				if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+					t.Fatal(err)
+				}
+				t.Setenv("CRUD_CONFIG_FILE", path)
+				return nil
+			},
+			want: func() *Config {
+				cfg := Default()
+				cfg.Repository.Backend = RepoBackendMemory
+				cfg.Mongo.URI = ""
+				return cfg
+			},
+			wantErr: false,
+		},
+		{
+			id:   4,
+			name: "Invalid port - failure",
+			beforeTest: func(t *testing.T) []string {
+				return []string{"-port", "70000"}
+			},
+			wantErr: true,
+		},
+		{
+			id:   5,
+			name: "Unknown backend - failure",
+			beforeTest: func(t *testing.T) []string {
+				t.Setenv("CRUD_REPO_BACKEND", "postgres")
+				return nil
+			},
+			wantErr: true,
+		},
+		{
+			id:   6,
+			name: "Missing Mongo URI - failure",
+			beforeTest: func(t *testing.T) []string {
+				return []string{"-mongo-uri", ""}
+			},
+			wantErr: true,
+		},
+		{
+			id:   7,
+			name: "Users retention from env - success",
+			beforeTest: func(t *testing.T) []string {
+				t.Setenv("CRUD_USERS_DELETED_RETENTION", "48h")
+				return []string{"-users-purge-interval", "15m"}
+			},
+			want: func() *Config {
+				cfg := Default()
+				cfg.Users.DeletedRetention = Duration(48 * time.Hour)
+				cfg.Users.PurgeInterval = Duration(15 * time.Minute)
+				return cfg
+			},
+			wantErr: false,
+		},
+		{
+			id:   8,
+			name: "Zero purge interval - failure",
+			beforeTest: func(t *testing.T) []string {
+				return []string{"-users-purge-interval", "0s"}
+			},
+			wantErr: true,
+		},
+		{
+			id:   9,
+			name: "Channel event sink from env - success",
+			beforeTest: func(t *testing.T) []string {
+				t.Setenv("CRUD_EVENTS_SINK", "channel")
+				return []string{"-events-relay-batch-size", "10"}
+			},
+			want: func() *Config {
+				cfg := Default()
+				cfg.Events.Sink = EventSinkChannel
+				cfg.Events.RelayBatchSize = 10
+				return cfg
+			},
+			wantErr: false,
+		},
+		{
+			id:   10,
+			name: "Unknown event sink - failure",
+			beforeTest: func(t *testing.T) []string {
+				return []string{"-events-sink", "kafka"}
+			},
+			wantErr: true,
+		},
+		{
+			id:   11,
+			name: "Webhook retries from env - success",
+			beforeTest: func(t *testing.T) []string {
+				t.Setenv("CRUD_WEBHOOKS_MAX_ATTEMPTS", "3")
+				return []string{"-webhooks-base-backoff", "1s"}
+			},
+			want: func() *Config {
+				cfg := Default()
+				cfg.Webhooks.MaxAttempts = 3
+				cfg.Webhooks.BaseBackoff = Duration(time.Second)
+				return cfg
+			},
+			wantErr: false,
+		},
+		{
+			id:   12,
+			name: "Base backoff above max backoff - failure",
+			beforeTest: func(t *testing.T) []string {
+				return []string{"-webhooks-base-backoff", "2h"}
+			},
+			wantErr: true,
+		},
+		{
+			id:   13,
+			name: "Change stream source from env - success",
+			beforeTest: func(t *testing.T) []string {
+				t.Setenv("CRUD_EVENTS_STREAM_SOURCE", "changestream")
+				return []string{"-events-stream-buffer", "50"}
+			},
+			want: func() *Config {
+				cfg := Default()
+				cfg.Events.StreamSource = EventStreamChangeStream
+				cfg.Events.StreamBuffer = 50
+				return cfg
+			},
+			wantErr: false,
+		},
+		{
+			id:   14,
+			name: "Change stream source without MongoDB - failure",
+			beforeTest: func(t *testing.T) []string {
+				return []string{"-events-stream-source", "changestream", "-repo-backend", "memory"}
+			},
+			wantErr: true,
+		},
+		{
+			id:   15,
+			name: "gRPC port from env - success",
+			beforeTest: func(t *testing.T) []string {
+				t.Setenv("CRUD_SERVER_GRPC_PORT", "9090")
+				return nil
+			},
+			want: func() *Config {
+				cfg := Default()
+				cfg.Server.GRPCPort = 9090
+				return cfg
+			},
+			wantErr: false,
+		},
+		{
+			id:   16,
+			name: "gRPC port equal to HTTP port - failure",
+			beforeTest: func(t *testing.T) []string {
+				return []string{"-grpc-port", "3010"}
+			},
+			wantErr: true,
+		},
+		{
+			id:   17,
+			name: "JWT settings from env and flags - success",
+			beforeTest: func(t *testing.T) []string {
+				t.Setenv("CRUD_AUTH_JWT_SECRET", "0123456789abcdef0123456789abcdef")
+				t.Setenv("CRUD_AUTH_JWT_ISSUER", "https://auth.example.com")
+				return []string{"-auth-jwt-audience", "crud-api"}
+			},
+			want: func() *Config {
+				cfg := Default()
+				cfg.Auth.JWTSecret = "0123456789abcdef0123456789abcdef"
+				cfg.Auth.JWTIssuer = "https://auth.example.com"
+				cfg.Auth.JWTAudience = "crud-api"
+				return cfg
+			},
+			wantErr: false,
+		},
+		{
+			id:   18,
+			name: "JWT secret without an issuer, and too short - failure",
+			beforeTest: func(t *testing.T) []string {
+				return []string{"-auth-jwt-secret", "secret"}
+			},
+			wantErr: true,
+		},
+		{
+			id:   19,
+			name: "Auth turned off in dev - success",
+			beforeTest: func(t *testing.T) []string {
+				t.Setenv("CRUD_AUTH_ENABLED", "false")
+				return nil
+			},
+			want: func() *Config {
+				cfg := Default()
+				cfg.Auth.Enabled = false
+				return cfg
+			},
+			wantErr: false,
+		},
+		{
+			id:   20,
+			name: "Auth turned off in prod - failure",
+			beforeTest: func(t *testing.T) []string {
+				return []string{"-env", "prod", "-auth-enabled", "false"}
+			},
+			wantErr: true,
+		},
+		{
+			id:   21,
+			name: "Token lifetimes from env and flags - success",
+			beforeTest: func(t *testing.T) []string {
+				t.Setenv("CRUD_AUTH_ACCESS_TOKEN_TTL", "5m")
+				return []string{"-auth-refresh-token-ttl", "24h"}
+			},
+			want: func() *Config {
+				cfg := Default()
+				cfg.Auth.AccessTokenTTL = Duration(5 * time.Minute)
+				cfg.Auth.RefreshTokenTTL = Duration(24 * time.Hour)
+				return cfg
+			},
+			wantErr: false,
+		},
+		{
+			id:   22,
+			name: "Refresh tokens shorter-lived than access tokens - failure",
+			beforeTest: func(t *testing.T) []string {
+				return []string{"-auth-access-token-ttl", "1h", "-auth-refresh-token-ttl", "30m"}
+			},
+			wantErr: true,
+		},
+		{
+			id:   23,
+			name: "SMTP mailer from env - success",
+			beforeTest: func(t *testing.T) []string {
+				t.Setenv("CRUD_MAIL_MAILER", "smtp")
+				t.Setenv("CRUD_MAIL_SMTP_HOST", "smtp.example.com")
+				return []string{"-mail-from", "Crud <no-reply@example.com>", "-mail-reset-token-ttl", "30m"}
+			},
+			want: func() *Config {
+				cfg := Default()
+				cfg.Mail.Mailer = MailerSMTP
+				cfg.Mail.SMTPHost = "smtp.example.com"
+				cfg.Mail.From = "Crud <no-reply@example.com>"
+				cfg.Mail.ResetTokenTTL = Duration(30 * time.Minute)
+				return cfg
+			},
+			wantErr: false,
+		},
+		{
+			id:   24,
+			name: "File mailer without a file - failure",
+			beforeTest: func(t *testing.T) []string {
+				return []string{"-mail-mailer", "file"}
+			},
+			wantErr: true,
+		},
+		{
+			id:   25,
+			name: "Relative link base URL - failure",
+			beforeTest: func(t *testing.T) []string {
+				return []string{"-mail-link-base-url", "/app"}
+			},
+			wantErr: true,
+		},
+		{
+			id:   26,
+			name: "Channel event sink in prod - failure",
+			beforeTest: func(t *testing.T) []string {
+				return []string{"-env", "prod", "-events-sink", "channel"}
+			},
+			wantErr: true,
+		},
+		{
+			id:   27,
+			name: "No mail token secret in prod - failure",
+			beforeTest: func(t *testing.T) []string {
+				return []string{"-env", "prod"}
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := tt.beforeTest(t)
+
+			got, err := Load(args)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ID %v Load() error = %v, wantErr %v", tt.id, err, tt.wantErr)
+				return
+			}
+			if tt.want != nil {
+				assert.Equal(t, tt.want(), got)
+			}
+		})
+	}
+}
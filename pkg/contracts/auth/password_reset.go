@@ -0,0 +1,12 @@
+package auth
+
+// @Description ForgotPasswordReq is the request structure for forgot password API call.
+type ForgotPasswordReq struct {
+	Email string `json:"email" example:"user@example.com" binding:"required,email,max=254"`
+} // @name ForgotPasswordReq
+
+// @Description ResetPasswordReq is the request structure for reset password API call.
+type ResetPasswordReq struct {
+	Token       string `json:"token" example:"eyJwIjoicmVzZXRfcGFzc3dvcmQiLi4ufQ.Qm9n..." binding:"required"` // Token of the link emailed to the user
+	NewPassword string `json:"new_password" binding:"required,min=8,max=72"`
+} // @name ResetPasswordReq
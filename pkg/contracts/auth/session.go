@@ -0,0 +1,27 @@
+package auth
+
+// @Description LoginReq is the request structure for login API call.
+type LoginReq struct {
+	Email    string `json:"email" example:"user@example.com" binding:"required,email,max=254"`
+	Password string `json:"password" example:"correct horse battery" binding:"required,max=72"`
+} // @name LoginReq
+
+// @Description RefreshReq is the request structure for refresh and logout API calls.
+type RefreshReq struct {
+	RefreshToken string `json:"refresh_token" example:"crudr_Yk3n0Jx6...Q" binding:"required"`
+} // @name RefreshReq
+
+// @Description TokenRes is the response structure for login and refresh API calls.
+// The refresh token replaces the one sent, which no longer works.
+type TokenRes struct {
+	AccessToken  string `json:"access_token" example:"eyJhbGciOiJIUzI1NiIs..."`
+	TokenType    string `json:"token_type" example:"Bearer"`
+	ExpiresIn    int64  `json:"expires_in" example:"900"` // Seconds until the access token expires
+	RefreshToken string `json:"refresh_token" example:"crudr_Yk3n0Jx6...Q"`
+} // @name TokenRes
+
+// @Description ChangePasswordReq is the request structure for change password API call.
+type ChangePasswordReq struct {
+	OldPassword string `json:"old_password" binding:"required,max=72"`
+	NewPassword string `json:"new_password" binding:"required,min=8,max=72"`
+} // @name ChangePasswordReq
@@ -0,0 +1,14 @@
+package event
+
+import "time"
+
+// @Description Event is the JSON form of a domain event, as POSTed to webhook receivers
+// and pushed on the user change stream.
+type Event struct {
+	ID          string         `json:"id" example:"6650c0ffee0000000000cafe"` // Same across redeliveries, for deduplication
+	Type        string         `json:"type" example:"user.created"`
+	AggregateID string         `json:"aggregate_id" example:"5118863e-a240-44b9-9d3a-2f1e0c7b6a59"`
+	Actor       string         `json:"actor" example:"anonymous"`
+	OccurredAt  time.Time      `json:"occurred_at" example:"2024-05-02T08:30:00Z"`
+	Payload     map[string]any `json:"payload"`
+} // @name Event
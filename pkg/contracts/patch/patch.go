@@ -0,0 +1,17 @@
+package patch
+
+import "encoding/json"
+
+// Media types of the patch documents PATCH endpoints accept besides application/json
+const (
+	MergePatchContentType = "application/merge-patch+json" // RFC 7396
+	JSONPatchContentType  = "application/json-patch+json"  // RFC 6902
+)
+
+// @Description Operation is one step of a JSON Patch (RFC 6902). Paths are JSON Pointers (RFC 6901).
+type Operation struct {
+	Op    string          `json:"op" example:"replace" binding:"required,oneof=add remove replace move copy test"`
+	Path  string          `json:"path" example:"/name" binding:"required"`
+	From  string          `json:"from,omitempty" example:"/email"` // Source of move and copy
+	Value json.RawMessage `json:"value,omitempty"`                 // Value of add, replace and test
+} // @name Operation
@@ -0,0 +1,21 @@
+package problem
+
+// ContentType is the media type of every error response
+const ContentType = "application/problem+json"
+
+// @Description Problem is the RFC 7807 error response returned by every endpoint.
+type Problem struct {
+	Type      string       `json:"type" example:"/problems/not-found"`
+	Title     string       `json:"title" example:"Not Found"`
+	Status    int          `json:"status" example:"404"`
+	Detail    string       `json:"detail,omitempty" example:"user tcuZwYseZKNUp8D3tjMkyiZrYGC3 not found"`
+	Instance  string       `json:"instance,omitempty" example:"/api/users/tcuZwYseZKNUp8D3tjMkyiZrYGC3"`
+	RequestID string       `json:"request_id,omitempty" example:"5118863e-a240-44b9-a294-9734382770d9"`
+	Errors    []FieldError `json:"errors,omitempty"`
+} // @name Problem
+
+// @Description FieldError describes why a single request field is invalid.
+type FieldError struct {
+	Field   string `json:"field" example:"email"`
+	Message string `json:"message" example:"must be a valid email address"`
+} // @name FieldError
@@ -0,0 +1,7 @@
+package user
+
+// @Description ChangeUserStatusReq is the request structure for the suspend, activate and deactivate user API calls.
+type ChangeUserStatusReq struct {
+	Status string `json:"-"`                                                             // Set from the route
+	Reason string `json:"reason" example:"Reported for spam" binding:"required,max=500"` // Recorded with the change
+} // @name ChangeUserStatusReq
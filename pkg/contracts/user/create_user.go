@@ -1,16 +1,27 @@
 package user
 
+import "time"
+
 // @Description CreateUserReq is the request structure for create user API call.
 type CreateUserReq struct {
-	Name        string `json:"name" binding:"required"`
-	Email       string `json:"email" example:"user@example.com" binding:"required,email"`
-	PhoneNumber int64  `json:"phone_number" binding:"required,numeric"`
+	Name        string `json:"name" binding:"required,max=100"`
+	Email       string `json:"email" example:"user@example.com" binding:"required,email,max=254"`
+	PhoneNumber string `json:"phone_number" example:"+919876543210" binding:"required"`
+	Password    string `json:"password,omitempty" binding:"omitempty,min=8,max=72"` // Lets the user log in; never returned
 } // @name CreateUserReq
 
 // @Description CreateUserRes is the response structure for create user API call.
 type CreateUserRes struct {
-	ID          string `json:"id" example:"tcuZwYseZKNUp8D3tjMkyiZrYGC3"`
-	Name        string `json:"name"`
-	Email       string `json:"email" example:"user@example.com"`
-	PhoneNumber int64  `json:"phone_number"`
+	ID            string    `json:"id" example:"tcuZwYseZKNUp8D3tjMkyiZrYGC3"`
+	Name          string    `json:"name"`
+	Email         string    `json:"email" example:"user@example.com"`
+	PhoneNumber   string    `json:"phone_number" example:"+919876543210"`
+	EmailVerified bool      `json:"email_verified"`                                   // Whether the user followed the link sent to their email
+	Status        string    `json:"status" example:"active"`                          // pending, active, suspended or deactivated
+	StatusReason  string    `json:"status_reason,omitempty" example:"email verified"` // Why the user got their status
+	Version       int64     `json:"version" example:"1"`                              // Also sent as the ETag header
+	CreatedAt     time.Time `json:"created_at" example:"2024-05-01T10:00:00Z"`
+	UpdatedAt     time.Time `json:"updated_at" example:"2024-05-02T08:30:00Z"`
+	CreatedBy     string    `json:"created_by" example:"anonymous"`
+	UpdatedBy     string    `json:"updated_by" example:"anonymous"`
 } // @name CreateUserRes
@@ -1,5 +1,7 @@
 package user
 
+import "time"
+
 // @Description GetUserReq is the request structure for get user API call.
 type GetUserReq struct {
 	ID string `json:"id" example:"tcuZwYseZKNUp8D3tjMkyiZrYGC3" binding:"required"`
@@ -7,8 +9,41 @@ type GetUserReq struct {
 
 // @Description GetUserRes is the response structure for get user API call.
 type GetUserRes struct {
-	ID          string `json:"id" example:"tcuZwYseZKNUp8D3tjMkyiZrYGC3"`
-	Name        string `json:"name"`
-	Email       string `json:"email" example:"user@example.com"`
-	PhoneNumber int64  `json:"phone_number" binding:"-"`
+	ID            string     `json:"id" example:"tcuZwYseZKNUp8D3tjMkyiZrYGC3"`
+	Name          string     `json:"name"`
+	Email         string     `json:"email" example:"user@example.com"`
+	PhoneNumber   string     `json:"phone_number" example:"+919876543210" binding:"-"`
+	EmailVerified bool       `json:"email_verified"`                                   // Whether the user followed the link sent to their email
+	Status        string     `json:"status" example:"active"`                          // pending, active, suspended or deactivated
+	StatusReason  string     `json:"status_reason,omitempty" example:"email verified"` // Why the user got their status
+	Version       int64      `json:"version" example:"1"`                              // Also sent as the ETag header
+	DeletedAt     *time.Time `json:"deleted_at,omitempty"`                             // Only set on deleted users listed with include_deleted
+	CreatedAt     time.Time  `json:"created_at" example:"2024-05-01T10:00:00Z"`
+	UpdatedAt     time.Time  `json:"updated_at" example:"2024-05-02T08:30:00Z"`
+	CreatedBy     string     `json:"created_by" example:"anonymous"`
+	UpdatedBy     string     `json:"updated_by" example:"anonymous"`
 } // @name GetUserRes
+
+// @Description GetUsersReq is the request structure for list users API call.
+type GetUsersReq struct {
+	Limit   int                 `form:"limit" json:"limit" example:"20" binding:"min=0,max=100"` // Page size, 1-100 (default 20)
+	Cursor  string              `form:"cursor" json:"cursor"`                                    // Opaque cursor from a previous response's next_cursor
+	Offset  int                 `form:"offset" json:"offset" example:"0" binding:"min=0"`        // Number of users to skip; cannot be combined with cursor
+	Search  string              `form:"q" json:"q" example:"alam"`                               // Case-insensitive text matched against name and email
+	Sort    string              `form:"sort" json:"sort" example:"-name,email"`
+	Filters map[string][]string `form:"-" json:"-"` // Remaining query parameters, e.g. "name~" => ["al"] for name~=al
+
+	IncludeDeleted bool `form:"include_deleted" json:"include_deleted"` // Also list soft-deleted users
+} // @name GetUsersReq
+
+// ListParams are the query parameters of the list users API call that are not filters
+var ListParams = []string{"limit", "cursor", "offset", "q", "sort", "include_deleted"}
+
+// @Description GetUsersRes is the response structure for list users API call.
+type GetUsersRes struct {
+	Users      []GetUserRes `json:"users"`
+	Total      int64        `json:"total" example:"42"`
+	Limit      int          `json:"limit" example:"20"`
+	Offset     int          `json:"offset" example:"0"`
+	NextCursor string       `json:"next_cursor,omitempty" example:"NTExODg2M2UtYTI0MC00NGI5"`
+} // @name GetUsersRes
@@ -0,0 +1,11 @@
+package user
+
+import "encoding/json"
+
+// PatchUserReq is the request structure for patch user API call.
+// Patch is a merge patch or a JSON Patch of the GetUserRes document of the user.
+type PatchUserReq struct {
+	ContentType string          // patch.MergePatchContentType or patch.JSONPatchContentType
+	Patch       json.RawMessage // The request body
+	IfMatch     []int64         // Versions the client expects, from If-Match; empty matches any
+}
@@ -1,17 +1,28 @@
 package user
 
+import "time"
+
 // @Description UpdateUserReq is the request structure for update user API call.
 type UpdateUserReq struct {
-	ID          string  `json:"id,omitempty" example:"tcuZwYseZKNUp8D3tjMkyiZrYGC3"` // ID of the user (populated later)
-	Name        *string `json:"name,omitempty"`                                      // Optional updated name
-	Email       *string `json:"email,omitempty" example:"user@example.com"`          // Optional updated email
-	PhoneNumber *int64  `json:"phone_number,omitempty"`                              // Optional updated phone number
+	ID          string  `json:"id,omitempty" example:"tcuZwYseZKNUp8D3tjMkyiZrYGC3"`                          // ID of the user (populated later)
+	Name        *string `json:"name,omitempty" binding:"omitempty,min=1,max=100"`                             // Optional updated name
+	Email       *string `json:"email,omitempty" example:"user@example.com" binding:"omitempty,email,max=254"` // Optional updated email
+	PhoneNumber *string `json:"phone_number,omitempty" example:"+919876543210" binding:"omitempty,min=1"`     // Optional updated phone number, E.164
+	IfMatch     []int64 `json:"-"`                                                                            // Versions the client expects, from If-Match; empty matches any
 } // @name UpdateUserReq
 
 // @Description UpdateUserRes is the response structure for update user API call.
 type UpdateUserRes struct {
-	ID          string `json:"id" example:"tcuZwYseZKNUp8D3tjMkyiZrYGC3"`
-	Name        string `json:"name"`
-	Email       string `json:"email" example:"user@example.com"`
-	PhoneNumber int64  `json:"phone_number"`
+	ID            string    `json:"id" example:"tcuZwYseZKNUp8D3tjMkyiZrYGC3"`
+	Name          string    `json:"name"`
+	Email         string    `json:"email" example:"user@example.com"`
+	PhoneNumber   string    `json:"phone_number" example:"+919876543210"`
+	EmailVerified bool      `json:"email_verified"`                                   // Whether the user followed the link sent to their email
+	Status        string    `json:"status" example:"active"`                          // pending, active, suspended or deactivated
+	StatusReason  string    `json:"status_reason,omitempty" example:"email verified"` // Why the user got their status
+	Version       int64     `json:"version" example:"1"`                              // Also sent as the ETag header
+	CreatedAt     time.Time `json:"created_at" example:"2024-05-01T10:00:00Z"`
+	UpdatedAt     time.Time `json:"updated_at" example:"2024-05-02T08:30:00Z"`
+	CreatedBy     string    `json:"created_by" example:"anonymous"`
+	UpdatedBy     string    `json:"updated_by" example:"anonymous"`
 } // @name UpdateUserRes
@@ -0,0 +1,36 @@
+package user
+
+import "time"
+
+// @Description GetUserHistoryReq is the request structure for user history API call.
+type GetUserHistoryReq struct {
+	Limit  int    `form:"limit" json:"limit" example:"20" binding:"min=0,max=100"` // Page size, 1-100 (default 20)
+	Cursor string `form:"cursor" json:"cursor"`                                    // Opaque cursor from a previous response's next_cursor
+	Offset int    `form:"offset" json:"offset" example:"0" binding:"min=0"`        // Number of entries to skip; cannot be combined with cursor
+} // @name GetUserHistoryReq
+
+// @Description AuditEntryRes is one recorded change of a user.
+type AuditEntryRes struct {
+	ID        string           `json:"id" example:"6650c0ffee0000000000beef"`
+	Action    string           `json:"action" example:"update"` // create, update, delete, restore, suspend, activate or deactivate
+	Actor     string           `json:"actor" example:"anonymous"`
+	RequestID string           `json:"request_id,omitempty" example:"0b6f9a8e-8c1d-4f5e-9d3a-2f1e0c7b6a59"`
+	Timestamp time.Time        `json:"timestamp" example:"2024-05-02T08:30:00Z"`
+	Changes   []FieldChangeRes `json:"changes"`
+} // @name AuditEntryRes
+
+// @Description FieldChangeRes is the value of a field before and after a change.
+type FieldChangeRes struct {
+	Field  string `json:"field" example:"name"`
+	Before any    `json:"before"` // null when the field was not set
+	After  any    `json:"after"`  // null when the field was cleared
+} // @name FieldChangeRes
+
+// @Description GetUserHistoryRes is the response structure for user history API call.
+type GetUserHistoryRes struct {
+	Entries    []AuditEntryRes `json:"entries"` // Newest first
+	Total      int64           `json:"total" example:"3"`
+	Limit      int             `json:"limit" example:"20"`
+	Offset     int             `json:"offset" example:"0"`
+	NextCursor string          `json:"next_cursor,omitempty" example:"NjY1MGMwZmZlZTAwMDAwMDAwMDBiZWVm"`
+} // @name GetUserHistoryRes
@@ -0,0 +1,6 @@
+package user
+
+// @Description VerifyEmailReq is the request structure for verify email API call.
+type VerifyEmailReq struct {
+	Token string `json:"token" example:"eyJwIjoidmVyaWZ5X2VtYWlsIi4uLn0.Qm9n..." binding:"required"` // Token of the link emailed to the user
+} // @name VerifyEmailReq
@@ -0,0 +1,15 @@
+package webhook
+
+// @Description CreateWebhookReq is the request structure for create webhook API call.
+type CreateWebhookReq struct {
+	URL    string   `json:"url" example:"https://partner.example.com/hooks/users" binding:"required,url"`
+	Secret string   `json:"secret" example:"2f1e0c7b6a59d3a84c1d4f5e9b6f9a8e" binding:"omitempty,min=16"` // Signing secret of at least 16 characters, generated when empty
+	Events []string `json:"events" example:"user.created,user.deleted"`                                   // Event types to receive, every type when empty
+} // @name CreateWebhookReq
+
+// @Description CreateWebhookRes is the response structure for create webhook API call.
+// It is the only response that carries the signing secret.
+type CreateWebhookRes struct {
+	WebhookRes
+	Secret string `json:"secret" example:"2f1e0c7b6a59d3a84c1d4f5e9b6f9a8e"`
+} // @name CreateWebhookRes
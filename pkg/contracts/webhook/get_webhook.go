@@ -0,0 +1,29 @@
+package webhook
+
+import "time"
+
+// @Description WebhookRes is a webhook subscription. The signing secret is never returned.
+type WebhookRes struct {
+	ID        string    `json:"id" example:"5118863e-a240-44b9-9d3a-2f1e0c7b6a59"`
+	URL       string    `json:"url" example:"https://partner.example.com/hooks/users"`
+	Events    []string  `json:"events" example:"user.created,user.deleted"` // Every event type when empty
+	Active    bool      `json:"active" example:"true"`
+	CreatedAt time.Time `json:"created_at" example:"2024-05-01T10:00:00Z"`
+	UpdatedAt time.Time `json:"updated_at" example:"2024-05-02T08:30:00Z"`
+} // @name WebhookRes
+
+// @Description GetWebhooksReq is the request structure for list webhooks API call.
+type GetWebhooksReq struct {
+	Limit  int    `form:"limit" json:"limit" example:"20" binding:"min=0,max=100"` // Page size, 1-100 (default 20)
+	Cursor string `form:"cursor" json:"cursor"`                                    // Opaque cursor from a previous response's next_cursor
+	Offset int    `form:"offset" json:"offset" example:"0" binding:"min=0"`        // Number of webhooks to skip; cannot be combined with cursor
+} // @name GetWebhooksReq
+
+// @Description GetWebhooksRes is the response structure for list webhooks API call.
+type GetWebhooksRes struct {
+	Webhooks   []WebhookRes `json:"webhooks"`
+	Total      int64        `json:"total" example:"2"`
+	Limit      int          `json:"limit" example:"20"`
+	Offset     int          `json:"offset" example:"0"`
+	NextCursor string       `json:"next_cursor,omitempty" example:"NTExODg2M2UtYTI0MC00NGI5"`
+} // @name GetWebhooksRes
@@ -0,0 +1,10 @@
+package webhook
+
+// @Description UpdateWebhookReq is the request structure for update webhook API call.
+// Omitted fields are left unchanged.
+type UpdateWebhookReq struct {
+	URL    *string  `json:"url,omitempty" example:"https://partner.example.com/hooks/users" binding:"omitempty,url"`
+	Secret *string  `json:"secret,omitempty" example:"2f1e0c7b6a59d3a84c1d4f5e9b6f9a8e" binding:"omitempty,min=16"`
+	Events []string `json:"events,omitempty" example:"user.created"` // [] subscribes to every event type
+	Active *bool    `json:"active,omitempty" example:"false"`        // Inactive webhooks receive no deliveries
+} // @name UpdateWebhookReq
@@ -0,0 +1,37 @@
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// @Description GetDeliveriesReq is the request structure for webhook delivery log API call.
+type GetDeliveriesReq struct {
+	Limit  int    `form:"limit" json:"limit" example:"20" binding:"min=0,max=100"` // Page size, 1-100 (default 20)
+	Cursor string `form:"cursor" json:"cursor"`                                    // Opaque cursor from a previous response's next_cursor
+	Offset int    `form:"offset" json:"offset" example:"0" binding:"min=0"`        // Number of deliveries to skip; cannot be combined with cursor
+} // @name GetDeliveriesReq
+
+// @Description DeliveryRes is one event sent, or being sent, to a webhook.
+type DeliveryRes struct {
+	ID             string          `json:"id" example:"6650c0ffee0000000000beef"`
+	EventID        string          `json:"event_id" example:"6650c0ffee0000000000cafe"`
+	EventType      string          `json:"event_type" example:"user.created"`
+	Status         string          `json:"status" example:"pending"` // pending, succeeded or dead
+	Attempts       int             `json:"attempts" example:"1"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty" example:"2024-05-02T08:30:10Z"` // Only set on pending deliveries
+	LastAttemptAt  *time.Time      `json:"last_attempt_at,omitempty" example:"2024-05-02T08:30:00Z"`
+	LastStatusCode int             `json:"last_status_code,omitempty" example:"503"` // Omitted when no response arrived
+	LastError      string          `json:"last_error,omitempty" example:"receiver answered 503 Service Unavailable"`
+	Body           json.RawMessage `json:"body" swaggertype:"object"` // The Event that is sent
+	CreatedAt      time.Time       `json:"created_at" example:"2024-05-02T08:30:00Z"`
+} // @name DeliveryRes
+
+// @Description GetDeliveriesRes is the response structure for webhook delivery log API call.
+type GetDeliveriesRes struct {
+	Deliveries []DeliveryRes `json:"deliveries"` // Newest first
+	Total      int64         `json:"total" example:"3"`
+	Limit      int           `json:"limit" example:"20"`
+	Offset     int           `json:"offset" example:"0"`
+	NextCursor string        `json:"next_cursor,omitempty" example:"NjY1MGMwZmZlZTAwMDAwMDAwMDBiZWVm"`
+} // @name GetDeliveriesRes
@@ -0,0 +1,23 @@
+package di
+
+import (
+	gh "github.com/Crud-application/pkg/api/grpcHandlers"
+	h "github.com/Crud-application/pkg/api/handlers"
+	svcInter "github.com/Crud-application/pkg/application/services"
+	uApp "github.com/Crud-application/pkg/application/user"
+	wApp "github.com/Crud-application/pkg/application/webhook"
+	"github.com/Crud-application/pkg/domain/authAgg"
+)
+
+// Application holds everything the server runs: the HTTP handlers, the gRPC
+// services, the authenticator and access policy guarding both and the background workers. They share a single set of repositories.
+type Application struct {
+	Handlers       *h.Handlers
+	UserServer     *gh.UserServer
+	Authenticator  svcInter.IAuthenticator
+	Policy         authAgg.Policy // Also guards the routes that bypass the services
+	UserPurger     *uApp.UserPurger
+	EventRelay     *uApp.EventRelay
+	StreamFeeder   *uApp.StreamFeeder
+	DeliveryWorker *wApp.DeliveryWorker
+}
@@ -4,30 +4,128 @@
 package di
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
 	db "github.com/Crud-application/db"
+	gqlH "github.com/Crud-application/pkg/api/graphqlHandlers"
+	gh "github.com/Crud-application/pkg/api/grpcHandlers"
 	h "github.com/Crud-application/pkg/api/handlers"
+	authApp "github.com/Crud-application/pkg/application/auth"
+	"github.com/Crud-application/pkg/application/authz"
 	svcInter "github.com/Crud-application/pkg/application/services"
 	uApp "github.com/Crud-application/pkg/application/user"
+	wApp "github.com/Crud-application/pkg/application/webhook"
+	"github.com/Crud-application/pkg/config"
+	"github.com/Crud-application/pkg/domain/authAgg"
+	"github.com/Crud-application/pkg/domain/domainEvent"
+	"github.com/Crud-application/pkg/domain/mail"
 	repoInter "github.com/Crud-application/pkg/domain/persistence"
+	"github.com/Crud-application/pkg/domain/webhookAgg"
+	mailInfra "github.com/Crud-application/pkg/infrastructure/mail"
+	"github.com/Crud-application/pkg/infrastructure/messaging"
+	kRepo "github.com/Crud-application/pkg/infrastructure/persistence/apiKey"
+	aRepo "github.com/Crud-application/pkg/infrastructure/persistence/audit"
+	oRepo "github.com/Crud-application/pkg/infrastructure/persistence/outbox"
+	rRepo "github.com/Crud-application/pkg/infrastructure/persistence/refreshToken"
 	uRepo "github.com/Crud-application/pkg/infrastructure/persistence/user"
+	wRepo "github.com/Crud-application/pkg/infrastructure/persistence/webhook"
 	"github.com/google/uuid"
 	"github.com/google/wire"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
-func provideMongoDBclient() *mongo.Client {
-	client, _, _ := db.GetMongoDB() // Use your GetMongoDB method to retrieve the client
-	return client
+var configSet = wire.NewSet(
+	wire.FieldsOf(new(*config.Config), "Server", "Mongo", "Repository", "Users", "Events", "Webhooks", "Auth", "Mail"),
+)
+
+func provideMongoDBclient(cfg config.MongoConfig) (*mongo.Client, error) {
+	client, _, err := db.GetMongoDB(cfg) // Use your GetMongoDB method to retrieve the client
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to MongoDB: %w", err)
+	}
+	return client, nil
 }
 
-func provideUserRepository() *uRepo.MongoUserRepository {
-	wire.Build(uRepo.NewMongoUserRepository, provideMongoDBclient)
-	return nil
+// repositories are the stores of the configured backend, sharing one MongoDB client
+type repositories struct {
+	User    repoInter.IUserRepository
+	Audit   repoInter.IAuditRepository
+	Outbox  repoInter.IOutboxRepository
+	Webhook repoInter.IWebhookRepository
+	APIKey  repoInter.IAPIKeyRepository
+
+	RefreshToken repoInter.IRefreshTokenRepository
+
+	OutboxWatcher repoInter.IOutboxWatcher // Only set when the change stream is fed from MongoDB
+}
+
+// provideRepositories picks the repository backend configured at startup.
+// MongoDB is the default; "memory" needs no network and is meant for dev and CI.
+// The MongoDB indexes are ensured before the repositories are handed out.
+func provideRepositories(cfg *config.Config) (*repositories, error) {
+	switch cfg.Repository.Backend {
+	case config.RepoBackendMemory:
+		log.Printf("Using in-memory repositories")
+		outbox := oRepo.NewInMemoryOutboxRepository()
+		audit := aRepo.NewInMemoryAuditRepository()
+		return &repositories{
+			User:    uRepo.NewInMemoryUserRepository(outbox, audit),
+			Audit:   audit,
+			Outbox:  outbox,
+			Webhook: wRepo.NewInMemoryWebhookRepository(),
+			APIKey:  kRepo.NewInMemoryAPIKeyRepository(),
+
+			RefreshToken: rRepo.NewInMemoryRefreshTokenRepository(),
+		}, nil
+	default:
+		client, err := provideMongoDBclient(cfg.Mongo)
+		if err != nil {
+			return nil, err
+		}
+		outbox := oRepo.NewMongoOutboxRepository(client, cfg.Mongo)
+		audit := aRepo.NewMongoAuditRepository(client, cfg.Mongo)
+		users := uRepo.NewMongoUserRepository(client, cfg.Mongo, outbox, audit)
+		webhooks := wRepo.NewMongoWebhookRepository(client, cfg.Mongo)
+		apiKeys := kRepo.NewMongoAPIKeyRepository(client, cfg.Mongo)
+		refreshTokens := rRepo.NewMongoRefreshTokenRepository(client, cfg.Mongo)
+
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.Mongo.ConnectTimeout.Std())
+		defer cancel()
+		if err := users.EnsureIndexes(ctx); err != nil {
+			return nil, fmt.Errorf("failed to ensure user indexes: %w", err)
+		}
+		if err := audit.EnsureIndexes(ctx); err != nil {
+			return nil, fmt.Errorf("failed to ensure audit indexes: %w", err)
+		}
+		if err := outbox.EnsureIndexes(ctx); err != nil {
+			return nil, fmt.Errorf("failed to ensure outbox indexes: %w", err)
+		}
+		if err := webhooks.EnsureIndexes(ctx); err != nil {
+			return nil, fmt.Errorf("failed to ensure webhook indexes: %w", err)
+		}
+		if err := apiKeys.EnsureIndexes(ctx); err != nil {
+			return nil, fmt.Errorf("failed to ensure API key indexes: %w", err)
+		}
+		if err := refreshTokens.EnsureIndexes(ctx); err != nil {
+			return nil, fmt.Errorf("failed to ensure refresh token indexes: %w", err)
+		}
+		repos := &repositories{User: users, Audit: audit, Outbox: outbox, Webhook: webhooks, APIKey: apiKeys, RefreshToken: refreshTokens}
+		if cfg.Events.StreamSource == config.EventStreamChangeStream {
+			repos.OutboxWatcher = outbox
+		}
+		return repos, nil
+	}
 }
 
-var userRepoSet = wire.NewSet(
-	provideUserRepository,
-	wire.Bind(new(repoInter.IUserRepository), new(*uRepo.MongoUserRepository)),
+var repoSet = wire.NewSet(
+	provideRepositories,
+	wire.FieldsOf(new(*repositories), "User", "Audit", "Outbox", "Webhook", "APIKey", "RefreshToken"),
 )
 
 func provideUUIDGenerator() uApp.UUIDGenerator {
@@ -36,27 +134,205 @@ func provideUUIDGenerator() uApp.UUIDGenerator {
 	}
 }
 
-func provideUserService() *uApp.UserService {
-	wire.Build(
-		uApp.NewUserService,
-		userRepoSet, // Injects the user repository
-		provideUUIDGenerator,
-	)
-	return nil
-}
-
 var userSvcSet = wire.NewSet(
+	uApp.NewUserService,
 	provideUserService,
-	wire.Bind(new(svcInter.IUserService), new(*uApp.UserService)),
 	provideUUIDGenerator,
+	provideClock,
 )
 
-func provideUserHandler() *h.UserHandler {
-	wire.Build(h.NewUserHandler, userSvcSet)
-	return nil
+// provideClock tells time in UTC with the millisecond precision MongoDB stores
+func provideClock() uApp.Clock {
+	return func() time.Time {
+		return time.Now().UTC().Truncate(time.Millisecond)
+	}
+}
+
+func provideUserPurger(repo repoInter.IUserRepository, now uApp.Clock, cfg config.UsersConfig) *uApp.UserPurger {
+	return uApp.NewUserPurger(repo, now, cfg.DeletedRetention.Std(), cfg.PurgeInterval.Std())
 }
 
-func InjectHandler() *h.Handlers {
-	wire.Build(h.NewHandlers, provideUserHandler)
-	return nil
+// provideEventPublisher publishes domain events to the configured sink, then
+// queues their webhook deliveries and, unless a change stream feeds it, pushes them
+// to the user change stream. A broker adapter such as NATS or Kafka plugs in here.
+func provideEventPublisher(cfg config.EventsConfig, dispatcher *wApp.Dispatcher, bus *messaging.EventBus) domainEvent.EventPublisher {
+	var sink domainEvent.EventPublisher
+	switch cfg.Sink {
+	case config.EventSinkChannel:
+		sink = messaging.NewChannelPublisher(cfg.RelayBatchSize)
+	default:
+		sink = messaging.NewLogPublisher(nil)
+	}
+	if cfg.StreamSource == config.EventStreamChangeStream {
+		return messaging.NewMultiPublisher(sink, dispatcher)
+	}
+	return messaging.NewMultiPublisher(sink, dispatcher, bus)
+}
+
+// provideEventBus creates the in-process bus behind the user change stream.
+// Subscribers may fall as far behind as the bus can replay before they are dropped.
+func provideEventBus(cfg config.EventsConfig) *messaging.EventBus {
+	return messaging.NewEventBus(cfg.StreamBuffer, cfg.StreamBuffer)
+}
+
+// provideStreamFeeder feeds the bus from the outbox change stream when one is configured
+func provideStreamFeeder(repos *repositories, bus *messaging.EventBus) *uApp.StreamFeeder {
+	return uApp.NewStreamFeeder(repos.OutboxWatcher, bus, 5*time.Second)
+}
+
+func provideUserStreamHandler(stream domainEvent.Stream, cfg config.EventsConfig) *h.UserStreamHandler {
+	return h.NewUserStreamHandler(stream, cfg.StreamHeartbeat.Std())
+}
+
+var streamSet = wire.NewSet(
+	provideEventBus,
+	wire.Bind(new(domainEvent.Stream), new(*messaging.EventBus)),
+	provideStreamFeeder,
+	provideUserStreamHandler,
+)
+
+func provideEventRelay(outbox repoInter.IOutboxRepository, publisher domainEvent.EventPublisher, now uApp.Clock, cfg config.EventsConfig) *uApp.EventRelay {
+	return uApp.NewEventRelay(outbox, publisher, now, cfg.RelayInterval.Std(), cfg.RelayBatchSize)
+}
+
+var webhookSvcSet = wire.NewSet(
+	provideTargetGuard,
+	wApp.NewWebhookService,
+	provideWebhookService,
+	wApp.NewDispatcher,
+)
+
+// provideTargetGuard keeps webhook receivers off loopback, private and link-local addresses,
+// except in dev, where receivers usually run locally
+func provideTargetGuard(cfg *config.Config) *wApp.TargetGuard {
+	return wApp.NewTargetGuard(!cfg.IsDev())
+}
+
+func provideDeliveryWorker(repo repoInter.IWebhookRepository, guard *wApp.TargetGuard, now uApp.Clock, cfg config.WebhooksConfig) *wApp.DeliveryWorker {
+	backoff := webhookAgg.Backoff{
+		MaxAttempts: cfg.MaxAttempts,
+		Base:        cfg.BaseBackoff.Std(),
+		Max:         cfg.MaxBackoff.Std(),
+	}
+	return wApp.NewDeliveryWorker(repo, guard.Client(cfg.Timeout.Std()), now, backoff, cfg.Interval.Std(), cfg.BatchSize)
+}
+
+// provideAuthenticator accepts API keys, and bearer tokens when a JWT secret or JWKS file is configured.
+// The users of bearer tokens are checked on every request.
+func provideAuthenticator(cfg config.AuthConfig, keys *authApp.APIKeyService, users repoInter.IUserRepository, now uApp.Clock) (*authApp.Authenticator, error) {
+	if !cfg.JWTEnabled() {
+		return authApp.NewAuthenticator(keys), nil
+	}
+	var rsaKeys map[string]*rsa.PublicKey
+	if cfg.JWKSFile != "" {
+		data, err := os.ReadFile(cfg.JWKSFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWKS file: %w", err)
+		}
+		if rsaKeys, err = authApp.ParseJWKS(data); err != nil {
+			return nil, err
+		}
+	}
+	tokens := authApp.NewJWTVerifier(cfg.JWTIssuer, cfg.JWTAudience, []byte(cfg.JWTSecret), rsaKeys, now)
+	return authApp.NewAuthenticator(authApp.NewActiveUserVerifier(tokens, users), keys), nil
+}
+
+// provideSessionService serves password login. Access tokens are signed with the JWT secret,
+// so without one login and refresh report the service unavailable.
+func provideSessionService(cfg config.AuthConfig, users repoInter.IUserRepository, tokens repoInter.IRefreshTokenRepository,
+	generateUUID uApp.UUIDGenerator, now uApp.Clock) *authApp.SessionService {
+	var issuer *authApp.TokenIssuer
+	if cfg.LoginEnabled() {
+		issuer = authApp.NewTokenIssuer(cfg.JWTIssuer, cfg.JWTAudience, []byte(cfg.JWTSecret), cfg.AccessTokenTTL.Std(), now)
+	}
+	return authApp.NewSessionService(users, tokens, issuer, cfg.RefreshTokenTTL.Std(), generateUUID, now)
+}
+
+// provideMailer picks the configured mailer. The log and file mailers are meant for dev.
+func provideMailer(cfg config.MailConfig, now uApp.Clock) (mail.Mailer, error) {
+	switch cfg.Mailer {
+	case config.MailerSMTP:
+		return mailInfra.NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.From, now)
+	case config.MailerFile:
+		return mailInfra.NewFileMailer(cfg.File, cfg.From, now), nil
+	default:
+		return mailInfra.NewLogMailer(nil), nil
+	}
+}
+
+// provideLinkTokenSigner signs the tokens of emailed links with the configured secret, or a random one
+// in dev, the only env config accepts without a secret
+func provideLinkTokenSigner(cfg config.MailConfig) (*authAgg.LinkTokenSigner, error) {
+	if cfg.TokenSecret != "" {
+		return authAgg.NewLinkTokenSigner([]byte(cfg.TokenSecret)), nil
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate link token secret: %w", err)
+	}
+	log.Printf("No mail token secret configured; emailed links stop working when the server restarts")
+	return authAgg.NewLinkTokenSigner(secret), nil
+}
+
+func provideAccountService(cfg config.MailConfig, users repoInter.IUserRepository, tokens repoInter.IRefreshTokenRepository,
+	mailer mail.Mailer, signer *authAgg.LinkTokenSigner, now uApp.Clock) *authApp.AccountService {
+	return authApp.NewAccountService(users, tokens, mailer, signer, cfg.LinkBaseURL, cfg.VerifyTokenTTL.Std(), cfg.ResetTokenTTL.Std(), now)
+}
+
+// provideUserService enforces the access policy on the user service when callers are authenticated
+func provideUserService(svc *uApp.UserService, policy authAgg.Policy, cfg config.AuthConfig) svcInter.IUserService {
+	if !cfg.Enabled {
+		return svc
+	}
+	return authz.NewUserService(svc, policy)
+}
+
+// provideWebhookService enforces the access policy on the webhook service when callers are authenticated
+func provideWebhookService(svc *wApp.WebhookService, policy authAgg.Policy, cfg config.AuthConfig) svcInter.IWebhookService {
+	if !cfg.Enabled {
+		return svc
+	}
+	return authz.NewWebhookService(svc, policy)
+}
+
+var authSet = wire.NewSet(
+	authApp.NewAPIKeyService,
+	provideAuthenticator,
+	authAgg.DefaultPolicy,
+	wire.Bind(new(svcInter.IAuthenticator), new(*authApp.Authenticator)),
+	provideSessionService,
+	wire.Bind(new(svcInter.ISessionService), new(*authApp.SessionService)),
+)
+
+var accountSet = wire.NewSet(
+	provideMailer,
+	provideLinkTokenSigner,
+	provideAccountService,
+	wire.Bind(new(uApp.EmailVerifier), new(*authApp.AccountService)),
+	wire.Bind(new(svcInter.IAccountService), new(*authApp.AccountService)),
+)
+
+var handlerSet = wire.NewSet(h.NewUserHandler, h.NewWebhookHandler, h.NewAuthHandler, h.NewAccountHandler, gqlH.NewGraphQLHandler, h.NewHandlers)
+
+var grpcSet = wire.NewSet(gh.NewUserServer)
+
+// InjectApplication builds the HTTP handlers, gRPC services, authenticator, access policy and workers around one shared set of repositories
+func InjectApplication(cfg *config.Config) (*Application, error) {
+	wire.Build(
+		configSet,
+		repoSet, // Injects the user, audit, outbox, webhook, API key and refresh token repositories
+		userSvcSet,
+		authSet,
+		accountSet,
+		webhookSvcSet,
+		streamSet,
+		handlerSet,
+		grpcSet,
+		provideUserPurger,
+		provideEventPublisher,
+		provideEventRelay,
+		provideDeliveryWorker,
+		wire.Struct(new(Application), "*"),
+	)
+	return nil, nil
 }
@@ -7,61 +7,366 @@
 package di
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
 	"github.com/Crud-application/db"
+	"github.com/Crud-application/pkg/api/graphqlHandlers"
+	"github.com/Crud-application/pkg/api/grpcHandlers"
 	"github.com/Crud-application/pkg/api/handlers"
+	"github.com/Crud-application/pkg/application/auth"
+	"github.com/Crud-application/pkg/application/authz"
 	"github.com/Crud-application/pkg/application/services"
-	user2 "github.com/Crud-application/pkg/application/user"
+	"github.com/Crud-application/pkg/application/user"
+	"github.com/Crud-application/pkg/application/webhook"
+	"github.com/Crud-application/pkg/config"
+	"github.com/Crud-application/pkg/domain/authAgg"
+	"github.com/Crud-application/pkg/domain/domainEvent"
+	"github.com/Crud-application/pkg/domain/mail"
 	"github.com/Crud-application/pkg/domain/persistence"
-	"github.com/Crud-application/pkg/infrastructure/persistence/user"
+	"github.com/Crud-application/pkg/domain/webhookAgg"
+	mail2 "github.com/Crud-application/pkg/infrastructure/mail"
+	"github.com/Crud-application/pkg/infrastructure/messaging"
+	"github.com/Crud-application/pkg/infrastructure/persistence/apiKey"
+	"github.com/Crud-application/pkg/infrastructure/persistence/audit"
+	"github.com/Crud-application/pkg/infrastructure/persistence/outbox"
+	"github.com/Crud-application/pkg/infrastructure/persistence/refreshToken"
+	user2 "github.com/Crud-application/pkg/infrastructure/persistence/user"
+	webhook2 "github.com/Crud-application/pkg/infrastructure/persistence/webhook"
 	"github.com/google/uuid"
 	"github.com/google/wire"
 	"go.mongodb.org/mongo-driver/mongo"
+	"log"
+	"os"
+	"time"
 )
 
 // Injectors from wire.go:
 
-func provideUserRepository() *user.MongoUserRepository {
-	client := provideMongoDBclient()
-	mongoUserRepository := user.NewMongoUserRepository(client)
-	return mongoUserRepository
-}
-
-func provideUserService() *user2.UserService {
-	mongoUserRepository := provideUserRepository()
+// InjectApplication builds the HTTP handlers, gRPC services, authenticator, access policy and workers around one shared set of repositories
+func InjectApplication(cfg *config.Config) (*Application, error) {
+	diRepositories, err := provideRepositories(cfg)
+	if err != nil {
+		return nil, err
+	}
+	iUserRepository := diRepositories.User
+	iAuditRepository := diRepositories.Audit
+	mailConfig := cfg.Mail
+	iRefreshTokenRepository := diRepositories.RefreshToken
+	clock := provideClock()
+	mailer, err := provideMailer(mailConfig, clock)
+	if err != nil {
+		return nil, err
+	}
+	linkTokenSigner, err := provideLinkTokenSigner(mailConfig)
+	if err != nil {
+		return nil, err
+	}
+	accountService := provideAccountService(mailConfig, iUserRepository, iRefreshTokenRepository, mailer, linkTokenSigner, clock)
 	uuidGenerator := provideUUIDGenerator()
-	userService := user2.NewUserService(mongoUserRepository, uuidGenerator)
-	return userService
+	userService := user.NewUserService(iUserRepository, iAuditRepository, accountService, uuidGenerator, clock)
+	policy := authAgg.DefaultPolicy()
+	authConfig := cfg.Auth
+	iUserService := provideUserService(userService, policy, authConfig)
+	userHandler := handlers.NewUserHandler(iUserService)
+	eventsConfig := cfg.Events
+	eventBus := provideEventBus(eventsConfig)
+	userStreamHandler := provideUserStreamHandler(eventBus, eventsConfig)
+	iWebhookRepository := diRepositories.Webhook
+	targetGuard := provideTargetGuard(cfg)
+	webhookService := webhook.NewWebhookService(iWebhookRepository, targetGuard, uuidGenerator, clock)
+	iWebhookService := provideWebhookService(webhookService, policy, authConfig)
+	webhookHandler := handlers.NewWebhookHandler(iWebhookService)
+	sessionService := provideSessionService(authConfig, iUserRepository, iRefreshTokenRepository, uuidGenerator, clock)
+	authHandler := handlers.NewAuthHandler(sessionService)
+	accountHandler := handlers.NewAccountHandler(accountService)
+	graphQLHandler := graphqlHandlers.NewGraphQLHandler(iUserService)
+	handlersHandlers := handlers.NewHandlers(userHandler, userStreamHandler, webhookHandler, authHandler, accountHandler, graphQLHandler)
+	userServer := grpcHandlers.NewUserServer(iUserService)
+	iapiKeyRepository := diRepositories.APIKey
+	apiKeyService := auth.NewAPIKeyService(iapiKeyRepository, uuidGenerator, clock)
+	authenticator, err := provideAuthenticator(authConfig, apiKeyService, iUserRepository, clock)
+	if err != nil {
+		return nil, err
+	}
+	usersConfig := cfg.Users
+	userPurger := provideUserPurger(iUserRepository, clock, usersConfig)
+	iOutboxRepository := diRepositories.Outbox
+	dispatcher := webhook.NewDispatcher(iWebhookRepository, clock)
+	eventPublisher := provideEventPublisher(eventsConfig, dispatcher, eventBus)
+	eventRelay := provideEventRelay(iOutboxRepository, eventPublisher, clock, eventsConfig)
+	streamFeeder := provideStreamFeeder(diRepositories, eventBus)
+	webhooksConfig := cfg.Webhooks
+	deliveryWorker := provideDeliveryWorker(iWebhookRepository, targetGuard, clock, webhooksConfig)
+	application := &Application{
+		Handlers:       handlersHandlers,
+		UserServer:     userServer,
+		Authenticator:  authenticator,
+		Policy:         policy,
+		UserPurger:     userPurger,
+		EventRelay:     eventRelay,
+		StreamFeeder:   streamFeeder,
+		DeliveryWorker: deliveryWorker,
+	}
+	return application, nil
 }
 
-func provideUserHandler() *handlers.UserHandler {
-	userService := provideUserService()
-	userHandler := handlers.NewUserHandler(userService)
-	return userHandler
+// wire.go:
+
+var configSet = wire.NewSet(wire.FieldsOf(new(*config.Config), "Server", "Mongo", "Repository", "Users", "Events", "Webhooks", "Auth", "Mail"))
+
+func provideMongoDBclient(cfg config.MongoConfig) (*mongo.Client, error) {
+	client, _, err := db.GetMongoDB(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to MongoDB: %w", err)
+	}
+	return client, nil
 }
 
-func InjectHandler() *handlers.Handlers {
-	userHandler := provideUserHandler()
-	handlersHandlers := handlers.NewHandlers(userHandler)
-	return handlersHandlers
+// repositories are the stores of the configured backend, sharing one MongoDB client
+type repositories struct {
+	User    persistence.IUserRepository
+	Audit   persistence.IAuditRepository
+	Outbox  persistence.IOutboxRepository
+	Webhook persistence.IWebhookRepository
+	APIKey  persistence.IAPIKeyRepository
+
+	RefreshToken persistence.IRefreshTokenRepository
+
+	OutboxWatcher persistence.IOutboxWatcher // Only set when the change stream is fed from MongoDB
 }
 
-// wire.go:
+// provideRepositories picks the repository backend configured at startup.
+// MongoDB is the default; "memory" needs no network and is meant for dev and CI.
+// The MongoDB indexes are ensured before the repositories are handed out.
+func provideRepositories(cfg *config.Config) (*repositories, error) {
+	switch cfg.Repository.Backend {
+	case config.RepoBackendMemory:
+		log.Printf("Using in-memory repositories")
+		outbox2 := outbox.NewInMemoryOutboxRepository()
+		audit2 := audit.NewInMemoryAuditRepository()
+		return &repositories{
+			User:    user2.NewInMemoryUserRepository(outbox2, audit2),
+			Audit:   audit2,
+			Outbox:  outbox2,
+			Webhook: webhook2.NewInMemoryWebhookRepository(),
+			APIKey:  apiKey.NewInMemoryAPIKeyRepository(),
 
-func provideMongoDBclient() *mongo.Client {
-	client, _, _ := db.GetMongoDB()
-	return client
+			RefreshToken: refreshToken.NewInMemoryRefreshTokenRepository(),
+		}, nil
+	default:
+		client, err := provideMongoDBclient(cfg.Mongo)
+		if err != nil {
+			return nil, err
+		}
+		outbox3 := outbox.NewMongoOutboxRepository(client, cfg.Mongo)
+		audit3 := audit.NewMongoAuditRepository(client, cfg.Mongo)
+		users := user2.NewMongoUserRepository(client, cfg.Mongo, outbox3, audit3)
+		webhooks := webhook2.NewMongoWebhookRepository(client, cfg.Mongo)
+		apiKeys := apiKey.NewMongoAPIKeyRepository(client, cfg.Mongo)
+		refreshTokens := refreshToken.NewMongoRefreshTokenRepository(client, cfg.Mongo)
+
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.Mongo.ConnectTimeout.Std())
+		defer cancel()
+		if err := users.EnsureIndexes(ctx); err != nil {
+			return nil, fmt.Errorf("failed to ensure user indexes: %w", err)
+		}
+		if err := audit3.EnsureIndexes(ctx); err != nil {
+			return nil, fmt.Errorf("failed to ensure audit indexes: %w", err)
+		}
+		if err := outbox3.EnsureIndexes(ctx); err != nil {
+			return nil, fmt.Errorf("failed to ensure outbox indexes: %w", err)
+		}
+		if err := webhooks.EnsureIndexes(ctx); err != nil {
+			return nil, fmt.Errorf("failed to ensure webhook indexes: %w", err)
+		}
+		if err := apiKeys.EnsureIndexes(ctx); err != nil {
+			return nil, fmt.Errorf("failed to ensure API key indexes: %w", err)
+		}
+		if err := refreshTokens.EnsureIndexes(ctx); err != nil {
+			return nil, fmt.Errorf("failed to ensure refresh token indexes: %w", err)
+		}
+		repos := &repositories{User: users, Audit: audit3, Outbox: outbox3, Webhook: webhooks, APIKey: apiKeys, RefreshToken: refreshTokens}
+		if cfg.Events.StreamSource == config.EventStreamChangeStream {
+			repos.OutboxWatcher = outbox3
+		}
+		return repos, nil
+	}
 }
 
-var userRepoSet = wire.NewSet(
-	provideUserRepository, wire.Bind(new(persistence.IUserRepository), new(*user.MongoUserRepository)),
+var repoSet = wire.NewSet(
+	provideRepositories, wire.FieldsOf(new(*repositories), "User", "Audit", "Outbox", "Webhook", "APIKey", "RefreshToken"),
 )
 
-func provideUUIDGenerator() user2.UUIDGenerator {
+func provideUUIDGenerator() user.UUIDGenerator {
 	return func() string {
 		return uuid.New().String()
 	}
 }
 
-var userSvcSet = wire.NewSet(
-	provideUserService, wire.Bind(new(services.IUserService), new(*user2.UserService)), provideUUIDGenerator,
+var userSvcSet = wire.NewSet(user.NewUserService, provideUserService,
+	provideUUIDGenerator,
+	provideClock,
 )
+
+// provideClock tells time in UTC with the millisecond precision MongoDB stores
+func provideClock() user.Clock {
+	return func() time.Time {
+		return time.Now().UTC().Truncate(time.Millisecond)
+	}
+}
+
+func provideUserPurger(repo persistence.IUserRepository, now user.Clock, cfg config.UsersConfig) *user.UserPurger {
+	return user.NewUserPurger(repo, now, cfg.DeletedRetention.Std(), cfg.PurgeInterval.Std())
+}
+
+// provideEventPublisher publishes domain events to the configured sink, then
+// queues their webhook deliveries and, unless a change stream feeds it, pushes them
+// to the user change stream. A broker adapter such as NATS or Kafka plugs in here.
+func provideEventPublisher(cfg config.EventsConfig, dispatcher *webhook.Dispatcher, bus *messaging.EventBus) domainEvent.EventPublisher {
+	var sink domainEvent.EventPublisher
+	switch cfg.Sink {
+	case config.EventSinkChannel:
+		sink = messaging.NewChannelPublisher(cfg.RelayBatchSize)
+	default:
+		sink = messaging.NewLogPublisher(nil)
+	}
+	if cfg.StreamSource == config.EventStreamChangeStream {
+		return messaging.NewMultiPublisher(sink, dispatcher)
+	}
+	return messaging.NewMultiPublisher(sink, dispatcher, bus)
+}
+
+// provideEventBus creates the in-process bus behind the user change stream.
+// Subscribers may fall as far behind as the bus can replay before they are dropped.
+func provideEventBus(cfg config.EventsConfig) *messaging.EventBus {
+	return messaging.NewEventBus(cfg.StreamBuffer, cfg.StreamBuffer)
+}
+
+// provideStreamFeeder feeds the bus from the outbox change stream when one is configured
+func provideStreamFeeder(repos *repositories, bus *messaging.EventBus) *user.StreamFeeder {
+	return user.NewStreamFeeder(repos.OutboxWatcher, bus, 5*time.Second)
+}
+
+func provideUserStreamHandler(stream domainEvent.Stream, cfg config.EventsConfig) *handlers.UserStreamHandler {
+	return handlers.NewUserStreamHandler(stream, cfg.StreamHeartbeat.Std())
+}
+
+var streamSet = wire.NewSet(
+	provideEventBus, wire.Bind(new(domainEvent.Stream), new(*messaging.EventBus)), provideStreamFeeder,
+	provideUserStreamHandler,
+)
+
+func provideEventRelay(outbox2 persistence.IOutboxRepository, publisher domainEvent.EventPublisher, now user.Clock, cfg config.EventsConfig) *user.EventRelay {
+	return user.NewEventRelay(outbox2, publisher, now, cfg.RelayInterval.Std(), cfg.RelayBatchSize)
+}
+
+var webhookSvcSet = wire.NewSet(
+	provideTargetGuard, webhook.NewWebhookService, provideWebhookService, webhook.NewDispatcher,
+)
+
+// provideTargetGuard keeps webhook receivers off loopback, private and link-local addresses,
+// except in dev, where receivers usually run locally
+func provideTargetGuard(cfg *config.Config) *webhook.TargetGuard {
+	return webhook.NewTargetGuard(!cfg.IsDev())
+}
+
+func provideDeliveryWorker(repo persistence.IWebhookRepository, guard *webhook.TargetGuard, now user.Clock, cfg config.WebhooksConfig) *webhook.DeliveryWorker {
+	backoff := webhookAgg.Backoff{
+		MaxAttempts: cfg.MaxAttempts,
+		Base:        cfg.BaseBackoff.Std(),
+		Max:         cfg.MaxBackoff.Std(),
+	}
+	return webhook.NewDeliveryWorker(repo, guard.Client(cfg.Timeout.Std()), now, backoff, cfg.Interval.Std(), cfg.BatchSize)
+}
+
+// provideAuthenticator accepts API keys, and bearer tokens when a JWT secret or JWKS file is configured.
+// The users of bearer tokens are checked on every request.
+func provideAuthenticator(cfg config.AuthConfig, keys *auth.APIKeyService, users persistence.IUserRepository, now user.Clock) (*auth.Authenticator, error) {
+	if !cfg.JWTEnabled() {
+		return auth.NewAuthenticator(keys), nil
+	}
+	var rsaKeys map[string]*rsa.PublicKey
+	if cfg.JWKSFile != "" {
+		data, err := os.ReadFile(cfg.JWKSFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWKS file: %w", err)
+		}
+		if rsaKeys, err = auth.ParseJWKS(data); err != nil {
+			return nil, err
+		}
+	}
+	tokens := auth.NewJWTVerifier(cfg.JWTIssuer, cfg.JWTAudience, []byte(cfg.JWTSecret), rsaKeys, now)
+	return auth.NewAuthenticator(auth.NewActiveUserVerifier(tokens, users), keys), nil
+}
+
+// provideSessionService serves password login. Access tokens are signed with the JWT secret,
+// so without one login and refresh report the service unavailable.
+func provideSessionService(cfg config.AuthConfig, users persistence.IUserRepository, tokens persistence.IRefreshTokenRepository,
+	generateUUID user.UUIDGenerator, now user.Clock) *auth.SessionService {
+	var issuer *auth.TokenIssuer
+	if cfg.LoginEnabled() {
+		issuer = auth.NewTokenIssuer(cfg.JWTIssuer, cfg.JWTAudience, []byte(cfg.JWTSecret), cfg.AccessTokenTTL.Std(), now)
+	}
+	return auth.NewSessionService(users, tokens, issuer, cfg.RefreshTokenTTL.Std(), generateUUID, now)
+}
+
+// provideMailer picks the configured mailer. The log and file mailers are meant for dev.
+func provideMailer(cfg config.MailConfig, now user.Clock) (mail.Mailer, error) {
+	switch cfg.Mailer {
+	case config.MailerSMTP:
+		return mail2.NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.From, now)
+	case config.MailerFile:
+		return mail2.NewFileMailer(cfg.File, cfg.From, now), nil
+	default:
+		return mail2.NewLogMailer(nil), nil
+	}
+}
+
+// provideLinkTokenSigner signs the tokens of emailed links with the configured secret, or a random one
+// in dev, the only env config accepts without a secret
+func provideLinkTokenSigner(cfg config.MailConfig) (*authAgg.LinkTokenSigner, error) {
+	if cfg.TokenSecret != "" {
+		return authAgg.NewLinkTokenSigner([]byte(cfg.TokenSecret)), nil
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate link token secret: %w", err)
+	}
+	log.Printf("No mail token secret configured; emailed links stop working when the server restarts")
+	return authAgg.NewLinkTokenSigner(secret), nil
+}
+
+func provideAccountService(cfg config.MailConfig, users persistence.IUserRepository, tokens persistence.IRefreshTokenRepository,
+	mailer mail.Mailer, signer *authAgg.LinkTokenSigner, now user.Clock) *auth.AccountService {
+	return auth.NewAccountService(users, tokens, mailer, signer, cfg.LinkBaseURL, cfg.VerifyTokenTTL.Std(), cfg.ResetTokenTTL.Std(), now)
+}
+
+// provideUserService enforces the access policy on the user service when callers are authenticated
+func provideUserService(svc *user.UserService, policy authAgg.Policy, cfg config.AuthConfig) services.IUserService {
+	if !cfg.Enabled {
+		return svc
+	}
+	return authz.NewUserService(svc, policy)
+}
+
+// provideWebhookService enforces the access policy on the webhook service when callers are authenticated
+func provideWebhookService(svc *webhook.WebhookService, policy authAgg.Policy, cfg config.AuthConfig) services.IWebhookService {
+	if !cfg.Enabled {
+		return svc
+	}
+	return authz.NewWebhookService(svc, policy)
+}
+
+var authSet = wire.NewSet(auth.NewAPIKeyService, provideAuthenticator, authAgg.DefaultPolicy, wire.Bind(new(services.IAuthenticator), new(*auth.Authenticator)), provideSessionService, wire.Bind(new(services.ISessionService), new(*auth.SessionService)))
+
+var accountSet = wire.NewSet(
+	provideMailer,
+	provideLinkTokenSigner,
+	provideAccountService, wire.Bind(new(user.EmailVerifier), new(*auth.AccountService)), wire.Bind(new(services.IAccountService), new(*auth.AccountService)),
+)
+
+var handlerSet = wire.NewSet(handlers.NewUserHandler, handlers.NewWebhookHandler, handlers.NewAuthHandler, handlers.NewAccountHandler, graphqlHandlers.NewGraphQLHandler, handlers.NewHandlers)
+
+var grpcSet = wire.NewSet(grpcHandlers.NewUserServer)
@@ -0,0 +1,69 @@
+package auditAgg
+
+import (
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/Crud-application/pkg/domain/query"
+)
+
+// Action is the kind of change an audit entry records
+type Action string
+
+const (
+	ActionCreate  Action = "create"
+	ActionUpdate  Action = "update"
+	ActionDelete  Action = "delete"
+	ActionRestore Action = "restore"
+
+	ActionSuspend    Action = "suspend"
+	ActionActivate   Action = "activate"
+	ActionDeactivate Action = "deactivate"
+)
+
+// FieldChange is the value of one field before and after a change.
+// Before is nil for created fields and After is nil for cleared ones.
+type FieldChange struct {
+	Field  string
+	Before any
+	After  any
+}
+
+// Entry records who changed a resource, how and when. Entries are never modified.
+type Entry struct {
+	ID         string // Assigned by the repository, in the order entries are added
+	ResourceID string
+	Action     Action
+	Actor      string
+	RequestID  string
+	Timestamp  time.Time
+	Changes    []FieldChange
+}
+
+// EntryPage is one page of audit entries, newest first
+type EntryPage struct {
+	Entries []Entry
+	Page    query.PageInfo
+}
+
+// Diff lists the fields whose values differ between two snapshots, ordered by field name.
+// A nil snapshot stands for a resource that does not exist.
+func Diff(before, after map[string]any) []FieldChange {
+	fields := make(map[string]struct{}, len(before)+len(after))
+	for f := range before {
+		fields[f] = struct{}{}
+	}
+	for f := range after {
+		fields[f] = struct{}{}
+	}
+
+	var changes []FieldChange
+	for f := range fields {
+		if !reflect.DeepEqual(before[f], after[f]) {
+			changes = append(changes, FieldChange{Field: f, Before: before[f], After: after[f]})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes
+}
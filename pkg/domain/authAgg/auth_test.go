@@ -0,0 +1,141 @@
+package authAgg
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Crud-application/pkg/domain/domainErr"
+)
+
+func TestNewAPIKey(t *testing.T) {
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	k, key, err := NewAPIKey("k1", " ci ", []string{"admin"}, now)
+	if err != nil {
+		t.Fatalf("NewAPIKey() error = %v", err)
+	}
+	if !strings.HasPrefix(key, KeyPrefix) || len(key) < len(KeyPrefix)+40 {
+		t.Errorf("NewAPIKey() key = %q, want a long key starting with %s", key, KeyPrefix)
+	}
+	if k.Hash != HashKey(key) || strings.Contains(k.Hash, key) {
+		t.Errorf("NewAPIKey() hash = %q, want the hash of the key only", k.Hash)
+	}
+	if k.Name != "ci" || !k.Active() || !k.CreatedAt.Equal(now) {
+		t.Errorf("NewAPIKey() = %+v, want an active key named ci", k)
+	}
+	if p := k.Principal(); p.Actor() != "api_key:k1" || !p.HasRole("admin") || p.HasRole("viewer") {
+		t.Errorf("Principal() = %+v, want the admin API key k1", p)
+	}
+
+	if _, other, _ := NewAPIKey("k2", "ci", nil, now); other == key {
+		t.Errorf("NewAPIKey() returned the same key twice")
+	}
+	if _, _, err := NewAPIKey("k3", "  ", nil, now); !errors.Is(err, domainErr.ErrValidation) {
+		t.Errorf("NewAPIKey() without a name error = %v, want validation", err)
+	}
+}
+
+func TestPolicy_Authorize(t *testing.T) {
+	admin := &Principal{ID: "a1", Kind: KindUser, Roles: []string{RoleAdmin}}
+	support := &Principal{ID: "k1", Kind: KindAPIKey, Roles: []string{RoleSupport}}
+	alice := &Principal{ID: "u1", Kind: KindUser}
+	key := &Principal{ID: "u1", Kind: KindAPIKey} // Not user u1, whatever its ID
+
+	tests := []struct {
+		id        int
+		name      string
+		principal *Principal
+		action    Action
+		userID    string
+		wantErr   error
+	}{
+		{id: 1, name: "Admin deletes a user - allowed", principal: admin, action: ActionDeleteUser, userID: "u1"},
+		{id: 2, name: "Support reads any user - allowed", principal: support, action: ActionReadUser, userID: "u1"},
+		{id: 3, name: "Support lists users - allowed", principal: support, action: ActionListUsers},
+		{id: 4, name: "Support updates a user - forbidden", principal: support, action: ActionUpdateUser, userID: "u1", wantErr: domainErr.ErrForbidden},
+		{id: 5, name: "User reads their own record - allowed", principal: alice, action: ActionReadUser, userID: "u1"},
+		{id: 6, name: "User updates their own record - allowed", principal: alice, action: ActionUpdateUser, userID: "u1"},
+		{id: 7, name: "User reads another record - forbidden", principal: alice, action: ActionReadUser, userID: "u2", wantErr: domainErr.ErrForbidden},
+		{id: 8, name: "User deletes their own record - forbidden", principal: alice, action: ActionDeleteUser, userID: "u1", wantErr: domainErr.ErrForbidden},
+		{id: 9, name: "User lists users - forbidden", principal: alice, action: ActionListUsers, wantErr: domainErr.ErrForbidden},
+		{id: 10, name: "API key with a user's ID is not that user - forbidden", principal: key, action: ActionReadUser, userID: "u1", wantErr: domainErr.ErrForbidden},
+		{id: 11, name: "Action without a rule - forbidden", principal: admin, action: "users:export", wantErr: domainErr.ErrForbidden},
+		{id: 12, name: "No principal - unauthenticated", action: ActionReadUser, userID: "u1", wantErr: domainErr.ErrUnauthenticated},
+		{id: 13, name: "Admin lists deleted users - allowed", principal: admin, action: ActionListDeleted},
+		{id: 14, name: "Support lists deleted users - forbidden", principal: support, action: ActionListDeleted, wantErr: domainErr.ErrForbidden},
+	}
+
+	policy := DefaultPolicy()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Authorize(tt.principal, tt.action, tt.userID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ID %v Authorize() error = %v, wantErr %v", tt.id, err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := policy.Authorize(alice, ActionDeleteUser, "u1"); err == nil || err.Error() != "only admins may delete users" {
+		t.Errorf("Authorize() error = %v, want the reason of the rule", err)
+	}
+}
+
+func TestNewRefreshToken(t *testing.T) {
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	first, token, err := NewRefreshToken("r1", "u1", "", now, time.Hour)
+	if err != nil {
+		t.Fatalf("NewRefreshToken() error = %v", err)
+	}
+	if !strings.HasPrefix(token, RefreshTokenPrefix) || first.Hash != HashKey(token) {
+		t.Errorf("NewRefreshToken() token = %q, hash %q, want a token starting with %s and its hash", token, first.Hash, RefreshTokenPrefix)
+	}
+	if first.FamilyID != "r1" || first.Revoked() || first.Expired(now.Add(59*time.Minute)) || !first.Expired(now.Add(time.Hour)) {
+		t.Errorf("NewRefreshToken() = %+v, want an active token starting family r1, expiring in an hour", first)
+	}
+
+	next, _, _ := NewRefreshToken("r2", "u1", first.FamilyID, now, time.Hour)
+	if next.FamilyID != "r1" {
+		t.Errorf("NewRefreshToken() of a family = %+v, want family r1", next)
+	}
+}
+
+func TestLinkTokenSigner(t *testing.T) {
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	signer := NewLinkTokenSigner([]byte("0123456789abcdef0123456789abcdef"))
+	token := signer.Sign(PurposeVerifyEmail, "u1", "alam@example.com", now.Add(time.Hour))
+
+	tests := []struct {
+		id      int
+		name    string
+		token   string
+		purpose Purpose
+		at      time.Time
+		wantErr error
+	}{
+		{id: 1, name: "Valid token - success", token: token, purpose: PurposeVerifyEmail, at: now},
+		{id: 2, name: "Other purpose - invalid", token: token, purpose: PurposeResetPassword, at: now, wantErr: ErrLinkTokenInvalid},
+		{id: 3, name: "Expired - failure", token: token, purpose: PurposeVerifyEmail, at: now.Add(time.Hour), wantErr: ErrLinkTokenExpired},
+		{id: 4, name: "Tampered signature - invalid", token: token + "x", purpose: PurposeVerifyEmail, at: now, wantErr: ErrLinkTokenInvalid},
+		{id: 5, name: "Signed with another secret - invalid", token: NewLinkTokenSigner([]byte("another secret")).Sign(PurposeVerifyEmail, "u1", "", now.Add(time.Hour)),
+			purpose: PurposeVerifyEmail, at: now, wantErr: ErrLinkTokenInvalid},
+		{id: 6, name: "Garbage - invalid", token: "not-a-token", purpose: PurposeVerifyEmail, at: now, wantErr: ErrLinkTokenInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := signer.Parse(tt.token, tt.purpose, tt.at)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ID %v Parse() error = %v, wantErr %v", tt.id, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.UserID != "u1" || !signer.BoundTo(got, "alam@example.com") || signer.BoundTo(got, "new@example.com") {
+				t.Errorf("ID %v Parse() = %+v, want a token of u1 bound to alam@example.com only", tt.id, got)
+			}
+		})
+	}
+}
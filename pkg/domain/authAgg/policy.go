@@ -0,0 +1,78 @@
+package authAgg
+
+import "github.com/Crud-application/pkg/domain/domainErr"
+
+// Roles granted to principals. Users acting on their own record need no role.
+const (
+	RoleAdmin   = "admin"   // Manages every user and webhook
+	RoleSupport = "support" // Reads every user
+)
+
+// Action is an operation guarded by the access policy
+type Action string
+
+// Actions of the user and webhook services
+const (
+	ActionCreateUser     Action = "users:create"
+	ActionReadUser       Action = "users:read"
+	ActionListUsers      Action = "users:list"
+	ActionListDeleted    Action = "users:list_deleted" // Listing soft-deleted users too
+	ActionUpdateUser     Action = "users:update"
+	ActionDeleteUser     Action = "users:delete"
+	ActionRestoreUser    Action = "users:restore"
+	ActionChangeStatus   Action = "users:status"
+	ActionReadHistory    Action = "users:history"
+	ActionWatchUsers     Action = "users:watch"
+	ActionManageWebhooks Action = "webhooks:manage"
+)
+
+// Rule grants an action to the principals holding one of Roles and, when Self
+// is set, to users acting on their own record
+type Rule struct {
+	Roles  []string
+	Self   bool
+	Reason string // Given to the principals the rule denies
+}
+
+// Policy decides which principals may perform each action.
+// Actions without a rule are denied to everyone.
+type Policy map[Action]Rule
+
+// DefaultPolicy lets admins do everything, support read every user, and users
+// read and change their own record
+func DefaultPolicy() Policy {
+	return Policy{
+		ActionCreateUser:     {Roles: []string{RoleAdmin}, Reason: "only admins may create users"},
+		ActionReadUser:       {Roles: []string{RoleAdmin, RoleSupport}, Self: true, Reason: "users may only read their own record"},
+		ActionListUsers:      {Roles: []string{RoleAdmin, RoleSupport}, Reason: "only admins and support may list users"},
+		ActionListDeleted:    {Roles: []string{RoleAdmin}, Reason: "only admins may list deleted users"},
+		ActionUpdateUser:     {Roles: []string{RoleAdmin}, Self: true, Reason: "users may only change their own record"},
+		ActionDeleteUser:     {Roles: []string{RoleAdmin}, Reason: "only admins may delete users"},
+		ActionRestoreUser:    {Roles: []string{RoleAdmin}, Reason: "only admins may restore users"},
+		ActionChangeStatus:   {Roles: []string{RoleAdmin}, Reason: "only admins may suspend, activate or deactivate users"},
+		ActionReadHistory:    {Roles: []string{RoleAdmin, RoleSupport}, Self: true, Reason: "users may only read the history of their own record"},
+		ActionWatchUsers:     {Roles: []string{RoleAdmin, RoleSupport}, Reason: "only admins and support may watch user changes"},
+		ActionManageWebhooks: {Roles: []string{RoleAdmin}, Reason: "only admins may manage webhooks"},
+	}
+}
+
+// Authorize returns a forbidden error giving the reason unless p may perform
+// action on the user with ID userID, which is empty for actions on no single user
+func (pol Policy) Authorize(p *Principal, action Action, userID string) error {
+	if p == nil {
+		return domainErr.Unauthenticated("authentication required")
+	}
+	rule, ok := pol[action]
+	if !ok {
+		return domainErr.Forbidden("%s is not permitted", action)
+	}
+	for _, role := range rule.Roles {
+		if p.HasRole(role) {
+			return nil
+		}
+	}
+	if rule.Self && p.IsUser(userID) {
+		return nil
+	}
+	return domainErr.Forbidden("%s", rule.Reason)
+}
@@ -0,0 +1,35 @@
+package authAgg
+
+import "slices"
+
+// Kinds of principals
+const (
+	KindUser   = "user"    // The subject of a bearer token
+	KindAPIKey = "api_key" // A static API key
+)
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	ID    string // Token subject or API key ID
+	Kind  string
+	Name  string // Human readable name, e.g. of the API key
+	Roles []string
+}
+
+// Actor names the principal in audit entries, e.g. "42" or "api_key:7c0d..."
+func (p *Principal) Actor() string {
+	if p.Kind == KindAPIKey {
+		return KindAPIKey + ":" + p.ID
+	}
+	return p.ID
+}
+
+// IsUser reports whether the principal is the user with the given ID
+func (p *Principal) IsUser(userID string) bool {
+	return p.Kind == KindUser && userID != "" && p.ID == userID
+}
+
+// HasRole reports whether the principal was granted role
+func (p *Principal) HasRole(role string) bool {
+	return slices.Contains(p.Roles, role)
+}
@@ -0,0 +1,49 @@
+package authAgg
+
+import "time"
+
+// RefreshTokenPrefix starts every refresh token
+const RefreshTokenPrefix = "crudr_"
+
+// RefreshToken lets a user get new access tokens without logging in again. Every
+// use rotates it: the token is revoked and replaced by a new one of the same family.
+// A revoked token used again has leaked, so its whole family is revoked.
+type RefreshToken struct {
+	ID        string
+	UserID    string
+	FamilyID  string // ID of the first token of the login session
+	Hash      string // Hex SHA-256 of the token, see HashKey
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	RevokedAt *time.Time
+}
+
+// NewRefreshToken generates a token of userID valid for ttl and returns it along with
+// its stored form. The token starts a new family unless familyID is given.
+func NewRefreshToken(id, userID, familyID string, now time.Time, ttl time.Duration) (*RefreshToken, string, error) {
+	token, err := newSecret(RefreshTokenPrefix)
+	if err != nil {
+		return nil, "", err
+	}
+	if familyID == "" {
+		familyID = id
+	}
+	return &RefreshToken{
+		ID:        id,
+		UserID:    userID,
+		FamilyID:  familyID,
+		Hash:      HashKey(token),
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}, token, nil
+}
+
+// Revoked reports whether the token was used or its session ended
+func (t *RefreshToken) Revoked() bool {
+	return t.RevokedAt != nil
+}
+
+// Expired reports whether the token is too old to be used at now
+func (t *RefreshToken) Expired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
@@ -0,0 +1,118 @@
+package domainErr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Sentinel kinds of domain errors. Match them with errors.Is.
+var (
+	ErrNotFound           = errors.New("not found")
+	ErrConflict           = errors.New("conflict")
+	ErrValidation         = errors.New("validation failed")
+	ErrPreconditionFailed = errors.New("precondition failed")
+	ErrUnavailable        = errors.New("service unavailable")
+	ErrUnauthenticated    = errors.New("unauthenticated")
+	ErrForbidden          = errors.New("forbidden")
+)
+
+// Kinds of conflicts that clients resolve differently: a stale version by reading the
+// resource again, an invalid state not at all. Both also match ErrConflict.
+var (
+	ErrStaleVersion = fmt.Errorf("stale version: %w", ErrConflict)
+	ErrInvalidState = fmt.Errorf("invalid state: %w", ErrConflict)
+)
+
+// FieldError describes why a single input field is invalid
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error is a domain error of a given kind with a message and an optional cause
+type Error struct {
+	Kind    error
+	Message string
+	Fields  []FieldError
+	Err     error
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Message)
+	for i, f := range e.Fields {
+		if i == 0 {
+			b.WriteString(": ")
+		} else {
+			b.WriteString("; ")
+		}
+		b.WriteString(f.Field + " " + f.Message)
+	}
+	if e.Err != nil {
+		b.WriteString(": " + e.Err.Error())
+	}
+	return b.String()
+}
+
+// Unwrap exposes both the kind and the cause to errors.Is and errors.As
+func (e *Error) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Kind}
+	}
+	return []error{e.Kind, e.Err}
+}
+
+// NotFound reports that the requested resource does not exist
+func NotFound(format string, args ...any) error {
+	return &Error{Kind: ErrNotFound, Message: fmt.Sprintf(format, args...)}
+}
+
+// Conflict reports that the request clashes with the current state of a resource
+func Conflict(format string, args ...any) error {
+	return &Error{Kind: ErrConflict, Message: fmt.Sprintf(format, args...)}
+}
+
+// StaleVersion reports that a resource changed since the version the client read
+func StaleVersion(format string, args ...any) error {
+	return &Error{Kind: ErrStaleVersion, Message: fmt.Sprintf(format, args...)}
+}
+
+// InvalidState reports that a resource is not in a state that allows the operation
+func InvalidState(format string, args ...any) error {
+	return &Error{Kind: ErrInvalidState, Message: fmt.Sprintf(format, args...)}
+}
+
+// Validation reports invalid input, optionally listing the offending fields
+func Validation(message string, fields ...FieldError) error {
+	return &Error{Kind: ErrValidation, Message: message, Fields: fields}
+}
+
+// PreconditionFailed reports that a resource no longer matches the state the client expected
+func PreconditionFailed(format string, args ...any) error {
+	return &Error{Kind: ErrPreconditionFailed, Message: fmt.Sprintf(format, args...)}
+}
+
+// Unavailable reports that a backing service could not be reached
+func Unavailable(err error, format string, args ...any) error {
+	return &Error{Kind: ErrUnavailable, Message: fmt.Sprintf(format, args...), Err: err}
+}
+
+// Unauthenticated reports that the caller presented no valid credentials
+func Unauthenticated(format string, args ...any) error {
+	return &Error{Kind: ErrUnauthenticated, Message: fmt.Sprintf(format, args...)}
+}
+
+// Forbidden reports that the caller may not perform the operation, and why
+func Forbidden(format string, args ...any) error {
+	return &Error{Kind: ErrForbidden, Message: fmt.Sprintf(format, args...)}
+}
+
+// FieldErrors returns the field errors carried by err, if any
+func FieldErrors(err error) []FieldError {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Fields
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+package domainEvent
+
+import "time"
+
+// Type names a kind of domain event, e.g. "user.created"
+type Type string
+
+// Event records something that happened to an aggregate, for other services to react to.
+// Events are raised by aggregates and stored with them, then published by the outbox relay.
+type Event struct {
+	ID          string // Assigned by the outbox, in the order events are stored
+	Type        Type
+	AggregateID string
+	Actor       string
+	OccurredAt  time.Time
+	Payload     map[string]any // The state of the aggregate the event is about
+}
@@ -0,0 +1,10 @@
+package domainEvent
+
+import "context"
+
+// EventPublisher delivers domain events to their subscribers, e.g. a log,
+// an in-process channel or a message broker such as NATS or Kafka.
+// Delivery is at least once: the same event may be published again after a failure.
+type EventPublisher interface {
+	Publish(ctx context.Context, event Event) error
+}
@@ -0,0 +1,17 @@
+package domainEvent
+
+// Stream is a live feed of published events that subscribers can resume
+// after a disconnect, as long as the events they missed are still buffered
+type Stream interface {
+	// Subscribe opens a subscription to the events published from now on, preceded by
+	// the buffered events published after lastEventID. It reports false when lastEventID
+	// is not buffered anymore, so the subscriber may have missed events.
+	Subscribe(lastEventID string) (*Subscription, bool)
+}
+
+// Subscription receives events until it is closed
+type Subscription struct {
+	// Events is closed when the subscription is closed, or dropped for falling behind
+	Events <-chan Event
+	Close  func()
+}
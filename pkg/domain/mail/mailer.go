@@ -0,0 +1,15 @@
+package mail
+
+import "context"
+
+// Message is a plain text email to one recipient
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends emails, e.g. over SMTP or to a log in dev
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
@@ -0,0 +1,20 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"github.com/Crud-application/pkg/domain/authAgg"
+	kPersist "github.com/Crud-application/pkg/infrastructure/persistence/apiKey"
+)
+
+var _ IAPIKeyRepository = (*kPersist.MongoAPIKeyRepository)(nil)
+var _ IAPIKeyRepository = (*kPersist.InMemoryAPIKeyRepository)(nil)
+
+// IAPIKeyRepository stores the hashes of the static API keys
+type IAPIKeyRepository interface {
+	AddAPIKey(ctx context.Context, k *authAgg.APIKey) error
+	// GetAPIKeyByHash returns the key with the given hash, revoked or not
+	GetAPIKeyByHash(ctx context.Context, hash string) (*authAgg.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id string, at time.Time) error
+}
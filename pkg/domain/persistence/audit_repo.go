@@ -0,0 +1,20 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/Crud-application/pkg/domain/auditAgg"
+	"github.com/Crud-application/pkg/domain/query"
+	aPersist "github.com/Crud-application/pkg/infrastructure/persistence/audit"
+)
+
+var _ IAuditRepository = (*aPersist.MongoAuditRepository)(nil)
+var _ IAuditRepository = (*aPersist.InMemoryAuditRepository)(nil)
+
+// IAuditRepository is an append-only store of audit entries
+type IAuditRepository interface {
+	// AddEntry appends entry and sets its ID
+	AddEntry(ctx context.Context, entry *auditAgg.Entry) error
+	// GetEntries returns one page of the entries of a resource, newest first
+	GetEntries(ctx context.Context, resourceID string, page query.PageRequest) (*auditAgg.EntryPage, error)
+}
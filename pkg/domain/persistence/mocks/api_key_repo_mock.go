@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/domain/persistence/api_key_repo.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	authAgg "github.com/Crud-application/pkg/domain/authAgg"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIAPIKeyRepository is a mock of IAPIKeyRepository interface.
+type MockIAPIKeyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIAPIKeyRepositoryMockRecorder
+}
+
+// MockIAPIKeyRepositoryMockRecorder is the mock recorder for MockIAPIKeyRepository.
+type MockIAPIKeyRepositoryMockRecorder struct {
+	mock *MockIAPIKeyRepository
+}
+
+// NewMockIAPIKeyRepository creates a new mock instance.
+func NewMockIAPIKeyRepository(ctrl *gomock.Controller) *MockIAPIKeyRepository {
+	mock := &MockIAPIKeyRepository{ctrl: ctrl}
+	mock.recorder = &MockIAPIKeyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIAPIKeyRepository) EXPECT() *MockIAPIKeyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddAPIKey mocks base method.
+func (m *MockIAPIKeyRepository) AddAPIKey(ctx context.Context, k *authAgg.APIKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAPIKey", ctx, k)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddAPIKey indicates an expected call of AddAPIKey.
+func (mr *MockIAPIKeyRepositoryMockRecorder) AddAPIKey(ctx, k interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAPIKey", reflect.TypeOf((*MockIAPIKeyRepository)(nil).AddAPIKey), ctx, k)
+}
+
+// GetAPIKeyByHash mocks base method.
+func (m *MockIAPIKeyRepository) GetAPIKeyByHash(ctx context.Context, hash string) (*authAgg.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKeyByHash", ctx, hash)
+	ret0, _ := ret[0].(*authAgg.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKeyByHash indicates an expected call of GetAPIKeyByHash.
+func (mr *MockIAPIKeyRepositoryMockRecorder) GetAPIKeyByHash(ctx, hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeyByHash", reflect.TypeOf((*MockIAPIKeyRepository)(nil).GetAPIKeyByHash), ctx, hash)
+}
+
+// RevokeAPIKey mocks base method.
+func (m *MockIAPIKeyRepository) RevokeAPIKey(ctx context.Context, id string, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKey", ctx, id, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey.
+func (mr *MockIAPIKeyRepositoryMockRecorder) RevokeAPIKey(ctx, id, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*MockIAPIKeyRepository)(nil).RevokeAPIKey), ctx, id, at)
+}
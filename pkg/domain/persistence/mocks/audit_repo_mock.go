@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/domain/persistence/audit_repo.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	auditAgg "github.com/Crud-application/pkg/domain/auditAgg"
+	query "github.com/Crud-application/pkg/domain/query"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIAuditRepository is a mock of IAuditRepository interface.
+type MockIAuditRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIAuditRepositoryMockRecorder
+}
+
+// MockIAuditRepositoryMockRecorder is the mock recorder for MockIAuditRepository.
+type MockIAuditRepositoryMockRecorder struct {
+	mock *MockIAuditRepository
+}
+
+// NewMockIAuditRepository creates a new mock instance.
+func NewMockIAuditRepository(ctrl *gomock.Controller) *MockIAuditRepository {
+	mock := &MockIAuditRepository{ctrl: ctrl}
+	mock.recorder = &MockIAuditRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIAuditRepository) EXPECT() *MockIAuditRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddEntry mocks base method.
+func (m *MockIAuditRepository) AddEntry(ctx context.Context, entry *auditAgg.Entry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddEntry", ctx, entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddEntry indicates an expected call of AddEntry.
+func (mr *MockIAuditRepositoryMockRecorder) AddEntry(ctx, entry interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddEntry", reflect.TypeOf((*MockIAuditRepository)(nil).AddEntry), ctx, entry)
+}
+
+// GetEntries mocks base method.
+func (m *MockIAuditRepository) GetEntries(ctx context.Context, resourceID string, page query.PageRequest) (*auditAgg.EntryPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntries", ctx, resourceID, page)
+	ret0, _ := ret[0].(*auditAgg.EntryPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntries indicates an expected call of GetEntries.
+func (mr *MockIAuditRepositoryMockRecorder) GetEntries(ctx, resourceID, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntries", reflect.TypeOf((*MockIAuditRepository)(nil).GetEntries), ctx, resourceID, page)
+}
@@ -0,0 +1,103 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/domain/persistence/outbox_repo.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domainEvent "github.com/Crud-application/pkg/domain/domainEvent"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIOutboxRepository is a mock of IOutboxRepository interface.
+type MockIOutboxRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIOutboxRepositoryMockRecorder
+}
+
+// MockIOutboxRepositoryMockRecorder is the mock recorder for MockIOutboxRepository.
+type MockIOutboxRepositoryMockRecorder struct {
+	mock *MockIOutboxRepository
+}
+
+// NewMockIOutboxRepository creates a new mock instance.
+func NewMockIOutboxRepository(ctrl *gomock.Controller) *MockIOutboxRepository {
+	mock := &MockIOutboxRepository{ctrl: ctrl}
+	mock.recorder = &MockIOutboxRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIOutboxRepository) EXPECT() *MockIOutboxRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetPending mocks base method.
+func (m *MockIOutboxRepository) GetPending(ctx context.Context, limit int) ([]domainEvent.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPending", ctx, limit)
+	ret0, _ := ret[0].([]domainEvent.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPending indicates an expected call of GetPending.
+func (mr *MockIOutboxRepositoryMockRecorder) GetPending(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPending", reflect.TypeOf((*MockIOutboxRepository)(nil).GetPending), ctx, limit)
+}
+
+// MarkPublished mocks base method.
+func (m *MockIOutboxRepository) MarkPublished(ctx context.Context, eventID string, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkPublished", ctx, eventID, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkPublished indicates an expected call of MarkPublished.
+func (mr *MockIOutboxRepositoryMockRecorder) MarkPublished(ctx, eventID, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkPublished", reflect.TypeOf((*MockIOutboxRepository)(nil).MarkPublished), ctx, eventID, at)
+}
+
+// MockIOutboxWatcher is a mock of IOutboxWatcher interface.
+type MockIOutboxWatcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockIOutboxWatcherMockRecorder
+}
+
+// MockIOutboxWatcherMockRecorder is the mock recorder for MockIOutboxWatcher.
+type MockIOutboxWatcherMockRecorder struct {
+	mock *MockIOutboxWatcher
+}
+
+// NewMockIOutboxWatcher creates a new mock instance.
+func NewMockIOutboxWatcher(ctrl *gomock.Controller) *MockIOutboxWatcher {
+	mock := &MockIOutboxWatcher{ctrl: ctrl}
+	mock.recorder = &MockIOutboxWatcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIOutboxWatcher) EXPECT() *MockIOutboxWatcherMockRecorder {
+	return m.recorder
+}
+
+// Watch mocks base method.
+func (m *MockIOutboxWatcher) Watch(ctx context.Context, publisher domainEvent.EventPublisher) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Watch", ctx, publisher)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Watch indicates an expected call of Watch.
+func (mr *MockIOutboxWatcherMockRecorder) Watch(ctx, publisher interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Watch", reflect.TypeOf((*MockIOutboxWatcher)(nil).Watch), ctx, publisher)
+}
@@ -0,0 +1,108 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/domain/persistence/refresh_token_repo.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	authAgg "github.com/Crud-application/pkg/domain/authAgg"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIRefreshTokenRepository is a mock of IRefreshTokenRepository interface.
+type MockIRefreshTokenRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIRefreshTokenRepositoryMockRecorder
+}
+
+// MockIRefreshTokenRepositoryMockRecorder is the mock recorder for MockIRefreshTokenRepository.
+type MockIRefreshTokenRepositoryMockRecorder struct {
+	mock *MockIRefreshTokenRepository
+}
+
+// NewMockIRefreshTokenRepository creates a new mock instance.
+func NewMockIRefreshTokenRepository(ctrl *gomock.Controller) *MockIRefreshTokenRepository {
+	mock := &MockIRefreshTokenRepository{ctrl: ctrl}
+	mock.recorder = &MockIRefreshTokenRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIRefreshTokenRepository) EXPECT() *MockIRefreshTokenRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddRefreshToken mocks base method.
+func (m *MockIRefreshTokenRepository) AddRefreshToken(ctx context.Context, t *authAgg.RefreshToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddRefreshToken", ctx, t)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddRefreshToken indicates an expected call of AddRefreshToken.
+func (mr *MockIRefreshTokenRepositoryMockRecorder) AddRefreshToken(ctx, t interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRefreshToken", reflect.TypeOf((*MockIRefreshTokenRepository)(nil).AddRefreshToken), ctx, t)
+}
+
+// GetRefreshTokenByHash mocks base method.
+func (m *MockIRefreshTokenRepository) GetRefreshTokenByHash(ctx context.Context, hash string) (*authAgg.RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRefreshTokenByHash", ctx, hash)
+	ret0, _ := ret[0].(*authAgg.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRefreshTokenByHash indicates an expected call of GetRefreshTokenByHash.
+func (mr *MockIRefreshTokenRepositoryMockRecorder) GetRefreshTokenByHash(ctx, hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefreshTokenByHash", reflect.TypeOf((*MockIRefreshTokenRepository)(nil).GetRefreshTokenByHash), ctx, hash)
+}
+
+// RevokeRefreshToken mocks base method.
+func (m *MockIRefreshTokenRepository) RevokeRefreshToken(ctx context.Context, id string, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeRefreshToken", ctx, id, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeRefreshToken indicates an expected call of RevokeRefreshToken.
+func (mr *MockIRefreshTokenRepositoryMockRecorder) RevokeRefreshToken(ctx, id, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeRefreshToken", reflect.TypeOf((*MockIRefreshTokenRepository)(nil).RevokeRefreshToken), ctx, id, at)
+}
+
+// RevokeRefreshTokenFamily mocks base method.
+func (m *MockIRefreshTokenRepository) RevokeRefreshTokenFamily(ctx context.Context, familyID string, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeRefreshTokenFamily", ctx, familyID, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeRefreshTokenFamily indicates an expected call of RevokeRefreshTokenFamily.
+func (mr *MockIRefreshTokenRepositoryMockRecorder) RevokeRefreshTokenFamily(ctx, familyID, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeRefreshTokenFamily", reflect.TypeOf((*MockIRefreshTokenRepository)(nil).RevokeRefreshTokenFamily), ctx, familyID, at)
+}
+
+// RevokeUserRefreshTokens mocks base method.
+func (m *MockIRefreshTokenRepository) RevokeUserRefreshTokens(ctx context.Context, userID string, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeUserRefreshTokens", ctx, userID, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeUserRefreshTokens indicates an expected call of RevokeUserRefreshTokens.
+func (mr *MockIRefreshTokenRepositoryMockRecorder) RevokeUserRefreshTokens(ctx, userID, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUserRefreshTokens", reflect.TypeOf((*MockIRefreshTokenRepository)(nil).RevokeUserRefreshTokens), ctx, userID, at)
+}
@@ -7,7 +7,9 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
+	query "github.com/Crud-application/pkg/domain/query"
 	userAgg "github.com/Crud-application/pkg/domain/userAgg"
 	gomock "github.com/golang/mock/gomock"
 )
@@ -50,32 +52,32 @@ func (mr *MockIUserRepositoryMockRecorder) AddUser(ctx, user interface{}) *gomoc
 }
 
 // DeleteUser mocks base method.
-func (m *MockIUserRepository) DeleteUser(ctx context.Context, userID string) error {
+func (m *MockIUserRepository) DeleteUser(ctx context.Context, user *userAgg.User) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteUser", ctx, userID)
+	ret := m.ctrl.Call(m, "DeleteUser", ctx, user)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteUser indicates an expected call of DeleteUser.
-func (mr *MockIUserRepositoryMockRecorder) DeleteUser(ctx, userID interface{}) *gomock.Call {
+func (mr *MockIUserRepositoryMockRecorder) DeleteUser(ctx, user interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockIUserRepository)(nil).DeleteUser), ctx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockIUserRepository)(nil).DeleteUser), ctx, user)
 }
 
 // GetAllUser mocks base method.
-func (m *MockIUserRepository) GetAllUser(ctx context.Context) ([]userAgg.User, error) {
+func (m *MockIUserRepository) GetAllUser(ctx context.Context, spec query.Spec) (*userAgg.UserPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllUser", ctx)
-	ret0, _ := ret[0].([]userAgg.User)
+	ret := m.ctrl.Call(m, "GetAllUser", ctx, spec)
+	ret0, _ := ret[0].(*userAgg.UserPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAllUser indicates an expected call of GetAllUser.
-func (mr *MockIUserRepositoryMockRecorder) GetAllUser(ctx interface{}) *gomock.Call {
+func (mr *MockIUserRepositoryMockRecorder) GetAllUser(ctx, spec interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllUser", reflect.TypeOf((*MockIUserRepository)(nil).GetAllUser), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllUser", reflect.TypeOf((*MockIUserRepository)(nil).GetAllUser), ctx, spec)
 }
 
 // GetUser mocks base method.
@@ -93,6 +95,65 @@ func (mr *MockIUserRepositoryMockRecorder) GetUser(ctx, userID interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockIUserRepository)(nil).GetUser), ctx, userID)
 }
 
+// GetUserByEmail mocks base method.
+func (m *MockIUserRepository) GetUserByEmail(ctx context.Context, email string) (*userAgg.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByEmail", ctx, email)
+	ret0, _ := ret[0].(*userAgg.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByEmail indicates an expected call of GetUserByEmail.
+func (mr *MockIUserRepositoryMockRecorder) GetUserByEmail(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockIUserRepository)(nil).GetUserByEmail), ctx, email)
+}
+
+// GetUserIncludingDeleted mocks base method.
+func (m *MockIUserRepository) GetUserIncludingDeleted(ctx context.Context, userID string) (*userAgg.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserIncludingDeleted", ctx, userID)
+	ret0, _ := ret[0].(*userAgg.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserIncludingDeleted indicates an expected call of GetUserIncludingDeleted.
+func (mr *MockIUserRepositoryMockRecorder) GetUserIncludingDeleted(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserIncludingDeleted", reflect.TypeOf((*MockIUserRepository)(nil).GetUserIncludingDeleted), ctx, userID)
+}
+
+// PurgeDeletedUsers mocks base method.
+func (m *MockIUserRepository) PurgeDeletedUsers(ctx context.Context, deletedBefore, at time.Time, by string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeletedUsers", ctx, deletedBefore, at, by)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeletedUsers indicates an expected call of PurgeDeletedUsers.
+func (mr *MockIUserRepositoryMockRecorder) PurgeDeletedUsers(ctx, deletedBefore, at, by interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeletedUsers", reflect.TypeOf((*MockIUserRepository)(nil).PurgeDeletedUsers), ctx, deletedBefore, at, by)
+}
+
+// RestoreUser mocks base method.
+func (m *MockIUserRepository) RestoreUser(ctx context.Context, user *userAgg.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreUser", ctx, user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreUser indicates an expected call of RestoreUser.
+func (mr *MockIUserRepositoryMockRecorder) RestoreUser(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreUser", reflect.TypeOf((*MockIUserRepository)(nil).RestoreUser), ctx, user)
+}
+
 // UpdateUser mocks base method.
 func (m *MockIUserRepository) UpdateUser(ctx context.Context, user *userAgg.User) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,184 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/domain/persistence/webhook_repo.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domainEvent "github.com/Crud-application/pkg/domain/domainEvent"
+	query "github.com/Crud-application/pkg/domain/query"
+	webhookAgg "github.com/Crud-application/pkg/domain/webhookAgg"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIWebhookRepository is a mock of IWebhookRepository interface.
+type MockIWebhookRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIWebhookRepositoryMockRecorder
+}
+
+// MockIWebhookRepositoryMockRecorder is the mock recorder for MockIWebhookRepository.
+type MockIWebhookRepositoryMockRecorder struct {
+	mock *MockIWebhookRepository
+}
+
+// NewMockIWebhookRepository creates a new mock instance.
+func NewMockIWebhookRepository(ctrl *gomock.Controller) *MockIWebhookRepository {
+	mock := &MockIWebhookRepository{ctrl: ctrl}
+	mock.recorder = &MockIWebhookRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIWebhookRepository) EXPECT() *MockIWebhookRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddDeliveries mocks base method.
+func (m *MockIWebhookRepository) AddDeliveries(ctx context.Context, deliveries []*webhookAgg.Delivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddDeliveries", ctx, deliveries)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddDeliveries indicates an expected call of AddDeliveries.
+func (mr *MockIWebhookRepositoryMockRecorder) AddDeliveries(ctx, deliveries interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDeliveries", reflect.TypeOf((*MockIWebhookRepository)(nil).AddDeliveries), ctx, deliveries)
+}
+
+// AddSubscription mocks base method.
+func (m *MockIWebhookRepository) AddSubscription(ctx context.Context, s *webhookAgg.Subscription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddSubscription", ctx, s)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddSubscription indicates an expected call of AddSubscription.
+func (mr *MockIWebhookRepositoryMockRecorder) AddSubscription(ctx, s interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddSubscription", reflect.TypeOf((*MockIWebhookRepository)(nil).AddSubscription), ctx, s)
+}
+
+// DeleteSubscription mocks base method.
+func (m *MockIWebhookRepository) DeleteSubscription(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSubscription", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSubscription indicates an expected call of DeleteSubscription.
+func (mr *MockIWebhookRepositoryMockRecorder) DeleteSubscription(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubscription", reflect.TypeOf((*MockIWebhookRepository)(nil).DeleteSubscription), ctx, id)
+}
+
+// GetDeliveries mocks base method.
+func (m *MockIWebhookRepository) GetDeliveries(ctx context.Context, subscriptionID string, page query.PageRequest) (*webhookAgg.DeliveryPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeliveries", ctx, subscriptionID, page)
+	ret0, _ := ret[0].(*webhookAgg.DeliveryPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeliveries indicates an expected call of GetDeliveries.
+func (mr *MockIWebhookRepositoryMockRecorder) GetDeliveries(ctx, subscriptionID, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveries", reflect.TypeOf((*MockIWebhookRepository)(nil).GetDeliveries), ctx, subscriptionID, page)
+}
+
+// GetDueDeliveries mocks base method.
+func (m *MockIWebhookRepository) GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]webhookAgg.Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDueDeliveries", ctx, now, limit)
+	ret0, _ := ret[0].([]webhookAgg.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDueDeliveries indicates an expected call of GetDueDeliveries.
+func (mr *MockIWebhookRepositoryMockRecorder) GetDueDeliveries(ctx, now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDueDeliveries", reflect.TypeOf((*MockIWebhookRepository)(nil).GetDueDeliveries), ctx, now, limit)
+}
+
+// GetSubscribers mocks base method.
+func (m *MockIWebhookRepository) GetSubscribers(ctx context.Context, t domainEvent.Type) ([]webhookAgg.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscribers", ctx, t)
+	ret0, _ := ret[0].([]webhookAgg.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubscribers indicates an expected call of GetSubscribers.
+func (mr *MockIWebhookRepositoryMockRecorder) GetSubscribers(ctx, t interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscribers", reflect.TypeOf((*MockIWebhookRepository)(nil).GetSubscribers), ctx, t)
+}
+
+// GetSubscription mocks base method.
+func (m *MockIWebhookRepository) GetSubscription(ctx context.Context, id string) (*webhookAgg.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscription", ctx, id)
+	ret0, _ := ret[0].(*webhookAgg.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubscription indicates an expected call of GetSubscription.
+func (mr *MockIWebhookRepositoryMockRecorder) GetSubscription(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscription", reflect.TypeOf((*MockIWebhookRepository)(nil).GetSubscription), ctx, id)
+}
+
+// GetSubscriptions mocks base method.
+func (m *MockIWebhookRepository) GetSubscriptions(ctx context.Context, page query.PageRequest) (*webhookAgg.SubscriptionPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscriptions", ctx, page)
+	ret0, _ := ret[0].(*webhookAgg.SubscriptionPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubscriptions indicates an expected call of GetSubscriptions.
+func (mr *MockIWebhookRepositoryMockRecorder) GetSubscriptions(ctx, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscriptions", reflect.TypeOf((*MockIWebhookRepository)(nil).GetSubscriptions), ctx, page)
+}
+
+// UpdateDelivery mocks base method.
+func (m *MockIWebhookRepository) UpdateDelivery(ctx context.Context, d *webhookAgg.Delivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDelivery", ctx, d)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDelivery indicates an expected call of UpdateDelivery.
+func (mr *MockIWebhookRepositoryMockRecorder) UpdateDelivery(ctx, d interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDelivery", reflect.TypeOf((*MockIWebhookRepository)(nil).UpdateDelivery), ctx, d)
+}
+
+// UpdateSubscription mocks base method.
+func (m *MockIWebhookRepository) UpdateSubscription(ctx context.Context, s *webhookAgg.Subscription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSubscription", ctx, s)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSubscription indicates an expected call of UpdateSubscription.
+func (mr *MockIWebhookRepositoryMockRecorder) UpdateSubscription(ctx, s interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSubscription", reflect.TypeOf((*MockIWebhookRepository)(nil).UpdateSubscription), ctx, s)
+}
@@ -0,0 +1,29 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"github.com/Crud-application/pkg/domain/domainEvent"
+	oPersist "github.com/Crud-application/pkg/infrastructure/persistence/outbox"
+)
+
+var _ IOutboxRepository = (*oPersist.MongoOutboxRepository)(nil)
+var _ IOutboxRepository = (*oPersist.InMemoryOutboxRepository)(nil)
+
+// IOutboxRepository reads the domain events that repositories store along with their
+// aggregates, so they can be published after the fact
+type IOutboxRepository interface {
+	// GetPending returns up to limit unpublished events, oldest first
+	GetPending(ctx context.Context, limit int) ([]domainEvent.Event, error)
+	// MarkPublished records that the event was published at the given time
+	MarkPublished(ctx context.Context, eventID string, at time.Time) error
+}
+
+var _ IOutboxWatcher = (*oPersist.MongoOutboxRepository)(nil)
+
+// IOutboxWatcher follows the events stored in the outbox as they are added
+type IOutboxWatcher interface {
+	// Watch publishes every event added to the outbox until ctx is done or watching fails
+	Watch(ctx context.Context, publisher domainEvent.EventPublisher) error
+}
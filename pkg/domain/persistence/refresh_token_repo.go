@@ -0,0 +1,26 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"github.com/Crud-application/pkg/domain/authAgg"
+	rPersist "github.com/Crud-application/pkg/infrastructure/persistence/refreshToken"
+)
+
+var _ IRefreshTokenRepository = (*rPersist.MongoRefreshTokenRepository)(nil)
+var _ IRefreshTokenRepository = (*rPersist.InMemoryRefreshTokenRepository)(nil)
+
+// IRefreshTokenRepository stores the hashes of the refresh tokens of login sessions
+type IRefreshTokenRepository interface {
+	AddRefreshToken(ctx context.Context, t *authAgg.RefreshToken) error
+	// GetRefreshTokenByHash returns the token with the given hash, revoked or not
+	GetRefreshTokenByHash(ctx context.Context, hash string) (*authAgg.RefreshToken, error)
+	// RevokeRefreshToken revokes a token that is still active. It fails with a
+	// conflict when the token was revoked already, so a token rotates only once.
+	RevokeRefreshToken(ctx context.Context, id string, at time.Time) error
+	// RevokeRefreshTokenFamily revokes every active token of a login session
+	RevokeRefreshTokenFamily(ctx context.Context, familyID string, at time.Time) error
+	// RevokeUserRefreshTokens revokes every active token of a user, ending all their sessions
+	RevokeUserRefreshTokens(ctx context.Context, userID string, at time.Time) error
+}
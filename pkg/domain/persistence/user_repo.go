@@ -2,18 +2,36 @@ package persistence
 
 import (
 	"context"
+	"time"
 
+	"github.com/Crud-application/pkg/domain/query"
 	useragg "github.com/Crud-application/pkg/domain/userAgg"
 	uPersist "github.com/Crud-application/pkg/infrastructure/persistence/user"
 )
 
 var _ IUserRepository = (*uPersist.MongoUserRepository)(nil)
+var _ IUserRepository = (*uPersist.InMemoryUserRepository)(nil)
 
-// IUserRepository interface
+// IUserRepository interface. The methods taking a user store its pending
+// events in the outbox atomically with the user, then clear them.
 type IUserRepository interface {
 	AddUser(ctx context.Context, user *useragg.User) error
-	DeleteUser(ctx context.Context, userID string) error
+	// DeleteUser soft-deletes a user marked deleted; it is hidden from reads until restored or purged
+	DeleteUser(ctx context.Context, user *useragg.User) error
+	// RestoreUser stores the restoration of a soft-deleted user marked restored,
+	// provided nobody changed it since it was read, and increments user.Version
+	RestoreUser(ctx context.Context, user *useragg.User) error
+	// PurgeDeletedUsers hard-deletes the users soft-deleted before deletedBefore, storing a
+	// purged event for each as happening at the given time by the given actor, and returns their count
+	PurgeDeletedUsers(ctx context.Context, deletedBefore, at time.Time, by string) (int64, error)
+	// GetUser returns a user that is not soft-deleted
 	GetUser(ctx context.Context, userID string) (*useragg.User, error)
-	GetAllUser(ctx context.Context) ([]useragg.User, error)
+	// GetUserIncludingDeleted returns a user whether or not it is soft-deleted, unless it is purged
+	GetUserIncludingDeleted(ctx context.Context, userID string) (*useragg.User, error)
+	// GetUserByEmail returns the user that is not soft-deleted with the given email, ignoring case
+	GetUserByEmail(ctx context.Context, email string) (*useragg.User, error)
+	GetAllUser(ctx context.Context, spec query.Spec) (*useragg.UserPage, error)
+	// UpdateUser stores user only if the stored version still equals user.Version,
+	// failing with a conflict otherwise, and increments user.Version on success
 	UpdateUser(ctx context.Context, user *useragg.User) error
 }
@@ -0,0 +1,37 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"github.com/Crud-application/pkg/domain/domainEvent"
+	"github.com/Crud-application/pkg/domain/query"
+	"github.com/Crud-application/pkg/domain/webhookAgg"
+	wPersist "github.com/Crud-application/pkg/infrastructure/persistence/webhook"
+)
+
+var _ IWebhookRepository = (*wPersist.MongoWebhookRepository)(nil)
+var _ IWebhookRepository = (*wPersist.InMemoryWebhookRepository)(nil)
+
+// IWebhookRepository stores webhook subscriptions and the deliveries made to them
+type IWebhookRepository interface {
+	AddSubscription(ctx context.Context, s *webhookAgg.Subscription) error
+	GetSubscription(ctx context.Context, id string) (*webhookAgg.Subscription, error)
+	// GetSubscriptions returns one page of the subscriptions in ID order
+	GetSubscriptions(ctx context.Context, page query.PageRequest) (*webhookAgg.SubscriptionPage, error)
+	// GetSubscribers returns the active subscriptions that receive events of type t
+	GetSubscribers(ctx context.Context, t domainEvent.Type) ([]webhookAgg.Subscription, error)
+	UpdateSubscription(ctx context.Context, s *webhookAgg.Subscription) error
+	// DeleteSubscription removes a subscription along with its delivery log
+	DeleteSubscription(ctx context.Context, id string) error
+
+	// AddDeliveries stores deliveries and sets their IDs, skipping those of an
+	// event that was already delivered to the same subscription
+	AddDeliveries(ctx context.Context, deliveries []*webhookAgg.Delivery) error
+	// GetDueDeliveries returns up to limit pending deliveries due at or before now, longest due first
+	GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]webhookAgg.Delivery, error)
+	// UpdateDelivery stores the outcome of a delivery attempt
+	UpdateDelivery(ctx context.Context, d *webhookAgg.Delivery) error
+	// GetDeliveries returns one page of the delivery log of a subscription, newest first
+	GetDeliveries(ctx context.Context, subscriptionID string, page query.PageRequest) (*webhookAgg.DeliveryPage, error)
+}
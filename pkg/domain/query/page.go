@@ -0,0 +1,74 @@
+package query
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/Crud-application/pkg/domain/domainErr"
+)
+
+// Page size limits for listings
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// PageRequest selects one page of a listing ordered by ID.
+// Cursor (keyset) and Offset are alternative ways to pick the page start.
+type PageRequest struct {
+	Limit  int
+	Cursor string // decoded ID after which the page starts
+	Offset int
+}
+
+// PageInfo describes the page that was returned
+type PageInfo struct {
+	Total      int64
+	Limit      int
+	Offset     int
+	NextCursor string // decoded ID of the last item, empty on the last page
+}
+
+// NewPageRequest builds a validated page request from client input.
+// A zero limit selects DefaultLimit and the cursor must come from EncodeCursor.
+func NewPageRequest(limit int, cursor string, offset int) (PageRequest, error) {
+	var fields []domainErr.FieldError
+	if limit < 0 || limit > MaxLimit {
+		fields = append(fields, domainErr.FieldError{Field: "limit", Message: fmt.Sprintf("must be between 1 and %d", MaxLimit)})
+	}
+	if offset < 0 {
+		fields = append(fields, domainErr.FieldError{Field: "offset", Message: "must not be negative"})
+	}
+	if cursor != "" && offset > 0 {
+		fields = append(fields, domainErr.FieldError{Field: "offset", Message: "cannot be combined with cursor"})
+	}
+	decoded, err := DecodeCursor(cursor)
+	if err != nil {
+		fields = append(fields, domainErr.FieldError{Field: "cursor", Message: "is invalid"})
+	}
+	if len(fields) > 0 {
+		return PageRequest{}, domainErr.Validation("invalid page request", fields...)
+	}
+
+	if limit == 0 {
+		limit = DefaultLimit
+	}
+	return PageRequest{Limit: limit, Cursor: decoded, Offset: offset}, nil
+}
+
+// EncodeCursor turns the ID of the last item of a page into an opaque cursor
+func EncodeCursor(id string) string {
+	if id == "" {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(id))
+}
+
+// DecodeCursor reverses EncodeCursor
+func DecodeCursor(cursor string) (string, error) {
+	id, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", err
+	}
+	return string(id), nil
+}
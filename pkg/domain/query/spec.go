@@ -0,0 +1,163 @@
+package query
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Crud-application/pkg/domain/domainErr"
+)
+
+// Operator compares a field with a filter value
+type Operator string
+
+const (
+	OpEq       Operator = "eq"
+	OpNe       Operator = "ne"
+	OpContains Operator = "contains" // case-insensitive substring match, strings only
+	OpGte      Operator = "gte"
+	OpLte      Operator = "lte"
+)
+
+// operatorSuffixes maps the character preceding "=" in a query parameter to its operator,
+// e.g. "name~=al" arrives as key "name~" and value "al"
+var operatorSuffixes = map[string]Operator{
+	"~": OpContains,
+	"!": OpNe,
+	">": OpGte,
+	"<": OpLte,
+}
+
+// FieldType is the value type of a queryable field
+type FieldType int
+
+const (
+	String FieldType = iota
+	Int
+	Time  // RFC 3339 timestamp, or a date that stands for its midnight in UTC
+	Exact // String that is only compared whole, with = and !=, as its order means nothing
+)
+
+// Fields lists the queryable fields of a resource by name
+type Fields map[string]FieldType
+
+// Filter restricts a listing to items whose Field compares to Value with Op.
+// Value is a string, an int64 or a time.Time depending on the field type.
+type Filter struct {
+	Field string
+	Op    Operator
+	Value any
+}
+
+// SortField orders a listing by one field
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// Spec is a backend-agnostic description of a listing query.
+// Results are always ordered by ID after the Sort fields so pages are stable.
+type Spec struct {
+	Filters []Filter
+	Search  string // case-insensitive text matched against the searchable fields
+	Sort    []SortField
+	Page    PageRequest
+
+	IncludeDeleted bool // Also list soft-deleted items
+}
+
+// NewSpec validates raw listing parameters against fields and builds a Spec.
+// filters holds query parameters keyed by field name with an optional operator suffix.
+// sortExpr is a comma separated list of fields, each optionally prefixed with "-" for descending order.
+func NewSpec(fields Fields, filters map[string][]string, search, sortExpr string, page PageRequest) (Spec, error) {
+	spec := Spec{Search: strings.TrimSpace(search), Page: page}
+	var errs []domainErr.FieldError
+
+	// Iterate in a stable order so errors and filters are deterministic
+	keys := make([]string, 0, len(filters))
+	for k := range filters {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		name, op := key, OpEq
+		if len(key) > 1 {
+			if o, ok := operatorSuffixes[key[len(key)-1:]]; ok {
+				name, op = key[:len(key)-1], o
+			}
+		}
+		ft, ok := fields[name]
+		if !ok {
+			errs = append(errs, domainErr.FieldError{Field: name, Message: "is not a filterable field"})
+			continue
+		}
+		for _, raw := range filters[key] {
+			f, err := newFilter(name, ft, op, raw)
+			if err != nil {
+				errs = append(errs, domainErr.FieldError{Field: name, Message: err.Error()})
+				continue
+			}
+			spec.Filters = append(spec.Filters, f)
+		}
+	}
+
+	if sortExpr != "" {
+		for _, part := range strings.Split(sortExpr, ",") {
+			part = strings.TrimSpace(part)
+			sf := SortField{Field: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+			if _, ok := fields[sf.Field]; !ok {
+				errs = append(errs, domainErr.FieldError{Field: "sort", Message: fmt.Sprintf("cannot sort by %q", sf.Field)})
+				continue
+			}
+			spec.Sort = append(spec.Sort, sf)
+		}
+	}
+	if len(spec.Sort) > 0 && page.Cursor != "" {
+		errs = append(errs, domainErr.FieldError{Field: "cursor", Message: "cannot be combined with sort, use offset instead"})
+	}
+
+	if len(errs) > 0 {
+		return Spec{}, domainErr.Validation("invalid query", errs...)
+	}
+	return spec, nil
+}
+
+// KeysetPaging reports whether the listing is in ID order, so pages can continue from a cursor
+func (s Spec) KeysetPaging() bool {
+	return len(s.Sort) == 0
+}
+
+func newFilter(name string, ft FieldType, op Operator, raw string) (Filter, error) {
+	switch ft {
+	case Int:
+		if op == OpContains {
+			return Filter{}, fmt.Errorf("does not support the ~= operator")
+		}
+		v, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return Filter{}, fmt.Errorf("must be an integer")
+		}
+		return Filter{Field: name, Op: op, Value: v}, nil
+	case Time:
+		if op == OpContains {
+			return Filter{}, fmt.Errorf("does not support the ~= operator")
+		}
+		v, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			if v, err = time.Parse(time.DateOnly, raw); err != nil {
+				return Filter{}, fmt.Errorf("must be an RFC 3339 timestamp or a YYYY-MM-DD date")
+			}
+		}
+		return Filter{Field: name, Op: op, Value: v.UTC()}, nil
+	case Exact:
+		if op != OpEq && op != OpNe {
+			return Filter{}, fmt.Errorf("supports only the = and != operators")
+		}
+		return Filter{Field: name, Op: op, Value: raw}, nil
+	default:
+		return Filter{Field: name, Op: op, Value: raw}, nil
+	}
+}
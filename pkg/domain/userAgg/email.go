@@ -0,0 +1,35 @@
+package userAgg
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+)
+
+// maxEmailLength is the longest address allowed in an SMTP path (RFC 5321)
+const maxEmailLength = 254
+
+// Email is a normalized email address: trimmed, lowercased and syntactically valid.
+// Two addresses differing only in case belong to the same user.
+type Email string
+
+// NewEmail normalizes raw and checks that it is a bare address such as "alam@gmail.com"
+func NewEmail(raw string) (Email, error) {
+	s := strings.ToLower(strings.TrimSpace(raw))
+	if s == "" {
+		return "", errors.New("is required")
+	}
+	if len(s) > maxEmailLength {
+		return "", errors.New("must be at most 254 characters")
+	}
+	// ParseAddress also accepts display names like "Alam <alam@gmail.com>", which are not stored
+	addr, err := mail.ParseAddress(s)
+	if err != nil || addr.Address != s || !strings.Contains(s[strings.LastIndex(s, "@"):], ".") {
+		return "", errors.New("must be a valid email address")
+	}
+	return Email(s), nil
+}
+
+func (e Email) String() string {
+	return string(e)
+}
@@ -0,0 +1,97 @@
+package userAgg
+
+import (
+	"errors"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Names of the password fields, as reported in validation errors
+const (
+	FieldPassword    = "password"
+	FieldOldPassword = "old_password"
+	FieldNewPassword = "new_password"
+)
+
+// Bounds on the length of a password. bcrypt ignores everything past 72 bytes.
+const (
+	minPasswordLength = 8
+	maxPasswordBytes  = 72
+)
+
+// decoyHash is checked against when there is no user to log in, so that unknown
+// emails take as long to reject as wrong passwords
+var decoyHash = []byte("$2a$10$x65.EVMNUR7z4Y2LZ.oYBuFABTg/WpdfYsEXbykL4KNk4OmKu.Zeq")
+
+// SetPassword replaces the password of the user. Only its bcrypt hash is kept.
+func (u *User) SetPassword(raw string) error {
+	return u.setPassword(FieldPassword, raw)
+}
+
+// ChangePassword replaces the password of the user, provided old is the current one,
+// and records who changed it and when. Users without a password cannot change it.
+func (u *User) ChangePassword(old, new string, at time.Time, by string) error {
+	if !u.CheckPassword(old) {
+		return domainErr.Validation("invalid password", domainErr.FieldError{Field: FieldOldPassword, Message: "is incorrect"})
+	}
+	if err := u.setPassword(FieldNewPassword, new); err != nil {
+		return err
+	}
+	u.UpdatedAt, u.UpdatedBy = at, by
+	return nil
+}
+
+// ResetPassword replaces the password of the user without the current one, which the
+// caller has been verified another way, and records who changed it and when
+func (u *User) ResetPassword(new string, at time.Time, by string) error {
+	if err := u.setPassword(FieldNewPassword, new); err != nil {
+		return err
+	}
+	u.UpdatedAt, u.UpdatedBy = at, by
+	return nil
+}
+
+// HasPassword reports whether the user can log in with a password
+func (u *User) HasPassword() bool {
+	return u.PasswordHash != ""
+}
+
+// CheckPassword reports whether raw is the password of the user
+func (u *User) CheckPassword(raw string) bool {
+	if !u.HasPassword() {
+		CheckNoPassword(raw)
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(raw)) == nil
+}
+
+// CheckNoPassword takes as long as CheckPassword, for logins that match no user
+func CheckNoPassword(raw string) {
+	_ = bcrypt.CompareHashAndPassword(decoyHash, []byte(raw))
+}
+
+// setPassword hashes raw, reporting an invalid password as field
+func (u *User) setPassword(field, raw string) error {
+	if err := checkPasswordLength(raw); err != nil {
+		return domainErr.Validation("invalid password", domainErr.FieldError{Field: field, Message: err.Error()})
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(raw), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	u.PasswordHash = string(hash)
+	return nil
+}
+
+func checkPasswordLength(raw string) error {
+	if utf8.RuneCountInString(raw) < minPasswordLength {
+		return errors.New("must be at least 8 characters")
+	}
+	if len(raw) > maxPasswordBytes {
+		return errors.New("must be at most 72 bytes")
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package userAgg
+
+import (
+	"errors"
+	"strings"
+)
+
+// E.164 numbers have a country code and at most 15 digits in total
+const (
+	minPhoneDigits = 8
+	maxPhoneDigits = 15
+)
+
+// phoneSeparators may be used to group digits and are dropped during normalization
+var phoneSeparators = strings.NewReplacer(" ", "", "-", "", ".", "", "(", "", ")", "")
+
+// PhoneNumber is a phone number in E.164 format, e.g. "+919876543210"
+type PhoneNumber string
+
+// NewPhoneNumber parses raw into E.164 format. The country code is required,
+// either after a leading "+" or the international "00" prefix.
+// Spaces, dashes, dots and parentheses between digits are ignored.
+func NewPhoneNumber(raw string) (PhoneNumber, error) {
+	s := phoneSeparators.Replace(strings.TrimSpace(raw))
+	if s == "" {
+		return "", errors.New("is required")
+	}
+	switch {
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	case strings.HasPrefix(s, "00"):
+		s = s[2:]
+	default:
+		return "", errors.New("must start with + and the country code")
+	}
+
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return "", errors.New("must contain only digits after the country code prefix")
+		}
+	}
+	if len(s) < minPhoneDigits || len(s) > maxPhoneDigits {
+		return "", errors.New("must have between 8 and 15 digits including the country code")
+	}
+	if s[0] == '0' {
+		return "", errors.New("country code cannot start with 0")
+	}
+	return PhoneNumber("+" + s), nil
+}
+
+func (p PhoneNumber) String() string {
+	return string(p)
+}
+
+// Legacy reports whether p was stored before the country code was required:
+// it is bare digits rather than E.164 and must be set again
+func (p PhoneNumber) Legacy() bool {
+	return p != "" && !strings.HasPrefix(string(p), "+")
+}
@@ -0,0 +1,81 @@
+package userAgg
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Crud-application/pkg/domain/domainErr"
+)
+
+// Status is where a user is in their lifecycle
+type Status string
+
+const (
+	StatusPending     Status = "pending"     // Created, with an email not verified yet
+	StatusActive      Status = "active"      // Verified, or activated by an admin
+	StatusSuspended   Status = "suspended"   // Blocked for now, e.g. while abuse is looked into
+	StatusDeactivated Status = "deactivated" // Closed, until an admin activates it again
+)
+
+// FieldStatusReason names the reason of the last status change in audit snapshots
+const FieldStatusReason = "status_reason"
+
+// maxReasonLength bounds the reason of a status change, in characters
+const maxReasonLength = 500
+
+// transitions lists the statuses each status may change to
+var transitions = map[Status][]Status{
+	StatusPending:     {StatusActive, StatusSuspended, StatusDeactivated},
+	StatusActive:      {StatusSuspended, StatusDeactivated},
+	StatusSuspended:   {StatusActive, StatusDeactivated},
+	StatusDeactivated: {StatusActive},
+}
+
+// ParseStatus returns the status named s
+func ParseStatus(s string) (Status, error) {
+	if _, ok := transitions[Status(s)]; !ok {
+		return "", fmt.Errorf("must be one of pending, active, suspended or deactivated")
+	}
+	return Status(s), nil
+}
+
+// CanTransitionTo reports whether a user may go from s to next
+func (s Status) CanTransitionTo(next Status) bool {
+	for _, to := range transitions[s] {
+		if to == next {
+			return true
+		}
+	}
+	return false
+}
+
+// Blocked reports whether users with the status may not log in
+func (s Status) Blocked() bool {
+	return s == StatusSuspended || s == StatusDeactivated
+}
+
+// ChangeStatus moves the user to status for reason and raises UserStatusChanged.
+// A transition the state machine does not allow is a conflict.
+func (u *User) ChangeStatus(status Status, reason string, at time.Time, by string) error {
+	reason = strings.TrimSpace(reason)
+	if n := utf8.RuneCountInString(reason); n == 0 || n > maxReasonLength {
+		return domainErr.Validation("invalid status change",
+			domainErr.FieldError{Field: "reason", Message: "must be between 1 and 500 characters"})
+	}
+	if !u.Status.CanTransitionTo(status) {
+		return domainErr.InvalidState("user %s cannot go from %s to %s", u.ID, u.Status, status)
+	}
+
+	previous := u.Status
+	u.Status, u.StatusReason = status, reason
+	u.UpdatedAt, u.UpdatedBy = at, by
+	u.raise(EventUserStatusChanged, at, by, map[string]any{
+		FieldID:           u.ID,
+		FieldStatus:       string(status),
+		"previous_status": string(previous),
+		FieldStatusReason: reason,
+	})
+	return nil
+}
@@ -1,17 +1,156 @@
 package userAgg
 
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Crud-application/pkg/domain/auditAgg"
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"github.com/Crud-application/pkg/domain/domainEvent"
+)
+
+// Bounds on the length of a user name, in characters
+const (
+	minNameLength = 1
+	maxNameLength = 100
+)
+
 type User struct {
 	ID          string
 	Name        string
-	Email       string
-	PhoneNumber int64
+	Email       Email
+	PhoneNumber PhoneNumber
+	Version     int64      // Incremented on every stored change, for optimistic concurrency
+	DeletedAt   *time.Time // Set while the user is soft-deleted and can still be restored
+	// bcrypt hash of the password, empty for users who cannot log in with one.
+	// It is never returned to clients.
+	PasswordHash string
+	// Set once the user followed the link sent to their email, and cleared when the email changes
+	EmailVerified bool
+	Status        Status
+	StatusReason  string // Why the user got their status, empty for new users
+
+	// Audit metadata, managed by the server
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	CreatedBy string
+	UpdatedBy string
+
+	// Events raised since the user was loaded. The repository stores them
+	// with the user and clears them.
+	Events []domainEvent.Event
+	// Audit entries of the changes since the user was loaded, stored and cleared like Events
+	AuditEntries []auditAgg.Entry
+}
+
+// InitialVersion is the version of a user that has just been created
+const InitialVersion int64 = 1
+
+// NewUser builds a user from raw input, normalizing the email and phone number.
+// Every invalid field is reported in the returned validation error.
+func NewUser(id, name, email, phoneNumber string) (*User, error) {
+	u := &User{ID: id, Version: InitialVersion, Status: StatusPending}
+	if err := u.Update(&name, &email, &phoneNumber); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// Update changes the fields that are not nil. Nothing is changed unless all of them are valid.
+func (u *User) Update(name, email, phoneNumber *string) error {
+	next := *u
+	var errs []domainErr.FieldError
+
+	if name != nil {
+		n, err := newName(*name)
+		if err != nil {
+			errs = append(errs, domainErr.FieldError{Field: FieldName, Message: err.Error()})
+		}
+		next.Name = n
+	}
+	if email != nil {
+		e, err := NewEmail(*email)
+		if err != nil {
+			errs = append(errs, domainErr.FieldError{Field: FieldEmail, Message: err.Error()})
+		}
+		if e != u.Email {
+			next.EmailVerified = false
+		}
+		next.Email = e
+	}
+	if phoneNumber != nil {
+		p, err := NewPhoneNumber(*phoneNumber)
+		if err != nil {
+			errs = append(errs, domainErr.FieldError{Field: FieldPhoneNumber, Message: err.Error()})
+		}
+		next.PhoneNumber = p
+	} else if u.PhoneNumber.Legacy() {
+		errs = append(errs, domainErr.FieldError{Field: FieldPhoneNumber, Message: "has no country code, it must be set again in E.164 format"})
+	}
+
+	if len(errs) > 0 {
+		return domainErr.Validation("invalid user", errs...)
+	}
+	*u = next
+	return nil
+}
+
+// MarkCreated records who created the user and when, and raises UserCreated
+func (u *User) MarkCreated(at time.Time, by string) {
+	u.CreatedAt, u.CreatedBy = at, by
+	u.UpdatedAt, u.UpdatedBy = at, by
+	u.raise(EventUserCreated, at, by, u.eventPayload())
+}
+
+// MarkUpdated records who changed the user last and when, and raises UserUpdated
+func (u *User) MarkUpdated(at time.Time, by string) {
+	u.UpdatedAt, u.UpdatedBy = at, by
+	u.raise(EventUserUpdated, at, by, u.eventPayload())
+}
+
+// VerifyEmail records that the user owns their email and raises UserUpdated.
+// A pending user becomes active first, raising UserStatusChanged.
+func (u *User) VerifyEmail(at time.Time, by string) {
+	u.EmailVerified = true
+	if u.Status == StatusPending {
+		_ = u.ChangeStatus(StatusActive, "email verified", at, by)
+	}
+	u.MarkUpdated(at, by)
+}
+
+// MarkDeleted soft-deletes the user, recording who deleted them and when, and raises UserDeleted
+func (u *User) MarkDeleted(at time.Time, by string) {
+	u.DeletedAt = &at
+	u.UpdatedAt, u.UpdatedBy = at, by
+	u.raise(EventUserDeleted, at, by, map[string]any{FieldID: u.ID, FieldDeletedAt: at})
+}
+
+// MarkRestored undoes the soft deletion of the user, recording who restored them and when.
+// Restoring a user that is not deleted is a conflict.
+func (u *User) MarkRestored(at time.Time, by string) error {
+	if u.DeletedAt == nil {
+		return domainErr.InvalidState("user %s is not deleted", u.ID)
+	}
+	u.DeletedAt = nil
+	u.UpdatedAt, u.UpdatedBy = at, by
+	u.raise(EventUserRestored, at, by, u.eventPayload())
+	return nil
 }
 
-func NewUser(id, name, email string, phoneNumber int64) (*User, error) {
-	return &User{
-		ID:          id,
-		Name:        name,
-		Email:       email,
-		PhoneNumber: phoneNumber,
-	}, nil
+// newName trims raw and checks that it is a plausible person name: letters,
+// combining marks, spaces and the punctuation found in names (' - .)
+func newName(raw string) (string, error) {
+	s := strings.TrimSpace(raw)
+	if n := utf8.RuneCountInString(s); n < minNameLength || n > maxNameLength {
+		return "", errors.New("must be between 1 and 100 characters")
+	}
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsMark(r) && !strings.ContainsRune(" '-.", r) {
+			return "", errors.New("may contain only letters, spaces, apostrophes, hyphens and periods")
+		}
+	}
+	return s, nil
 }
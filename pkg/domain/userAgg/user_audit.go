@@ -0,0 +1,28 @@
+package userAgg
+
+// Names of the fields in audit snapshots that cannot be filtered on
+const (
+	FieldDeletedAt     = "deleted_at"
+	FieldEmailVerified = "email_verified"
+)
+
+// AuditSnapshot returns the client-visible fields of the user whose changes are audited,
+// keyed by field name. Server-managed metadata such as the version is left out.
+func (u *User) AuditSnapshot() map[string]any {
+	s := map[string]any{
+		FieldName:          u.Name,
+		FieldEmail:         u.Email.String(),
+		FieldPhoneNumber:   u.PhoneNumber.String(),
+		FieldEmailVerified: u.EmailVerified,
+		FieldStatus:        string(u.Status),
+		FieldStatusReason:  nil,
+		FieldDeletedAt:     nil,
+	}
+	if u.StatusReason != "" {
+		s[FieldStatusReason] = u.StatusReason
+	}
+	if u.DeletedAt != nil {
+		s[FieldDeletedAt] = *u.DeletedAt
+	}
+	return s
+}
@@ -4,5 +4,5 @@ var UserAgg = User{
 	ID:          "5118863e-a240-44b9-a294-9734382770d9",
 	Name:        "shaharyar",
 	Email:       "samar123@example.com",
-	PhoneNumber: 98765430000,
+	PhoneNumber: "+918765430000",
 }
@@ -0,0 +1,54 @@
+package userAgg
+
+import (
+	"time"
+
+	"github.com/Crud-application/pkg/domain/domainEvent"
+)
+
+// Domain events raised by users
+const (
+	EventUserCreated domainEvent.Type = "user.created"
+	EventUserUpdated domainEvent.Type = "user.updated"
+	EventUserDeleted domainEvent.Type = "user.deleted"
+
+	EventUserRestored domainEvent.Type = "user.restored"
+	EventUserPurged   domainEvent.Type = "user.purged"
+
+	EventUserStatusChanged domainEvent.Type = "user.status_changed"
+)
+
+// raise records an event about the user, to be stored along with it
+func (u *User) raise(t domainEvent.Type, at time.Time, by string, payload map[string]any) {
+	u.Events = append(u.Events, domainEvent.Event{
+		Type:        t,
+		AggregateID: u.ID,
+		Actor:       by,
+		OccurredAt:  at,
+		Payload:     payload,
+	})
+}
+
+// PurgedEvent is the event of a deleted user removed for good once its retention ran out.
+// Purged users are no longer loaded, so the repository raises it on their behalf.
+func PurgedEvent(userID string, at time.Time, by string) domainEvent.Event {
+	return domainEvent.Event{
+		Type:        EventUserPurged,
+		AggregateID: userID,
+		Actor:       by,
+		OccurredAt:  at,
+		Payload:     map[string]any{FieldID: userID},
+	}
+}
+
+// eventPayload is the state of the user carried by its created and updated events
+func (u *User) eventPayload() map[string]any {
+	return map[string]any{
+		FieldID:            u.ID,
+		FieldName:          u.Name,
+		FieldEmail:         u.Email.String(),
+		FieldPhoneNumber:   u.PhoneNumber.String(),
+		FieldEmailVerified: u.EmailVerified,
+		FieldStatus:        string(u.Status),
+	}
+}
@@ -0,0 +1,9 @@
+package userAgg
+
+import "github.com/Crud-application/pkg/domain/query"
+
+// UserPage is one page of a user listing
+type UserPage struct {
+	Users []User
+	Page  query.PageInfo
+}
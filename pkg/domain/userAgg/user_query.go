@@ -0,0 +1,82 @@
+package userAgg
+
+import (
+	"strings"
+
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"github.com/Crud-application/pkg/domain/query"
+)
+
+// Names of the user fields that listings can filter and sort on
+const (
+	FieldID          = "id"
+	FieldName        = "name"
+	FieldEmail       = "email"
+	FieldPhoneNumber = "phone_number"
+	FieldCreatedAt   = "created_at"
+	FieldUpdatedAt   = "updated_at"
+	FieldCreatedBy   = "created_by"
+	FieldUpdatedBy   = "updated_by"
+	FieldStatus      = "status"
+)
+
+// QueryFields lists the filterable and sortable user fields with their types
+var QueryFields = query.Fields{
+	FieldID:          query.String,
+	FieldName:        query.String,
+	FieldEmail:       query.String,
+	FieldPhoneNumber: query.Exact,
+	FieldCreatedAt:   query.Time,
+	FieldUpdatedAt:   query.Time,
+	FieldCreatedBy:   query.String,
+	FieldUpdatedBy:   query.String,
+	FieldStatus:      query.String,
+}
+
+// SearchFields are matched by the free text search of a listing
+var SearchFields = []string{FieldName, FieldEmail}
+
+// NewQuerySpec builds the spec of a user listing from raw parameters, like query.NewSpec
+// over QueryFields, with filter values normalized the way the fields are stored
+func NewQuerySpec(filters map[string][]string, search, sortExpr string, page query.PageRequest) (query.Spec, error) {
+	spec, err := query.NewSpec(QueryFields, filters, search, sortExpr, page)
+	if err != nil {
+		return query.Spec{}, err
+	}
+	var errs []domainErr.FieldError
+	for i, f := range spec.Filters {
+		v, err := normalizeFilterValue(f)
+		if err != nil {
+			errs = append(errs, domainErr.FieldError{Field: f.Field, Message: err.Error()})
+			continue
+		}
+		spec.Filters[i].Value = v
+	}
+	if len(errs) > 0 {
+		return query.Spec{}, domainErr.Validation("invalid query", errs...)
+	}
+	return spec, nil
+}
+
+// normalizeFilterValue returns the value of f the way the field is stored: whole emails
+// validated and lowercased, email bounds lowercased and phone numbers in E.164. The ~=
+// operator ignores case anyway.
+func normalizeFilterValue(f query.Filter) (any, error) {
+	switch {
+	case f.Field == FieldEmail && (f.Op == query.OpEq || f.Op == query.OpNe):
+		e, err := NewEmail(f.Value.(string))
+		return e.String(), err
+	case f.Field == FieldEmail && (f.Op == query.OpGte || f.Op == query.OpLte):
+		return strings.ToLower(f.Value.(string)), nil
+	case f.Field == FieldPhoneNumber:
+		raw := f.Value.(string)
+		// An unescaped + in a query string arrives as a space
+		if strings.HasPrefix(raw, " ") {
+			raw = "+" + strings.TrimSpace(raw)
+		}
+		p, err := NewPhoneNumber(raw)
+		return p.String(), err
+	default:
+		return f.Value, nil
+	}
+}
@@ -0,0 +1,337 @@
+package userAgg
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"github.com/Crud-application/pkg/domain/domainEvent"
+	"github.com/Crud-application/pkg/domain/query"
+)
+
+func TestNewUser(t *testing.T) {
+	type args struct {
+		name, email, phoneNumber string
+	}
+	tests := []struct {
+		id         int
+		name       string
+		args       args
+		want       *User
+		wantFields []string // Fields reported in the validation error
+	}{
+		{
+			id:   1,
+			name: "Valid user is normalized - Success",
+			args: args{name: "  Shaharyar Alam ", email: " Samar123@Example.COM", phoneNumber: "+91 (876) 543-0000"},
+			want: &User{ID: "id", Name: "Shaharyar Alam", Email: "samar123@example.com", PhoneNumber: "+918765430000", Version: InitialVersion, Status: StatusPending},
+		},
+		{
+			id:   2,
+			name: "International 00 prefix and accented name - Success",
+			args: args{name: "Zoë O'Brien-Núñez", email: "zoe@example.ie", phoneNumber: "00353 1 234 5678"},
+			want: &User{ID: "id", Name: "Zoë O'Brien-Núñez", Email: "zoe@example.ie", PhoneNumber: "+35312345678", Version: InitialVersion, Status: StatusPending},
+		},
+		{
+			id:         3,
+			name:       "Every invalid field is reported - Failure",
+			args:       args{name: "", email: "Alam <alam@gmail.com>", phoneNumber: "9876543210"},
+			wantFields: []string{FieldName, FieldEmail, FieldPhoneNumber},
+		},
+		{
+			id:         4,
+			name:       "Digits in name and email without domain - Failure",
+			args:       args{name: "alam123", email: "alam@localhost", phoneNumber: "+919876543210"},
+			wantFields: []string{FieldName, FieldEmail},
+		},
+		{
+			id:         5,
+			name:       "Phone number with more than 15 digits - Failure",
+			args:       args{name: "Alam", email: "alam@gmail.com", phoneNumber: "+91987654321012345"},
+			wantFields: []string{FieldPhoneNumber},
+		},
+		{
+			id:         6,
+			name:       "Country code starting with 0 - Failure",
+			args:       args{name: "Alam", email: "alam@gmail.com", phoneNumber: "+0987654321"},
+			wantFields: []string{FieldPhoneNumber},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewUser("id", tt.args.name, tt.args.email, tt.args.phoneNumber)
+			if tt.wantFields == nil {
+				if err != nil {
+					t.Fatalf("ID %v NewUser() error = %v", tt.id, err)
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("ID %v got = %v want = %v", tt.id, got, tt.want)
+				}
+				return
+			}
+
+			if !errors.Is(err, domainErr.ErrValidation) {
+				t.Fatalf("ID %v NewUser() error = %v, want a validation error", tt.id, err)
+			}
+			var fields []string
+			for _, f := range domainErr.FieldErrors(err) {
+				fields = append(fields, f.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.wantFields) {
+				t.Errorf("ID %v invalid fields = %v want = %v", tt.id, fields, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestUser_Update(t *testing.T) {
+	u := UserAgg
+	name, badEmail := "New Name", "not-an-email"
+	if err := u.Update(&name, &badEmail, nil); err == nil {
+		t.Fatal("Update() with an invalid email succeeded")
+	}
+	if !reflect.DeepEqual(u, UserAgg) {
+		t.Errorf("failed Update() changed the user to %v", u)
+	}
+
+	email := "New@Example.com"
+	if err := u.Update(nil, &email, nil); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	want := UserAgg
+	want.Email = "new@example.com"
+	if !reflect.DeepEqual(u, want) {
+		t.Errorf("Update() got = %v want = %v", u, want)
+	}
+
+	// A phone number without country code must be set again before anything else changes
+	legacy := UserAgg
+	legacy.PhoneNumber = "9876543210"
+	if err := legacy.Update(&name, nil, nil); !errors.Is(err, domainErr.ErrValidation) {
+		t.Errorf("Update() of a legacy phone number error = %v, want validation error", err)
+	}
+	phone := "+91 98765 43210"
+	if err := legacy.Update(&name, nil, &phone); err != nil || legacy.PhoneNumber != "+919876543210" {
+		t.Errorf("Update() setting the phone number again = %v, %v", legacy.PhoneNumber, err)
+	}
+}
+
+func TestUser_VerifyEmail(t *testing.T) {
+	at := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	u, _ := NewUser("u1", "John Doe", "john@example.com", "+919876543210")
+	u.VerifyEmail(at, "u1")
+	if !u.EmailVerified || u.UpdatedBy != "u1" || len(u.Events) != 2 || u.Events[1].Payload[FieldEmailVerified] != true {
+		t.Fatalf("VerifyEmail() = %+v, want a verified email and a user.updated event", u)
+	}
+	if u.Status != StatusActive || u.Events[0].Type != EventUserStatusChanged {
+		t.Errorf("VerifyEmail() of a pending user status = %v, events %v, want it activated", u.Status, u.Events)
+	}
+
+	name, email := "John Smith", "JOHN@example.com"
+	if err := u.Update(&name, &email, nil); err != nil || !u.EmailVerified {
+		t.Errorf("Update() of the name = %v, verified %v, want the email to stay verified", err, u.EmailVerified)
+	}
+	email = "john.smith@example.com"
+	if err := u.Update(nil, &email, nil); err != nil || u.EmailVerified {
+		t.Errorf("Update() of the email = %v, verified %v, want verification reset", err, u.EmailVerified)
+	}
+}
+
+func TestUser_Events(t *testing.T) {
+	at := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	u, _ := NewUser("u1", "John Doe", "john@example.com", "+919876543210")
+	u.MarkCreated(at, "admin")
+	u.MarkUpdated(at.Add(time.Minute), "editor")
+	u.MarkDeleted(at.Add(2*time.Minute), "editor")
+
+	want := []domainEvent.Event{
+		{
+			Type: EventUserCreated, AggregateID: "u1", Actor: "admin", OccurredAt: at,
+			Payload: map[string]any{"id": "u1", "name": "John Doe", "email": "john@example.com", "phone_number": "+919876543210", "email_verified": false, "status": "pending"},
+		},
+		{
+			Type: EventUserUpdated, AggregateID: "u1", Actor: "editor", OccurredAt: at.Add(time.Minute),
+			Payload: map[string]any{"id": "u1", "name": "John Doe", "email": "john@example.com", "phone_number": "+919876543210", "email_verified": false, "status": "pending"},
+		},
+		{
+			Type: EventUserDeleted, AggregateID: "u1", Actor: "editor", OccurredAt: at.Add(2 * time.Minute),
+			Payload: map[string]any{"id": "u1", "deleted_at": at.Add(2 * time.Minute)},
+		},
+	}
+	if !reflect.DeepEqual(u.Events, want) {
+		t.Errorf("Events = %v want = %v", u.Events, want)
+	}
+}
+
+func TestUser_DeleteAndRestore(t *testing.T) {
+	at := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	u, _ := NewUser("u1", "John Doe", "john@example.com", "+919876543210")
+	u.MarkCreated(at, "admin")
+
+	if err := u.MarkRestored(at, "admin"); !errors.Is(err, domainErr.ErrConflict) {
+		t.Errorf("MarkRestored() of a user that is not deleted error = %v, want conflict", err)
+	}
+
+	u.MarkDeleted(at.Add(time.Minute), "remover")
+	if u.DeletedAt == nil || !u.UpdatedAt.Equal(at.Add(time.Minute)) || u.UpdatedBy != "remover" {
+		t.Errorf("MarkDeleted() got deleted_at = %v, updated_at = %v, updated_by = %v", u.DeletedAt, u.UpdatedAt, u.UpdatedBy)
+	}
+
+	if err := u.MarkRestored(at.Add(time.Hour), "restorer"); err != nil {
+		t.Fatalf("MarkRestored() error = %v", err)
+	}
+	if u.DeletedAt != nil || !u.UpdatedAt.Equal(at.Add(time.Hour)) || u.UpdatedBy != "restorer" {
+		t.Errorf("MarkRestored() got deleted_at = %v, updated_at = %v, updated_by = %v", u.DeletedAt, u.UpdatedAt, u.UpdatedBy)
+	}
+	if last := u.Events[len(u.Events)-1]; last.Type != EventUserRestored || last.Actor != "restorer" {
+		t.Errorf("MarkRestored() raised %v by %v, want %v by restorer", last.Type, last.Actor, EventUserRestored)
+	}
+}
+
+func TestUser_ChangeStatus(t *testing.T) {
+	at := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		id      int
+		name    string
+		from    Status
+		to      Status
+		reason  string
+		wantErr error
+	}{
+		{id: 1, name: "Suspend an active user - Success", from: StatusActive, to: StatusSuspended, reason: " Spam reports "},
+		{id: 2, name: "Activate a suspended user - Success", from: StatusSuspended, to: StatusActive, reason: "Cleared"},
+		{id: 3, name: "Deactivate a pending user - Success", from: StatusPending, to: StatusDeactivated, reason: "Asked to close"},
+		{id: 4, name: "Reactivate a deactivated user - Success", from: StatusDeactivated, to: StatusActive, reason: "Came back"},
+		{id: 5, name: "Suspend a suspended user - Conflict", from: StatusSuspended, to: StatusSuspended, reason: "Again", wantErr: domainErr.ErrConflict},
+		{id: 6, name: "Suspend a deactivated user - Conflict", from: StatusDeactivated, to: StatusSuspended, reason: "Spam", wantErr: domainErr.ErrConflict},
+		{id: 7, name: "Back to pending - Conflict", from: StatusActive, to: StatusPending, reason: "Unverify", wantErr: domainErr.ErrConflict},
+		{id: 8, name: "Blank reason - Validation error", from: StatusActive, to: StatusSuspended, reason: "  ", wantErr: domainErr.ErrValidation},
+		{id: 9, name: "Too long a reason - Validation error", from: StatusActive, to: StatusSuspended, reason: strings.Repeat("a", 501), wantErr: domainErr.ErrValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := &User{ID: "u1", Status: tt.from}
+			err := u.ChangeStatus(tt.to, tt.reason, at, "admin")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ID %v ChangeStatus() error = %v, wantErr %v", tt.id, err, tt.wantErr)
+			}
+			if err != nil {
+				if u.Status != tt.from || len(u.Events) != 0 {
+					t.Errorf("ID %v failed ChangeStatus() changed the user to %+v", tt.id, u)
+				}
+				return
+			}
+			want := []domainEvent.Event{{
+				Type: EventUserStatusChanged, AggregateID: "u1", Actor: "admin", OccurredAt: at,
+				Payload: map[string]any{"id": "u1", "status": string(tt.to), "previous_status": string(tt.from), "status_reason": strings.TrimSpace(tt.reason)},
+			}}
+			if u.Status != tt.to || u.StatusReason != strings.TrimSpace(tt.reason) || u.UpdatedBy != "admin" || !reflect.DeepEqual(u.Events, want) {
+				t.Errorf("ID %v ChangeStatus() = %+v", tt.id, u)
+			}
+		})
+	}
+}
+
+func TestParseStatus(t *testing.T) {
+	if s, err := ParseStatus("suspended"); err != nil || s != StatusSuspended {
+		t.Errorf("ParseStatus(suspended) = %v, %v", s, err)
+	}
+	if _, err := ParseStatus("banned"); err == nil {
+		t.Errorf("ParseStatus(banned) succeeded")
+	}
+	if !StatusSuspended.Blocked() || !StatusDeactivated.Blocked() || StatusPending.Blocked() || StatusActive.Blocked() {
+		t.Errorf("Blocked() is wrong for some status")
+	}
+}
+
+func TestUser_Password(t *testing.T) {
+	at := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	u := &User{ID: "u1"}
+	if u.HasPassword() || u.CheckPassword("") {
+		t.Fatalf("new user has a password")
+	}
+	if err := u.ChangePassword("", "correct horse", at, "u1"); !hasFieldError(err, FieldOldPassword) {
+		t.Errorf("ChangePassword() without a password error = %v, want old_password invalid", err)
+	}
+
+	if err := u.SetPassword("short"); !hasFieldError(err, FieldPassword) {
+		t.Errorf("SetPassword() of a short password error = %v, want password invalid", err)
+	}
+	if err := u.SetPassword(strings.Repeat("é", 37)); !hasFieldError(err, FieldPassword) {
+		t.Errorf("SetPassword() of 74 bytes error = %v, want password invalid", err)
+	}
+	if err := u.SetPassword("correct horse"); err != nil {
+		t.Fatalf("SetPassword() error = %v", err)
+	}
+	if !u.HasPassword() || strings.Contains(u.PasswordHash, "correct horse") {
+		t.Errorf("SetPassword() hash = %q, want a hash of the password", u.PasswordHash)
+	}
+	if !u.CheckPassword("correct horse") || u.CheckPassword("correct horsE") {
+		t.Errorf("CheckPassword() does not tell the password apart")
+	}
+
+	if err := u.ChangePassword("wrong", "battery staple", at, "u1"); !hasFieldError(err, FieldOldPassword) {
+		t.Errorf("ChangePassword() with a wrong old password error = %v, want old_password invalid", err)
+	}
+	if err := u.ChangePassword("correct horse", "short", at, "u1"); !hasFieldError(err, FieldNewPassword) {
+		t.Errorf("ChangePassword() to a short password error = %v, want new_password invalid", err)
+	}
+	if err := u.ChangePassword("correct horse", "battery staple", at, "u1"); err != nil {
+		t.Fatalf("ChangePassword() error = %v", err)
+	}
+	if !u.CheckPassword("battery staple") || u.CheckPassword("correct horse") || !u.UpdatedAt.Equal(at) || u.UpdatedBy != "u1" {
+		t.Errorf("ChangePassword() = %+v, want the new password recorded as changed by u1", u)
+	}
+
+	if err := u.ResetPassword("short", at, "u1"); !hasFieldError(err, FieldNewPassword) {
+		t.Errorf("ResetPassword() to a short password error = %v, want new_password invalid", err)
+	}
+	if err := u.ResetPassword("tr0ub4dor&3", at.Add(time.Hour), "u1"); err != nil || !u.CheckPassword("tr0ub4dor&3") || !u.UpdatedAt.Equal(at.Add(time.Hour)) {
+		t.Errorf("ResetPassword() = %v, want the new password recorded", err)
+	}
+}
+
+// hasFieldError reports whether err is a validation error of field
+func hasFieldError(err error, field string) bool {
+	for _, f := range domainErr.FieldErrors(err) {
+		if f.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+func TestNewQuerySpec(t *testing.T) {
+	tests := []struct {
+		id        int
+		name      string
+		filters   map[string][]string
+		wantValue string
+		wantErr   error
+	}{
+		{id: 1, name: "Phone number with separators - normalized", filters: map[string][]string{"phone_number": {"+91 98765 43210"}}, wantValue: "+919876543210"},
+		{id: 2, name: "Phone number with 00 prefix - normalized", filters: map[string][]string{"phone_number!": {"0091-9876543210"}}, wantValue: "+919876543210"},
+		{id: 3, name: "Unescaped + decoded as a space - normalized", filters: map[string][]string{"phone_number": {" 919876543210"}}, wantValue: "+919876543210"},
+		{id: 4, name: "Invalid phone number - validation error", filters: map[string][]string{"phone_number": {"12345"}}, wantErr: domainErr.ErrValidation},
+		{id: 5, name: "Email in another case - lowercased", filters: map[string][]string{"email": {"Alice@Example.com "}}, wantValue: "alice@example.com"},
+		{id: 6, name: "Email contained - kept as given", filters: map[string][]string{"email~": {"Example"}}, wantValue: "Example"},
+		{id: 7, name: "Email bound - lowercased", filters: map[string][]string{"email>": {"M"}}, wantValue: "m"},
+		{id: 8, name: "Invalid email - validation error", filters: map[string][]string{"email!": {"alice"}}, wantErr: domainErr.ErrValidation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := NewQuerySpec(tt.filters, "", "", query.PageRequest{Limit: 10})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ID %v NewQuerySpec() error = %v, wantErr %v", tt.id, err, tt.wantErr)
+			}
+			if err == nil && spec.Filters[0].Value != tt.wantValue {
+				t.Errorf("ID %v NewQuerySpec() value = %v, want %v", tt.id, spec.Filters[0].Value, tt.wantValue)
+			}
+		})
+	}
+}
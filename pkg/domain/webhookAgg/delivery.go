@@ -0,0 +1,94 @@
+package webhookAgg
+
+import (
+	"time"
+
+	"github.com/Crud-application/pkg/domain/domainEvent"
+	"github.com/Crud-application/pkg/domain/query"
+)
+
+// DeliveryStatus is where a delivery is in its lifecycle
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"   // Waiting for its next attempt
+	DeliverySucceeded DeliveryStatus = "succeeded" // The receiver answered 2xx
+	DeliveryDead      DeliveryStatus = "dead"      // Gave up after the last attempt
+)
+
+// Delivery is one event sent to one subscription, retried with exponential
+// backoff until the receiver accepts it or the attempts run out
+type Delivery struct {
+	ID             string // Assigned by the repository, in the order deliveries are added
+	SubscriptionID string
+	EventID        string // Together with SubscriptionID, identifies the delivery
+	EventType      domainEvent.Type
+	Body           []byte // The JSON that is signed and sent
+	Status         DeliveryStatus
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastAttemptAt  *time.Time
+	LastStatusCode int    // HTTP status of the last attempt, 0 when no response arrived
+	LastError      string // Why the last attempt failed
+	CreatedAt      time.Time
+}
+
+// DeliveryPage is one page of the delivery log of a subscription, newest first
+type DeliveryPage struct {
+	Deliveries []Delivery
+	Page       query.PageInfo
+}
+
+// Backoff is the retry schedule of deliveries
+type Backoff struct {
+	MaxAttempts int
+	Base        time.Duration // Wait after the first failed attempt, doubled after each further one
+	Max         time.Duration // Upper bound of the wait
+}
+
+// NewDelivery builds the pending delivery of an event to a subscription, due at once
+func NewDelivery(s *Subscription, e domainEvent.Event, body []byte, at time.Time) *Delivery {
+	return &Delivery{
+		SubscriptionID: s.ID,
+		EventID:        e.ID,
+		EventType:      e.Type,
+		Body:           body,
+		Status:         DeliveryPending,
+		NextAttemptAt:  at,
+		CreatedAt:      at,
+	}
+}
+
+// Succeeded records an attempt the receiver accepted
+func (d *Delivery) Succeeded(at time.Time, statusCode int) {
+	d.attempted(at, statusCode, "")
+	d.Status = DeliverySucceeded
+}
+
+// Failed records a failed attempt, scheduling the next one or giving up when
+// the attempts of b are used up
+func (d *Delivery) Failed(at time.Time, statusCode int, reason string, b Backoff) {
+	d.attempted(at, statusCode, reason)
+	if d.Attempts >= b.MaxAttempts {
+		d.Status = DeliveryDead
+		return
+	}
+	wait := b.Base << (d.Attempts - 1)
+	if wait > b.Max || wait <= 0 {
+		wait = b.Max
+	}
+	d.NextAttemptAt = at.Add(wait)
+}
+
+// Abandon dead-letters a delivery that can no longer be made, without attempting it
+func (d *Delivery) Abandon(reason string) {
+	d.Status = DeliveryDead
+	d.LastError = reason
+}
+
+func (d *Delivery) attempted(at time.Time, statusCode int, reason string) {
+	d.Attempts++
+	d.LastAttemptAt = &at
+	d.LastStatusCode = statusCode
+	d.LastError = reason
+}
@@ -0,0 +1,117 @@
+package webhookAgg
+
+import (
+	"net/netip"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"github.com/Crud-application/pkg/domain/domainEvent"
+	"github.com/Crud-application/pkg/domain/query"
+	uAgg "github.com/Crud-application/pkg/domain/userAgg"
+)
+
+// Field names of a subscription, as reported in validation errors
+const (
+	FieldURL    = "url"
+	FieldSecret = "secret"
+	FieldEvents = "events"
+)
+
+// minSecretLength is the shortest signing secret accepted, in bytes
+const minSecretLength = 16
+
+// EventTypes are the events a subscription can receive
+var EventTypes = []domainEvent.Type{
+	uAgg.EventUserCreated,
+	uAgg.EventUserUpdated,
+	uAgg.EventUserStatusChanged,
+	uAgg.EventUserDeleted,
+	uAgg.EventUserRestored,
+	uAgg.EventUserPurged,
+}
+
+// Subscription asks for the events of the given types to be POSTed to URL,
+// signed with Secret
+type Subscription struct {
+	ID     string
+	URL    string
+	Secret string
+	Events []domainEvent.Type // Empty subscribes to every event type
+	Active bool               // Inactive subscriptions receive no new deliveries
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// SubscriptionPage is one page of subscriptions
+type SubscriptionPage struct {
+	Subscriptions []Subscription
+	Page          query.PageInfo
+}
+
+// NewSubscription builds an active subscription from raw input.
+// Every invalid field is reported in the returned validation error.
+func NewSubscription(id, rawURL, secret string, events []string) (*Subscription, error) {
+	s := &Subscription{ID: id, Active: true}
+	if err := s.Update(&rawURL, &secret, events, nil); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Update changes the fields that are not nil. Nothing is changed unless all of them are valid.
+func (s *Subscription) Update(rawURL, secret *string, events []string, active *bool) error {
+	next := *s
+	var errs []domainErr.FieldError
+
+	if rawURL != nil {
+		u, err := url.Parse(*rawURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, domainErr.FieldError{Field: FieldURL, Message: "must be an absolute http or https URL"})
+		}
+		next.URL = *rawURL
+	}
+	if secret != nil {
+		if len(*secret) < minSecretLength {
+			errs = append(errs, domainErr.FieldError{Field: FieldSecret, Message: "must be at least 16 characters"})
+		}
+		next.Secret = *secret
+	}
+	if events != nil {
+		next.Events = make([]domainEvent.Type, 0, len(events))
+		for _, e := range events {
+			t := domainEvent.Type(e)
+			if !slices.Contains(EventTypes, t) {
+				errs = append(errs, domainErr.FieldError{Field: FieldEvents, Message: "has unknown event type " + e})
+				continue
+			}
+			if !slices.Contains(next.Events, t) {
+				next.Events = append(next.Events, t)
+			}
+		}
+	}
+	if active != nil {
+		next.Active = *active
+	}
+
+	if len(errs) > 0 {
+		return domainErr.Validation("invalid webhook", errs...)
+	}
+	*s = next
+	return nil
+}
+
+// PublicAddr reports whether a receiver at addr is outside the networks of the server:
+// not loopback, private, link-local, multicast or unspecified
+func PublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsValid() && !addr.IsLoopback() && !addr.IsPrivate() && !addr.IsLinkLocalUnicast() &&
+		!addr.IsLinkLocalMulticast() && !addr.IsInterfaceLocalMulticast() && !addr.IsMulticast() && !addr.IsUnspecified()
+}
+
+// Wants reports whether the subscription receives events of type t
+func (s *Subscription) Wants(t domainEvent.Type) bool {
+	return s.Active && (len(s.Events) == 0 || slices.Contains(s.Events, t))
+}
@@ -0,0 +1,169 @@
+package webhookAgg
+
+import (
+	"errors"
+	"net/netip"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"github.com/Crud-application/pkg/domain/domainEvent"
+)
+
+func TestNewSubscription(t *testing.T) {
+	tests := []struct {
+		id         int
+		name       string
+		url        string
+		secret     string
+		events     []string
+		wantEvents []domainEvent.Type
+		wantFields []string
+	}{
+		{
+			id:         1,
+			name:       "Valid subscription - success",
+			url:        "https://partner.example.com/hook",
+			secret:     "0123456789abcdef",
+			events:     []string{"user.created", "user.deleted", "user.created"},
+			wantEvents: []domainEvent.Type{"user.created", "user.deleted"},
+		},
+		{
+			id:         2,
+			name:       "Every invalid field is reported - failure",
+			url:        "ftp://partner.example.com",
+			secret:     "short",
+			events:     []string{"user.renamed"},
+			wantFields: []string{FieldURL, FieldSecret, FieldEvents},
+		},
+		{
+			id:         3,
+			name:       "Relative URL - failure",
+			url:        "/hook",
+			secret:     "0123456789abcdef",
+			wantFields: []string{FieldURL},
+		},
+		{
+			id:         4,
+			name:       "Every user event type - success",
+			url:        "https://partner.example.com/hook",
+			secret:     "0123456789abcdef",
+			events:     []string{"user.created", "user.updated", "user.status_changed", "user.deleted", "user.restored", "user.purged"},
+			wantEvents: []domainEvent.Type{"user.created", "user.updated", "user.status_changed", "user.deleted", "user.restored", "user.purged"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewSubscription("w1", tt.url, tt.secret, tt.events)
+			if tt.wantFields != nil {
+				if !errors.Is(err, domainErr.ErrValidation) {
+					t.Fatalf("ID %v NewSubscription() error = %v, want a validation error", tt.id, err)
+				}
+				var fields []string
+				for _, f := range domainErr.FieldErrors(err) {
+					fields = append(fields, f.Field)
+				}
+				if !reflect.DeepEqual(fields, tt.wantFields) {
+					t.Errorf("ID %v NewSubscription() invalid fields = %v, want %v", tt.id, fields, tt.wantFields)
+				}
+				return
+			}
+			if err != nil || !got.Active || !reflect.DeepEqual(got.Events, tt.wantEvents) {
+				t.Errorf("ID %v NewSubscription() = %+v, %v", tt.id, got, err)
+			}
+		})
+	}
+}
+
+func TestSubscription_Wants(t *testing.T) {
+	s, _ := NewSubscription("w1", "http://example.test", "0123456789abcdef", nil)
+	if !s.Wants("user.deleted") {
+		t.Errorf("Wants() = false for a subscription without event filter")
+	}
+	_ = s.Update(nil, nil, []string{"user.created"}, nil)
+	if s.Wants("user.deleted") || !s.Wants("user.created") {
+		t.Errorf("Wants() ignores the event filter %v", s.Events)
+	}
+	inactive := false
+	_ = s.Update(nil, nil, nil, &inactive)
+	if s.Wants("user.created") {
+		t.Errorf("Wants() = true for an inactive subscription")
+	}
+	if err := s.Update(nil, nil, []string{"user.renamed"}, nil); err == nil || !reflect.DeepEqual(s.Events, []domainEvent.Type{"user.created"}) {
+		t.Errorf("Update() with an unknown event = %v, events %v, want an error and no change", err, s.Events)
+	}
+}
+
+func TestPublicAddr(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"fd00::1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"0.0.0.0", false},
+		{"::ffff:127.0.0.1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			if got := PublicAddr(netip.MustParseAddr(tt.addr)); got != tt.want {
+				t.Errorf("PublicAddr(%s) = %v, want %v", tt.addr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDelivery_Failed(t *testing.T) {
+	at := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	b := Backoff{MaxAttempts: 5, Base: time.Second, Max: 3 * time.Second}
+	d := &Delivery{Status: DeliveryPending, NextAttemptAt: at}
+
+	wantWaits := []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second}
+	for i, want := range wantWaits {
+		d.Failed(at, 503, "unavailable", b)
+		if d.Status != DeliveryPending || d.NextAttemptAt.Sub(at) != want || d.Attempts != i+1 {
+			t.Fatalf("Failed() attempt %d = %+v, want pending and next attempt after %v", i+1, d, want)
+		}
+	}
+	d.Failed(at, 0, "connection refused", b)
+	if d.Status != DeliveryDead || d.Attempts != 5 || d.LastStatusCode != 0 || d.LastError != "connection refused" {
+		t.Errorf("Failed() last attempt = %+v, want dead", d)
+	}
+}
+
+func TestSignature(t *testing.T) {
+	at := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	body := []byte(`{"type":"user.created"}`)
+	header := Sign("0123456789abcdef", at, body)
+
+	tests := []struct {
+		id     string
+		secret string
+		header string
+		body   []byte
+		now    time.Time
+		want   bool
+	}{
+		{"valid", "0123456789abcdef", header, body, at.Add(time.Minute), true},
+		{"wrong secret", "fedcba9876543210", header, body, at, false},
+		{"tampered body", "0123456789abcdef", header, []byte(`{"type":"user.deleted"}`), at, false},
+		{"too old", "0123456789abcdef", header, body, at.Add(10 * time.Minute), false},
+		{"malformed header", "0123456789abcdef", "v1=abc", body, at, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			if got := Verify(tt.secret, tt.header, tt.body, tt.now, 5*time.Minute); got != tt.want {
+				t.Errorf("Verify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Crud-application/pkg/domain/mail"
+)
+
+var _ mail.Mailer = (*FileMailer)(nil)
+
+// FileMailer appends every email to a file in mbox format instead of sending it,
+// for dev setups that want to open the emails in a mail client
+type FileMailer struct {
+	mu   sync.Mutex
+	path string
+	from string
+	now  func() time.Time
+}
+
+func NewFileMailer(path, from string, now func() time.Time) *FileMailer {
+	return &FileMailer{path: path, from: from, now: now}
+}
+
+// Send appends the email to the file, creating it if needed
+func (m *FileMailer) Send(ctx context.Context, msg mail.Message) error {
+	at := m.now()
+	data, err := format(m.from, msg, at)
+	if err != nil {
+		return fmt.Errorf("failed to format email: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f, err := os.OpenFile(m.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open mail file: %w", err)
+	}
+	defer f.Close()
+	if _, err := fmt.Fprintf(f, "From crud %s\r\n%s\r\n", at.UTC().Format(time.ANSIC), data); err != nil {
+		return fmt.Errorf("failed to write email: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package mail
+
+import (
+	"context"
+	"log"
+
+	"github.com/Crud-application/pkg/domain/mail"
+)
+
+var _ mail.Mailer = (*LogMailer)(nil)
+
+// LogMailer writes every email to the log instead of sending it, for dev
+type LogMailer struct {
+	logger *log.Logger
+}
+
+// NewLogMailer creates a mailer writing to logger, or to the standard logger when it is nil
+func NewLogMailer(logger *log.Logger) *LogMailer {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &LogMailer{logger: logger}
+}
+
+// Send logs the email
+func (m *LogMailer) Send(ctx context.Context, msg mail.Message) error {
+	m.logger.Printf("Email to %s: %s\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
@@ -0,0 +1,110 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	netmail "net/mail"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"github.com/Crud-application/pkg/domain/mail"
+	"github.com/stretchr/testify/assert"
+)
+
+var testNow = time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+func testClock() time.Time { return testNow }
+
+var testMessage = mail.Message{
+	To:      "alam@example.com",
+	Subject: "Vérifiez votre email",
+	Body:    "Open this link:\nhttps://app.example.com/verify-email?token=abc=def",
+}
+
+func TestSMTPMailer_Send(t *testing.T) {
+	tests := []struct {
+		id       int
+		name     string
+		msg      mail.Message
+		sendErr  error
+		wantErr  bool
+		wantKind error
+	}{
+		{id: 1, name: "Sent - success", msg: testMessage},
+		{id: 2, name: "Server down - unavailable", msg: testMessage, sendErr: errors.New("connection refused"), wantErr: true, wantKind: domainErr.ErrUnavailable},
+		{id: 3, name: "Header injection in the recipient - failure", msg: mail.Message{To: "alam@example.com\r\nBcc: eve@example.com"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewSMTPMailer("smtp.example.com", 587, "crud", "secret", "Crud <no-reply@example.com>", testClock)
+			if err != nil {
+				t.Fatalf("NewSMTPMailer() error = %v", err)
+			}
+			var gotFrom string
+			var gotTo []string
+			var gotMsg []byte
+			m.sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+				assert.Equal(t, "smtp.example.com:587", addr)
+				assert.NotNil(t, a)
+				gotFrom, gotTo, gotMsg = from, to, msg
+				return tt.sendErr
+			}
+
+			err = m.Send(context.Background(), tt.msg)
+			if (err != nil) != tt.wantErr || (tt.wantKind != nil && !errors.Is(err, tt.wantKind)) {
+				t.Fatalf("ID %v Send() error = %v, wantErr %v", tt.id, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			assert.Equal(t, "no-reply@example.com", gotFrom)
+			assert.Equal(t, []string{"alam@example.com"}, gotTo)
+
+			parsed, err := netmail.ReadMessage(strings.NewReader(string(gotMsg)))
+			if err != nil {
+				t.Fatalf("ID %v sent an unreadable message: %v", tt.id, err)
+			}
+			subject, _ := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+			assert.Equal(t, testMessage.Subject, subject)
+			assert.Equal(t, `"Crud" <no-reply@example.com>`, parsed.Header.Get("From"))
+			body, _ := io.ReadAll(parsed.Body)
+			assert.Contains(t, string(body), "token=3Dabc=3Ddef") // Quoted-printable
+		})
+	}
+}
+
+func TestFileMailer_Send(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mail.mbox")
+	m := NewFileMailer(path, "no-reply@example.com", testClock)
+	for i := 0; i < 2; i++ {
+		if err := m.Send(context.Background(), testMessage); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(data), "From crud "); n != 2 {
+		t.Errorf("file holds %d emails, want 2", n)
+	}
+	assert.Contains(t, string(data), "To: alam@example.com\r\n")
+}
+
+func TestInMemoryMailer_Send(t *testing.T) {
+	m := NewInMemoryMailer()
+	_ = m.Send(context.Background(), testMessage)
+	got := m.Messages()
+	assert.Equal(t, []mail.Message{testMessage}, got)
+
+	got[0].To = "changed@example.com"
+	assert.Equal(t, testMessage, m.Messages()[0], "Messages() must return a copy")
+}
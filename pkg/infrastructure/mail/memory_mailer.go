@@ -0,0 +1,35 @@
+package mail
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Crud-application/pkg/domain/mail"
+)
+
+var _ mail.Mailer = (*InMemoryMailer)(nil)
+
+// InMemoryMailer keeps the emails it is asked to send, for tests
+type InMemoryMailer struct {
+	mu       sync.Mutex
+	messages []mail.Message
+}
+
+func NewInMemoryMailer() *InMemoryMailer {
+	return &InMemoryMailer{}
+}
+
+// Send keeps the email
+func (m *InMemoryMailer) Send(ctx context.Context, msg mail.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = append(m.messages, msg)
+	return nil
+}
+
+// Messages returns the emails sent so far, oldest first
+func (m *InMemoryMailer) Messages() []mail.Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]mail.Message(nil), m.messages...)
+}
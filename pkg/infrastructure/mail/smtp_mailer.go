@@ -0,0 +1,63 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	netmail "net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"github.com/Crud-application/pkg/domain/mail"
+)
+
+var _ mail.Mailer = (*SMTPMailer)(nil)
+
+// SMTPMailer sends emails through an SMTP server, upgrading to TLS when the server offers it
+type SMTPMailer struct {
+	addr     string
+	auth     smtp.Auth // Nil when no username is configured
+	from     string
+	envelope string // Bare address of from, for MAIL FROM
+	now      func() time.Time
+
+	// sendMail is smtp.SendMail, replaced in tests
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewSMTPMailer creates a mailer sending through host:port as from, a name and address such as
+// "Crud <no-reply@example.com>". PLAIN auth is used when a username is given.
+func NewSMTPMailer(host string, port int, username, password, from string, now func() time.Time) (*SMTPMailer, error) {
+	sender, err := netmail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender address %q: %w", from, err)
+	}
+	m := &SMTPMailer{
+		addr:     host + ":" + strconv.Itoa(port),
+		from:     sender.String(),
+		envelope: sender.Address,
+		now:      now,
+		sendMail: smtp.SendMail,
+	}
+	if username != "" {
+		m.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return m, nil
+}
+
+// Send delivers the email to the SMTP server
+func (m *SMTPMailer) Send(ctx context.Context, msg mail.Message) error {
+	to, err := netmail.ParseAddress(msg.To)
+	if err != nil {
+		return fmt.Errorf("invalid recipient address %q: %w", msg.To, err)
+	}
+	data, err := format(m.from, msg, m.now())
+	if err != nil {
+		return fmt.Errorf("failed to format email: %w", err)
+	}
+	if err := m.sendMail(m.addr, m.auth, m.envelope, []string{to.Address}, data); err != nil {
+		return domainErr.Unavailable(err, "failed to send email to %s", to.Address)
+	}
+	return nil
+}
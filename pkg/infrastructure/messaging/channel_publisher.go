@@ -0,0 +1,39 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Crud-application/pkg/domain/domainEvent"
+)
+
+var _ domainEvent.EventPublisher = (*ChannelPublisher)(nil)
+
+// ErrChannelFull reports an event dropped because nobody drained the channel
+var ErrChannelFull = errors.New("event channel is full")
+
+// ChannelPublisher hands events to in-process subscribers through a buffered channel
+type ChannelPublisher struct {
+	events chan domainEvent.Event
+}
+
+// NewChannelPublisher creates a publisher whose channel buffers up to size events
+func NewChannelPublisher(size int) *ChannelPublisher {
+	return &ChannelPublisher{events: make(chan domainEvent.Event, size)}
+}
+
+// Events is the channel the published events arrive on
+func (p *ChannelPublisher) Events() <-chan domainEvent.Event {
+	return p.events
+}
+
+// Publish sends the event without waiting: while the buffer is full the event is
+// dropped with ErrChannelFull, so a reader that falls behind cannot stall the relay
+func (p *ChannelPublisher) Publish(ctx context.Context, event domainEvent.Event) error {
+	select {
+	case p.events <- event:
+		return nil
+	default:
+		return ErrChannelFull
+	}
+}
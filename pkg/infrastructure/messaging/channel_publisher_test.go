@@ -0,0 +1,27 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Crud-application/pkg/domain/domainEvent"
+)
+
+func TestChannelPublisher_Publish(t *testing.T) {
+	p := NewChannelPublisher(1)
+	event := domainEvent.Event{ID: "e1", Type: "user.created"}
+
+	if err := p.Publish(context.Background(), event); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if got := <-p.Events(); got.ID != event.ID {
+		t.Errorf("Events() received %v want %v", got, event)
+	}
+
+	// With the buffer full, Publish drops the event rather than wait for a reader
+	_ = p.Publish(context.Background(), event)
+	if err := p.Publish(context.Background(), event); !errors.Is(err, ErrChannelFull) {
+		t.Errorf("Publish() on a full buffer error = %v, want ErrChannelFull", err)
+	}
+}
@@ -0,0 +1,98 @@
+package messaging
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Crud-application/pkg/domain/domainEvent"
+)
+
+var _ domainEvent.EventPublisher = (*EventBus)(nil)
+var _ domainEvent.Stream = (*EventBus)(nil)
+
+// EventBus fans published events out to in-process subscribers and keeps the
+// last events in a bounded buffer, so subscribers can resume where they left off
+type EventBus struct {
+	mu          sync.Mutex
+	buffer      []domainEvent.Event // The last bufferSize events, oldest first
+	buffered    map[string]bool     // IDs of the buffered events
+	bufferSize  int
+	subscribers map[chan domainEvent.Event]bool
+	backlog     int
+}
+
+// NewEventBus creates a bus replaying up to bufferSize events. Subscribers that
+// fall more than backlog events behind are dropped rather than slowing down publishers.
+func NewEventBus(bufferSize, backlog int) *EventBus {
+	return &EventBus{
+		buffered:    make(map[string]bool, bufferSize),
+		bufferSize:  bufferSize,
+		subscribers: make(map[chan domainEvent.Event]bool),
+		backlog:     backlog,
+	}
+}
+
+// Publish buffers the event and hands it to every subscriber. Events published
+// again after a relay failure are ignored while they are still buffered.
+func (b *EventBus) Publish(ctx context.Context, event domainEvent.Event) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.buffered[event.ID] {
+		return nil
+	}
+	if len(b.buffer) == b.bufferSize {
+		delete(b.buffered, b.buffer[0].ID)
+		b.buffer = b.buffer[1:]
+	}
+	b.buffer = append(b.buffer, event)
+	b.buffered[event.ID] = true
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+			// The subscriber fell behind; it resumes from its last event when it reconnects
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+	return nil
+}
+
+// Subscribe opens a subscription to the events published from now on, preceded by
+// the buffered events published after lastEventID
+func (b *EventBus) Subscribe(lastEventID string) (*domainEvent.Subscription, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var replay []domainEvent.Event
+	resumed := lastEventID == ""
+	if !resumed && b.buffered[lastEventID] {
+		for i, e := range b.buffer {
+			if e.ID == lastEventID {
+				replay = b.buffer[i+1:]
+				break
+			}
+		}
+		resumed = true
+	}
+
+	ch := make(chan domainEvent.Event, len(replay)+b.backlog)
+	for _, e := range replay {
+		ch <- e
+	}
+	b.subscribers[ch] = true
+
+	return &domainEvent.Subscription{
+		Events: ch,
+		Close: func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			if b.subscribers[ch] {
+				delete(b.subscribers, ch)
+				close(ch)
+			}
+		},
+	}, resumed
+}
@@ -0,0 +1,87 @@
+package messaging
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/Crud-application/pkg/domain/domainEvent"
+)
+
+// received drains the events already waiting on sub, reporting whether it was closed
+func received(sub *domainEvent.Subscription) (ids []string, closed bool) {
+	for {
+		select {
+		case e, ok := <-sub.Events:
+			if !ok {
+				return ids, true
+			}
+			ids = append(ids, e.ID)
+		default:
+			return ids, false
+		}
+	}
+}
+
+func TestEventBus_Subscribe(t *testing.T) {
+	ctx := context.Background()
+	bus := NewEventBus(3, 10)
+	for _, id := range []string{"e1", "e2", "e3", "e4"} {
+		_ = bus.Publish(ctx, domainEvent.Event{ID: id})
+	}
+
+	tests := []struct {
+		id          int
+		name        string
+		lastEventID string
+		want        []string
+		wantResumed bool
+	}{
+		{id: 1, name: "New subscriber gets live events only", lastEventID: "", want: nil, wantResumed: true},
+		{id: 2, name: "Buffered events after the last one are replayed", lastEventID: "e2", want: []string{"e3", "e4"}, wantResumed: true},
+		{id: 3, name: "Up to date subscriber gets nothing to replay", lastEventID: "e4", want: nil, wantResumed: true},
+		{id: 4, name: "Evicted last event cannot be resumed", lastEventID: "e1", want: nil, wantResumed: false},
+		{id: 5, name: "Unknown last event cannot be resumed", lastEventID: "nope", want: nil, wantResumed: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub, resumed := bus.Subscribe(tt.lastEventID)
+			defer sub.Close()
+
+			got, _ := received(sub)
+			if resumed != tt.wantResumed || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ID %v Subscribe(%q) replayed %v, %v want %v, %v", tt.id, tt.lastEventID, got, resumed, tt.want, tt.wantResumed)
+			}
+		})
+	}
+}
+
+func TestEventBus_Publish(t *testing.T) {
+	ctx := context.Background()
+	bus := NewEventBus(10, 2)
+	fast, _ := bus.Subscribe("")
+	slow, _ := bus.Subscribe("")
+	closed, _ := bus.Subscribe("")
+	closed.Close()
+
+	_ = bus.Publish(ctx, domainEvent.Event{ID: "e1"})
+	_ = bus.Publish(ctx, domainEvent.Event{ID: "e1"}) // Published again by the relay
+	if got, _ := received(fast); len(got) != 1 {
+		t.Errorf("Publish() of a duplicate delivered %v, want e1 once", got)
+	}
+
+	// slow never reads, so the third event overflows its backlog of two
+	for _, id := range []string{"e2", "e3"} {
+		_ = bus.Publish(ctx, domainEvent.Event{ID: id})
+	}
+	if got, _ := received(fast); len(got) != 2 {
+		t.Errorf("fast subscriber received %v, want e2 and e3", got)
+	}
+	if got, dropped := received(slow); !dropped || len(got) != 2 {
+		t.Errorf("slow subscriber received %v, dropped %v, want e1 and e2 then dropped", got, dropped)
+	}
+	if _, done := received(closed); !done {
+		t.Errorf("closed subscription is still open")
+	}
+	slow.Close() // Closing a dropped subscription again is harmless
+}
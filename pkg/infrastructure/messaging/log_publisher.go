@@ -0,0 +1,35 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/Crud-application/pkg/domain/domainEvent"
+)
+
+var _ domainEvent.EventPublisher = (*LogPublisher)(nil)
+
+// LogPublisher writes every event to the log as JSON
+type LogPublisher struct {
+	logger *log.Logger
+}
+
+// NewLogPublisher creates a publisher writing to logger, or to the standard logger when it is nil
+func NewLogPublisher(logger *log.Logger) *LogPublisher {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &LogPublisher{logger: logger}
+}
+
+// Publish logs the event
+func (p *LogPublisher) Publish(ctx context.Context, event domainEvent.Event) error {
+	data, err := json.Marshal(toMessage(event))
+	if err != nil {
+		return fmt.Errorf("failed to encode event %s: %w", event.ID, err)
+	}
+	p.logger.Printf("Event %s: %s", event.Type, data)
+	return nil
+}
@@ -0,0 +1,29 @@
+package messaging
+
+import (
+	"time"
+
+	"github.com/Crud-application/pkg/domain/domainEvent"
+)
+
+// Message is the wire format of a domain event, shared by the sinks so that
+// broker adapters such as NATS or Kafka publish the same JSON
+type Message struct {
+	ID          string         `json:"id"`
+	Type        string         `json:"type"`
+	AggregateID string         `json:"aggregate_id"`
+	Actor       string         `json:"actor"`
+	OccurredAt  time.Time      `json:"occurred_at"`
+	Payload     map[string]any `json:"payload"`
+}
+
+func toMessage(e domainEvent.Event) Message {
+	return Message{
+		ID:          e.ID,
+		Type:        string(e.Type),
+		AggregateID: e.AggregateID,
+		Actor:       e.Actor,
+		OccurredAt:  e.OccurredAt,
+		Payload:     e.Payload,
+	}
+}
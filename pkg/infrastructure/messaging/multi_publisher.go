@@ -0,0 +1,30 @@
+package messaging
+
+import (
+	"context"
+
+	"github.com/Crud-application/pkg/domain/domainEvent"
+)
+
+var _ domainEvent.EventPublisher = (*MultiPublisher)(nil)
+
+// MultiPublisher publishes every event to several publishers in turn
+type MultiPublisher struct {
+	publishers []domainEvent.EventPublisher
+}
+
+// NewMultiPublisher creates a publisher fanning events out to publishers
+func NewMultiPublisher(publishers ...domainEvent.EventPublisher) *MultiPublisher {
+	return &MultiPublisher{publishers: publishers}
+}
+
+// Publish stops at the first publisher that fails. The relay publishes the event
+// again later, so the publishers before it see it twice.
+func (p *MultiPublisher) Publish(ctx context.Context, event domainEvent.Event) error {
+	for _, pub := range p.publishers {
+		if err := pub.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package apiKey
+
+import (
+	"time"
+
+	"github.com/Crud-application/pkg/domain/authAgg"
+)
+
+// APIKey is the stored form of an API key
+type APIKey struct {
+	ID        string     `bson:"_id"`
+	Name      string     `bson:"name"`
+	Hash      string     `bson:"hash"`
+	Roles     []string   `bson:"roles"`
+	CreatedAt time.Time  `bson:"created_at"`
+	RevokedAt *time.Time `bson:"revoked_at,omitempty"`
+}
+
+func toAPIKeyModel(k *authAgg.APIKey) *APIKey {
+	return &APIKey{
+		ID:        k.ID,
+		Name:      k.Name,
+		Hash:      k.Hash,
+		Roles:     k.Roles,
+		CreatedAt: k.CreatedAt,
+		RevokedAt: k.RevokedAt,
+	}
+}
+
+func (m *APIKey) toAggregate() *authAgg.APIKey {
+	return &authAgg.APIKey{
+		ID:        m.ID,
+		Name:      m.Name,
+		Hash:      m.Hash,
+		Roles:     m.Roles,
+		CreatedAt: m.CreatedAt,
+		RevokedAt: m.RevokedAt,
+	}
+}
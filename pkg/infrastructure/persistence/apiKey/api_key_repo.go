@@ -0,0 +1,92 @@
+package apiKey
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/Crud-application/pkg/config"
+	"github.com/Crud-application/pkg/domain/authAgg"
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"github.com/Crud-application/pkg/infrastructure/persistence/mongoerr"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// store names the API key collection in unavailability errors
+const store = "API key store"
+
+// MongoAPIKeyRepository stores the hashes of API keys in a collection
+type MongoAPIKeyRepository struct {
+	client     *mongo.Client
+	database   string
+	collection string
+}
+
+// NewMongoAPIKeyRepository constructor that accepts the MongoDB client and its configuration
+func NewMongoAPIKeyRepository(client *mongo.Client, cfg config.MongoConfig) *MongoAPIKeyRepository {
+	return &MongoAPIKeyRepository{
+		client:     client,
+		database:   cfg.Database,
+		collection: cfg.APIKeyCollection,
+	}
+}
+
+func (r *MongoAPIKeyRepository) getCollection() *mongo.Collection {
+	return r.client.Database(r.database).Collection(r.collection)
+}
+
+// EnsureIndexes creates the index keys are looked up by. It is idempotent.
+func (r *MongoAPIKeyRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.getCollection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "hash", Value: 1}},
+		Options: options.Index().SetName("hash").SetUnique(true),
+	})
+	if err != nil {
+		log.Printf("Error creating API key indexes: %v", err)
+		return mongoerr.ToDomain(err, store)
+	}
+	return nil
+}
+
+// AddAPIKey inserts a new API key
+func (r *MongoAPIKeyRepository) AddAPIKey(ctx context.Context, k *authAgg.APIKey) error {
+	if _, err := r.getCollection().InsertOne(ctx, toAPIKeyModel(k)); err != nil {
+		log.Printf("Error inserting API key: %v", err)
+		if mongo.IsDuplicateKeyError(err) {
+			return domainErr.Conflict("API key %s already exists", k.ID)
+		}
+		return mongoerr.ToDomain(err, store)
+	}
+	return nil
+}
+
+// GetAPIKeyByHash returns the key with the given hash, revoked or not
+func (r *MongoAPIKeyRepository) GetAPIKeyByHash(ctx context.Context, hash string) (*authAgg.APIKey, error) {
+	var m APIKey
+	err := r.getCollection().FindOne(ctx, bson.M{"hash": hash}).Decode(&m)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domainErr.NotFound("API key not found")
+	}
+	if err != nil {
+		return nil, mongoerr.ToDomain(err, store)
+	}
+	return m.toAggregate(), nil
+}
+
+// RevokeAPIKey marks the key with the given ID revoked, keeping the time it was first revoked
+func (r *MongoAPIKeyRepository) RevokeAPIKey(ctx context.Context, id string, at time.Time) error {
+	res, err := r.getCollection().UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.A{bson.M{"$set": bson.M{"revoked_at": bson.M{"$ifNull": bson.A{"$revoked_at", at}}}}},
+	)
+	if err != nil {
+		return mongoerr.ToDomain(err, store)
+	}
+	if res.MatchedCount == 0 {
+		return domainErr.NotFound("API key %s not found", id)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package apiKey
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Crud-application/pkg/config"
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestMongoAPIKeyRepository_GetAPIKeyByHash(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	revokedAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		id         int
+		name       string
+		beforeTest func(mt *mtest.T)
+		wantActive bool
+		wantErr    error
+	}{
+		{
+			id:   1,
+			name: "Active key found - Success",
+			beforeTest: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateCursorResponse(0, "crud.api_keys", mtest.FirstBatch, bson.D{
+					{Key: "_id", Value: "k1"},
+					{Key: "name", Value: "ci"},
+					{Key: "hash", Value: "abc"},
+					{Key: "roles", Value: bson.A{"admin"}},
+				}))
+			},
+			wantActive: true,
+		},
+		{
+			id:   2,
+			name: "Revoked key found - Success",
+			beforeTest: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateCursorResponse(0, "crud.api_keys", mtest.FirstBatch, bson.D{
+					{Key: "_id", Value: "k1"},
+					{Key: "hash", Value: "abc"},
+					{Key: "revoked_at", Value: revokedAt},
+				}))
+			},
+			wantActive: false,
+		},
+		{
+			id:   3,
+			name: "Unknown hash - NotFound",
+			beforeTest: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateCursorResponse(0, "crud.api_keys", mtest.FirstBatch))
+			},
+			wantErr: domainErr.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			r := NewMongoAPIKeyRepository(mt.Client, config.Default().Mongo)
+			tt.beforeTest(mt)
+
+			got, err := r.GetAPIKeyByHash(context.Background(), "abc")
+			if !errors.Is(err, tt.wantErr) {
+				mt.Fatalf("ID %v GetAPIKeyByHash() error = %v, wantErr %v", tt.id, err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (got.ID != "k1" || got.Active() != tt.wantActive) {
+				mt.Errorf("ID %v GetAPIKeyByHash() = %+v, want active %v", tt.id, got, tt.wantActive)
+			}
+		})
+	}
+}
+
+func TestMongoAPIKeyRepository_RevokeAPIKey(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	tests := []struct {
+		id         int
+		name       string
+		beforeTest func(mt *mtest.T)
+		wantErr    error
+	}{
+		{
+			id:   1,
+			name: "Key revoked - Success",
+			beforeTest: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+			},
+		},
+		{
+			id:   2,
+			name: "Unknown key - NotFound",
+			beforeTest: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}, bson.E{Key: "nModified", Value: 0}))
+			},
+			wantErr: domainErr.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			r := NewMongoAPIKeyRepository(mt.Client, config.Default().Mongo)
+			tt.beforeTest(mt)
+
+			err := r.RevokeAPIKey(context.Background(), "k1", time.Now())
+			if !errors.Is(err, tt.wantErr) {
+				mt.Errorf("ID %v RevokeAPIKey() error = %v, wantErr %v", tt.id, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package apiKey
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Crud-application/pkg/domain/authAgg"
+	"github.com/Crud-application/pkg/domain/domainErr"
+)
+
+// InMemoryAPIKeyRepository is a thread-safe, process-local store of API keys
+// that mirrors MongoAPIKeyRepository for development and CI
+type InMemoryAPIKeyRepository struct {
+	mu   sync.RWMutex
+	keys map[string]APIKey // By ID
+}
+
+// NewInMemoryAPIKeyRepository creates an empty in-memory API key store
+func NewInMemoryAPIKeyRepository() *InMemoryAPIKeyRepository {
+	return &InMemoryAPIKeyRepository{keys: make(map[string]APIKey)}
+}
+
+// AddAPIKey stores a new API key, rejecting duplicate IDs and hashes
+func (r *InMemoryAPIKeyRepository) AddAPIKey(ctx context.Context, k *authAgg.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, m := range r.keys {
+		if m.ID == k.ID || m.Hash == k.Hash {
+			return domainErr.Conflict("API key %s already exists", k.ID)
+		}
+	}
+	r.keys[k.ID] = *toAPIKeyModel(k)
+	return nil
+}
+
+// GetAPIKeyByHash returns the key with the given hash, revoked or not
+func (r *InMemoryAPIKeyRepository) GetAPIKeyByHash(ctx context.Context, hash string) (*authAgg.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, m := range r.keys {
+		if m.Hash == hash {
+			return m.toAggregate(), nil
+		}
+	}
+	return nil, domainErr.NotFound("API key not found")
+}
+
+// RevokeAPIKey marks the key with the given ID revoked, keeping the time it was first revoked
+func (r *InMemoryAPIKeyRepository) RevokeAPIKey(ctx context.Context, id string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m, ok := r.keys[id]
+	if !ok {
+		return domainErr.NotFound("API key %s not found", id)
+	}
+	if m.RevokedAt == nil {
+		m.RevokedAt = &at
+		r.keys[id] = m
+	}
+	return nil
+}
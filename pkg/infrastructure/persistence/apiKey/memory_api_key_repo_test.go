@@ -0,0 +1,42 @@
+package apiKey
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Crud-application/pkg/domain/authAgg"
+	"github.com/Crud-application/pkg/domain/domainErr"
+)
+
+func TestInMemoryAPIKeyRepository(t *testing.T) {
+	ctx := context.Background()
+	r := NewInMemoryAPIKeyRepository()
+	first := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	k := &authAgg.APIKey{ID: "k1", Name: "ci", Hash: "abc", Roles: []string{"admin"}}
+	if err := r.AddAPIKey(ctx, k); err != nil {
+		t.Fatalf("AddAPIKey() error = %v", err)
+	}
+	if err := r.AddAPIKey(ctx, &authAgg.APIKey{ID: "k2", Hash: "abc"}); !errors.Is(err, domainErr.ErrConflict) {
+		t.Errorf("AddAPIKey() with a duplicate hash error = %v, want conflict", err)
+	}
+
+	got, err := r.GetAPIKeyByHash(ctx, "abc")
+	if err != nil || got.ID != "k1" || !got.Active() {
+		t.Errorf("GetAPIKeyByHash() = %+v, %v, want the active key k1", got, err)
+	}
+	if _, err := r.GetAPIKeyByHash(ctx, "def"); !errors.Is(err, domainErr.ErrNotFound) {
+		t.Errorf("GetAPIKeyByHash() of an unknown hash error = %v, want not found", err)
+	}
+
+	_ = r.RevokeAPIKey(ctx, "k1", first)
+	_ = r.RevokeAPIKey(ctx, "k1", first.Add(time.Hour))
+	if got, _ := r.GetAPIKeyByHash(ctx, "abc"); got.Active() || !got.RevokedAt.Equal(first) {
+		t.Errorf("GetAPIKeyByHash() after revoking = %+v, want revoked at %v", got, first)
+	}
+	if err := r.RevokeAPIKey(ctx, "k2", first); !errors.Is(err, domainErr.ErrNotFound) {
+		t.Errorf("RevokeAPIKey() of an unknown key error = %v, want not found", err)
+	}
+}
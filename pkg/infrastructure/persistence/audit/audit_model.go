@@ -0,0 +1,80 @@
+package audit
+
+import (
+	"time"
+
+	"github.com/Crud-application/pkg/domain/auditAgg"
+	"github.com/Crud-application/pkg/domain/query"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Entry is the stored form of an audit entry. ObjectIDs grow over time,
+// so ordering by _id lists entries in the order they were added.
+type Entry struct {
+	ID         primitive.ObjectID `bson:"_id"`
+	ResourceID string             `bson:"resource_id"`
+	Action     string             `bson:"action"`
+	Actor      string             `bson:"actor"`
+	RequestID  string             `bson:"request_id,omitempty"`
+	Timestamp  time.Time          `bson:"timestamp"`
+	Changes    []FieldChange      `bson:"changes"`
+}
+
+// FieldChange is the stored form of a field change
+type FieldChange struct {
+	Field  string `bson:"field"`
+	Before any    `bson:"before"`
+	After  any    `bson:"after"`
+}
+
+func toEntryModel(id primitive.ObjectID, e *auditAgg.Entry) *Entry {
+	m := &Entry{
+		ID:         id,
+		ResourceID: e.ResourceID,
+		Action:     string(e.Action),
+		Actor:      e.Actor,
+		RequestID:  e.RequestID,
+		Timestamp:  e.Timestamp,
+		Changes:    make([]FieldChange, 0, len(e.Changes)),
+	}
+	for _, c := range e.Changes {
+		m.Changes = append(m.Changes, FieldChange{Field: c.Field, Before: c.Before, After: c.After})
+	}
+	return m
+}
+
+func (m *Entry) toAggregate() auditAgg.Entry {
+	e := auditAgg.Entry{
+		ID:         m.ID.Hex(),
+		ResourceID: m.ResourceID,
+		Action:     auditAgg.Action(m.Action),
+		Actor:      m.Actor,
+		RequestID:  m.RequestID,
+		Timestamp:  m.Timestamp,
+	}
+	for _, c := range m.Changes {
+		e.Changes = append(e.Changes, auditAgg.FieldChange{Field: c.Field, Before: c.Before, After: c.After})
+	}
+	return e
+}
+
+// toEntryPage trims the extra look-ahead entry fetched beyond the page
+// limit and uses it to decide whether there is a next page
+func toEntryPage(entries []Entry, page query.PageRequest, total int64) *auditAgg.EntryPage {
+	res := &auditAgg.EntryPage{
+		Entries: make([]auditAgg.Entry, 0, len(entries)),
+		Page: query.PageInfo{
+			Total:  total,
+			Limit:  page.Limit,
+			Offset: page.Offset,
+		},
+	}
+	if len(entries) > page.Limit {
+		entries = entries[:page.Limit]
+		res.Page.NextCursor = entries[len(entries)-1].ID.Hex()
+	}
+	for _, e := range entries {
+		res.Entries = append(res.Entries, e.toAggregate())
+	}
+	return res
+}
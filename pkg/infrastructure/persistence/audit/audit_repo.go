@@ -0,0 +1,114 @@
+package audit
+
+import (
+	"context"
+	"log"
+
+	"github.com/Crud-application/pkg/config"
+	"github.com/Crud-application/pkg/domain/auditAgg"
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"github.com/Crud-application/pkg/domain/query"
+	"github.com/Crud-application/pkg/infrastructure/persistence/mongoerr"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// store names the audit log in unavailability errors
+const store = "audit store"
+
+// MongoAuditRepository appends audit entries to their own collection
+type MongoAuditRepository struct {
+	client     *mongo.Client
+	database   string
+	collection string
+}
+
+// NewMongoAuditRepository constructor that accepts the MongoDB client and its configuration
+func NewMongoAuditRepository(client *mongo.Client, cfg config.MongoConfig) *MongoAuditRepository {
+	return &MongoAuditRepository{
+		client:     client,
+		database:   cfg.Database,
+		collection: cfg.AuditCollection,
+	}
+}
+
+func (r *MongoAuditRepository) auditCollection() *mongo.Collection {
+	return r.client.Database(r.database).Collection(r.collection)
+}
+
+// EnsureIndexes creates the index history lookups rely on. It is idempotent.
+func (r *MongoAuditRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.auditCollection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "resource_id", Value: 1}, {Key: "_id", Value: -1}},
+		Options: options.Index().SetName("resource_history"),
+	})
+	if err != nil {
+		log.Printf("Error creating audit indexes: %v", err)
+		return mongoerr.ToDomain(err, store)
+	}
+	return nil
+}
+
+// AddEntry inserts entry with a new ObjectID
+func (r *MongoAuditRepository) AddEntry(ctx context.Context, entry *auditAgg.Entry) error {
+	id := primitive.NewObjectID()
+	if _, err := r.auditCollection().InsertOne(ctx, toEntryModel(id, entry)); err != nil {
+		log.Printf("Error inserting audit entry: %v", err)
+		return mongoerr.ToDomain(err, store)
+	}
+	entry.ID = id.Hex()
+	return nil
+}
+
+// AddEntries inserts entries with new ObjectIDs. Pass the session context of a
+// transaction to store them atomically with the change they record.
+func (r *MongoAuditRepository) AddEntries(ctx context.Context, entries []auditAgg.Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	docs := make([]any, 0, len(entries))
+	for i := range entries {
+		docs = append(docs, toEntryModel(primitive.NewObjectID(), &entries[i]))
+	}
+	if _, err := r.auditCollection().InsertMany(ctx, docs); err != nil {
+		log.Printf("Error inserting audit entries: %v", err)
+		return mongoerr.ToDomain(err, store)
+	}
+	return nil
+}
+
+// GetEntries returns one page of the entries of a resource, newest first
+func (r *MongoAuditRepository) GetEntries(ctx context.Context, resourceID string, page query.PageRequest) (*auditAgg.EntryPage, error) {
+	filter := bson.M{"resource_id": resourceID}
+
+	pageFilter := filter
+	if page.Cursor != "" {
+		cursorID, err := primitive.ObjectIDFromHex(page.Cursor)
+		if err != nil {
+			return nil, domainErr.Validation("invalid query", domainErr.FieldError{Field: "cursor", Message: "is malformed"})
+		}
+		pageFilter = bson.M{"resource_id": resourceID, "_id": bson.M{"$lt": cursorID}}
+	}
+	// Fetch one extra entry to know whether another page follows
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: -1}}).
+		SetSkip(int64(page.Offset)).
+		SetLimit(int64(page.Limit + 1))
+
+	cursor, err := r.auditCollection().Find(ctx, pageFilter, opts)
+	if err != nil {
+		return nil, mongoerr.ToDomain(err, store)
+	}
+	var entries []Entry
+	if err = cursor.All(ctx, &entries); err != nil {
+		return nil, mongoerr.ToDomain(err, store)
+	}
+
+	total, err := r.auditCollection().CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, mongoerr.ToDomain(err, store)
+	}
+	return toEntryPage(entries, page, total), nil
+}
@@ -0,0 +1,125 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Crud-application/pkg/config"
+	"github.com/Crud-application/pkg/domain/auditAgg"
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"github.com/Crud-application/pkg/domain/query"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestMongoAuditRepository_AddEntry(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	mt.Run("Entry added - Success", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+		repo := NewMongoAuditRepository(mt.Client, config.Default().Mongo)
+
+		entry := &auditAgg.Entry{
+			ResourceID: "u1",
+			Action:     auditAgg.ActionUpdate,
+			Changes:    []auditAgg.FieldChange{{Field: "name", Before: "Old", After: "New"}},
+		}
+		if err := repo.AddEntry(context.Background(), entry); err != nil {
+			mt.Fatalf("AddEntry() error = %v", err)
+		}
+		if _, err := primitive.ObjectIDFromHex(entry.ID); err != nil {
+			mt.Errorf("AddEntry() id = %q, want an ObjectID", entry.ID)
+		}
+		if got := mt.GetStartedEvent().Command.Lookup("insert").StringValue(); got != config.Default().Mongo.AuditCollection {
+			mt.Errorf("AddEntry() inserted into %q, want the audit collection", got)
+		}
+	})
+}
+
+func TestMongoAuditRepository_AddEntries(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	mt.Run("Entries added in one insert - Success", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+		repo := NewMongoAuditRepository(mt.Client, config.Default().Mongo)
+
+		entries := []auditAgg.Entry{{ResourceID: "u1", Action: auditAgg.ActionUpdate}, {ResourceID: "u1", Action: auditAgg.ActionSuspend}}
+		if err := repo.AddEntries(context.Background(), entries); err != nil {
+			mt.Fatalf("AddEntries() error = %v", err)
+		}
+		docs, _ := mt.GetStartedEvent().Command.Lookup("documents").Array().Values()
+		if len(docs) != len(entries) {
+			mt.Errorf("AddEntries() inserted %d documents, want %d", len(docs), len(entries))
+		}
+	})
+	mt.Run("No entries - Success", func(mt *mtest.T) {
+		repo := NewMongoAuditRepository(mt.Client, config.Default().Mongo)
+		if err := repo.AddEntries(context.Background(), nil); err != nil {
+			mt.Fatalf("AddEntries() error = %v", err)
+		}
+		if started := mt.GetStartedEvent(); started != nil {
+			mt.Errorf("AddEntries() ran %s, want no command", started.CommandName)
+		}
+	})
+}
+
+func TestMongoAuditRepository_GetEntries(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	ns := "crud.user_audit"
+	ids := []primitive.ObjectID{primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()}
+	at := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	doc := func(id primitive.ObjectID, action string) bson.D {
+		return bson.D{{Key: "_id", Value: id}, {Key: "resource_id", Value: "u1"}, {Key: "action", Value: action}, {Key: "timestamp", Value: at}}
+	}
+
+	tests := []struct {
+		id          int
+		name        string
+		beforeTest  func(mt *mtest.T)
+		page        query.PageRequest
+		wantEntries int
+		wantNext    string
+		wantErr     error
+	}{
+		{
+			id:   1,
+			name: "Page with a next cursor - Success",
+			beforeTest: func(mt *mtest.T) {
+				mt.AddMockResponses(
+					mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, doc(ids[2], "delete"), doc(ids[1], "update"), doc(ids[0], "create")),
+					mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{{Key: "n", Value: 3}}),
+				)
+			},
+			page:        query.PageRequest{Limit: 2},
+			wantEntries: 2,
+			wantNext:    ids[1].Hex(),
+		},
+		{
+			id:      2,
+			name:    "Malformed cursor - Failure",
+			page:    query.PageRequest{Limit: 2, Cursor: "not-an-id"},
+			wantErr: domainErr.ErrValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			repo := NewMongoAuditRepository(mt.Client, config.Default().Mongo)
+			if tt.beforeTest != nil {
+				tt.beforeTest(mt)
+			}
+
+			got, err := repo.GetEntries(context.Background(), "u1", tt.page)
+			if !errors.Is(err, tt.wantErr) {
+				mt.Fatalf("Test Case ID: %v | GetEntries() error = %v, wantErr = %v", tt.id, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(got.Entries) != tt.wantEntries || got.Page.NextCursor != tt.wantNext || got.Page.Total != 3 {
+				mt.Errorf("Test Case ID: %v | GetEntries() = %+v, want %d entries and next cursor %q", tt.id, got, tt.wantEntries, tt.wantNext)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package audit
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Crud-application/pkg/domain/auditAgg"
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"github.com/Crud-application/pkg/domain/query"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// InMemoryAuditRepository is a thread-safe, process-local audit log that
+// mirrors MongoAuditRepository for development and CI
+type InMemoryAuditRepository struct {
+	mu      sync.RWMutex
+	entries []Entry // In the order they were added
+}
+
+// NewInMemoryAuditRepository creates an empty in-memory audit log
+func NewInMemoryAuditRepository() *InMemoryAuditRepository {
+	return &InMemoryAuditRepository{}
+}
+
+// AddEntry appends entry with a new ObjectID
+func (r *InMemoryAuditRepository) AddEntry(ctx context.Context, entry *auditAgg.Entry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := primitive.NewObjectID()
+	r.entries = append(r.entries, *toEntryModel(id, entry))
+	entry.ID = id.Hex()
+	return nil
+}
+
+// AddEntries appends entries with new ObjectIDs
+func (r *InMemoryAuditRepository) AddEntries(ctx context.Context, entries []auditAgg.Entry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range entries {
+		r.entries = append(r.entries, *toEntryModel(primitive.NewObjectID(), &entries[i]))
+	}
+	return nil
+}
+
+// GetEntries returns one page of the entries of a resource, newest first
+func (r *InMemoryAuditRepository) GetEntries(ctx context.Context, resourceID string, page query.PageRequest) (*auditAgg.EntryPage, error) {
+	var cursorID primitive.ObjectID
+	if page.Cursor != "" {
+		var err error
+		if cursorID, err = primitive.ObjectIDFromHex(page.Cursor); err != nil {
+			return nil, domainErr.Validation("invalid query", domainErr.FieldError{Field: "cursor", Message: "is malformed"})
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var entries []Entry
+	var total int64
+	for i := len(r.entries) - 1; i >= 0; i-- {
+		e := r.entries[i]
+		if e.ResourceID != resourceID {
+			continue
+		}
+		total++
+		if page.Cursor == "" || e.ID.Hex() < cursorID.Hex() {
+			entries = append(entries, e)
+		}
+	}
+	entries = entries[min(page.Offset, len(entries)):]
+	entries = entries[:min(page.Limit+1, len(entries))]
+
+	return toEntryPage(entries, page, total), nil
+}
@@ -0,0 +1,93 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Crud-application/pkg/domain/auditAgg"
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"github.com/Crud-application/pkg/domain/query"
+)
+
+func TestInMemoryAuditRepository_GetEntries(t *testing.T) {
+	ctx := context.Background()
+	r := NewInMemoryAuditRepository()
+	at := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	for _, e := range []*auditAgg.Entry{
+		{ResourceID: "u1", Action: auditAgg.ActionCreate, Timestamp: at},
+		{ResourceID: "u2", Action: auditAgg.ActionCreate, Timestamp: at},
+		{ResourceID: "u1", Action: auditAgg.ActionUpdate, Timestamp: at.Add(time.Minute)},
+		{ResourceID: "u1", Action: auditAgg.ActionDelete, Timestamp: at.Add(2 * time.Minute)},
+	} {
+		if err := r.AddEntry(ctx, e); err != nil || e.ID == "" {
+			t.Fatalf("AddEntry() id = %q, error = %v", e.ID, err)
+		}
+	}
+
+	tests := []struct {
+		id          int
+		name        string
+		page        query.PageRequest
+		wantActions []auditAgg.Action
+		wantNext    bool
+		wantErr     error
+	}{
+		{
+			id:          1,
+			name:        "First page newest first - Success",
+			page:        query.PageRequest{Limit: 2},
+			wantActions: []auditAgg.Action{auditAgg.ActionDelete, auditAgg.ActionUpdate},
+			wantNext:    true,
+		},
+		{
+			id:          2,
+			name:        "Offset past the first entries - Success",
+			page:        query.PageRequest{Limit: 2, Offset: 2},
+			wantActions: []auditAgg.Action{auditAgg.ActionCreate},
+		},
+		{
+			id:      3,
+			name:    "Malformed cursor - Failure",
+			page:    query.PageRequest{Limit: 2, Cursor: "not-an-id"},
+			wantErr: domainErr.ErrValidation,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.GetEntries(ctx, "u1", tt.page)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ID %v GetEntries() error = %v, wantErr %v", tt.id, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.Page.Total != 3 {
+				t.Errorf("ID %v GetEntries() total = %d, want 3", tt.id, got.Page.Total)
+			}
+			var actions []auditAgg.Action
+			for _, e := range got.Entries {
+				actions = append(actions, e.Action)
+			}
+			if len(actions) != len(tt.wantActions) {
+				t.Fatalf("ID %v GetEntries() actions = %v, want %v", tt.id, actions, tt.wantActions)
+			}
+			for i := range actions {
+				if actions[i] != tt.wantActions[i] {
+					t.Errorf("ID %v GetEntries() actions = %v, want %v", tt.id, actions, tt.wantActions)
+				}
+			}
+			if (got.Page.NextCursor != "") != tt.wantNext {
+				t.Errorf("ID %v GetEntries() next cursor = %q, want one: %v", tt.id, got.Page.NextCursor, tt.wantNext)
+			}
+		})
+	}
+
+	// Following the cursor continues after the last entry of the first page
+	first, _ := r.GetEntries(ctx, "u1", query.PageRequest{Limit: 2})
+	next, err := r.GetEntries(ctx, "u1", query.PageRequest{Limit: 2, Cursor: first.Page.NextCursor})
+	if err != nil || len(next.Entries) != 1 || next.Entries[0].Action != auditAgg.ActionCreate {
+		t.Errorf("GetEntries() after cursor = %+v, error = %v, want the create entry", next, err)
+	}
+}
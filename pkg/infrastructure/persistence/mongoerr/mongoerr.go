@@ -0,0 +1,27 @@
+// Package mongoerr translates the MongoDB driver errors shared by every repository
+package mongoerr
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/topology"
+)
+
+// ToDomain reports err as the given store being unavailable when MongoDB could not be
+// reached in time. Other errors are returned unchanged.
+func ToDomain(err error, store string) error {
+	var serverSelectionErr topology.ServerSelectionError
+	switch {
+	case err == nil:
+		return nil
+	case mongo.IsNetworkError(err), mongo.IsTimeout(err),
+		errors.As(err, &serverSelectionErr), errors.Is(err, mongo.ErrClientDisconnected),
+		errors.Is(err, context.DeadlineExceeded):
+		return domainErr.Unavailable(err, "%s unavailable", store)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,37 @@
+package mongoerr
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestToDomain(t *testing.T) {
+	other := errors.New("bad query")
+	tests := []struct {
+		id      int
+		name    string
+		err     error
+		wantErr error
+	}{
+		{id: 1, name: "No error", err: nil, wantErr: nil},
+		{id: 2, name: "Deadline exceeded - unavailable", err: context.DeadlineExceeded, wantErr: domainErr.ErrUnavailable},
+		{id: 3, name: "Client disconnected - unavailable", err: mongo.ErrClientDisconnected, wantErr: domainErr.ErrUnavailable},
+		{id: 4, name: "Other error - unchanged", err: other, wantErr: other},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ToDomain(tt.err, "audit store")
+			if !errors.Is(got, tt.wantErr) || (tt.wantErr == nil && got != nil) {
+				t.Errorf("ID %v ToDomain() = %v, want %v", tt.id, got, tt.wantErr)
+			}
+		})
+	}
+	if got := ToDomain(context.DeadlineExceeded, "audit store"); !strings.HasPrefix(got.Error(), "audit store unavailable") {
+		t.Errorf("ToDomain() message = %q, want it to name the store", got.Error())
+	}
+}
@@ -0,0 +1,55 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/Crud-application/pkg/domain/domainEvent"
+	"github.com/Crud-application/pkg/infrastructure/persistence/mongoerr"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// insertedEvent is the part of a change stream document Watch reads
+type insertedEvent struct {
+	FullDocument Event `bson:"fullDocument"`
+}
+
+// Watch publishes the events any instance stores in the outbox as soon as their
+// transaction commits, until ctx is done or the change stream fails. A later call
+// resumes after the last event seen. Change streams need MongoDB to run as a replica set.
+func (r *MongoOutboxRepository) Watch(ctx context.Context, publisher domainEvent.EventPublisher) error {
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: bson.M{"operationType": "insert"}}}}
+	opts := options.ChangeStream()
+	r.mu.Lock()
+	if r.resumeToken != nil {
+		opts.SetResumeAfter(r.resumeToken)
+	}
+	r.mu.Unlock()
+
+	stream, err := r.outboxCollection().Watch(ctx, pipeline, opts)
+	if err != nil {
+		return mongoerr.ToDomain(err, store)
+	}
+	defer stream.Close(context.Background())
+	log.Printf("Watching the outbox for new events")
+
+	for stream.Next(ctx) {
+		var change insertedEvent
+		if err := stream.Decode(&change); err != nil {
+			return fmt.Errorf("failed to decode outbox change: %w", err)
+		}
+		if err := publisher.Publish(ctx, change.FullDocument.toDomain()); err != nil {
+			return fmt.Errorf("failed to publish event %s: %w", change.FullDocument.ID.Hex(), err)
+		}
+		r.mu.Lock()
+		r.resumeToken = stream.ResumeToken()
+		r.mu.Unlock()
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+	return mongoerr.ToDomain(stream.Err(), store)
+}
@@ -0,0 +1,51 @@
+package outbox
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Crud-application/pkg/config"
+	"github.com/Crud-application/pkg/domain/domainEvent"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// publisherFunc adapts a function to domainEvent.EventPublisher
+type publisherFunc func(ctx context.Context, event domainEvent.Event) error
+
+func (f publisherFunc) Publish(ctx context.Context, event domainEvent.Event) error {
+	return f(ctx, event)
+}
+
+func TestMongoOutboxRepository_Watch(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	mt.Run("Inserted events are published - Success", func(mt *mtest.T) {
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(mtest.CreateCursorResponse(1, "crud.outbox", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: bson.D{{Key: "_data", Value: "token-1"}}},
+			{Key: "operationType", Value: "insert"},
+			{Key: "fullDocument", Value: bson.D{
+				{Key: "_id", Value: id},
+				{Key: "type", Value: "user.created"},
+				{Key: "aggregate_id", Value: "u1"},
+			}},
+		}))
+		r := NewMongoOutboxRepository(mt.Client, config.Default().Mongo)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		var got []domainEvent.Event
+		err := r.Watch(ctx, publisherFunc(func(ctx context.Context, e domainEvent.Event) error {
+			got = append(got, e)
+			cancel() // Stop watching after the first event
+			return nil
+		}))
+
+		if err != nil || len(got) != 1 || got[0].ID != id.Hex() || got[0].Type != "user.created" {
+			mt.Errorf("Watch() published %v, %v", got, err)
+		}
+		if r.resumeToken == nil {
+			mt.Errorf("Watch() did not keep the resume token")
+		}
+	})
+}
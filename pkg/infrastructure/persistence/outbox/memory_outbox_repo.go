@@ -0,0 +1,62 @@
+package outbox
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"github.com/Crud-application/pkg/domain/domainEvent"
+)
+
+// InMemoryOutboxRepository is a thread-safe, process-local outbox that
+// mirrors MongoOutboxRepository for development and CI
+type InMemoryOutboxRepository struct {
+	mu     sync.Mutex
+	events []*Event // In the order they were added
+}
+
+// NewInMemoryOutboxRepository creates an empty in-memory outbox
+func NewInMemoryOutboxRepository() *InMemoryOutboxRepository {
+	return &InMemoryOutboxRepository{}
+}
+
+// AddEvents stores events as pending
+func (r *InMemoryOutboxRepository) AddEvents(ctx context.Context, events []domainEvent.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, toEventModels(events)...)
+	return nil
+}
+
+// GetPending returns up to limit unpublished events, oldest first
+func (r *InMemoryOutboxRepository) GetPending(ctx context.Context, limit int) ([]domainEvent.Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var events []domainEvent.Event
+	for _, e := range r.events {
+		if len(events) == limit {
+			break
+		}
+		if e.PublishedAt == nil {
+			events = append(events, e.toDomain())
+		}
+	}
+	return events, nil
+}
+
+// MarkPublished records that the event was published at the given time
+func (r *InMemoryOutboxRepository) MarkPublished(ctx context.Context, eventID string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, e := range r.events {
+		if e.ID.Hex() == eventID {
+			e.PublishedAt = &at
+			return nil
+		}
+	}
+	return domainErr.NotFound("event %s not found", eventID)
+}
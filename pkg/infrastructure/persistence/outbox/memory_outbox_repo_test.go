@@ -0,0 +1,39 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"github.com/Crud-application/pkg/domain/domainEvent"
+)
+
+func TestInMemoryOutboxRepository(t *testing.T) {
+	ctx := context.Background()
+	r := NewInMemoryOutboxRepository()
+	at := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	_ = r.AddEvents(ctx, []domainEvent.Event{
+		{Type: "user.created", AggregateID: "u1"},
+		{Type: "user.updated", AggregateID: "u1"},
+	})
+	_ = r.AddEvents(ctx, []domainEvent.Event{{Type: "user.deleted", AggregateID: "u1"}})
+
+	first, err := r.GetPending(ctx, 2)
+	if err != nil || len(first) != 2 || first[0].Type != "user.created" || first[1].Type != "user.updated" {
+		t.Fatalf("GetPending() = %v, %v want the first two events in order", first, err)
+	}
+	if err := r.MarkPublished(ctx, first[0].ID, at); err != nil {
+		t.Fatalf("MarkPublished() error = %v", err)
+	}
+
+	rest, _ := r.GetPending(ctx, 10)
+	if len(rest) != 2 || rest[0].ID != first[1].ID || rest[1].Type != "user.deleted" {
+		t.Errorf("GetPending() after publishing = %v want the last two events", rest)
+	}
+	if err := r.MarkPublished(ctx, "unknown", at); !errors.Is(err, domainErr.ErrNotFound) {
+		t.Errorf("MarkPublished() of an unknown event error = %v, want not found", err)
+	}
+}
@@ -0,0 +1,46 @@
+package outbox
+
+import (
+	"time"
+
+	"github.com/Crud-application/pkg/domain/domainEvent"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Event is the stored form of a domain event waiting to be published.
+// ObjectIDs grow over time, so ordering by _id keeps the events in order.
+type Event struct {
+	ID          primitive.ObjectID `bson:"_id"`
+	Type        string             `bson:"type"`
+	AggregateID string             `bson:"aggregate_id"`
+	Actor       string             `bson:"actor"`
+	OccurredAt  time.Time          `bson:"occurred_at"`
+	Payload     map[string]any     `bson:"payload"`
+	PublishedAt *time.Time         `bson:"published_at"` // nil until the relay published the event
+}
+
+func toEventModels(events []domainEvent.Event) []*Event {
+	docs := make([]*Event, 0, len(events))
+	for _, e := range events {
+		docs = append(docs, &Event{
+			ID:          primitive.NewObjectID(),
+			Type:        string(e.Type),
+			AggregateID: e.AggregateID,
+			Actor:       e.Actor,
+			OccurredAt:  e.OccurredAt,
+			Payload:     e.Payload,
+		})
+	}
+	return docs
+}
+
+func (m *Event) toDomain() domainEvent.Event {
+	return domainEvent.Event{
+		ID:          m.ID.Hex(),
+		Type:        domainEvent.Type(m.Type),
+		AggregateID: m.AggregateID,
+		Actor:       m.Actor,
+		OccurredAt:  m.OccurredAt,
+		Payload:     m.Payload,
+	}
+}
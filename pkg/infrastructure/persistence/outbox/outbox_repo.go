@@ -0,0 +1,115 @@
+package outbox
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Crud-application/pkg/config"
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"github.com/Crud-application/pkg/domain/domainEvent"
+	"github.com/Crud-application/pkg/infrastructure/persistence/mongoerr"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// store names the outbox collection in unavailability errors
+const store = "outbox"
+
+// MongoOutboxRepository stores domain events in an outbox collection until they are published.
+// Repositories add events in the transaction that stores their aggregate, so an event
+// is recorded if and only if the change it describes is.
+type MongoOutboxRepository struct {
+	client     *mongo.Client
+	database   string
+	collection string
+
+	mu          sync.Mutex
+	resumeToken bson.Raw // Where Watch resumes the change stream
+}
+
+// NewMongoOutboxRepository constructor that accepts the MongoDB client and its configuration
+func NewMongoOutboxRepository(client *mongo.Client, cfg config.MongoConfig) *MongoOutboxRepository {
+	return &MongoOutboxRepository{
+		client:     client,
+		database:   cfg.Database,
+		collection: cfg.OutboxCollection,
+	}
+}
+
+func (r *MongoOutboxRepository) outboxCollection() *mongo.Collection {
+	return r.client.Database(r.database).Collection(r.collection)
+}
+
+// EnsureIndexes creates the index the relay uses to find pending events. It is idempotent.
+func (r *MongoOutboxRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.outboxCollection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "published_at", Value: 1}, {Key: "_id", Value: 1}},
+		Options: options.Index().SetName("pending_events"),
+	})
+	if err != nil {
+		log.Printf("Error creating outbox indexes: %v", err)
+		return mongoerr.ToDomain(err, store)
+	}
+	return nil
+}
+
+// AddEvents stores events as pending. Pass the session context of a
+// transaction to store them atomically with the aggregate they are about.
+func (r *MongoOutboxRepository) AddEvents(ctx context.Context, events []domainEvent.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	docs := make([]any, 0, len(events))
+	for _, m := range toEventModels(events) {
+		docs = append(docs, m)
+	}
+	if _, err := r.outboxCollection().InsertMany(ctx, docs); err != nil {
+		log.Printf("Error inserting outbox events: %v", err)
+		return mongoerr.ToDomain(err, store)
+	}
+	return nil
+}
+
+// GetPending returns up to limit unpublished events, oldest first
+func (r *MongoOutboxRepository) GetPending(ctx context.Context, limit int) ([]domainEvent.Event, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.outboxCollection().Find(ctx, bson.M{"published_at": nil}, opts)
+	if err != nil {
+		return nil, mongoerr.ToDomain(err, store)
+	}
+	var models []Event
+	if err = cursor.All(ctx, &models); err != nil {
+		return nil, mongoerr.ToDomain(err, store)
+	}
+
+	events := make([]domainEvent.Event, 0, len(models))
+	for _, m := range models {
+		events = append(events, m.toDomain())
+	}
+	return events, nil
+}
+
+// MarkPublished records that the event was published at the given time
+func (r *MongoOutboxRepository) MarkPublished(ctx context.Context, eventID string, at time.Time) error {
+	id, err := primitive.ObjectIDFromHex(eventID)
+	if err != nil {
+		return domainErr.NotFound("event %s not found", eventID)
+	}
+
+	result, err := r.outboxCollection().UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"published_at": at}})
+	if err != nil {
+		log.Printf("Error marking event published: %v", err)
+		return mongoerr.ToDomain(err, store)
+	}
+	if result.MatchedCount == 0 {
+		return domainErr.NotFound("event %s not found", eventID)
+	}
+	return nil
+}
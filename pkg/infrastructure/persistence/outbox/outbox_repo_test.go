@@ -0,0 +1,84 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Crud-application/pkg/config"
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestMongoOutboxRepository_GetPending(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	mt.Run("Pending events in order - Success", func(mt *mtest.T) {
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "crud.outbox", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: id},
+			{Key: "type", Value: "user.created"},
+			{Key: "aggregate_id", Value: "u1"},
+			{Key: "payload", Value: bson.D{{Key: "name", Value: "alam"}}},
+		}))
+		r := NewMongoOutboxRepository(mt.Client, config.Default().Mongo)
+
+		got, err := r.GetPending(context.Background(), 10)
+		if err != nil || len(got) != 1 || got[0].ID != id.Hex() || got[0].Type != "user.created" || got[0].Payload["name"] != "alam" {
+			mt.Errorf("GetPending() = %v, %v", got, err)
+		}
+		if filter := mt.GetStartedEvent().Command.Lookup("filter").String(); filter != `{"published_at": null}` {
+			mt.Errorf("GetPending() filter = %s, want unpublished events", filter)
+		}
+	})
+}
+
+func TestMongoOutboxRepository_MarkPublished(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	at := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		id         int
+		name       string
+		eventID    string
+		beforeTest func(mt *mtest.T)
+		wantErr    error
+	}{
+		{
+			id:      1,
+			name:    "Event marked published - Success",
+			eventID: primitive.NewObjectID().Hex(),
+			beforeTest: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+			},
+			wantErr: nil,
+		},
+		{
+			id:      2,
+			name:    "Unknown event - NotFound",
+			eventID: primitive.NewObjectID().Hex(),
+			beforeTest: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}, bson.E{Key: "nModified", Value: 0}))
+			},
+			wantErr: domainErr.ErrNotFound,
+		},
+		{
+			id:      3,
+			name:    "Malformed event ID - NotFound",
+			eventID: "not-an-id",
+			wantErr: domainErr.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			r := NewMongoOutboxRepository(mt.Client, config.Default().Mongo)
+			if tt.beforeTest != nil {
+				tt.beforeTest(mt)
+			}
+			if err := r.MarkPublished(context.Background(), tt.eventID, at); !errors.Is(err, tt.wantErr) {
+				mt.Errorf("ID %v MarkPublished() error = %v, wantErr %v", tt.id, err, tt.wantErr)
+			}
+		})
+	}
+}
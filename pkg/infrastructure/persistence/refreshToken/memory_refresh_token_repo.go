@@ -0,0 +1,91 @@
+package refreshToken
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Crud-application/pkg/domain/authAgg"
+	"github.com/Crud-application/pkg/domain/domainErr"
+)
+
+// InMemoryRefreshTokenRepository is a thread-safe, process-local store of refresh
+// tokens that mirrors MongoRefreshTokenRepository for development and CI.
+// Expired tokens are kept until the process exits.
+type InMemoryRefreshTokenRepository struct {
+	mu     sync.RWMutex
+	tokens map[string]RefreshToken // By ID
+}
+
+// NewInMemoryRefreshTokenRepository creates an empty in-memory refresh token store
+func NewInMemoryRefreshTokenRepository() *InMemoryRefreshTokenRepository {
+	return &InMemoryRefreshTokenRepository{tokens: make(map[string]RefreshToken)}
+}
+
+// AddRefreshToken stores a new refresh token, rejecting duplicate IDs and hashes
+func (r *InMemoryRefreshTokenRepository) AddRefreshToken(ctx context.Context, t *authAgg.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, m := range r.tokens {
+		if m.ID == t.ID || m.Hash == t.Hash {
+			return domainErr.Conflict("refresh token %s already exists", t.ID)
+		}
+	}
+	r.tokens[t.ID] = *toRefreshTokenModel(t)
+	return nil
+}
+
+// GetRefreshTokenByHash returns the token with the given hash, revoked or not
+func (r *InMemoryRefreshTokenRepository) GetRefreshTokenByHash(ctx context.Context, hash string) (*authAgg.RefreshToken, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, m := range r.tokens {
+		if m.Hash == hash {
+			return m.toAggregate(), nil
+		}
+	}
+	return nil, domainErr.NotFound("refresh token not found")
+}
+
+// RevokeRefreshToken revokes the token with the given ID if it is still active
+func (r *InMemoryRefreshTokenRepository) RevokeRefreshToken(ctx context.Context, id string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m, ok := r.tokens[id]
+	if !ok {
+		return domainErr.NotFound("refresh token %s not found", id)
+	}
+	if m.RevokedAt != nil {
+		return domainErr.Conflict("refresh token %s is already revoked", id)
+	}
+	m.RevokedAt = &at
+	r.tokens[id] = m
+	return nil
+}
+
+// RevokeRefreshTokenFamily revokes the active tokens of a login session
+func (r *InMemoryRefreshTokenRepository) RevokeRefreshTokenFamily(ctx context.Context, familyID string, at time.Time) error {
+	r.revokeAll(func(m RefreshToken) bool { return m.FamilyID == familyID }, at)
+	return nil
+}
+
+// RevokeUserRefreshTokens revokes the active tokens of every session of a user
+func (r *InMemoryRefreshTokenRepository) RevokeUserRefreshTokens(ctx context.Context, userID string, at time.Time) error {
+	r.revokeAll(func(m RefreshToken) bool { return m.UserID == userID }, at)
+	return nil
+}
+
+func (r *InMemoryRefreshTokenRepository) revokeAll(match func(RefreshToken) bool, at time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, m := range r.tokens {
+		if m.RevokedAt == nil && match(m) {
+			m.RevokedAt = &at
+			r.tokens[id] = m
+		}
+	}
+}
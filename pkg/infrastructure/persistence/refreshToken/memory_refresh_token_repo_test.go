@@ -0,0 +1,63 @@
+package refreshToken
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Crud-application/pkg/domain/authAgg"
+	"github.com/Crud-application/pkg/domain/domainErr"
+)
+
+func TestInMemoryRefreshTokenRepository(t *testing.T) {
+	ctx := context.Background()
+	r := NewInMemoryRefreshTokenRepository()
+	at := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, tok := range []*authAgg.RefreshToken{
+		{ID: "r1", UserID: "u1", FamilyID: "r1", Hash: "h1"},
+		{ID: "r2", UserID: "u1", FamilyID: "r1", Hash: "h2"},
+		{ID: "r3", UserID: "u1", FamilyID: "r3", Hash: "h3"},
+		{ID: "r4", UserID: "u2", FamilyID: "r4", Hash: "h4"},
+	} {
+		if err := r.AddRefreshToken(ctx, tok); err != nil {
+			t.Fatalf("AddRefreshToken() error = %v", err)
+		}
+	}
+	if err := r.AddRefreshToken(ctx, &authAgg.RefreshToken{ID: "r5", Hash: "h1"}); !errors.Is(err, domainErr.ErrConflict) {
+		t.Errorf("AddRefreshToken() with a duplicate hash error = %v, want conflict", err)
+	}
+	if _, err := r.GetRefreshTokenByHash(ctx, "h9"); !errors.Is(err, domainErr.ErrNotFound) {
+		t.Errorf("GetRefreshTokenByHash() of an unknown hash error = %v, want not found", err)
+	}
+
+	if err := r.RevokeRefreshToken(ctx, "r1", at); err != nil {
+		t.Fatalf("RevokeRefreshToken() error = %v", err)
+	}
+	if err := r.RevokeRefreshToken(ctx, "r1", at); !errors.Is(err, domainErr.ErrConflict) {
+		t.Errorf("RevokeRefreshToken() twice error = %v, want conflict", err)
+	}
+	if err := r.RevokeRefreshToken(ctx, "r9", at); !errors.Is(err, domainErr.ErrNotFound) {
+		t.Errorf("RevokeRefreshToken() of an unknown token error = %v, want not found", err)
+	}
+
+	_ = r.RevokeRefreshTokenFamily(ctx, "r1", at.Add(time.Hour))
+	if got, _ := r.GetRefreshTokenByHash(ctx, "h1"); !got.RevokedAt.Equal(at) {
+		t.Errorf("RevokeRefreshTokenFamily() changed the revocation time of r1 to %v", got.RevokedAt)
+	}
+	if got, _ := r.GetRefreshTokenByHash(ctx, "h2"); !got.Revoked() {
+		t.Errorf("RevokeRefreshTokenFamily() left r2 of the family active")
+	}
+	if got, _ := r.GetRefreshTokenByHash(ctx, "h3"); got.Revoked() {
+		t.Errorf("RevokeRefreshTokenFamily() revoked r3 of another family")
+	}
+
+	_ = r.RevokeUserRefreshTokens(ctx, "u1", at)
+	if got, _ := r.GetRefreshTokenByHash(ctx, "h3"); !got.Revoked() {
+		t.Errorf("RevokeUserRefreshTokens() left r3 of the user active")
+	}
+	if got, _ := r.GetRefreshTokenByHash(ctx, "h4"); got.Revoked() {
+		t.Errorf("RevokeUserRefreshTokens() revoked r4 of another user")
+	}
+}
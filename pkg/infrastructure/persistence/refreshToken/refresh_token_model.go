@@ -0,0 +1,42 @@
+package refreshToken
+
+import (
+	"time"
+
+	"github.com/Crud-application/pkg/domain/authAgg"
+)
+
+// RefreshToken is the stored form of a refresh token
+type RefreshToken struct {
+	ID        string     `bson:"_id"`
+	UserID    string     `bson:"user_id"`
+	FamilyID  string     `bson:"family_id"`
+	Hash      string     `bson:"hash"`
+	CreatedAt time.Time  `bson:"created_at"`
+	ExpiresAt time.Time  `bson:"expires_at"`
+	RevokedAt *time.Time `bson:"revoked_at,omitempty"`
+}
+
+func toRefreshTokenModel(t *authAgg.RefreshToken) *RefreshToken {
+	return &RefreshToken{
+		ID:        t.ID,
+		UserID:    t.UserID,
+		FamilyID:  t.FamilyID,
+		Hash:      t.Hash,
+		CreatedAt: t.CreatedAt,
+		ExpiresAt: t.ExpiresAt,
+		RevokedAt: t.RevokedAt,
+	}
+}
+
+func (m *RefreshToken) toAggregate() *authAgg.RefreshToken {
+	return &authAgg.RefreshToken{
+		ID:        m.ID,
+		UserID:    m.UserID,
+		FamilyID:  m.FamilyID,
+		Hash:      m.Hash,
+		CreatedAt: m.CreatedAt,
+		ExpiresAt: m.ExpiresAt,
+		RevokedAt: m.RevokedAt,
+	}
+}
@@ -0,0 +1,132 @@
+package refreshToken
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/Crud-application/pkg/config"
+	"github.com/Crud-application/pkg/domain/authAgg"
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"github.com/Crud-application/pkg/infrastructure/persistence/mongoerr"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// store names the refresh token collection in unavailability errors
+const store = "refresh token store"
+
+// MongoRefreshTokenRepository stores the hashes of refresh tokens in a collection
+type MongoRefreshTokenRepository struct {
+	client     *mongo.Client
+	database   string
+	collection string
+}
+
+// NewMongoRefreshTokenRepository constructor that accepts the MongoDB client and its configuration
+func NewMongoRefreshTokenRepository(client *mongo.Client, cfg config.MongoConfig) *MongoRefreshTokenRepository {
+	return &MongoRefreshTokenRepository{
+		client:     client,
+		database:   cfg.Database,
+		collection: cfg.RefreshTokenCollection,
+	}
+}
+
+func (r *MongoRefreshTokenRepository) getCollection() *mongo.Collection {
+	return r.client.Database(r.database).Collection(r.collection)
+}
+
+// EnsureIndexes creates the indexes tokens are looked up and revoked by, and lets
+// MongoDB remove tokens once they expire. It is idempotent.
+func (r *MongoRefreshTokenRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.getCollection().Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "hash", Value: 1}},
+			Options: options.Index().SetName("hash").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "family_id", Value: 1}},
+			Options: options.Index().SetName("family_id"),
+		},
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}},
+			Options: options.Index().SetName("user_id"),
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetName("expires_at_ttl").SetExpireAfterSeconds(0),
+		},
+	})
+	if err != nil {
+		log.Printf("Error creating refresh token indexes: %v", err)
+		return mongoerr.ToDomain(err, store)
+	}
+	return nil
+}
+
+// AddRefreshToken inserts a new refresh token
+func (r *MongoRefreshTokenRepository) AddRefreshToken(ctx context.Context, t *authAgg.RefreshToken) error {
+	if _, err := r.getCollection().InsertOne(ctx, toRefreshTokenModel(t)); err != nil {
+		log.Printf("Error inserting refresh token: %v", err)
+		if mongo.IsDuplicateKeyError(err) {
+			return domainErr.Conflict("refresh token %s already exists", t.ID)
+		}
+		return mongoerr.ToDomain(err, store)
+	}
+	return nil
+}
+
+// GetRefreshTokenByHash returns the token with the given hash, revoked or not
+func (r *MongoRefreshTokenRepository) GetRefreshTokenByHash(ctx context.Context, hash string) (*authAgg.RefreshToken, error) {
+	var m RefreshToken
+	err := r.getCollection().FindOne(ctx, bson.M{"hash": hash}).Decode(&m)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domainErr.NotFound("refresh token not found")
+	}
+	if err != nil {
+		return nil, mongoerr.ToDomain(err, store)
+	}
+	return m.toAggregate(), nil
+}
+
+// RevokeRefreshToken revokes the token with the given ID if it is still active
+func (r *MongoRefreshTokenRepository) RevokeRefreshToken(ctx context.Context, id string, at time.Time) error {
+	res, err := r.getCollection().UpdateOne(ctx,
+		bson.M{"_id": id, "revoked_at": nil},
+		bson.M{"$set": bson.M{"revoked_at": at}},
+	)
+	if err != nil {
+		return mongoerr.ToDomain(err, store)
+	}
+	if res.MatchedCount == 0 {
+		n, err := r.getCollection().CountDocuments(ctx, bson.M{"_id": id}, options.Count().SetLimit(1))
+		if err != nil {
+			return mongoerr.ToDomain(err, store)
+		}
+		if n == 0 {
+			return domainErr.NotFound("refresh token %s not found", id)
+		}
+		return domainErr.Conflict("refresh token %s is already revoked", id)
+	}
+	return nil
+}
+
+// RevokeRefreshTokenFamily revokes the active tokens of a login session
+func (r *MongoRefreshTokenRepository) RevokeRefreshTokenFamily(ctx context.Context, familyID string, at time.Time) error {
+	return r.revokeAll(ctx, bson.M{"family_id": familyID, "revoked_at": nil}, at)
+}
+
+// RevokeUserRefreshTokens revokes the active tokens of every session of a user
+func (r *MongoRefreshTokenRepository) RevokeUserRefreshTokens(ctx context.Context, userID string, at time.Time) error {
+	return r.revokeAll(ctx, bson.M{"user_id": userID, "revoked_at": nil}, at)
+}
+
+func (r *MongoRefreshTokenRepository) revokeAll(ctx context.Context, filter bson.M, at time.Time) error {
+	if _, err := r.getCollection().UpdateMany(ctx, filter, bson.M{"$set": bson.M{"revoked_at": at}}); err != nil {
+		log.Printf("Error revoking refresh tokens: %v", err)
+		return mongoerr.ToDomain(err, store)
+	}
+	return nil
+}
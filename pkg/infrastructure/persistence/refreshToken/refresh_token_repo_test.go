@@ -0,0 +1,111 @@
+package refreshToken
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Crud-application/pkg/config"
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestMongoRefreshTokenRepository_GetRefreshTokenByHash(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	expiresAt := time.Date(2024, 5, 31, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		id         int
+		name       string
+		beforeTest func(mt *mtest.T)
+		wantErr    error
+	}{
+		{
+			id:   1,
+			name: "Token found - Success",
+			beforeTest: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateCursorResponse(0, "crud.refresh_tokens", mtest.FirstBatch, bson.D{
+					{Key: "_id", Value: "r2"},
+					{Key: "user_id", Value: "u1"},
+					{Key: "family_id", Value: "r1"},
+					{Key: "hash", Value: "abc"},
+					{Key: "expires_at", Value: expiresAt},
+				}))
+			},
+		},
+		{
+			id:   2,
+			name: "Unknown hash - NotFound",
+			beforeTest: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateCursorResponse(0, "crud.refresh_tokens", mtest.FirstBatch))
+			},
+			wantErr: domainErr.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			r := NewMongoRefreshTokenRepository(mt.Client, config.Default().Mongo)
+			tt.beforeTest(mt)
+
+			got, err := r.GetRefreshTokenByHash(context.Background(), "abc")
+			if !errors.Is(err, tt.wantErr) {
+				mt.Fatalf("ID %v GetRefreshTokenByHash() error = %v, wantErr %v", tt.id, err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (got.ID != "r2" || got.FamilyID != "r1" || got.Revoked() || !got.ExpiresAt.Equal(expiresAt)) {
+				mt.Errorf("ID %v GetRefreshTokenByHash() = %+v, want the active token r2 of family r1", tt.id, got)
+			}
+		})
+	}
+}
+
+func TestMongoRefreshTokenRepository_RevokeRefreshToken(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	tests := []struct {
+		id         int
+		name       string
+		beforeTest func(mt *mtest.T)
+		wantErr    error
+	}{
+		{
+			id:   1,
+			name: "Active token revoked - Success",
+			beforeTest: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+			},
+		},
+		{
+			id:   2,
+			name: "Token already revoked - Conflict",
+			beforeTest: func(mt *mtest.T) {
+				mt.AddMockResponses(
+					mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}, bson.E{Key: "nModified", Value: 0}),
+					mtest.CreateCursorResponse(0, "crud.refresh_tokens", mtest.FirstBatch, bson.D{{Key: "n", Value: 1}}),
+				)
+			},
+			wantErr: domainErr.ErrConflict,
+		},
+		{
+			id:   3,
+			name: "Unknown token - NotFound",
+			beforeTest: func(mt *mtest.T) {
+				mt.AddMockResponses(
+					mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}, bson.E{Key: "nModified", Value: 0}),
+					mtest.CreateCursorResponse(0, "crud.refresh_tokens", mtest.FirstBatch),
+				)
+			},
+			wantErr: domainErr.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			r := NewMongoRefreshTokenRepository(mt.Client, config.Default().Mongo)
+			tt.beforeTest(mt)
+
+			err := r.RevokeRefreshToken(context.Background(), "r1", time.Now())
+			if !errors.Is(err, tt.wantErr) {
+				mt.Errorf("ID %v RevokeRefreshToken() error = %v, wantErr %v", tt.id, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package user
+
+import (
+	"errors"
+
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"github.com/Crud-application/pkg/infrastructure/persistence/mongoerr"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// toDomainError translates MongoDB driver errors into domain errors.
+// Errors without a domain meaning are returned unchanged.
+func toDomainError(err error, userID string) error {
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return domainErr.NotFound("user %s not found", userID)
+	case mongo.IsDuplicateKeyError(err):
+		return domainErr.Conflict("user %s already exists", userID)
+	default:
+		return mongoerr.ToDomain(err, "user store")
+	}
+}
@@ -0,0 +1,78 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// emailIndexName names the unique index on the email of the users that are not deleted
+const emailIndexName = "email_unique_active"
+
+// legacyEmailIndexName names the unique index on the email of all users, which kept
+// the emails of deleted users reserved. EnsureIndexes replaces it.
+const legacyEmailIndexName = "email_unique"
+
+// caseInsensitive compares strings ignoring case, so "A@x.com" and "a@x.com" collide
+var caseInsensitive = &options.Collation{Locale: "en", Strength: 2}
+
+// EnsureIndexes creates the indexes the repository relies on.
+// It is idempotent and meant to run once at startup.
+func (r *MongoUserRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.userCollection(ctx).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			// Deleted users free their email, which must be free again to restore them
+			Keys: bson.D{{Key: "email", Value: 1}},
+			Options: options.Index().
+				SetName(emailIndexName).
+				SetUnique(true).
+				SetCollation(caseInsensitive).
+				SetPartialFilterExpression(bson.M{"deleted_at": nil}),
+		},
+		{
+			// Only soft-deleted users have the field, which the purge looks up
+			Keys:    bson.D{{Key: "deleted_at", Value: 1}},
+			Options: options.Index().SetName("deleted_at").SetSparse(true),
+		},
+		{
+			// Listings filter on the status, e.g. to find suspended users
+			Keys:    bson.D{{Key: "status", Value: 1}},
+			Options: options.Index().SetName("status"),
+		},
+	})
+	if err != nil {
+		log.Printf("Error creating user indexes: %v", err)
+		return toDomainError(err, "")
+	}
+	if _, err := r.userCollection(ctx).Indexes().DropOne(ctx, legacyEmailIndexName); err != nil && !isIndexNotFound(err) {
+		log.Printf("Error dropping the legacy email index: %v", err)
+		return toDomainError(err, "")
+	}
+	log.Printf("Ensured user indexes")
+	return nil
+}
+
+// isIndexNotFound reports whether err was caused by dropping an index that does not exist
+func isIndexNotFound(err error) bool {
+	var ce mongo.CommandError
+	return errors.As(err, &ce) && (ce.Code == 27 || ce.Name == "IndexNotFound")
+}
+
+// isDuplicateEmail reports whether err was caused by a unique email index
+func isDuplicateEmail(err error) bool {
+	var we mongo.WriteException
+	if !errors.As(err, &we) {
+		return false
+	}
+	for _, e := range we.WriteErrors {
+		if e.HasErrorCode(11000) && (strings.Contains(e.Message, emailIndexName) || strings.Contains(e.Message, legacyEmailIndexName+" ")) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package user
+
+import (
+	"cmp"
+	"strings"
+	"time"
+
+	"github.com/Crud-application/pkg/domain/query"
+	uAgg "github.com/Crud-application/pkg/domain/userAgg"
+)
+
+// fieldValue returns the value of a queryable domain field of u
+func (u User) fieldValue(field string) any {
+	switch field {
+	case uAgg.FieldName:
+		return u.Name
+	case uAgg.FieldEmail:
+		return u.Email
+	case uAgg.FieldPhoneNumber:
+		return string(u.PhoneNumber)
+	case uAgg.FieldCreatedAt:
+		return u.CreatedAt
+	case uAgg.FieldUpdatedAt:
+		return u.UpdatedAt
+	case uAgg.FieldCreatedBy:
+		return u.CreatedBy
+	case uAgg.FieldUpdatedBy:
+		return u.UpdatedBy
+	case uAgg.FieldStatus:
+		return u.Status
+	default:
+		return u.ID
+	}
+}
+
+// matchesSpec evaluates the filters and search of spec against u,
+// with the same semantics as the compiled MongoDB filter
+func matchesSpec(u User, spec query.Spec) bool {
+	if u.DeletedAt != nil && !spec.IncludeDeleted {
+		return false
+	}
+	for _, f := range spec.Filters {
+		if !matchesFilter(u.fieldValue(f.Field), f) {
+			return false
+		}
+	}
+
+	if spec.Search != "" {
+		found := false
+		for _, field := range uAgg.SearchFields {
+			if containsFold(u.fieldValue(field).(string), spec.Search) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesFilter(v any, f query.Filter) bool {
+	if f.Op == query.OpContains {
+		return containsFold(v.(string), f.Value.(string))
+	}
+
+	// Legacy phone numbers match the number of the same digits, as the integer stored in MongoDB does
+	if f.Field == uAgg.FieldPhoneNumber && uAgg.PhoneNumber(v.(string)).Legacy() {
+		v = "+" + v.(string)
+	}
+	c := compareValues(v, f.Value)
+	switch f.Op {
+	case query.OpNe:
+		return c != 0
+	case query.OpGte:
+		return c >= 0
+	case query.OpLte:
+		return c <= 0
+	default:
+		return c == 0
+	}
+}
+
+// compareUsers orders users by the sort fields of spec, then by ID
+func compareUsers(a, b User, spec query.Spec) int {
+	for _, s := range spec.Sort {
+		c := compareValues(a.fieldValue(s.Field), b.fieldValue(s.Field))
+		if s.Desc {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(a.ID, b.ID)
+}
+
+func compareValues(a, b any) int {
+	switch av := a.(type) {
+	case int64:
+		return cmp.Compare(av, b.(int64))
+	case time.Time:
+		return av.Compare(b.(time.Time))
+	default:
+		return cmp.Compare(a.(string), b.(string))
+	}
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
@@ -0,0 +1,237 @@
+package user
+
+import (
+	"context"
+	"log"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"github.com/Crud-application/pkg/domain/domainEvent"
+	"github.com/Crud-application/pkg/domain/query"
+	uAgg "github.com/Crud-application/pkg/domain/userAgg"
+	"github.com/Crud-application/pkg/infrastructure/persistence/audit"
+	"github.com/Crud-application/pkg/infrastructure/persistence/outbox"
+)
+
+// InMemoryUserRepository is a thread-safe, process-local user store.
+// It mirrors the behaviour of MongoUserRepository so it can stand in for it
+// in development and CI where MongoDB is not reachable.
+type InMemoryUserRepository struct {
+	mu     sync.RWMutex
+	users  map[string]User
+	outbox *outbox.InMemoryOutboxRepository
+	audit  *audit.InMemoryAuditRepository
+}
+
+// NewInMemoryUserRepository creates an empty in-memory user store whose events are stored
+// in ob and the audit entries of their changes in al
+func NewInMemoryUserRepository(ob *outbox.InMemoryOutboxRepository, al *audit.InMemoryAuditRepository) *InMemoryUserRepository {
+	return &InMemoryUserRepository{
+		users:  make(map[string]User),
+		outbox: ob,
+		audit:  al,
+	}
+}
+
+// AddUser stores a new user, rejecting duplicate IDs like the unique _id index does
+func (r *InMemoryUserRepository) AddUser(ctx context.Context, user *uAgg.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	u := toUserModel(user)
+	if _, ok := r.users[u.ID]; ok {
+		return domainErr.Conflict("user %s already exists", u.ID)
+	}
+	if r.emailTaken(u.Email, u.ID) {
+		return domainErr.Conflict("email %s is already in use", u.Email)
+	}
+	r.users[u.ID] = *u
+	r.storePending(ctx, user)
+	return nil
+}
+
+// GetUser returns the user with the given ID unless it is soft-deleted
+func (r *InMemoryUserRepository) GetUser(ctx context.Context, userID string) (*uAgg.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	u, ok := r.users[userID]
+	if !ok || u.DeletedAt != nil {
+		err := domainErr.NotFound("user %s not found", userID)
+		log.Printf("Error getting user: %v", err)
+		return nil, err
+	}
+	return u.toAggregate()
+}
+
+// GetUserIncludingDeleted returns the user with the given ID, even if it is soft-deleted
+func (r *InMemoryUserRepository) GetUserIncludingDeleted(ctx context.Context, userID string) (*uAgg.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	u, ok := r.users[userID]
+	if !ok {
+		return nil, domainErr.NotFound("user %s not found", userID)
+	}
+	return u.toAggregate()
+}
+
+// GetUserByEmail returns the user with the given email, ignoring case, unless it is soft-deleted
+func (r *InMemoryUserRepository) GetUserByEmail(ctx context.Context, email string) (*uAgg.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, u := range r.users {
+		if u.DeletedAt == nil && strings.EqualFold(u.Email, email) {
+			return u.toAggregate()
+		}
+	}
+	return nil, domainErr.NotFound("no user with email %s", email)
+}
+
+// DeleteUser stores the deletion mark of a user
+func (r *InMemoryUserRepository) DeleteUser(ctx context.Context, user *uAgg.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	u, ok := r.users[user.ID]
+	if !ok || u.DeletedAt != nil {
+		return domainErr.NotFound("user %s not found", user.ID)
+	}
+	deletedAt := *user.DeletedAt
+	u.DeletedAt = &deletedAt
+	u.UpdatedAt, u.UpdatedBy = user.UpdatedAt, user.UpdatedBy
+	u.Version++
+	r.users[user.ID] = u
+	user.Version = u.Version
+	r.storePending(ctx, user)
+
+	log.Printf("Deleted user with ID: %s", user.ID)
+	return nil
+}
+
+// RestoreUser clears the deletion mark of a soft-deleted user if its version is unchanged
+func (r *InMemoryUserRepository) RestoreUser(ctx context.Context, user *uAgg.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	u, ok := r.users[user.ID]
+	if !ok {
+		return domainErr.NotFound("user %s not found", user.ID)
+	}
+	if u.DeletedAt == nil || u.Version != user.Version {
+		return domainErr.StaleVersion("user %s was restored or modified concurrently, version %d is stale", user.ID, user.Version)
+	}
+	if r.emailTaken(u.Email, u.ID) {
+		return domainErr.Conflict("the email of user %s is in use by another user", user.ID)
+	}
+	u.DeletedAt = nil
+	u.UpdatedAt, u.UpdatedBy = user.UpdatedAt, user.UpdatedBy
+	u.Version++
+	r.users[user.ID] = u
+	user.Version = u.Version
+	r.storePending(ctx, user)
+
+	log.Printf("Restored user with ID: %s", user.ID)
+	return nil
+}
+
+// PurgeDeletedUsers removes the users deleted before deletedBefore, storing a purged event for each
+func (r *InMemoryUserRepository) PurgeDeletedUsers(ctx context.Context, deletedBefore, at time.Time, by string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var events []domainEvent.Event
+	for id, u := range r.users {
+		if u.DeletedAt != nil && u.DeletedAt.Before(deletedBefore) {
+			delete(r.users, id)
+			events = append(events, uAgg.PurgedEvent(id, at, by))
+		}
+	}
+	_ = r.outbox.AddEvents(ctx, events) // Cannot fail in memory
+	return int64(len(events)), nil
+}
+
+// GetAllUser returns one page of the users matching spec, along with their total count
+func (r *InMemoryUserRepository) GetAllUser(ctx context.Context, spec query.Spec) (*uAgg.UserPage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := make([]User, 0, len(r.users))
+	for _, u := range r.users {
+		if matchesSpec(u, spec) {
+			users = append(users, u)
+		}
+	}
+	total := int64(len(users))
+	slices.SortFunc(users, func(a, b User) int { return compareUsers(a, b, spec) })
+
+	if spec.Page.Cursor != "" {
+		start := sort.Search(len(users), func(i int) bool { return users[i].ID > spec.Page.Cursor })
+		users = users[start:]
+	}
+	users = users[min(spec.Page.Offset, len(users)):]
+	users = users[:min(spec.Page.Limit+1, len(users))]
+
+	return toUserPage(users, spec, total), nil
+}
+
+// UpdateUser overwrites the mutable fields of an existing user if its version is unchanged
+func (r *InMemoryUserRepository) UpdateUser(ctx context.Context, user *uAgg.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.users[user.ID]
+	if !ok || existing.DeletedAt != nil {
+		return domainErr.NotFound("user %s not found", user.ID)
+	}
+	if existing.Version != user.Version {
+		return domainErr.StaleVersion("user %s was modified concurrently, version %d is stale", user.ID, user.Version)
+	}
+	u := toUserModel(user)
+	if r.emailTaken(u.Email, u.ID) {
+		err := domainErr.Conflict("email %s is already in use", u.Email)
+		log.Printf("Error updating user: %v", err)
+		return err
+	}
+	existing.Name = u.Name
+	existing.Email = u.Email
+	existing.PhoneNumber = u.PhoneNumber
+	existing.PasswordHash = u.PasswordHash
+	existing.EmailVerified = u.EmailVerified
+	existing.Status = u.Status
+	existing.StatusReason = u.StatusReason
+	existing.UpdatedAt = u.UpdatedAt
+	existing.UpdatedBy = u.UpdatedBy
+	existing.Version++
+	r.users[user.ID] = existing
+	user.Version = existing.Version
+	r.storePending(ctx, user)
+
+	log.Printf("Updated user with ID: %s", user.ID)
+	return nil
+}
+
+// storePending moves the pending events of user to the outbox and its audit entries to the
+// audit log. Callers hold the lock, so both are visible together with the change they describe.
+func (r *InMemoryUserRepository) storePending(ctx context.Context, user *uAgg.User) {
+	// Cannot fail in memory
+	_ = r.outbox.AddEvents(ctx, user.Events)
+	_ = r.audit.AddEntries(ctx, user.AuditEntries)
+	user.Events, user.AuditEntries = nil, nil
+}
+
+// emailTaken reports whether another user that is not deleted already has email, ignoring
+// case like the unique email index of the MongoDB repository. Callers hold the lock.
+func (r *InMemoryUserRepository) emailTaken(email, exceptID string) bool {
+	for id, u := range r.users {
+		if id != exceptID && u.DeletedAt == nil && strings.EqualFold(u.Email, email) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,399 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Crud-application/pkg/domain/auditAgg"
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"github.com/Crud-application/pkg/domain/query"
+	uAgg "github.com/Crud-application/pkg/domain/userAgg"
+	"github.com/Crud-application/pkg/infrastructure/persistence/audit"
+	"github.com/Crud-application/pkg/infrastructure/persistence/outbox"
+)
+
+func TestInMemoryUserRepository_AddUser(t *testing.T) {
+	u1, _ := TestUserModelData.toAggregate()
+	tests := []struct {
+		id         int
+		name       string
+		beforeTest func(r *InMemoryUserRepository)
+		user       *uAgg.User
+		wantErr    bool
+	}{
+		{
+			id:      1,
+			name:    "User Added successfully - Success",
+			user:    u1,
+			wantErr: false,
+		},
+		{
+			id:   2,
+			name: "Duplicate email ignoring case - Failure",
+			beforeTest: func(r *InMemoryUserRepository) {
+				_ = r.AddUser(context.Background(), &uAgg.User{ID: "other", Email: "SAMAR123@example.com"})
+			},
+			user:    u1,
+			wantErr: true,
+		},
+		{
+			id:   3,
+			name: "Duplicate user ID - Failure",
+			beforeTest: func(r *InMemoryUserRepository) {
+				_ = r.AddUser(context.Background(), u1)
+			},
+			user:    u1,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewInMemoryUserRepository(outbox.NewInMemoryOutboxRepository(), audit.NewInMemoryAuditRepository())
+			if tt.beforeTest != nil {
+				tt.beforeTest(r)
+			}
+			if err := r.AddUser(context.Background(), tt.user); (err != nil) != tt.wantErr {
+				t.Errorf("ID %v AddUser() error = %v, wantErr %v", tt.id, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestInMemoryUserRepository_GetUser(t *testing.T) {
+	u1, _ := TestUserModelData.toAggregate()
+	r := NewInMemoryUserRepository(outbox.NewInMemoryOutboxRepository(), audit.NewInMemoryAuditRepository())
+	_ = r.AddUser(context.Background(), u1)
+
+	tests := []struct {
+		id      int
+		name    string
+		userID  string
+		want    *uAgg.User
+		wantErr error
+	}{
+		{
+			id:     1,
+			name:   "Retrieved User by ID - Success",
+			userID: u1.ID,
+			want:   u1,
+		},
+		{
+			id:      2,
+			name:    "User not found by ID - Failure",
+			userID:  "nonexistentID",
+			want:    nil,
+			wantErr: domainErr.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.GetUser(context.Background(), tt.userID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ID %v GetUser() error = %v, wantErr %v", tt.id, err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ID %v got = %v want = %v", tt.id, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInMemoryUserRepository_UpdateAndDelete(t *testing.T) {
+	ctx := context.Background()
+	u1, _ := TestUserModelData.toAggregate()
+	u2, _ := TestUserModelData2.toAggregate()
+	ob := outbox.NewInMemoryOutboxRepository()
+	r := NewInMemoryUserRepository(ob, audit.NewInMemoryAuditRepository())
+	_ = r.AddUser(ctx, u1)
+	_ = r.AddUser(ctx, u2)
+
+	updated := *u1
+	updated.Name = "updated"
+	updated.PhoneNumber = "+4930123456"
+	if err := r.UpdateUser(ctx, &updated); err != nil {
+		t.Fatalf("UpdateUser() error = %v", err)
+	}
+	got, _ := r.GetUser(ctx, u1.ID)
+	if !reflect.DeepEqual(got, &updated) {
+		t.Errorf("UpdateUser() got = %v want = %v", got, &updated)
+	}
+
+	if updated.Version != u1.Version+1 {
+		t.Errorf("UpdateUser() version = %v want = %v", updated.Version, u1.Version+1)
+	}
+	stale := *u1
+	if err := r.UpdateUser(ctx, &stale); !errors.Is(err, domainErr.ErrStaleVersion) {
+		t.Errorf("UpdateUser() at a stale version error = %v, want stale version", err)
+	}
+
+	taken := updated
+	taken.Email = u2.Email
+	if err := r.UpdateUser(ctx, &taken); !errors.Is(err, domainErr.ErrConflict) {
+		t.Errorf("UpdateUser() to a taken email error = %v, want conflict", err)
+	}
+
+	if err := r.UpdateUser(ctx, &uAgg.User{ID: "nonexistentID"}); !errors.Is(err, domainErr.ErrNotFound) {
+		t.Errorf("UpdateUser() on missing user error = %v, want not found", err)
+	}
+
+	deletedAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	deleted := updated
+	deleted.DeletedAt = &deletedAt
+	if err := r.DeleteUser(ctx, &deleted); err != nil {
+		t.Fatalf("DeleteUser() error = %v", err)
+	}
+	if err := r.DeleteUser(ctx, &deleted); !errors.Is(err, domainErr.ErrNotFound) {
+		t.Errorf("DeleteUser() on deleted user error = %v, want not found", err)
+	}
+	if _, err := r.GetUser(ctx, u1.ID); !errors.Is(err, domainErr.ErrNotFound) {
+		t.Errorf("GetUser() on deleted user error = %v, want not found", err)
+	}
+
+	all, _ := r.GetAllUser(ctx, query.Spec{Page: query.PageRequest{Limit: query.DefaultLimit}})
+	if !reflect.DeepEqual(all.Users, []uAgg.User{*u2}) {
+		t.Errorf("GetAllUser() got = %v want = %v", all.Users, []uAgg.User{*u2})
+	}
+	all, _ = r.GetAllUser(ctx, query.Spec{Page: query.PageRequest{Limit: query.DefaultLimit}, IncludeDeleted: true})
+	if len(all.Users) != 2 || all.Users[1].ID != u1.ID || all.Users[1].DeletedAt == nil || !all.Users[1].DeletedAt.Equal(deletedAt) {
+		t.Errorf("GetAllUser() including deleted got = %v", all.Users)
+	}
+
+	restored, err := r.GetUserIncludingDeleted(ctx, u1.ID)
+	if err != nil || restored.DeletedAt == nil {
+		t.Fatalf("GetUserIncludingDeleted() got = %v, error = %v", restored, err)
+	}
+	stale = *restored
+	restoredAt := deletedAt.Add(time.Hour)
+	_ = restored.MarkRestored(restoredAt, "admin")
+	if err := r.RestoreUser(ctx, restored); err != nil {
+		t.Fatalf("RestoreUser() error = %v", err)
+	}
+	_ = stale.MarkRestored(restoredAt, "admin")
+	if err := r.RestoreUser(ctx, &stale); !errors.Is(err, domainErr.ErrConflict) {
+		t.Errorf("RestoreUser() on live user error = %v, want conflict", err)
+	}
+	if got, err := r.GetUser(ctx, u1.ID); err != nil || got.DeletedAt != nil || !got.UpdatedAt.Equal(restoredAt) || got.UpdatedBy != "admin" {
+		t.Errorf("GetUser() after restore got = %v, error = %v", got, err)
+	}
+
+	deleted = *restored
+	deleted.MarkDeleted(deletedAt, "admin")
+	_ = r.DeleteUser(ctx, &deleted)
+	// A deleted user frees their email, which then keeps them from being restored
+	reused, _ := uAgg.NewUser("reused-email", "Someone Else", updated.Email.String(), "+4930123456")
+	if err := r.AddUser(ctx, reused); err != nil {
+		t.Fatalf("AddUser() with the email of a deleted user error = %v", err)
+	}
+	restored, _ = r.GetUserIncludingDeleted(ctx, u1.ID)
+	_ = restored.MarkRestored(restoredAt, "admin")
+	if err := r.RestoreUser(ctx, restored); !errors.Is(err, domainErr.ErrConflict) {
+		t.Errorf("RestoreUser() with a reused email error = %v, want conflict", err)
+	}
+	if n, _ := r.PurgeDeletedUsers(ctx, deletedAt, restoredAt, "system"); n != 0 {
+		t.Errorf("PurgeDeletedUsers() at the deletion time purged %v users, want 0", n)
+	}
+	if n, _ := r.PurgeDeletedUsers(ctx, deletedAt.Add(time.Second), restoredAt, "system"); n != 1 {
+		t.Errorf("PurgeDeletedUsers() after the deletion time purged %v users, want 1", n)
+	}
+	pending, _ := ob.GetPending(ctx, 100)
+	if last := pending[len(pending)-1]; last.Type != uAgg.EventUserPurged || last.AggregateID != u1.ID {
+		t.Errorf("PurgeDeletedUsers() stored %v of %v, want %v of %v", last.Type, last.AggregateID, uAgg.EventUserPurged, u1.ID)
+	}
+	if err := r.RestoreUser(ctx, restored); !errors.Is(err, domainErr.ErrNotFound) {
+		t.Errorf("RestoreUser() on purged user error = %v, want not found", err)
+	}
+	if _, err := r.GetUserIncludingDeleted(ctx, u1.ID); !errors.Is(err, domainErr.ErrNotFound) {
+		t.Errorf("GetUserIncludingDeleted() on purged user error = %v, want not found", err)
+	}
+}
+
+func TestInMemoryUserRepository_Events(t *testing.T) {
+	ctx := context.Background()
+	ob := outbox.NewInMemoryOutboxRepository()
+	al := audit.NewInMemoryAuditRepository()
+	r := NewInMemoryUserRepository(ob, al)
+	at := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	audited := func(u *uAgg.User, action auditAgg.Action) *uAgg.User {
+		u.AuditEntries = append(u.AuditEntries, auditAgg.Entry{ResourceID: u.ID, Action: action, Timestamp: at})
+		return u
+	}
+
+	u, _ := TestUserModelData.toAggregate()
+	u.MarkCreated(at, "admin")
+	if err := r.AddUser(ctx, audited(u, auditAgg.ActionCreate)); err != nil || u.Events != nil || u.AuditEntries != nil {
+		t.Fatalf("AddUser() error = %v, events left = %v, audit entries left = %v", err, u.Events, u.AuditEntries)
+	}
+	u.MarkUpdated(at, "admin")
+	if err := r.UpdateUser(ctx, audited(u, auditAgg.ActionUpdate)); err != nil || u.Events != nil || u.AuditEntries != nil {
+		t.Fatalf("UpdateUser() error = %v, events left = %v, audit entries left = %v", err, u.Events, u.AuditEntries)
+	}
+	// A failed change stores no events and no audit entries
+	stale := *u
+	stale.Version--
+	stale.MarkUpdated(at, "admin")
+	_ = r.UpdateUser(ctx, audited(&stale, auditAgg.ActionUpdate))
+	u.MarkDeleted(at, "admin")
+	if err := r.DeleteUser(ctx, audited(u, auditAgg.ActionDelete)); err != nil || u.Events != nil || u.AuditEntries != nil {
+		t.Fatalf("DeleteUser() error = %v, events left = %v, audit entries left = %v", err, u.Events, u.AuditEntries)
+	}
+
+	history, _ := al.GetEntries(ctx, u.ID, query.PageRequest{Limit: 10})
+	var actions []auditAgg.Action
+	for _, e := range history.Entries {
+		actions = append(actions, e.Action)
+	}
+	if want := []auditAgg.Action{auditAgg.ActionDelete, auditAgg.ActionUpdate, auditAgg.ActionCreate}; !reflect.DeepEqual(actions, want) {
+		t.Errorf("audit entries = %v want = %v", actions, want)
+	}
+
+	pending, _ := ob.GetPending(ctx, 10)
+	var types []string
+	for _, e := range pending {
+		types = append(types, string(e.Type))
+	}
+	want := []string{"user.created", "user.updated", "user.deleted"}
+	if !reflect.DeepEqual(types, want) {
+		t.Errorf("outbox events = %v want = %v", types, want)
+	}
+}
+
+func TestInMemoryUserRepository_GetAllUser(t *testing.T) {
+	ctx := context.Background()
+	r := NewInMemoryUserRepository(outbox.NewInMemoryOutboxRepository(), audit.NewInMemoryAuditRepository())
+	created := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	for i, id := range []string{"c", "a", "d", "b", "e"} {
+		u := &uAgg.User{ID: id, Name: "user " + id, Email: uAgg.Email(id + "@example.com"), PhoneNumber: uAgg.PhoneNumber(fmt.Sprintf("+4930%06d", i)), Status: uAgg.StatusActive}
+		if id == "d" {
+			u.Status = uAgg.StatusSuspended
+		}
+		u.MarkCreated(created.Add(time.Duration(i)*time.Hour), "admin")
+		_ = r.AddUser(ctx, u)
+	}
+
+	tests := []struct {
+		id        int
+		name      string
+		spec      query.Spec
+		wantIDs   []string
+		wantNext  string
+		wantTotal int64
+	}{
+		{
+			id:        1,
+			name:      "First page - Success",
+			spec:      query.Spec{Page: query.PageRequest{Limit: 2}},
+			wantIDs:   []string{"a", "b"},
+			wantNext:  "b",
+			wantTotal: 5,
+		},
+		{
+			id:        2,
+			name:      "Page after cursor - Success",
+			spec:      query.Spec{Page: query.PageRequest{Limit: 2, Cursor: "b"}},
+			wantIDs:   []string{"c", "d"},
+			wantNext:  "d",
+			wantTotal: 5,
+		},
+		{
+			id:        3,
+			name:      "Last page by offset - Success",
+			spec:      query.Spec{Page: query.PageRequest{Limit: 2, Offset: 4}},
+			wantIDs:   []string{"e"},
+			wantNext:  "",
+			wantTotal: 5,
+		},
+		{
+			id:   4,
+			name: "Filtered and sorted descending - Success",
+			spec: query.Spec{
+				Filters: []query.Filter{{Field: uAgg.FieldPhoneNumber, Op: query.OpGte, Value: "+4930000001"}},
+				Sort:    []query.SortField{{Field: uAgg.FieldPhoneNumber, Desc: true}},
+				Page:    query.PageRequest{Limit: 3},
+			},
+			wantIDs:   []string{"e", "b", "d"},
+			wantNext:  "",
+			wantTotal: 4,
+		},
+		{
+			id:   5,
+			name: "Case-insensitive search - Success",
+			spec: query.Spec{
+				Search: "C@EXAMPLE",
+				Page:   query.PageRequest{Limit: 2},
+			},
+			wantIDs:   []string{"c"},
+			wantNext:  "",
+			wantTotal: 1,
+		},
+		{
+			id:   6,
+			name: "Created in a time range, newest first - Success",
+			spec: query.Spec{
+				Filters: []query.Filter{
+					{Field: uAgg.FieldCreatedAt, Op: query.OpGte, Value: created.Add(time.Hour)},
+					{Field: uAgg.FieldCreatedAt, Op: query.OpLte, Value: created.Add(3 * time.Hour)},
+				},
+				Sort: []query.SortField{{Field: uAgg.FieldCreatedAt, Desc: true}},
+				Page: query.PageRequest{Limit: 5},
+			},
+			wantIDs:   []string{"b", "d", "a"},
+			wantNext:  "",
+			wantTotal: 3,
+		},
+		{
+			id:   7,
+			name: "Filtered on status - Success",
+			spec: query.Spec{
+				Filters: []query.Filter{{Field: uAgg.FieldStatus, Op: query.OpNe, Value: "active"}},
+				Page:    query.PageRequest{Limit: 5},
+			},
+			wantIDs:   []string{"d"},
+			wantNext:  "",
+			wantTotal: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.GetAllUser(ctx, tt.spec)
+			if err != nil {
+				t.Fatalf("ID %v GetAllUser() error = %v", tt.id, err)
+			}
+			var ids []string
+			for _, u := range got.Users {
+				ids = append(ids, u.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) || got.Page.NextCursor != tt.wantNext || got.Page.Total != tt.wantTotal {
+				t.Errorf("ID %v GetAllUser() got ids = %v next = %q total = %d, want ids = %v next = %q total = %d",
+					tt.id, ids, got.Page.NextCursor, got.Page.Total, tt.wantIDs, tt.wantNext, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestInMemoryUserRepository_GetAllUser_LegacyPhoneNumber(t *testing.T) {
+	ctx := context.Background()
+	r := NewInMemoryUserRepository(outbox.NewInMemoryOutboxRepository(), audit.NewInMemoryAuditRepository())
+	_ = r.AddUser(ctx, &uAgg.User{ID: "a", Email: "a@example.com", PhoneNumber: "919876543210"})
+	_ = r.AddUser(ctx, &uAgg.User{ID: "b", Email: "b@example.com", PhoneNumber: "+919876543210"})
+	_ = r.AddUser(ctx, &uAgg.User{ID: "c", Email: "c@example.com", PhoneNumber: "+4930000000"})
+
+	page, err := r.GetAllUser(ctx, query.Spec{
+		Filters: []query.Filter{{Field: uAgg.FieldPhoneNumber, Op: query.OpEq, Value: "+919876543210"}},
+		Page:    query.PageRequest{Limit: 10},
+	})
+	if err != nil {
+		t.Fatalf("GetAllUser() error = %v", err)
+	}
+	var ids []string
+	for _, u := range page.Users {
+		ids = append(ids, u.ID)
+	}
+	if !reflect.DeepEqual(ids, []string{"a", "b"}) {
+		t.Errorf("GetAllUser() = %v, want the legacy and the E.164 number", ids)
+	}
+}
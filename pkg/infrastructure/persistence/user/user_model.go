@@ -1,36 +1,132 @@
 package user
 
 import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Crud-application/pkg/domain/query"
 	uAgg "github.com/Crud-application/pkg/domain/userAgg"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
 )
 
 // User struct
 type User struct {
-	ID          string `bson:"_id"`
-	Name        string `json:"name" bson:"name"`
-	Email       string `json:"email" bson:"email"`
-	PhoneNumber int64  `json:"phone_number" bson:"phone_number"`
+	ID          string      `bson:"_id"`
+	Name        string      `json:"name" bson:"name"`
+	Email       string      `json:"email" bson:"email"`
+	PhoneNumber phoneNumber `json:"phone_number" bson:"phone_number"`
+	Version     int64       `json:"version" bson:"version"`
+	DeletedAt   *time.Time  `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
+	// PasswordHash is never serialized to JSON
+	PasswordHash  string `json:"-" bson:"password_hash,omitempty"`
+	EmailVerified bool   `json:"email_verified" bson:"email_verified"`
+	// Users written before statuses have none and are read as active
+	Status       string    `json:"status" bson:"status"`
+	StatusReason string    `json:"status_reason,omitempty" bson:"status_reason,omitempty"`
+	CreatedAt    time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" bson:"updated_at"`
+	CreatedBy    string    `json:"created_by" bson:"created_by"`
+	UpdatedBy    string    `json:"updated_by" bson:"updated_by"`
 }
 
 func toUserModel(ua *uAgg.User) *User {
 	u := &User{
-		ID:          ua.ID,
-		Name:        ua.Name,
-		Email:       ua.Email,
-		PhoneNumber: ua.PhoneNumber,
+		ID:            ua.ID,
+		Name:          ua.Name,
+		Email:         ua.Email.String(),
+		PhoneNumber:   phoneNumber(ua.PhoneNumber),
+		Version:       ua.Version,
+		DeletedAt:     ua.DeletedAt,
+		PasswordHash:  ua.PasswordHash,
+		EmailVerified: ua.EmailVerified,
+		Status:        string(ua.Status),
+		StatusReason:  ua.StatusReason,
+		CreatedAt:     ua.CreatedAt,
+		UpdatedAt:     ua.UpdatedAt,
+		CreatedBy:     ua.CreatedBy,
+		UpdatedBy:     ua.UpdatedBy,
 	}
 	return u
 }
 
 func (ua *User) toAggregate() (*uAgg.User, error) {
+	status := uAgg.Status(ua.Status)
+	if status == "" {
+		status = uAgg.StatusActive
+	}
 	return &uAgg.User{
-		ID:          ua.ID,
-		Name:        ua.Name,
-		Email:       ua.Email,
-		PhoneNumber: ua.PhoneNumber,
+		ID:            ua.ID,
+		Name:          ua.Name,
+		Email:         uAgg.Email(ua.Email),
+		PhoneNumber:   uAgg.PhoneNumber(ua.PhoneNumber),
+		Version:       ua.Version,
+		DeletedAt:     ua.DeletedAt,
+		PasswordHash:  ua.PasswordHash,
+		EmailVerified: ua.EmailVerified,
+		Status:        status,
+		StatusReason:  ua.StatusReason,
+		CreatedAt:     ua.CreatedAt,
+		UpdatedAt:     ua.UpdatedAt,
+		CreatedBy:     ua.CreatedBy,
+		UpdatedBy:     ua.UpdatedBy,
 	}, nil
 }
 
+// phoneNumber is the stored E.164 phone number. Documents written before phone
+// numbers carried a country code hold an integer. Its country code cannot be told,
+// so it is read back as its bare digits and written back as the same integer.
+type phoneNumber string
+
+func (p *phoneNumber) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	v := bson.RawValue{Type: t, Value: data}
+	switch t {
+	case bson.TypeInt64, bson.TypeInt32:
+		*p = phoneNumber(strconv.FormatInt(v.AsInt64(), 10))
+	case bson.TypeString:
+		*p = phoneNumber(v.StringValue())
+	case bson.TypeNull:
+		*p = ""
+	default:
+		return fmt.Errorf("cannot decode %v into a phone number", t)
+	}
+	return nil
+}
+
+func (p phoneNumber) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	if uAgg.PhoneNumber(p).Legacy() {
+		if n, err := strconv.ParseInt(string(p), 10, 64); err == nil {
+			return bson.MarshalValue(n)
+		}
+	}
+	return bson.MarshalValue(string(p))
+}
+
+// toUserPage trims the extra look-ahead document fetched beyond the page
+// limit and uses it to decide whether there is a next page
+func toUserPage(users []User, spec query.Spec, total int64) *uAgg.UserPage {
+	res := &uAgg.UserPage{
+		Users: make([]uAgg.User, 0, len(users)),
+		Page: query.PageInfo{
+			Total:  total,
+			Limit:  spec.Page.Limit,
+			Offset: spec.Page.Offset,
+		},
+	}
+	if len(users) > spec.Page.Limit {
+		users = users[:spec.Page.Limit]
+		if spec.KeysetPaging() {
+			res.Page.NextCursor = users[len(users)-1].ID
+		}
+	}
+	for _, user := range users {
+		u, _ := user.toAggregate()
+		res.Users = append(res.Users, *u)
+	}
+	return res
+}
+
 // func (ua *User) ToBsonD() bson.D {
 // 	return bson.D{
 // 		{Key: "_id", Value: ua.ID},
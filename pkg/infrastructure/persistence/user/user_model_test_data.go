@@ -4,12 +4,12 @@ var TestUserModelData = User{
 	ID:          "5118863e-a240-44b9-a294-9734382770d9",
 	Name:        "shaharyar",
 	Email:       "samar123@example.com",
-	PhoneNumber: 98765430000,
+	PhoneNumber: "+918765430000",
 }
 
 var TestUserModelData2 = User{
 	ID:          "4d53761b-cf9b-4c59-b4d1-70f597e52f29",
 	Name:        "alam",
 	Email:       "alam@gmail.com",
-	PhoneNumber: 7365725436,
+	PhoneNumber: "+917365725436",
 }
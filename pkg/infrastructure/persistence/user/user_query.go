@@ -0,0 +1,109 @@
+package user
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Crud-application/pkg/domain/query"
+	uAgg "github.com/Crud-application/pkg/domain/userAgg"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// bsonFields maps queryable domain fields to user document keys
+var bsonFields = map[string]string{
+	uAgg.FieldID:          "_id",
+	uAgg.FieldName:        "name",
+	uAgg.FieldEmail:       "email",
+	uAgg.FieldPhoneNumber: "phone_number",
+	uAgg.FieldCreatedAt:   "created_at",
+	uAgg.FieldUpdatedAt:   "updated_at",
+	uAgg.FieldCreatedBy:   "created_by",
+	uAgg.FieldUpdatedBy:   "updated_by",
+	uAgg.FieldStatus:      "status",
+}
+
+// toBsonFilter compiles the filters and search of a query spec into a MongoDB filter.
+// Soft-deleted users are left out unless the spec includes them.
+func toBsonFilter(spec query.Spec) bson.M {
+	var clauses []bson.M
+	if !spec.IncludeDeleted {
+		clauses = append(clauses, bson.M{"deleted_at": nil})
+	}
+	for _, f := range spec.Filters {
+		clauses = append(clauses, bson.M{bsonFields[f.Field]: toBsonCondition(f)})
+	}
+
+	if spec.Search != "" {
+		or := bson.A{}
+		for _, field := range uAgg.SearchFields {
+			or = append(or, bson.M{bsonFields[field]: containsRegex(spec.Search)})
+		}
+		clauses = append(clauses, bson.M{"$or": or})
+	}
+
+	switch len(clauses) {
+	case 0:
+		return bson.M{}
+	case 1:
+		return clauses[0]
+	default:
+		return bson.M{"$and": clauses}
+	}
+}
+
+func toBsonCondition(f query.Filter) bson.M {
+	// Users written before statuses have none and are read as active
+	if f.Field == uAgg.FieldStatus && f.Value == string(uAgg.StatusActive) {
+		switch f.Op {
+		case query.OpEq:
+			return bson.M{"$in": bson.A{f.Value, nil}}
+		case query.OpNe:
+			return bson.M{"$nin": bson.A{f.Value, nil}}
+		}
+	}
+	// Phone numbers stored before country codes were required hold the integer of their digits
+	if f.Field == uAgg.FieldPhoneNumber {
+		if legacy, err := strconv.ParseInt(strings.TrimPrefix(f.Value.(string), "+"), 10, 64); err == nil {
+			switch f.Op {
+			case query.OpEq:
+				return bson.M{"$in": bson.A{f.Value, legacy}}
+			case query.OpNe:
+				return bson.M{"$nin": bson.A{f.Value, legacy}}
+			}
+		}
+	}
+	switch f.Op {
+	case query.OpNe:
+		return bson.M{"$ne": f.Value}
+	case query.OpContains:
+		return containsRegex(f.Value.(string))
+	case query.OpGte:
+		return bson.M{"$gte": f.Value}
+	case query.OpLte:
+		return bson.M{"$lte": f.Value}
+	default:
+		return bson.M{"$eq": f.Value}
+	}
+}
+
+// containsRegex matches documents whose field contains s, ignoring case
+func containsRegex(s string) bson.M {
+	return bson.M{"$regex": regexp.QuoteMeta(s), "$options": "i"}
+}
+
+// toBsonSort compiles the sort of a query spec, using _id as the final tie-breaker
+func toBsonSort(spec query.Spec) bson.D {
+	sort := bson.D{}
+	for _, s := range spec.Sort {
+		dir := 1
+		if s.Desc {
+			dir = -1
+		}
+		sort = append(sort, bson.E{Key: bsonFields[s.Field], Value: dir})
+		if s.Field == uAgg.FieldID {
+			return sort
+		}
+	}
+	return append(sort, bson.E{Key: "_id", Value: 1})
+}
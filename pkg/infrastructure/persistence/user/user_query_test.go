@@ -0,0 +1,101 @@
+package user
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Crud-application/pkg/domain/query"
+	uAgg "github.com/Crud-application/pkg/domain/userAgg"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestToBsonFilterAndSort(t *testing.T) {
+	tests := []struct {
+		id         int
+		name       string
+		spec       query.Spec
+		wantFilter bson.M
+		wantSort   bson.D
+	}{
+		{
+			id:         1,
+			name:       "Empty spec - match all live users by ID",
+			spec:       query.Spec{},
+			wantFilter: bson.M{"deleted_at": nil},
+			wantSort:   bson.D{{Key: "_id", Value: 1}},
+		},
+		{
+			id:   2,
+			name: "Single filter - sort with tie-breaker",
+			spec: query.Spec{
+				Filters: []query.Filter{{Field: uAgg.FieldName, Op: query.OpContains, Value: "a.b"}},
+				Sort:    []query.SortField{{Field: uAgg.FieldName, Desc: true}, {Field: uAgg.FieldEmail}},
+			},
+			wantFilter: bson.M{"$and": []bson.M{
+				{"deleted_at": nil},
+				{"name": bson.M{"$regex": `a\.b`, "$options": "i"}},
+			}},
+			wantSort: bson.D{{Key: "name", Value: -1}, {Key: "email", Value: 1}, {Key: "_id", Value: 1}},
+		},
+		{
+			id:   3,
+			name: "Filters and search including deleted - combined with $and",
+			spec: query.Spec{
+				Filters:        []query.Filter{{Field: uAgg.FieldPhoneNumber, Op: query.OpGte, Value: "+49"}},
+				Search:         "al",
+				Sort:           []query.SortField{{Field: uAgg.FieldID, Desc: true}},
+				IncludeDeleted: true,
+			},
+			wantFilter: bson.M{"$and": []bson.M{
+				{"phone_number": bson.M{"$gte": "+49"}},
+				{"$or": bson.A{
+					bson.M{"name": bson.M{"$regex": "al", "$options": "i"}},
+					bson.M{"email": bson.M{"$regex": "al", "$options": "i"}},
+				}},
+			}},
+			wantSort: bson.D{{Key: "_id", Value: -1}},
+		},
+		{
+			id:   4,
+			name: "Active status - also matches users written before statuses",
+			spec: query.Spec{
+				Filters: []query.Filter{
+					{Field: uAgg.FieldStatus, Op: query.OpEq, Value: "active"},
+					{Field: uAgg.FieldStatus, Op: query.OpNe, Value: "suspended"},
+				},
+				IncludeDeleted: true,
+			},
+			wantFilter: bson.M{"$and": []bson.M{
+				{"status": bson.M{"$in": bson.A{"active", nil}}},
+				{"status": bson.M{"$ne": "suspended"}},
+			}},
+			wantSort: bson.D{{Key: "_id", Value: 1}},
+		},
+		{
+			id:   5,
+			name: "Phone number - also matches the legacy integer of its digits",
+			spec: query.Spec{
+				Filters: []query.Filter{
+					{Field: uAgg.FieldPhoneNumber, Op: query.OpEq, Value: "+919876543210"},
+					{Field: uAgg.FieldPhoneNumber, Op: query.OpNe, Value: "+4930000000"},
+				},
+				IncludeDeleted: true,
+			},
+			wantFilter: bson.M{"$and": []bson.M{
+				{"phone_number": bson.M{"$in": bson.A{"+919876543210", int64(919876543210)}}},
+				{"phone_number": bson.M{"$nin": bson.A{"+4930000000", int64(4930000000)}}},
+			}},
+			wantSort: bson.D{{Key: "_id", Value: 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := toBsonFilter(tt.spec); !reflect.DeepEqual(got, tt.wantFilter) {
+				t.Errorf("ID %v toBsonFilter() = %v, want %v", tt.id, got, tt.wantFilter)
+			}
+			if got := toBsonSort(tt.spec); !reflect.DeepEqual(got, tt.wantSort) {
+				t.Errorf("ID %v toBsonSort() = %v, want %v", tt.id, got, tt.wantSort)
+			}
+		})
+	}
+}
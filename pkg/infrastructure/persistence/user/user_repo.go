@@ -2,115 +2,339 @@ package user
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"time"
 
+	"github.com/Crud-application/pkg/config"
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"github.com/Crud-application/pkg/domain/domainEvent"
+	"github.com/Crud-application/pkg/domain/query"
 	uAgg "github.com/Crud-application/pkg/domain/userAgg"
+	"github.com/Crud-application/pkg/infrastructure/persistence/audit"
+	"github.com/Crud-application/pkg/infrastructure/persistence/outbox"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type MongoUserRepository struct {
-	client *mongo.Client
+	client     *mongo.Client
+	database   string
+	collection string
+	outbox     *outbox.MongoOutboxRepository
+	audit      *audit.MongoAuditRepository
 }
 
-// NewMongoUserRepository constructor that accepts the MongoDB client
-func NewMongoUserRepository(client *mongo.Client) *MongoUserRepository {
+// NewMongoUserRepository constructor that accepts the MongoDB client, its configuration,
+// the outbox the events of users are stored in and the audit log of their changes
+func NewMongoUserRepository(client *mongo.Client, cfg config.MongoConfig, ob *outbox.MongoOutboxRepository, al *audit.MongoAuditRepository) *MongoUserRepository {
 	return &MongoUserRepository{
-		client: client,
+		client:     client,
+		database:   cfg.Database,
+		collection: cfg.UserCollection,
+		outbox:     ob,
+		audit:      al,
 	}
 }
 
-// userCollection retrieves the users collection from the database
+// userCollection retrieves the users collection from the configured database
 func (r *MongoUserRepository) userCollection(ctx context.Context) *mongo.Collection {
-	// Fetch the collection from the database "crud" and "users"
-	return r.client.Database("crud").Collection("users")
+	return r.client.Database(r.database).Collection(r.collection)
+}
+
+// inTransaction runs fn in a transaction, so that a user and its events are stored together.
+// Transactions need MongoDB to run as a replica set.
+func (r *MongoUserRepository) inTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return r.client.UseSession(ctx, func(sc mongo.SessionContext) error {
+		_, err := sc.WithTransaction(sc, func(sc mongo.SessionContext) (any, error) {
+			return nil, fn(sc)
+		})
+		return err
+	})
+}
+
+// storePending stores the events and audit entries of user. Callers run it
+// in the transaction that stores the user.
+func (r *MongoUserRepository) storePending(ctx context.Context, user *uAgg.User) error {
+	if err := r.outbox.AddEvents(ctx, user.Events); err != nil {
+		return err
+	}
+	return r.audit.AddEntries(ctx, user.AuditEntries)
 }
 
 // AddUser adds a new user to the MongoDB collection
 func (r *MongoUserRepository) AddUser(ctx context.Context, user *uAgg.User) error {
 	u := toUserModel(user)
-	// Insert the document
-	result, err := r.userCollection(ctx).InsertOne(ctx, u)
+	// Insert the document along with its events
+	err := r.inTransaction(ctx, func(ctx context.Context) error {
+		if _, err := r.userCollection(ctx).InsertOne(ctx, u); err != nil {
+			return err
+		}
+		return r.storePending(ctx, user)
+	})
 	if err != nil {
 		fmt.Printf("Error inserting user: %v", err)
-		return err
+		if isDuplicateEmail(err) {
+			return domainErr.Conflict("email %s is already in use", user.Email)
+		}
+		return toDomainError(err, user.ID)
 	}
-	fmt.Printf("Inserted user with ID: %s", result.InsertedID)
+	user.Events, user.AuditEntries = nil, nil
+	fmt.Printf("Inserted user with ID: %s", u.ID)
 	return nil
 }
 func (r *MongoUserRepository) GetUser(ctx context.Context, userID string) (*uAgg.User, error) {
-	filter := bson.M{"_id": userID}
+	filter := bson.M{"_id": userID, "deleted_at": nil}
 	var user *User
 	err := r.userCollection(ctx).FindOne(ctx, filter).Decode(&user)
 	if err != nil {
 		log.Printf("Error getting user: %v", err)
-		return nil, err
+		return nil, toDomainError(err, userID)
+	}
+	return user.toAggregate()
+}
+
+// GetUserIncludingDeleted returns the user with the given ID, even if it is soft-deleted
+func (r *MongoUserRepository) GetUserIncludingDeleted(ctx context.Context, userID string) (*uAgg.User, error) {
+	var user *User
+	err := r.userCollection(ctx).FindOne(ctx, bson.M{"_id": userID}).Decode(&user)
+	if err != nil {
+		log.Printf("Error getting user: %v", err)
+		return nil, toDomainError(err, userID)
 	}
 	return user.toAggregate()
 }
 
-func (r *MongoUserRepository) DeleteUser(ctx context.Context, userID string) error {
+// GetUserByEmail returns the user that is not soft-deleted with the given email, ignoring case
+func (r *MongoUserRepository) GetUserByEmail(ctx context.Context, email string) (*uAgg.User, error) {
+	filter := bson.M{"email": email, "deleted_at": nil}
+	var user *User
+	// The collation of the unique email index lets the lookup use it
+	err := r.userCollection(ctx).FindOne(ctx, filter, options.FindOne().SetCollation(caseInsensitive)).Decode(&user)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domainErr.NotFound("no user with email %s", email)
+	}
+	if err != nil {
+		log.Printf("Error getting user by email: %v", err)
+		return nil, toDomainError(err, "")
+	}
+	return user.toAggregate()
+}
 
-	filter := bson.M{"_id": userID}
+// DeleteUser stores the deletion mark of a user, keeping the document so it can be restored
+func (r *MongoUserRepository) DeleteUser(ctx context.Context, user *uAgg.User) error {
+	filter := bson.M{"_id": user.ID, "deleted_at": nil}
+	update := bson.M{
+		"$set": bson.M{
+			"deleted_at": user.DeletedAt,
+			"updated_at": user.UpdatedAt,
+			"updated_by": user.UpdatedBy,
+		},
+		"$inc": bson.M{"version": 1},
+	}
 
-	result, err := r.userCollection(ctx).DeleteOne(ctx, filter)
+	err := r.inTransaction(ctx, func(ctx context.Context) error {
+		result, err := r.userCollection(ctx).UpdateOne(ctx, filter, update)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return domainErr.NotFound("user %s not found", user.ID)
+		}
+		return r.storePending(ctx, user)
+	})
 	if err != nil {
 		log.Printf("Error deleting user: %v", err)
-		return err
+		return toDomainError(err, user.ID)
 	}
+	user.Version++
+	user.Events, user.AuditEntries = nil, nil
+
+	log.Printf("Deleted user with ID: %s", user.ID)
+	return nil
+}
 
-	if result.DeletedCount == 0 {
-		return fmt.Errorf("no user found with ID: %s", userID)
+// RestoreUser clears the deletion mark of a soft-deleted user, provided nobody changed it since it was read
+func (r *MongoUserRepository) RestoreUser(ctx context.Context, user *uAgg.User) error {
+	filter := bson.M{"_id": user.ID, "version": versionFilter(user.Version), "deleted_at": bson.M{"$ne": nil}}
+	update := bson.M{
+		"$unset": bson.M{"deleted_at": ""},
+		"$set": bson.M{
+			"updated_at": user.UpdatedAt,
+			"updated_by": user.UpdatedBy,
+			"version":    user.Version + 1,
+		},
 	}
 
-	log.Printf("Deleted user with ID: %s", userID)
+	err := r.inTransaction(ctx, func(ctx context.Context) error {
+		result, err := r.userCollection(ctx).UpdateOne(ctx, filter, update)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			n, err := r.userCollection(ctx).CountDocuments(ctx, bson.M{"_id": user.ID}, options.Count().SetLimit(1))
+			if err != nil {
+				return err
+			}
+			if n == 0 {
+				return domainErr.NotFound("user %s not found", user.ID)
+			}
+			return domainErr.StaleVersion("user %s was restored or modified concurrently, version %d is stale", user.ID, user.Version)
+		}
+		return r.storePending(ctx, user)
+	})
+	if err != nil {
+		log.Printf("Error restoring user: %v", err)
+		if isDuplicateEmail(err) {
+			return domainErr.Conflict("the email of user %s is in use by another user", user.ID)
+		}
+		return toDomainError(err, user.ID)
+	}
+	user.Version++
+	user.Events, user.AuditEntries = nil, nil
+
+	log.Printf("Restored user with ID: %s", user.ID)
 	return nil
 }
 
-func (r *MongoUserRepository) GetAllUser(ctx context.Context) ([]uAgg.User, error) {
-	// Get all users
-	cursor, err := r.userCollection(ctx).Find(ctx, bson.M{})
+// purgeBatchSize bounds how many users one purge transaction removes
+const purgeBatchSize = 500
+
+// PurgeDeletedUsers removes the documents of users deleted before deletedBefore, a batch per
+// transaction, storing the purged event of each batch along with its removal
+func (r *MongoUserRepository) PurgeDeletedUsers(ctx context.Context, deletedBefore, at time.Time, by string) (int64, error) {
+	filter := bson.M{"deleted_at": bson.M{"$lt": deletedBefore}}
+
+	var total int64
+	for {
+		var n int
+		err := r.inTransaction(ctx, func(ctx context.Context) error {
+			opts := options.Find().SetProjection(bson.M{"_id": 1}).SetLimit(purgeBatchSize)
+			cursor, err := r.userCollection(ctx).Find(ctx, filter, opts)
+			if err != nil {
+				return err
+			}
+			var users []User
+			if err := cursor.All(ctx, &users); err != nil {
+				return err
+			}
+			n = len(users)
+			if n == 0 {
+				return nil
+			}
+			ids := make(bson.A, 0, n)
+			events := make([]domainEvent.Event, 0, n)
+			for _, u := range users {
+				ids = append(ids, u.ID)
+				events = append(events, uAgg.PurgedEvent(u.ID, at, by))
+			}
+			if _, err := r.userCollection(ctx).DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}}); err != nil {
+				return err
+			}
+			return r.outbox.AddEvents(ctx, events)
+		})
+		if err != nil {
+			log.Printf("Error purging deleted users: %v", err)
+			return total, toDomainError(err, "")
+		}
+		total += int64(n)
+		if n < purgeBatchSize {
+			return total, nil
+		}
+	}
+}
+
+// GetAllUser returns one page of the users matching spec, along with their total count
+func (r *MongoUserRepository) GetAllUser(ctx context.Context, spec query.Spec) (*uAgg.UserPage, error) {
+	filter := toBsonFilter(spec)
+
+	pageFilter := filter
+	if spec.Page.Cursor != "" {
+		pageFilter = bson.M{"$and": bson.A{filter, bson.M{"_id": bson.M{"$gt": spec.Page.Cursor}}}}
+	}
+	// Fetch one extra document to know whether another page follows
+	opts := options.Find().
+		SetSort(toBsonSort(spec)).
+		SetSkip(int64(spec.Page.Offset)).
+		SetLimit(int64(spec.Page.Limit + 1))
+
+	cursor, err := r.userCollection(ctx).Find(ctx, pageFilter, opts)
 	if err != nil {
-		return nil, err
+		return nil, toDomainError(err, "")
 	}
 
 	// Iterate through the cursor
 	var users []User
 	if err = cursor.All(ctx, &users); err != nil {
-		return nil, err
+		return nil, toDomainError(err, "")
 	}
 
-	var res []uAgg.User
-	for _, user := range users {
-		res = append(res, uAgg.User{
-			ID:          user.ID,
-			Name:        user.Name,
-			Email:       user.Email,
-			PhoneNumber: user.PhoneNumber,
-		})
+	total, err := r.userCollection(ctx).CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, toDomainError(err, "")
 	}
 
-	return res, nil
+	return toUserPage(users, spec, total), nil
 }
 
+// UpdateUser replaces the mutable fields of a user, provided nobody changed it since it was read
 func (r *MongoUserRepository) UpdateUser(ctx context.Context, user *uAgg.User) error {
-	// Update the user
-	filter := bson.M{"_id": user.ID}
+	// Update the user only if it is still at the version that was read
+	filter := bson.M{"_id": user.ID, "version": versionFilter(user.Version), "deleted_at": nil}
 
 	update := bson.M{
 		"$set": bson.M{
-			"name":        user.Name,
-			"email":       user.Email,
-			"phoneNumber": user.PhoneNumber,
+			"name":           user.Name,
+			"email":          user.Email,
+			"phone_number":   phoneNumber(user.PhoneNumber),
+			"password_hash":  user.PasswordHash,
+			"email_verified": user.EmailVerified,
+			"status":         user.Status,
+			"status_reason":  user.StatusReason,
+			"updated_at":     user.UpdatedAt,
+			"updated_by":     user.UpdatedBy,
+			"version":        user.Version + 1,
 		},
 	}
 
-	_, err := r.userCollection(ctx).UpdateOne(ctx, filter, update)
+	err := r.inTransaction(ctx, func(ctx context.Context) error {
+		result, err := r.userCollection(ctx).UpdateOne(ctx, filter, update)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			// Either the user is gone or another update got in first
+			n, err := r.userCollection(ctx).CountDocuments(ctx, bson.M{"_id": user.ID, "deleted_at": nil}, options.Count().SetLimit(1))
+			if err != nil {
+				return err
+			}
+			if n == 0 {
+				return domainErr.NotFound("user %s not found", user.ID)
+			}
+			return domainErr.StaleVersion("user %s was modified concurrently, version %d is stale", user.ID, user.Version)
+		}
+		return r.storePending(ctx, user)
+	})
 	if err != nil {
 		log.Printf("Error updating user: %v", err)
-		return err
+		if isDuplicateEmail(err) {
+			return domainErr.Conflict("email %s is already in use", user.Email)
+		}
+		return toDomainError(err, user.ID)
 	}
+	user.Version++
+	user.Events, user.AuditEntries = nil, nil
 	log.Printf("Updated user with ID: %s", user.ID)
 	return nil
 }
+
+// versionFilter matches the stored version v. Users written before versioning
+// have no version field and are read as version 0.
+func versionFilter(v int64) any {
+	if v == 0 {
+		return bson.M{"$in": bson.A{int64(0), nil}}
+	}
+	return v
+}
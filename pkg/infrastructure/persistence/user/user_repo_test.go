@@ -2,84 +2,138 @@ package user
 
 import (
 	"context"
+	"errors"
 	"reflect"
 	"testing"
+	"time"
 
+	"github.com/Crud-application/pkg/config"
+	"github.com/Crud-application/pkg/domain/auditAgg"
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"github.com/Crud-application/pkg/domain/domainEvent"
+	"github.com/Crud-application/pkg/domain/query"
 	uAgg "github.com/Crud-application/pkg/domain/userAgg"
+	"github.com/Crud-application/pkg/infrastructure/persistence/audit"
+	"github.com/Crud-application/pkg/infrastructure/persistence/outbox"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
 )
 
-// func TestMongoUserRepository_AddUser(t *testing.T) {
-// 	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
-// 	//defer mt.Close()  method for mtest package is removed  not necessary
+// newTestRepository builds a repository on the mocked client of mt, with its outbox and audit log
+func newTestRepository(mt *mtest.T) *MongoUserRepository {
+	cfg := config.Default().Mongo
+	return NewMongoUserRepository(mt.Client, cfg, outbox.NewMongoOutboxRepository(mt.Client, cfg), audit.NewMongoAuditRepository(mt.Client, cfg))
+}
 
-// 	type args struct {
-// 		ctx  context.Context
-// 		user *uAgg.User
-// 	}
-// 	tests := []struct {
-// 		id         int
-// 		name       string
-// 		beforeTest func(mt *mtest.T) // Sets up mock responses
-// 		arg        args              // Input user for the AddUser function
-// 		wantErr    bool              // Whether an error is expected
-// 	}{
-// 		{
-// 			id:   1,
-// 			name: "User Added successfully - Success",
-// 			beforeTest: func(mt *mtest.T) {
-// 				mt.AddMockResponses(mtest.CreateSuccessResponse())
-// 			},
-// 			arg: args{
-// 				ctx:  context.Background(),
-// 				user: &uAgg.UserAgg,
-// 			},
-// 			wantErr: false,
-// 		},
+func TestMongoUserRepository_AddUser(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
 
-// 		{
-// 			id:   2,
-// 			name: "Create user - MongoDB error - Failure",
-// 			beforeTest: func(mt *mtest.T) {
-// 				mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
-// 			},
-// 			arg: args{
-// 				ctx:  context.Background(),
-// 				user: &uAgg.UserAgg,
-// 			},
-// 			wantErr: true,
-// 		},
-// 	}
+	type args struct {
+		ctx  context.Context
+		user *uAgg.User
+	}
+	tests := []struct {
+		id         int
+		name       string
+		beforeTest func(mt *mtest.T) // Sets up mock responses
+		arg        args              // Input user for the AddUser function
+		wantErr    error             // Expected domain error kind, nil on success
+	}{
+		{
+			id:   1,
+			name: "User Added successfully - Success",
+			beforeTest: func(mt *mtest.T) {
+				// Insert, then commit the transaction
+				mt.AddMockResponses(mtest.CreateSuccessResponse(), mtest.CreateSuccessResponse())
+			},
+			arg: args{
+				ctx:  context.Background(),
+				user: &uAgg.UserAgg,
+			},
+			wantErr: nil,
+		},
+		{
+			id:   2,
+			name: "Duplicate email - Conflict",
+			beforeTest: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{
+					Index:   0,
+					Code:    11000,
+					Message: "E11000 duplicate key error collection: crud.users index: email_unique_active dup key",
+				}))
+			},
+			arg: args{
+				ctx:  context.Background(),
+				user: &uAgg.UserAgg,
+			},
+			wantErr: domainErr.ErrConflict,
+		},
+	}
 
-// 	// Run test cases
-// 	for _, tt := range tests { // Iterate through test cases
-// 		mt.Run(tt.name, func(mt *mtest.T) {
-// 			// Step 1: Initialize repository with mock MongoDB client
-// 			repo := &MongoUserRepository{
-// 				client: mt.Client,
-// 			}
+	for _, tt := range tests {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			repo := newTestRepository(mt)
+			if tt.beforeTest != nil {
+				tt.beforeTest(mt)
+			}
 
-// 			// Step 2: Set up mock responses
-// 			if tt.beforeTest != nil {
-// 				tt.beforeTest(mt) // Configure mock behavior
-// 			}
+			err := repo.AddUser(tt.arg.ctx, tt.arg.user)
 
-// 			// Step 3: Call the AddUser method
-// 			err := repo.AddUser(tt.arg.ctx, tt.arg.user)
+			if !errors.Is(err, tt.wantErr) {
+				mt.Errorf("Test Case ID: %v | AddUser() error = %v, wantErr = %v", tt.id, err, tt.wantErr)
+			}
+		})
+	}
+}
 
-// 			// Step 4: Validate error state
-// 			if (err != nil) != tt.wantErr {
-// 				mt.Errorf(
-// 					"Test Case ID: %v | AddUser() error = %v, wantErr = %v",
-// 					tt.id, err, tt.wantErr,
-// 				)
-// 				return // Exit if error state doesn't match
-// 			}
+func TestPhoneNumber_MarshalBSONValue(t *testing.T) {
+	tests := []struct {
+		id   int
+		name string
+		p    phoneNumber
+		want bson.RawValue
+	}{
+		{id: 1, name: "E.164 number - string", p: "+919876543210", want: bson.RawValue{Type: bson.TypeString}},
+		{id: 2, name: "Legacy number - integer kept as is", p: "9876543210", want: bson.RawValue{Type: bson.TypeInt64}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			typ, data, err := tt.p.MarshalBSONValue()
+			if err != nil || typ != tt.want.Type {
+				t.Fatalf("ID %v MarshalBSONValue() = %v, %v, want type %v", tt.id, typ, err, tt.want.Type)
+			}
+			var back phoneNumber
+			if err := back.UnmarshalBSONValue(typ, data); err != nil || back != tt.p {
+				t.Errorf("ID %v read back %q, %v, want %q", tt.id, back, err, tt.p)
+			}
+		})
+	}
+}
 
-// 		})
-// 	}
-// }
+func TestMongoUserRepository_EnsureIndexes(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	mt.Run("Indexes created - Success", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse(),
+			// There is no legacy email index to drop
+			mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 27, Name: "IndexNotFound", Message: "index not found"}))
+		repo := newTestRepository(mt)
+		if err := repo.EnsureIndexes(context.Background()); err != nil {
+			mt.Errorf("EnsureIndexes() error = %v", err)
+		}
+		started := mt.GetStartedEvent()
+		if started == nil || started.CommandName != "createIndexes" {
+			mt.Fatalf("expected createIndexes command, got %v", started)
+		}
+		index := started.Command.Lookup("indexes").Array().Index(0).Value().Document()
+		if !index.Lookup("unique").Boolean() || index.Lookup("collation", "strength").Int32() != 2 ||
+			index.Lookup("partialFilterExpression", "deleted_at").Type != bson.TypeNull {
+			mt.Errorf("unexpected index definition %v", index)
+		}
+		if dropped := mt.GetStartedEvent(); dropped == nil || dropped.Command.Lookup("index").StringValue() != legacyEmailIndexName {
+			mt.Errorf("expected the legacy email index to be dropped, got %v", dropped)
+		}
+	})
+}
 
 func TestMongoUserRepository_GetUser(t *testing.T) {
 	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
@@ -89,6 +143,8 @@ func TestMongoUserRepository_GetUser(t *testing.T) {
 	}
 
 	saExpected, _ := TestUserModelData.toAggregate()
+	legacy := *saExpected
+	legacy.PhoneNumber = "918765430000"
 	tests := []struct {
 		id         int
 		name       string
@@ -125,12 +181,28 @@ func TestMongoUserRepository_GetUser(t *testing.T) {
 			want:    nil,
 			wantErr: true,
 		},
+		{
+			id:   3,
+			name: "Legacy integer phone number - Success",
+			arg: args{
+				ctx:    context.Background(),
+				userID: TestUserModelData.ID,
+			},
+			beforeTest: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateCursorResponse(1, "foo.bar", mtest.FirstBatch, bson.D{
+					{Key: "_id", Value: TestUserModelData.ID},
+					{Key: "name", Value: TestUserModelData.Name},
+					{Key: "email", Value: TestUserModelData.Email},
+					{Key: "phone_number", Value: int64(918765430000)},
+				}))
+			},
+			want:    &legacy,
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		mt.Run(tt.name, func(mt *mtest.T) {
-			repo := &MongoUserRepository{
-				client: mt.Client,
-			}
+			repo := newTestRepository(mt)
 			// Step 2: Set up mock responses
 			if tt.beforeTest != nil {
 				tt.beforeTest(mt) // Configure mock behavior
@@ -201,124 +273,355 @@ func TestMongoUserRepository_GetUser(t *testing.T) {
 // 		})
 // 	}
 // }
-// func TestMongoUserRepository_UpdateUser(t *testing.T) {
-// 	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
-// 	type args struct {
-// 		ctx  context.Context
-// 		user *uAgg.User
-// 	}
-// 	saExpected, _ := TestUserModelData.toAggregate()
-// 	tests := []struct {
-// 		id         int
-// 		name       string
-// 		args       args
-// 		beforeTest func(mt *mtest.T)
-// 		want       *uAgg.User
-// 		wantErr    bool
-// 	}{
-// 		{
-// 			id:   1,
-// 			name: "User updated successfully - Success",
-// 			args: args{
-// 				ctx:  context.Background(),
-// 				user: saExpected,
-// 			},
-// 			beforeTest: func(mt *mtest.T) {
-// 				mt.AddMockResponses(bson.D{
-// 					{Key: "ok", Value: 1},
-// 					{Key: "value", Value: MongoDBModelToBson(TestUserModelData)},
-// 				})
-// 			},
-// 			want:    saExpected,
-// 			wantErr: false,
-// 		},
-// 		{
-// 			id:   2,
-// 			name: "update User Mongo Error - failure",
-// 			beforeTest: func(m *mtest.T) {
-// 				m.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
-// 			},
-// 			args: args{
-// 				ctx:  context.Background(),
-// 				user: saExpected,
-// 			},
-// 			want:    nil,
-// 			wantErr: true,
-// 		},
-// 	}
-// 	for _, tt := range tests {
-// 		mt.Run(tt.name, func(mt *mtest.T) {
-// 			r := &MongoUserRepository{
-// 				client: mt.Client,
-// 			}
-// 			if tt.beforeTest != nil {
-// 				tt.beforeTest(mt) // Configure mock behavior
-// 			}
-// 			if err := r.UpdateUser(tt.args.ctx, tt.args.user); (err != nil) != tt.wantErr {
-// 				t.Errorf("ID %v UpdateUser() error = %v, wantErr %v", tt.id, err, tt.wantErr)
-// 			}
-// 		})
-// 	}
-// }
-// func TestMongoUserRepository_GetAllUser(t *testing.T) {
-// 	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
-// 	type args struct {
-// 		ctx context.Context
-// 	}
-// 	uExpected1, _ := TestUserModelData.toAggregate()
-// 	uExpected2, _ := TestUserModelData2.toAggregate()
-// 	tests := []struct {
-// 		id         int
-// 		name       string
-// 		args       args
-// 		beforeTest func(mt *mtest.T)
-// 		want       []uAgg.User
-// 		wantErr    bool
-// 	}{
-// 		{
-// 			id:   1,
-// 			name: "Get all users - Success",
-// 			args: args{
-// 				ctx: context.Background(),
-// 			},
-// 			beforeTest: func(mt *mtest.T) {
-// 				first := mtest.CreateCursorResponse(1, "foo.bar", mtest.FirstBatch, MongoDBModelToBson(TestUserModelData))
-// 				getMore := mtest.CreateCursorResponse(1, "foo.bar", mtest.NextBatch, MongoDBModelToBson(TestUserModelData2))
-// 				killCursors := mtest.CreateCursorResponse(0, "foo.bar", mtest.NextBatch)
-// 				mt.AddMockResponses(first, getMore, killCursors)
-// 			},
-// 			want:    []uAgg.User{*uExpected1, *uExpected2},
-// 			wantErr: false,
-// 		},
-// 		{
-// 			id:   2,
-// 			name: "Get all users - MongoDB error - Failure",
-// 			args: args{
-// 				ctx: context.Background(),
-// 			},
-// 			beforeTest: func(mt *mtest.T) {
-// 				mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
-// 			},
-// 			want:    nil,
-// 			wantErr: true,
-// 		},
-// 	}
-// 	for _, tt := range tests {
-// 		mt.Run(tt.name, func(mt *mtest.T) {
-// 			r := &MongoUserRepository{
-// 				client: mt.Client,
-// 			}
-// 			if tt.beforeTest != nil {
-// 				tt.beforeTest(mt) // Configure mock behavior
-// 			}
-// 			got, err := r.GetAllUser(tt.args.ctx)
-// 			if (err != nil) != tt.wantErr {
-// 				t.Errorf("ID %v GetAllUser() error = %v, wantErr %v", tt.id, err, tt.wantErr)
-// 				return
-// 			}
-// 			if !reflect.DeepEqual(got, tt.want) {
-// 				t.Errorf("ID %v GetAllUser() got = %v, want = %v", tt.id, got, tt.want)
-// 			}
-// 		})
-// 	}
-// }
+
+func TestMongoUserRepository_UpdateUser(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	type args struct {
+		ctx  context.Context
+		user *uAgg.User
+	}
+	tests := []struct {
+		id          int
+		name        string
+		args        args
+		beforeTest  func(mt *mtest.T)
+		wantVersion int64 // Version of the user after the call
+		wantErr     error
+	}{
+		{
+			id:   1,
+			name: "User updated at the expected version - Success",
+			args: args{
+				ctx:  context.Background(),
+				user: &uAgg.User{ID: TestUserModelData.ID, Name: "alam", Version: 3, Events: []domainEvent.Event{{Type: uAgg.EventUserUpdated}}},
+			},
+			beforeTest: func(mt *mtest.T) {
+				// Update the user, insert its event into the outbox, then commit
+				mt.AddMockResponses(
+					mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+					mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),
+					mtest.CreateSuccessResponse(),
+				)
+			},
+			wantVersion: 4,
+			wantErr:     nil,
+		},
+		{
+			id:   2,
+			name: "Stale version - Conflict",
+			args: args{
+				ctx:  context.Background(),
+				user: &uAgg.User{ID: TestUserModelData.ID, Name: "alam", Version: 3, Events: []domainEvent.Event{{Type: uAgg.EventUserUpdated}}},
+			},
+			beforeTest: func(mt *mtest.T) {
+				mt.AddMockResponses(
+					mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}, bson.E{Key: "nModified", Value: 0}),
+					mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, bson.D{{Key: "n", Value: 1}}),
+				)
+			},
+			wantVersion: 3,
+			wantErr:     domainErr.ErrConflict,
+		},
+		{
+			id:   3,
+			name: "Missing user - NotFound",
+			args: args{
+				ctx:  context.Background(),
+				user: &uAgg.User{ID: "nonexistentID", Name: "alam", Version: 3, Events: []domainEvent.Event{{Type: uAgg.EventUserUpdated}}},
+			},
+			beforeTest: func(mt *mtest.T) {
+				mt.AddMockResponses(
+					mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}, bson.E{Key: "nModified", Value: 0}),
+					mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch),
+				)
+			},
+			wantVersion: 3,
+			wantErr:     domainErr.ErrNotFound,
+		},
+		{
+			id:   4,
+			name: "User updated with its audit entry - Success",
+			args: args{
+				ctx: context.Background(),
+				user: &uAgg.User{
+					ID: TestUserModelData.ID, Name: "alam", Version: 3,
+					Events:       []domainEvent.Event{{Type: uAgg.EventUserUpdated}},
+					AuditEntries: []auditAgg.Entry{{ResourceID: TestUserModelData.ID, Action: auditAgg.ActionUpdate}},
+				},
+			},
+			beforeTest: func(mt *mtest.T) {
+				// Update the user, insert its event into the outbox and its entry into the audit log, then commit
+				mt.AddMockResponses(
+					mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+					mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),
+					mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),
+					mtest.CreateSuccessResponse(),
+				)
+			},
+			wantVersion: 4,
+			wantErr:     nil,
+		},
+	}
+	for _, tt := range tests {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			r := newTestRepository(mt)
+			if tt.beforeTest != nil {
+				tt.beforeTest(mt) // Configure mock behavior
+			}
+			if err := r.UpdateUser(tt.args.ctx, tt.args.user); !errors.Is(err, tt.wantErr) {
+				mt.Errorf("ID %v UpdateUser() error = %v, wantErr %v", tt.id, err, tt.wantErr)
+			}
+			if tt.args.user.Version != tt.wantVersion {
+				mt.Errorf("ID %v UpdateUser() version = %v, want %v", tt.id, tt.args.user.Version, tt.wantVersion)
+			}
+			// Events are handed to the outbox only when the update is stored
+			if (tt.args.user.Events == nil) != (tt.wantErr == nil) {
+				mt.Errorf("ID %v UpdateUser() events left = %v", tt.id, tt.args.user.Events)
+			}
+			if tt.args.user.AuditEntries != nil && tt.wantErr == nil {
+				mt.Errorf("ID %v UpdateUser() audit entries left = %v", tt.id, tt.args.user.AuditEntries)
+			}
+		})
+	}
+}
+
+func TestMongoUserRepository_RestoreUser(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	tests := []struct {
+		id         int
+		name       string
+		userID     string
+		beforeTest func(mt *mtest.T)
+		wantErr    error
+	}{
+		{
+			id:     1,
+			name:   "Deleted user restored - Success",
+			userID: TestUserModelData.ID,
+			beforeTest: func(mt *mtest.T) {
+				// Restore the user, insert its event into the outbox, then commit
+				mt.AddMockResponses(
+					mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+					mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),
+					mtest.CreateSuccessResponse(),
+				)
+			},
+			wantErr: nil,
+		},
+		{
+			id:     2,
+			name:   "User is not deleted - Conflict",
+			userID: TestUserModelData.ID,
+			beforeTest: func(mt *mtest.T) {
+				mt.AddMockResponses(
+					mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}, bson.E{Key: "nModified", Value: 0}),
+					mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, bson.D{{Key: "n", Value: 1}}),
+				)
+			},
+			wantErr: domainErr.ErrConflict,
+		},
+		{
+			id:     3,
+			name:   "Missing or purged user - NotFound",
+			userID: "nonexistentID",
+			beforeTest: func(mt *mtest.T) {
+				mt.AddMockResponses(
+					mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}, bson.E{Key: "nModified", Value: 0}),
+					mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch),
+				)
+			},
+			wantErr: domainErr.ErrNotFound,
+		},
+		{
+			id:     4,
+			name:   "Email in use by another user - Conflict",
+			userID: TestUserModelData.ID,
+			beforeTest: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{
+					Index:   0,
+					Code:    11000,
+					Message: "E11000 duplicate key error collection: crud.users index: email_unique_active dup key",
+				}))
+			},
+			wantErr: domainErr.ErrConflict,
+		},
+	}
+	for _, tt := range tests {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			r := newTestRepository(mt)
+			tt.beforeTest(mt)
+			user := &uAgg.User{ID: tt.userID, Version: 2, UpdatedBy: "admin", Events: []domainEvent.Event{{Type: uAgg.EventUserRestored}}}
+			if err := r.RestoreUser(context.Background(), user); !errors.Is(err, tt.wantErr) {
+				mt.Errorf("ID %v RestoreUser() error = %v, wantErr %v", tt.id, err, tt.wantErr)
+			}
+			wantVersion := int64(2)
+			if tt.wantErr == nil {
+				wantVersion = 3
+			}
+			if user.Version != wantVersion {
+				mt.Errorf("ID %v RestoreUser() version = %v, want %v", tt.id, user.Version, wantVersion)
+			}
+			// Events are handed to the outbox only when the restore is stored
+			if (user.Events == nil) != (tt.wantErr == nil) {
+				mt.Errorf("ID %v RestoreUser() events left = %v", tt.id, user.Events)
+			}
+			started := mt.GetStartedEvent()
+			if set := started.Command.Lookup("updates").Array().Index(0).Value().Document().Lookup("u", "$set"); set.Document().Lookup("updated_by").StringValue() != "admin" {
+				mt.Errorf("ID %v RestoreUser() update = %v, want updated_by set", tt.id, set)
+			}
+		})
+	}
+}
+
+func TestMongoUserRepository_PurgeDeletedUsers(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	tests := []struct {
+		id         int
+		name       string
+		beforeTest func(mt *mtest.T)
+		want       int64
+		wantErr    bool
+	}{
+		{
+			id:   1,
+			name: "Users past retention purged - Success",
+			beforeTest: func(mt *mtest.T) {
+				// Find the batch, delete it, insert its events into the outbox, then commit
+				mt.AddMockResponses(
+					mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, bson.D{{Key: "_id", Value: "u1"}}, bson.D{{Key: "_id", Value: "u2"}}),
+					mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 2}),
+					mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 2}),
+					mtest.CreateSuccessResponse(),
+				)
+			},
+			want: 2,
+		},
+		{
+			id:   2,
+			name: "Nothing to purge - Success",
+			beforeTest: func(mt *mtest.T) {
+				mt.AddMockResponses(
+					mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch),
+					mtest.CreateSuccessResponse(),
+				)
+			},
+			want: 0,
+		},
+		{
+			id:   3,
+			name: "Database error",
+			beforeTest: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 2, Message: "bad query"}))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			r := newTestRepository(mt)
+			tt.beforeTest(mt)
+			now := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+			got, err := r.PurgeDeletedUsers(context.Background(), now.Add(-time.Hour), now, "system")
+			if (err != nil) != tt.wantErr {
+				mt.Errorf("ID %v PurgeDeletedUsers() error = %v, wantErr %v", tt.id, err, tt.wantErr)
+			}
+			if got != tt.want {
+				mt.Errorf("ID %v PurgeDeletedUsers() got = %v, want %v", tt.id, got, tt.want)
+			}
+			if tt.want == 0 {
+				return
+			}
+			mt.GetStartedEvent() // find
+			mt.GetStartedEvent() // delete
+			insert := mt.GetStartedEvent()
+			if event := insert.Command.Lookup("documents").Array().Index(0).Value().Document(); event.Lookup("type").StringValue() != string(uAgg.EventUserPurged) {
+				mt.Errorf("ID %v PurgeDeletedUsers() stored event = %v, want %v", tt.id, event, uAgg.EventUserPurged)
+			}
+		})
+	}
+}
+
+func TestMongoUserRepository_GetAllUser(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	type args struct {
+		ctx  context.Context
+		spec query.Spec
+	}
+	uExpected1, _ := TestUserModelData.toAggregate()
+	uExpected2, _ := TestUserModelData2.toAggregate()
+	tests := []struct {
+		id         int
+		name       string
+		args       args
+		beforeTest func(mt *mtest.T)
+		want       *uAgg.UserPage
+		wantErr    bool
+	}{
+		{
+			id:   1,
+			name: "Get first page of users - Success",
+			args: args{
+				ctx:  context.Background(),
+				spec: query.Spec{Page: query.PageRequest{Limit: 1}},
+			},
+			beforeTest: func(mt *mtest.T) {
+				find := mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch,
+					MongoDBModelToBson(TestUserModelData2), MongoDBModelToBson(TestUserModelData))
+				count := mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, bson.D{{Key: "n", Value: 2}})
+				mt.AddMockResponses(find, count)
+			},
+			want: &uAgg.UserPage{
+				Users: []uAgg.User{*uExpected2},
+				Page:  query.PageInfo{Total: 2, Limit: 1, NextCursor: uExpected2.ID},
+			},
+			wantErr: false,
+		},
+		{
+			id:   2,
+			name: "Get last page of users - Success",
+			args: args{
+				ctx:  context.Background(),
+				spec: query.Spec{Page: query.PageRequest{Limit: 1, Cursor: uExpected2.ID}},
+			},
+			beforeTest: func(mt *mtest.T) {
+				find := mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, MongoDBModelToBson(TestUserModelData))
+				count := mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, bson.D{{Key: "n", Value: 2}})
+				mt.AddMockResponses(find, count)
+			},
+			want: &uAgg.UserPage{
+				Users: []uAgg.User{*uExpected1},
+				Page:  query.PageInfo{Total: 2, Limit: 1},
+			},
+			wantErr: false,
+		},
+		{
+			id:   3,
+			name: "Get all users - MongoDB error - Failure",
+			args: args{
+				ctx:  context.Background(),
+				spec: query.Spec{Page: query.PageRequest{Limit: 1}},
+			},
+			beforeTest: func(mt *mtest.T) {
+				mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			r := newTestRepository(mt)
+			if tt.beforeTest != nil {
+				tt.beforeTest(mt) // Configure mock behavior
+			}
+			got, err := r.GetAllUser(tt.args.ctx, tt.args.spec)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ID %v GetAllUser() error = %v, wantErr %v", tt.id, err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ID %v GetAllUser() got = %v, want = %v", tt.id, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package webhook
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"github.com/Crud-application/pkg/domain/domainEvent"
+	"github.com/Crud-application/pkg/domain/query"
+	"github.com/Crud-application/pkg/domain/webhookAgg"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// InMemoryWebhookRepository is a thread-safe, process-local store of webhook
+// subscriptions and deliveries that mirrors MongoWebhookRepository for development and CI
+type InMemoryWebhookRepository struct {
+	mu            sync.RWMutex
+	subscriptions map[string]Subscription
+	deliveries    []Delivery // In the order they were added
+}
+
+// NewInMemoryWebhookRepository creates an empty in-memory webhook store
+func NewInMemoryWebhookRepository() *InMemoryWebhookRepository {
+	return &InMemoryWebhookRepository{subscriptions: make(map[string]Subscription)}
+}
+
+// AddSubscription stores a new subscription, rejecting duplicate IDs
+func (r *InMemoryWebhookRepository) AddSubscription(ctx context.Context, s *webhookAgg.Subscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.subscriptions[s.ID]; ok {
+		return domainErr.Conflict("webhook %s already exists", s.ID)
+	}
+	r.subscriptions[s.ID] = *toSubscriptionModel(s)
+	return nil
+}
+
+// GetSubscription returns the subscription with the given ID
+func (r *InMemoryWebhookRepository) GetSubscription(ctx context.Context, id string) (*webhookAgg.Subscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	m, ok := r.subscriptions[id]
+	if !ok {
+		return nil, domainErr.NotFound("webhook %s not found", id)
+	}
+	s := m.toAggregate()
+	return &s, nil
+}
+
+// GetSubscriptions returns one page of the subscriptions in ID order
+func (r *InMemoryWebhookRepository) GetSubscriptions(ctx context.Context, page query.PageRequest) (*webhookAgg.SubscriptionPage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	subs := make([]Subscription, 0, len(r.subscriptions))
+	for _, m := range r.subscriptions {
+		if page.Cursor == "" || m.ID > page.Cursor {
+			subs = append(subs, m)
+		}
+	}
+	slices.SortFunc(subs, func(a, b Subscription) int { return strings.Compare(a.ID, b.ID) })
+	subs = subs[min(page.Offset, len(subs)):]
+	subs = subs[:min(page.Limit+1, len(subs))]
+
+	return toSubscriptionPage(subs, page, int64(len(r.subscriptions))), nil
+}
+
+// GetSubscribers returns the active subscriptions that receive events of type t
+func (r *InMemoryWebhookRepository) GetSubscribers(ctx context.Context, t domainEvent.Type) ([]webhookAgg.Subscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var subs []webhookAgg.Subscription
+	for _, m := range r.subscriptions {
+		if s := m.toAggregate(); s.Wants(t) {
+			subs = append(subs, s)
+		}
+	}
+	return subs, nil
+}
+
+// UpdateSubscription overwrites an existing subscription
+func (r *InMemoryWebhookRepository) UpdateSubscription(ctx context.Context, s *webhookAgg.Subscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.subscriptions[s.ID]; !ok {
+		return domainErr.NotFound("webhook %s not found", s.ID)
+	}
+	r.subscriptions[s.ID] = *toSubscriptionModel(s)
+	return nil
+}
+
+// DeleteSubscription removes a subscription along with its delivery log
+func (r *InMemoryWebhookRepository) DeleteSubscription(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.subscriptions[id]; !ok {
+		return domainErr.NotFound("webhook %s not found", id)
+	}
+	delete(r.subscriptions, id)
+	r.deliveries = slices.DeleteFunc(r.deliveries, func(d Delivery) bool { return d.SubscriptionID == id })
+	return nil
+}
+
+// AddDeliveries stores deliveries and sets their IDs, skipping those of an
+// event that was already delivered to the same subscription
+func (r *InMemoryWebhookRepository) AddDeliveries(ctx context.Context, deliveries []*webhookAgg.Delivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, d := range deliveries {
+		if slices.ContainsFunc(r.deliveries, func(m Delivery) bool {
+			return m.SubscriptionID == d.SubscriptionID && m.EventID == d.EventID
+		}) {
+			continue
+		}
+		id := primitive.NewObjectID()
+		r.deliveries = append(r.deliveries, *toDeliveryModel(id, d))
+		d.ID = id.Hex()
+	}
+	return nil
+}
+
+// GetDueDeliveries returns up to limit pending deliveries due at or before now, longest due first
+func (r *InMemoryWebhookRepository) GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]webhookAgg.Delivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var due []Delivery
+	for _, m := range r.deliveries {
+		if m.Status == string(webhookAgg.DeliveryPending) && !m.NextAttemptAt.After(now) {
+			due = append(due, m)
+		}
+	}
+	slices.SortStableFunc(due, func(a, b Delivery) int { return a.NextAttemptAt.Compare(b.NextAttemptAt) })
+
+	deliveries := make([]webhookAgg.Delivery, 0, min(limit, len(due)))
+	for _, m := range due[:min(limit, len(due))] {
+		deliveries = append(deliveries, m.toAggregate())
+	}
+	return deliveries, nil
+}
+
+// UpdateDelivery stores the outcome of a delivery attempt
+func (r *InMemoryWebhookRepository) UpdateDelivery(ctx context.Context, d *webhookAgg.Delivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := slices.IndexFunc(r.deliveries, func(m Delivery) bool { return m.ID.Hex() == d.ID })
+	if i < 0 {
+		return domainErr.NotFound("delivery %s not found", d.ID)
+	}
+	r.deliveries[i] = *toDeliveryModel(r.deliveries[i].ID, d)
+	return nil
+}
+
+// GetDeliveries returns one page of the delivery log of a subscription, newest first
+func (r *InMemoryWebhookRepository) GetDeliveries(ctx context.Context, subscriptionID string, page query.PageRequest) (*webhookAgg.DeliveryPage, error) {
+	var cursorID primitive.ObjectID
+	if page.Cursor != "" {
+		var err error
+		if cursorID, err = primitive.ObjectIDFromHex(page.Cursor); err != nil {
+			return nil, domainErr.Validation("invalid query", domainErr.FieldError{Field: "cursor", Message: "is malformed"})
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var deliveries []Delivery
+	var total int64
+	for i := len(r.deliveries) - 1; i >= 0; i-- {
+		d := r.deliveries[i]
+		if d.SubscriptionID != subscriptionID {
+			continue
+		}
+		total++
+		if page.Cursor == "" || d.ID.Hex() < cursorID.Hex() {
+			deliveries = append(deliveries, d)
+		}
+	}
+	deliveries = deliveries[min(page.Offset, len(deliveries)):]
+	deliveries = deliveries[:min(page.Limit+1, len(deliveries))]
+
+	return toDeliveryPage(deliveries, page, total), nil
+}
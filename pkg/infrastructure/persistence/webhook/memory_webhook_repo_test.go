@@ -0,0 +1,104 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"github.com/Crud-application/pkg/domain/domainEvent"
+	"github.com/Crud-application/pkg/domain/query"
+	"github.com/Crud-application/pkg/domain/webhookAgg"
+)
+
+func TestInMemoryWebhookRepository_Subscriptions(t *testing.T) {
+	ctx := context.Background()
+	r := NewInMemoryWebhookRepository()
+
+	all := &webhookAgg.Subscription{ID: "a", URL: "http://a.test", Active: true}
+	created := &webhookAgg.Subscription{ID: "b", URL: "http://b.test", Active: true, Events: []domainEvent.Type{"user.created"}}
+	inactive := &webhookAgg.Subscription{ID: "c", URL: "http://c.test"}
+	for _, s := range []*webhookAgg.Subscription{all, created, inactive} {
+		if err := r.AddSubscription(ctx, s); err != nil {
+			t.Fatalf("AddSubscription() error = %v", err)
+		}
+	}
+	if err := r.AddSubscription(ctx, all); !errors.Is(err, domainErr.ErrConflict) {
+		t.Errorf("AddSubscription() of a duplicate error = %v, want conflict", err)
+	}
+
+	subs, _ := r.GetSubscribers(ctx, "user.deleted")
+	if len(subs) != 1 || subs[0].ID != "a" {
+		t.Errorf("GetSubscribers(user.deleted) = %v, want only the unfiltered subscription", subs)
+	}
+	subs, _ = r.GetSubscribers(ctx, "user.created")
+	if len(subs) != 2 {
+		t.Errorf("GetSubscribers(user.created) = %v, want the two active subscriptions", subs)
+	}
+
+	page, _ := r.GetSubscriptions(ctx, query.PageRequest{Limit: 2})
+	if len(page.Subscriptions) != 2 || page.Page.NextCursor != "b" || page.Page.Total != 3 {
+		t.Errorf("GetSubscriptions() = %+v, want a and b then a cursor", page)
+	}
+	page, _ = r.GetSubscriptions(ctx, query.PageRequest{Limit: 2, Cursor: "b"})
+	if len(page.Subscriptions) != 1 || page.Subscriptions[0].ID != "c" || page.Page.NextCursor != "" {
+		t.Errorf("GetSubscriptions() after b = %+v, want only c", page)
+	}
+
+	_ = r.AddDeliveries(ctx, []*webhookAgg.Delivery{{SubscriptionID: "a", EventID: "e1"}, {SubscriptionID: "b", EventID: "e1"}})
+	if err := r.DeleteSubscription(ctx, "a"); err != nil {
+		t.Fatalf("DeleteSubscription() error = %v", err)
+	}
+	if _, err := r.GetSubscription(ctx, "a"); !errors.Is(err, domainErr.ErrNotFound) {
+		t.Errorf("GetSubscription() after delete error = %v, want not found", err)
+	}
+	if log, _ := r.GetDeliveries(ctx, "a", query.PageRequest{Limit: 10}); log.Page.Total != 0 {
+		t.Errorf("GetDeliveries() after delete = %+v, want the log removed", log)
+	}
+	if log, _ := r.GetDeliveries(ctx, "b", query.PageRequest{Limit: 10}); log.Page.Total != 1 {
+		t.Errorf("GetDeliveries() of another subscription = %+v, want it kept", log)
+	}
+}
+
+func TestInMemoryWebhookRepository_Deliveries(t *testing.T) {
+	ctx := context.Background()
+	r := NewInMemoryWebhookRepository()
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	first := &webhookAgg.Delivery{SubscriptionID: "s", EventID: "e1", Status: webhookAgg.DeliveryPending, NextAttemptAt: now}
+	later := &webhookAgg.Delivery{SubscriptionID: "s", EventID: "e2", Status: webhookAgg.DeliveryPending, NextAttemptAt: now.Add(time.Minute)}
+	_ = r.AddDeliveries(ctx, []*webhookAgg.Delivery{first, later})
+	if first.ID == "" || later.ID == "" {
+		t.Fatalf("AddDeliveries() did not set the IDs")
+	}
+	again := &webhookAgg.Delivery{SubscriptionID: "s", EventID: "e1", Status: webhookAgg.DeliveryPending}
+	_ = r.AddDeliveries(ctx, []*webhookAgg.Delivery{again})
+	if again.ID != "" {
+		t.Errorf("AddDeliveries() stored the same event twice")
+	}
+
+	due, _ := r.GetDueDeliveries(ctx, now, 10)
+	if len(due) != 1 || due[0].ID != first.ID {
+		t.Errorf("GetDueDeliveries() = %v, want only the first delivery", due)
+	}
+	first.Status = webhookAgg.DeliverySucceeded
+	if err := r.UpdateDelivery(ctx, first); err != nil {
+		t.Fatalf("UpdateDelivery() error = %v", err)
+	}
+	if due, _ = r.GetDueDeliveries(ctx, now.Add(time.Hour), 10); len(due) != 1 || due[0].ID != later.ID {
+		t.Errorf("GetDueDeliveries() after success = %v, want only the later delivery", due)
+	}
+
+	log, _ := r.GetDeliveries(ctx, "s", query.PageRequest{Limit: 1})
+	if len(log.Deliveries) != 1 || log.Deliveries[0].ID != later.ID || log.Page.NextCursor != later.ID {
+		t.Errorf("GetDeliveries() = %+v, want the newest delivery first", log)
+	}
+	log, _ = r.GetDeliveries(ctx, "s", query.PageRequest{Limit: 1, Cursor: log.Page.NextCursor})
+	if len(log.Deliveries) != 1 || log.Deliveries[0].Status != webhookAgg.DeliverySucceeded {
+		t.Errorf("GetDeliveries() next page = %+v, want the updated first delivery", log)
+	}
+	if err := r.UpdateDelivery(ctx, &webhookAgg.Delivery{ID: "unknown"}); !errors.Is(err, domainErr.ErrNotFound) {
+		t.Errorf("UpdateDelivery() of an unknown delivery error = %v, want not found", err)
+	}
+}
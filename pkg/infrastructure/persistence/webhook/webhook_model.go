@@ -0,0 +1,145 @@
+package webhook
+
+import (
+	"time"
+
+	"github.com/Crud-application/pkg/domain/domainEvent"
+	"github.com/Crud-application/pkg/domain/query"
+	"github.com/Crud-application/pkg/domain/webhookAgg"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Subscription is the stored form of a webhook subscription
+type Subscription struct {
+	ID        string    `bson:"_id"`
+	URL       string    `bson:"url"`
+	Secret    string    `bson:"secret"`
+	Events    []string  `bson:"events"` // Never null, so an empty filter can be matched with $size
+	Active    bool      `bson:"active"`
+	CreatedAt time.Time `bson:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
+
+// Delivery is the stored form of a webhook delivery. ObjectIDs grow over time,
+// so ordering by _id lists deliveries in the order they were added.
+type Delivery struct {
+	ID             primitive.ObjectID `bson:"_id"`
+	SubscriptionID string             `bson:"subscription_id"`
+	EventID        string             `bson:"event_id"`
+	EventType      string             `bson:"event_type"`
+	Body           string             `bson:"body"`
+	Status         string             `bson:"status"`
+	Attempts       int                `bson:"attempts"`
+	NextAttemptAt  time.Time          `bson:"next_attempt_at"`
+	LastAttemptAt  *time.Time         `bson:"last_attempt_at,omitempty"`
+	LastStatusCode int                `bson:"last_status_code,omitempty"`
+	LastError      string             `bson:"last_error,omitempty"`
+	CreatedAt      time.Time          `bson:"created_at"`
+}
+
+func toSubscriptionModel(s *webhookAgg.Subscription) *Subscription {
+	m := &Subscription{
+		ID:        s.ID,
+		URL:       s.URL,
+		Secret:    s.Secret,
+		Events:    make([]string, 0, len(s.Events)),
+		Active:    s.Active,
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+	}
+	for _, e := range s.Events {
+		m.Events = append(m.Events, string(e))
+	}
+	return m
+}
+
+func (m *Subscription) toAggregate() webhookAgg.Subscription {
+	s := webhookAgg.Subscription{
+		ID:        m.ID,
+		URL:       m.URL,
+		Secret:    m.Secret,
+		Active:    m.Active,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+	for _, e := range m.Events {
+		s.Events = append(s.Events, domainEvent.Type(e))
+	}
+	return s
+}
+
+func toDeliveryModel(id primitive.ObjectID, d *webhookAgg.Delivery) *Delivery {
+	return &Delivery{
+		ID:             id,
+		SubscriptionID: d.SubscriptionID,
+		EventID:        d.EventID,
+		EventType:      string(d.EventType),
+		Body:           string(d.Body),
+		Status:         string(d.Status),
+		Attempts:       d.Attempts,
+		NextAttemptAt:  d.NextAttemptAt,
+		LastAttemptAt:  d.LastAttemptAt,
+		LastStatusCode: d.LastStatusCode,
+		LastError:      d.LastError,
+		CreatedAt:      d.CreatedAt,
+	}
+}
+
+func (m *Delivery) toAggregate() webhookAgg.Delivery {
+	return webhookAgg.Delivery{
+		ID:             m.ID.Hex(),
+		SubscriptionID: m.SubscriptionID,
+		EventID:        m.EventID,
+		EventType:      domainEvent.Type(m.EventType),
+		Body:           []byte(m.Body),
+		Status:         webhookAgg.DeliveryStatus(m.Status),
+		Attempts:       m.Attempts,
+		NextAttemptAt:  m.NextAttemptAt,
+		LastAttemptAt:  m.LastAttemptAt,
+		LastStatusCode: m.LastStatusCode,
+		LastError:      m.LastError,
+		CreatedAt:      m.CreatedAt,
+	}
+}
+
+// toSubscriptionPage trims the extra look-ahead subscription fetched beyond
+// the page limit and uses it to decide whether there is a next page
+func toSubscriptionPage(subs []Subscription, page query.PageRequest, total int64) *webhookAgg.SubscriptionPage {
+	res := &webhookAgg.SubscriptionPage{
+		Subscriptions: make([]webhookAgg.Subscription, 0, len(subs)),
+		Page: query.PageInfo{
+			Total:  total,
+			Limit:  page.Limit,
+			Offset: page.Offset,
+		},
+	}
+	if len(subs) > page.Limit {
+		subs = subs[:page.Limit]
+		res.Page.NextCursor = subs[len(subs)-1].ID
+	}
+	for _, s := range subs {
+		res.Subscriptions = append(res.Subscriptions, s.toAggregate())
+	}
+	return res
+}
+
+// toDeliveryPage trims the extra look-ahead delivery fetched beyond the page
+// limit and uses it to decide whether there is a next page
+func toDeliveryPage(deliveries []Delivery, page query.PageRequest, total int64) *webhookAgg.DeliveryPage {
+	res := &webhookAgg.DeliveryPage{
+		Deliveries: make([]webhookAgg.Delivery, 0, len(deliveries)),
+		Page: query.PageInfo{
+			Total:  total,
+			Limit:  page.Limit,
+			Offset: page.Offset,
+		},
+	}
+	if len(deliveries) > page.Limit {
+		deliveries = deliveries[:page.Limit]
+		res.Page.NextCursor = deliveries[len(deliveries)-1].ID.Hex()
+	}
+	for _, d := range deliveries {
+		res.Deliveries = append(res.Deliveries, d.toAggregate())
+	}
+	return res
+}
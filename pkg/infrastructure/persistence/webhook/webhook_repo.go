@@ -0,0 +1,307 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/Crud-application/pkg/config"
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"github.com/Crud-application/pkg/domain/domainEvent"
+	"github.com/Crud-application/pkg/domain/query"
+	"github.com/Crud-application/pkg/domain/webhookAgg"
+	"github.com/Crud-application/pkg/infrastructure/persistence/mongoerr"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// store names the subscription and delivery collections in unavailability errors
+const store = "webhook store"
+
+// MongoWebhookRepository stores webhook subscriptions and their deliveries in two collections
+type MongoWebhookRepository struct {
+	client       *mongo.Client
+	database     string
+	subscription string
+	delivery     string
+}
+
+// NewMongoWebhookRepository constructor that accepts the MongoDB client and its configuration
+func NewMongoWebhookRepository(client *mongo.Client, cfg config.MongoConfig) *MongoWebhookRepository {
+	return &MongoWebhookRepository{
+		client:       client,
+		database:     cfg.Database,
+		subscription: cfg.WebhookCollection,
+		delivery:     cfg.WebhookDeliveryCollection,
+	}
+}
+
+func (r *MongoWebhookRepository) subscriptionCollection() *mongo.Collection {
+	return r.client.Database(r.database).Collection(r.subscription)
+}
+
+func (r *MongoWebhookRepository) deliveryCollection() *mongo.Collection {
+	return r.client.Database(r.database).Collection(r.delivery)
+}
+
+// EnsureIndexes creates the indexes the delivery worker and log rely on. It is idempotent.
+func (r *MongoWebhookRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.deliveryCollection().Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			// An event is delivered at most once per subscription, even if the relay publishes it twice
+			Keys:    bson.D{{Key: "subscription_id", Value: 1}, {Key: "event_id", Value: 1}},
+			Options: options.Index().SetName("subscription_event").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "subscription_id", Value: 1}, {Key: "_id", Value: -1}},
+			Options: options.Index().SetName("subscription_log"),
+		},
+		{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}},
+			Options: options.Index().SetName("due_deliveries"),
+		},
+	})
+	if err != nil {
+		log.Printf("Error creating webhook indexes: %v", err)
+		return mongoerr.ToDomain(err, store)
+	}
+	return nil
+}
+
+// AddSubscription inserts a new subscription
+func (r *MongoWebhookRepository) AddSubscription(ctx context.Context, s *webhookAgg.Subscription) error {
+	if _, err := r.subscriptionCollection().InsertOne(ctx, toSubscriptionModel(s)); err != nil {
+		log.Printf("Error inserting webhook: %v", err)
+		if mongo.IsDuplicateKeyError(err) {
+			return domainErr.Conflict("webhook %s already exists", s.ID)
+		}
+		return mongoerr.ToDomain(err, store)
+	}
+	return nil
+}
+
+// GetSubscription returns the subscription with the given ID
+func (r *MongoWebhookRepository) GetSubscription(ctx context.Context, id string) (*webhookAgg.Subscription, error) {
+	var m Subscription
+	err := r.subscriptionCollection().FindOne(ctx, bson.M{"_id": id}).Decode(&m)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domainErr.NotFound("webhook %s not found", id)
+	}
+	if err != nil {
+		return nil, mongoerr.ToDomain(err, store)
+	}
+	s := m.toAggregate()
+	return &s, nil
+}
+
+// GetSubscriptions returns one page of the subscriptions in ID order
+func (r *MongoWebhookRepository) GetSubscriptions(ctx context.Context, page query.PageRequest) (*webhookAgg.SubscriptionPage, error) {
+	filter := bson.M{}
+	if page.Cursor != "" {
+		filter = bson.M{"_id": bson.M{"$gt": page.Cursor}}
+	}
+	// Fetch one extra subscription to know whether another page follows
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetSkip(int64(page.Offset)).
+		SetLimit(int64(page.Limit + 1))
+
+	cursor, err := r.subscriptionCollection().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, mongoerr.ToDomain(err, store)
+	}
+	var subs []Subscription
+	if err = cursor.All(ctx, &subs); err != nil {
+		return nil, mongoerr.ToDomain(err, store)
+	}
+
+	total, err := r.subscriptionCollection().CountDocuments(ctx, bson.M{})
+	if err != nil {
+		return nil, mongoerr.ToDomain(err, store)
+	}
+	return toSubscriptionPage(subs, page, total), nil
+}
+
+// GetSubscribers returns the active subscriptions that receive events of type t
+func (r *MongoWebhookRepository) GetSubscribers(ctx context.Context, t domainEvent.Type) ([]webhookAgg.Subscription, error) {
+	filter := bson.M{
+		"active": true,
+		"$or": bson.A{
+			bson.M{"events": string(t)},
+			bson.M{"events": bson.M{"$size": 0}},
+		},
+	}
+	cursor, err := r.subscriptionCollection().Find(ctx, filter)
+	if err != nil {
+		return nil, mongoerr.ToDomain(err, store)
+	}
+	var models []Subscription
+	if err = cursor.All(ctx, &models); err != nil {
+		return nil, mongoerr.ToDomain(err, store)
+	}
+
+	subs := make([]webhookAgg.Subscription, 0, len(models))
+	for _, m := range models {
+		subs = append(subs, m.toAggregate())
+	}
+	return subs, nil
+}
+
+// UpdateSubscription overwrites an existing subscription
+func (r *MongoWebhookRepository) UpdateSubscription(ctx context.Context, s *webhookAgg.Subscription) error {
+	result, err := r.subscriptionCollection().ReplaceOne(ctx, bson.M{"_id": s.ID}, toSubscriptionModel(s))
+	if err != nil {
+		log.Printf("Error updating webhook: %v", err)
+		return mongoerr.ToDomain(err, store)
+	}
+	if result.MatchedCount == 0 {
+		return domainErr.NotFound("webhook %s not found", s.ID)
+	}
+	return nil
+}
+
+// DeleteSubscription removes a subscription along with its delivery log
+func (r *MongoWebhookRepository) DeleteSubscription(ctx context.Context, id string) error {
+	result, err := r.subscriptionCollection().DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		log.Printf("Error deleting webhook: %v", err)
+		return mongoerr.ToDomain(err, store)
+	}
+	if result.DeletedCount == 0 {
+		return domainErr.NotFound("webhook %s not found", id)
+	}
+	// Deliveries left behind by a failure here are dead-lettered by the worker
+	if _, err := r.deliveryCollection().DeleteMany(ctx, bson.M{"subscription_id": id}); err != nil {
+		log.Printf("Error deleting webhook deliveries: %v", err)
+		return mongoerr.ToDomain(err, store)
+	}
+	return nil
+}
+
+// AddDeliveries inserts deliveries and sets their IDs, skipping those of an
+// event that was already delivered to the same subscription
+func (r *MongoWebhookRepository) AddDeliveries(ctx context.Context, deliveries []*webhookAgg.Delivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	ids := make([]primitive.ObjectID, 0, len(deliveries))
+	docs := make([]any, 0, len(deliveries))
+	for _, d := range deliveries {
+		ids = append(ids, primitive.NewObjectID())
+		docs = append(docs, toDeliveryModel(ids[len(ids)-1], d))
+	}
+	// Unordered, so a duplicate does not stop the rest from being inserted
+	_, err := r.deliveryCollection().InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+	skipped, ok := duplicates(err)
+	if !ok {
+		log.Printf("Error inserting webhook deliveries: %v", err)
+		return mongoerr.ToDomain(err, store)
+	}
+	for i, d := range deliveries {
+		if !skipped[i] {
+			d.ID = ids[i].Hex()
+		}
+	}
+	return nil
+}
+
+// duplicates returns the positions of the documents a bulk insert skipped because
+// of a unique index. It reports false if the insert failed for another reason.
+func duplicates(err error) (map[int]bool, bool) {
+	if err == nil {
+		return nil, true
+	}
+	var bwe mongo.BulkWriteException
+	if !errors.As(err, &bwe) || bwe.WriteConcernError != nil {
+		return nil, false
+	}
+	skipped := make(map[int]bool, len(bwe.WriteErrors))
+	for _, e := range bwe.WriteErrors {
+		if e.Code != 11000 {
+			return nil, false
+		}
+		skipped[e.Index] = true
+	}
+	return skipped, true
+}
+
+// GetDueDeliveries returns up to limit pending deliveries due at or before now, longest due first
+func (r *MongoWebhookRepository) GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]webhookAgg.Delivery, error) {
+	filter := bson.M{
+		"status":          string(webhookAgg.DeliveryPending),
+		"next_attempt_at": bson.M{"$lte": now},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.deliveryCollection().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, mongoerr.ToDomain(err, store)
+	}
+	var models []Delivery
+	if err = cursor.All(ctx, &models); err != nil {
+		return nil, mongoerr.ToDomain(err, store)
+	}
+
+	deliveries := make([]webhookAgg.Delivery, 0, len(models))
+	for _, m := range models {
+		deliveries = append(deliveries, m.toAggregate())
+	}
+	return deliveries, nil
+}
+
+// UpdateDelivery stores the outcome of a delivery attempt
+func (r *MongoWebhookRepository) UpdateDelivery(ctx context.Context, d *webhookAgg.Delivery) error {
+	id, err := primitive.ObjectIDFromHex(d.ID)
+	if err != nil {
+		return domainErr.NotFound("delivery %s not found", d.ID)
+	}
+
+	result, err := r.deliveryCollection().ReplaceOne(ctx, bson.M{"_id": id}, toDeliveryModel(id, d))
+	if err != nil {
+		log.Printf("Error updating webhook delivery: %v", err)
+		return mongoerr.ToDomain(err, store)
+	}
+	if result.MatchedCount == 0 {
+		return domainErr.NotFound("delivery %s not found", d.ID)
+	}
+	return nil
+}
+
+// GetDeliveries returns one page of the delivery log of a subscription, newest first
+func (r *MongoWebhookRepository) GetDeliveries(ctx context.Context, subscriptionID string, page query.PageRequest) (*webhookAgg.DeliveryPage, error) {
+	filter := bson.M{"subscription_id": subscriptionID}
+
+	pageFilter := filter
+	if page.Cursor != "" {
+		cursorID, err := primitive.ObjectIDFromHex(page.Cursor)
+		if err != nil {
+			return nil, domainErr.Validation("invalid query", domainErr.FieldError{Field: "cursor", Message: "is malformed"})
+		}
+		pageFilter = bson.M{"subscription_id": subscriptionID, "_id": bson.M{"$lt": cursorID}}
+	}
+	// Fetch one extra delivery to know whether another page follows
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: -1}}).
+		SetSkip(int64(page.Offset)).
+		SetLimit(int64(page.Limit + 1))
+
+	cursor, err := r.deliveryCollection().Find(ctx, pageFilter, opts)
+	if err != nil {
+		return nil, mongoerr.ToDomain(err, store)
+	}
+	var deliveries []Delivery
+	if err = cursor.All(ctx, &deliveries); err != nil {
+		return nil, mongoerr.ToDomain(err, store)
+	}
+
+	total, err := r.deliveryCollection().CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, mongoerr.ToDomain(err, store)
+	}
+	return toDeliveryPage(deliveries, page, total), nil
+}
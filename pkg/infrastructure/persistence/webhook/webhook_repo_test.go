@@ -0,0 +1,150 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Crud-application/pkg/config"
+	"github.com/Crud-application/pkg/domain/domainErr"
+	"github.com/Crud-application/pkg/domain/webhookAgg"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestMongoWebhookRepository_GetSubscription(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	tests := []struct {
+		id         int
+		name       string
+		beforeTest func(mt *mtest.T)
+		wantErr    error
+	}{
+		{
+			id:   1,
+			name: "Subscription found - Success",
+			beforeTest: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateCursorResponse(0, "crud.webhooks", mtest.FirstBatch, bson.D{
+					{Key: "_id", Value: "w1"},
+					{Key: "url", Value: "http://example.test/hook"},
+					{Key: "events", Value: bson.A{"user.created"}},
+					{Key: "active", Value: true},
+				}))
+			},
+			wantErr: nil,
+		},
+		{
+			id:   2,
+			name: "Unknown subscription - NotFound",
+			beforeTest: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateCursorResponse(0, "crud.webhooks", mtest.FirstBatch))
+			},
+			wantErr: domainErr.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			r := NewMongoWebhookRepository(mt.Client, config.Default().Mongo)
+			tt.beforeTest(mt)
+
+			got, err := r.GetSubscription(context.Background(), "w1")
+			if !errors.Is(err, tt.wantErr) {
+				mt.Errorf("ID %v GetSubscription() error = %v, wantErr %v", tt.id, err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (got.URL != "http://example.test/hook" || !got.Wants("user.created") || got.Wants("user.deleted")) {
+				mt.Errorf("ID %v GetSubscription() = %+v", tt.id, got)
+			}
+		})
+	}
+}
+
+func TestMongoWebhookRepository_AddDeliveries(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	tests := []struct {
+		id         int
+		name       string
+		beforeTest func(mt *mtest.T)
+		wantIDs    []bool // Whether each delivery was stored
+		wantErr    bool
+	}{
+		{
+			id:   1,
+			name: "Deliveries stored - Success",
+			beforeTest: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 2}))
+			},
+			wantIDs: []bool{true, true},
+			wantErr: false,
+		},
+		{
+			id:   2,
+			name: "Event already delivered is skipped - Success",
+			beforeTest: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{Index: 0, Code: 11000, Message: "E11000 duplicate key error"}))
+			},
+			wantIDs: []bool{false, true},
+			wantErr: false,
+		},
+		{
+			id:   3,
+			name: "Other write error - Failure",
+			beforeTest: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{Index: 1, Code: 2, Message: "bad value"}))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			r := NewMongoWebhookRepository(mt.Client, config.Default().Mongo)
+			tt.beforeTest(mt)
+			deliveries := []*webhookAgg.Delivery{
+				{SubscriptionID: "w1", EventID: "e1"},
+				{SubscriptionID: "w2", EventID: "e1"},
+			}
+
+			err := r.AddDeliveries(context.Background(), deliveries)
+			if (err != nil) != tt.wantErr {
+				mt.Fatalf("ID %v AddDeliveries() error = %v, wantErr %v", tt.id, err, tt.wantErr)
+			}
+			for i, want := range tt.wantIDs {
+				if (deliveries[i].ID != "") != want {
+					mt.Errorf("ID %v AddDeliveries() delivery %d ID = %q, want stored %v", tt.id, i, deliveries[i].ID, want)
+				}
+			}
+			if ordered, _ := mt.GetStartedEvent().Command.Lookup("ordered").BooleanOK(); ordered {
+				mt.Errorf("ID %v AddDeliveries() inserted in order, want unordered", tt.id)
+			}
+		})
+	}
+}
+
+func TestMongoWebhookRepository_GetDueDeliveries(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	mt.Run("Due deliveries - Success", func(mt *mtest.T) {
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "crud.webhook_deliveries", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: id},
+			{Key: "subscription_id", Value: "w1"},
+			{Key: "body", Value: `{"type":"user.created"}`},
+			{Key: "status", Value: "pending"},
+			{Key: "attempts", Value: 2},
+		}))
+		r := NewMongoWebhookRepository(mt.Client, config.Default().Mongo)
+
+		got, err := r.GetDueDeliveries(context.Background(), now, 5)
+		if err != nil || len(got) != 1 || got[0].ID != id.Hex() || got[0].Attempts != 2 || string(got[0].Body) != `{"type":"user.created"}` {
+			mt.Errorf("GetDueDeliveries() = %v, %v", got, err)
+		}
+		filter := mt.GetStartedEvent().Command.Lookup("filter").Document()
+		if status := filter.Lookup("status").StringValue(); status != "pending" {
+			mt.Errorf("GetDueDeliveries() status filter = %q, want pending", status)
+		}
+		if due := filter.Lookup("next_attempt_at", "$lte").Time(); !due.Equal(now) {
+			mt.Errorf("GetDueDeliveries() due filter = %v, want %v", due, now)
+		}
+	})
+}